@@ -56,7 +56,11 @@ p, *, *, POST, /api/callback, *, *
 p, *, *, POST, /api/device-auth, *, *
 p, *, *, POST, /api/cancel-device-auth, *, *
 p, *, *, POST, /api/device-auth-complete, *, *
+p, *, *, GET, /api/get-ciba-request, *, *
+p, *, *, POST, /api/complete-ciba-request, *, *
 p, *, *, GET, /api/get-account, *, *
+p, *, *, GET, /api/get-account-session-devices, *, *
+p, *, *, POST, /api/revoke-account-session-device, *, *
 p, *, *, GET, /api/userinfo, *, *
 p, *, *, GET, /api/user, *, *
 p, *, *, GET, /api/health, *, *
@@ -101,6 +105,7 @@ p, *, *, GET, /.well-known/openid-configuration, *, *
 p, *, *, GET, /.well-known/oauth-authorization-server, *, *
 p, *, *, GET, /.well-known/oauth-protected-resource, *, *
 p, *, *, GET, /.well-known/webfinger, *, *
+p, *, *, GET, /.well-known/ssf-configuration, *, *
 p, *, *, *, /.well-known/jwks, *, *
 p, *, *, GET, /.well-known/:application/openid-configuration, *, *
 p, *, *, GET, /.well-known/:application/oauth-authorization-server, *, *
@@ -108,9 +113,14 @@ p, *, *, GET, /.well-known/:application/oauth-protected-resource, *, *
 p, *, *, GET, /.well-known/:application/webfinger, *, *
 p, *, *, *, /.well-known/:application/jwks, *, *
 p, *, *, GET, /api/get-saml-login, *, *
+p, *, *, GET, /api/oidc-login, *, *
+p, *, *, *, /api/provider-logout, *, *
+p, *, *, POST, /api/ssf-receiver, *, *
 p, *, *, POST, /api/acs, *, *
 p, *, *, GET, /api/saml/metadata, *, *
 p, *, *, *, /api/saml/redirect, *, *
+p, *, *, *, /api/saml/slo, *, *
+p, *, *, *, /api/wsfed, *, *
 p, *, *, *, /cas, *, *
 p, *, *, *, /scim, *, *
 p, *, *, *, /api/webauthn, *, *
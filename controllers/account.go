@@ -18,7 +18,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 
 	"github.com/beego/beego/v2/core/logs"
@@ -36,6 +35,7 @@ const (
 	ResponseTypeSaml    = "saml"
 	ResponseTypeCas     = "cas"
 	ResponseTypeDevice  = "device"
+	ResponseTypeWsFed   = "wsfed"
 )
 
 type Response struct {
@@ -318,8 +318,10 @@ func (c *ApiController) Signup() {
 		}
 	}
 
+	authContext := object.NewAuthContext(object.AmrPassword)
 	if user.Type == "normal-user" {
 		c.SetSessionUsername(user.GetId())
+		c.setSessionAuthContext(user.GetId(), authContext)
 	} else if user.Type == "paid-user" {
 		c.SetSession("paidUsername", user.GetId())
 	}
@@ -354,6 +356,7 @@ func (c *ApiController) Signup() {
 	state := c.Ctx.Input.Query("state")
 	nonce := c.Ctx.Input.Query("nonce")
 	codeChallenge := c.Ctx.Input.Query("code_challenge")
+	requestUri := c.Ctx.Input.Query("request_uri")
 
 	// If OAuth parameters are present, generate OAuth code and return it
 	if clientId != "" && responseType == ResponseTypeCode {
@@ -368,7 +371,7 @@ func (c *ApiController) Signup() {
 			return
 		}
 
-		code, err := object.GetOAuthCode(userId, clientId, "", "password", responseType, redirectUri, scope, state, nonce, codeChallenge, "", c.Ctx.Request.Host, c.GetAcceptLanguage())
+		code, err := object.GetOAuthCode(userId, clientId, "", "password", responseType, redirectUri, scope, state, nonce, codeChallenge, "", c.Ctx.Input.Query("acr_values"), c.Ctx.Input.Query("max_age"), requestUri, c.Ctx.Request.Host, c.GetAcceptLanguage(), authContext, c.Ctx.Input.CruSession.SessionID(context.Background()))
 		if err != nil {
 			c.ResponseError(err.Error(), nil)
 			return
@@ -430,10 +433,13 @@ func (c *ApiController) Logout() {
 		// replaces CruSession's id, so reading it afterwards would miss the id stored in the DB.
 		beegoSessionId := c.Ctx.Input.CruSession.SessionID(context.Background())
 
-		c.ClearUserSession()
-		c.ClearTokenSession()
+		samlSessions, err := c.getSamlFrontChannelSessions(user, beegoSessionId, redirectUri)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
 
-		if err := c.deleteUserSession(user, beegoSessionId); err != nil {
+		if err := c.logoutUserSession(user, beegoSessionId); err != nil {
 			c.ResponseError(err.Error())
 			return
 		}
@@ -441,13 +447,9 @@ func (c *ApiController) Logout() {
 		// Propagate logout to external Custom OAuth2 providers
 		object.InvokeCustomProviderLogout(application, sessionToken)
 
-		// Send OIDC Back-Channel Logout notifications (https://openid.net/specs/openid-connect-backchannel-1_0.html)
-		bcOwner, bcUsername := util.GetOwnerAndNameFromIdNoCheck(user)
-		object.SendBackchannelLogout(bcOwner, bcUsername, "", c.Ctx.Request.Host)
-
 		// "post_logout_redirect_uri" has been made optional, see: https://github.com/casdoor/casdoor/issues/2151
 		if redirectUri != "" {
-			c.redirectToPostLogout(application, redirectUri, state)
+			c.redirectToPostLogout(application, redirectUri, state, samlSessions)
 			return
 		}
 
@@ -486,11 +488,14 @@ func (c *ApiController) Logout() {
 		// replaces CruSession's id, so reading it afterwards would miss the id stored in the DB.
 		beegoSessionId := c.Ctx.Input.CruSession.SessionID(context.Background())
 
-		c.ClearUserSession()
-		c.ClearTokenSession()
+		samlSessions, err := c.getSamlFrontChannelSessions(user, beegoSessionId, redirectUri)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
 
 		// TODO https://github.com/casdoor/casdoor/pull/1494#discussion_r1095675265
-		if err := c.deleteUserSession(user, beegoSessionId); err != nil {
+		if err := c.logoutUserSession(user, beegoSessionId); err != nil {
 			c.ResponseError(err.Error())
 			return
 		}
@@ -498,22 +503,35 @@ func (c *ApiController) Logout() {
 		// Propagate logout to external Custom OAuth2 providers
 		object.InvokeCustomProviderLogout(application, accessToken)
 
-		// Send OIDC Back-Channel Logout notifications (https://openid.net/specs/openid-connect-backchannel-1_0.html)
-		object.SendBackchannelLogout(token.Organization, token.User, "", c.Ctx.Request.Host)
-
 		// "post_logout_redirect_uri" has been made optional, see: https://github.com/casdoor/casdoor/issues/2151
 		if redirectUri == "" {
 			c.ResponseOk()
 			return
 		}
-		c.redirectToPostLogout(application, redirectUri, state)
+		c.redirectToPostLogout(application, redirectUri, state, samlSessions)
 		return
 	}
 }
 
+// getSamlFrontChannelSessions returns the SAML sessions of the Beego session which the browser logs out of on its
+// way to "post_logout_redirect_uri", they are read before the logout deletes them. A logout without the redirect
+// isn't a navigation of the browser, so it has none.
+func (c *ApiController) getSamlFrontChannelSessions(user string, sessionId string, redirectUri string) ([]*object.SamlSession, error) {
+	if redirectUri == "" {
+		return nil, nil
+	}
+
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(user)
+	if err != nil {
+		return nil, err
+	}
+	return object.GetSamlFrontChannelSessions(owner, name, sessionId)
+}
+
 // redirectToPostLogout validates "post_logout_redirect_uri" against the application's allowed
-// redirect URI list and redirects the user agent to it, appending "state" when present.
-func (c *ApiController) redirectToPostLogout(application *object.Application, redirectUri string, state string) {
+// redirect URI list and redirects the user agent to it, appending "state" when present. The
+// user agent goes through the front-channel SAML service providers of the session first.
+func (c *ApiController) redirectToPostLogout(application *object.Application, redirectUri string, state string, samlSessions []*object.SamlSession) {
 	if application == nil || !application.IsRedirectUriValid(redirectUri) {
 		c.ResponseError(fmt.Sprintf(c.T("token:Redirect URI: %s doesn't exist in the allowed Redirect URI list"), redirectUri))
 		return
@@ -527,7 +545,9 @@ func (c *ApiController) redirectToPostLogout(application *object.Application, re
 			redirectUrl = fmt.Sprintf("%s?state=%s", strings.TrimSuffix(redirectUri, "/"), state)
 		}
 	}
-	c.Ctx.Redirect(http.StatusFound, redirectUrl)
+
+	redirectUrl, form := object.StartSamlLogoutChain(samlSessions, redirectUrl, nil, c.Ctx.Request.Host)
+	c.responseSamlMessage(redirectUrl, form)
 }
 
 // SsoLogout
@@ -578,6 +598,12 @@ func (c *ApiController) SsoLogout() {
 		return
 	}
 
+	// SendBackchannelLogout only reaches the SAML service providers of the current session,
+	// the ones of the other sessions are logged out here when all the sessions are
+	if logoutAllSessions {
+		object.SendSamlLogoutAll(owner, username, c.Ctx.Request.Host)
+	}
+
 	// Send OIDC Back-Channel Logout notifications BEFORE expiring tokens,
 	// because SendBackchannelLogout calls GetActiveTokensByUser (expires_in > 0).
 	object.SendBackchannelLogout(owner, username, currentSessionId, c.Ctx.Request.Host)
@@ -753,6 +779,23 @@ func (c *ApiController) GetUserinfo() {
 		return
 	}
 
+	application, err := object.GetApplicationByClientId(aud)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	encryptedUserInfo, ok, err := object.EncryptUserinfo(application, userInfo)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if ok {
+		c.Ctx.Output.Header("Content-Type", "application/jwt")
+		c.Ctx.Output.Body([]byte(encryptedUserInfo))
+		return
+	}
+
 	c.Data["json"] = userInfo
 	c.ServeJSON()
 }
@@ -874,6 +917,23 @@ func (c *ApiController) GetCaptcha() {
 	c.ResponseOk(Captcha{Type: "none"})
 }
 
+// logoutUserSession signs the user out of the Beego session beegoSessionId, which is the current one: the session is
+// cleared and dropped, then the applications signed in to are sent OIDC Back-Channel Logout tokens and SAML LogoutRequests
+// (https://openid.net/specs/openid-connect-backchannel-1_0.html). The id has to be read before, see Logout().
+func (c *ApiController) logoutUserSession(user string, beegoSessionId string) error {
+	c.ClearUserSession()
+	c.ClearTokenSession()
+
+	err := c.deleteUserSession(user, beegoSessionId)
+	if err != nil {
+		return err
+	}
+
+	owner, username := util.GetOwnerAndNameFromIdNoCheck(user)
+	object.SendBackchannelLogout(owner, username, beegoSessionId, c.Ctx.Request.Host)
+	return nil
+}
+
 func (c *ApiController) deleteUserSession(user string, beegoSessionId string) error {
 	owner, username, err := util.GetOwnerAndNameFromIdWithError(user)
 	if err != nil {
@@ -239,6 +239,15 @@ func (c *ApiController) UpdateApplication() {
 		return
 	}
 
+	if err = object.CheckEncryptionSetting(application.IdTokenEncryptedResponseAlg, application.IdTokenEncryptedResponseEnc); err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if err = object.CheckEncryptionSetting(application.UserinfoEncryptedResponseAlg, application.UserinfoEncryptedResponseEnc); err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	columns := []string{}
 	if columnsStr != "" {
 		for _, col := range strings.Split(columnsStr, ",") {
@@ -281,6 +290,15 @@ func (c *ApiController) AddApplication() {
 		return
 	}
 
+	if err = object.CheckEncryptionSetting(application.IdTokenEncryptedResponseAlg, application.IdTokenEncryptedResponseEnc); err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if err = object.CheckEncryptionSetting(application.UserinfoEncryptedResponseAlg, application.UserinfoEncryptedResponseEnc); err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	if len(application.GrantTypes) == 0 {
 		application.GrantTypes = []string{"authorization_code"}
 	}
@@ -57,7 +57,7 @@ func tokenToResponse(token *object.Token) *Response {
 }
 
 // HandleLoggedIn ...
-func (c *ApiController) HandleLoggedIn(application *object.Application, user *object.User, form *form.AuthForm) (resp *Response) {
+func (c *ApiController) HandleLoggedIn(application *object.Application, user *object.User, form *form.AuthForm, authContext *object.AuthContext) (resp *Response) {
 	if user.IsForbidden {
 		c.ResponseError(c.T("check:The user is forbidden to sign in, please contact the administrator"))
 		return
@@ -177,6 +177,7 @@ func (c *ApiController) HandleLoggedIn(application *object.Application, user *ob
 		challengeMethod := c.Ctx.Input.Query("code_challenge_method")
 		codeChallenge := c.Ctx.Input.Query("code_challenge")
 		resource := c.Ctx.Input.Query("resource")
+		requestUri := c.Ctx.Input.Query("request_uri")
 
 		if challengeMethod != "S256" && challengeMethod != "null" && challengeMethod != "" {
 			c.ResponseError(c.T("auth:Challenge method should be S256"))
@@ -198,7 +199,7 @@ func (c *ApiController) HandleLoggedIn(application *object.Application, user *ob
 		if consentRequired {
 			resp = &Response{Status: "ok", Data: map[string]bool{"required": true}}
 		} else {
-			code, err := object.GetOAuthCode(userId, clientId, form.Provider, form.SigninMethod, responseType, redirectUri, scope, state, nonce, codeChallenge, resource, c.Ctx.Request.Host, c.GetAcceptLanguage())
+			code, err := object.GetOAuthCode(userId, clientId, form.Provider, form.SigninMethod, responseType, redirectUri, scope, state, nonce, codeChallenge, resource, c.Ctx.Input.Query("acr_values"), c.Ctx.Input.Query("max_age"), requestUri, c.Ctx.Request.Host, c.GetAcceptLanguage(), authContext, c.Ctx.Input.CruSession.SessionID(context.Background()))
 			if err != nil {
 				c.ResponseError(err.Error(), nil)
 				return
@@ -216,8 +217,16 @@ func (c *ApiController) HandleLoggedIn(application *object.Application, user *ob
 			if !valid {
 				resp = &Response{Status: "error", Msg: "error: invalid_scope", Data: ""}
 			} else {
-				token, _ := object.GetTokenByUser(application, user, expandedScope, nonce, c.Ctx.Request.Host)
+				token, _ := object.GetTokenByUser(application, user, expandedScope, nonce, c.Ctx.Request.Host, authContext, c.Ctx.Input.CruSession.SessionID(context.Background()))
 				resp = tokenToResponse(token)
+				if form.Type == ResponseTypeIdToken && resp.Status == "ok" {
+					idToken, err := object.EncryptIdToken(application, token.AccessToken)
+					if err != nil {
+						c.ResponseError(err.Error(), nil)
+						return
+					}
+					resp.Data = idToken
+				}
 			}
 		}
 	} else if form.Type == ResponseTypeDevice {
@@ -263,13 +272,26 @@ func (c *ApiController) HandleLoggedIn(application *object.Application, user *ob
 
 		resp = &Response{Status: "ok", Msg: "", Data: userId}
 	} else if form.Type == ResponseTypeSaml { // saml flow
-		res, redirectUrl, method, err := object.GetSamlResponse(application, user, form.SamlRequest, c.Ctx.Request.Host)
+		querySignature := &object.SamlQuerySignature{RelayState: form.RelayState, SigAlg: form.SigAlg, Signature: form.Signature, RawQuery: form.SamlQuery}
+		res, redirectUrl, method, err := object.GetSamlResponse(application, user, form.SamlRequest, querySignature, c.Ctx.Request.Host, c.Ctx.Input.CruSession.SessionID(context.Background()))
 		if err != nil {
 			c.ResponseError(err.Error(), nil)
 			return
 		}
 		resp = &Response{Status: "ok", Msg: "", Data: res, Data2: map[string]interface{}{"redirectUrl": redirectUrl, "method": method}}
 
+		if application.EnableSigninSession || application.HasPromptPage() {
+			// The prompt page needs the user to be signed in
+			c.SetSessionUsername(userId)
+		}
+	} else if form.Type == ResponseTypeWsFed {
+		res, replyUrl, err := object.GetWsFedResponse(application, user, form.WsFedRealm, form.WsFedReply, c.Ctx.Request.Host, c.Ctx.Input.CruSession.SessionID(context.Background()))
+		if err != nil {
+			c.ResponseError(err.Error(), nil)
+			return
+		}
+		resp = &Response{Status: "ok", Msg: "", Data: res, Data2: replyUrl}
+
 		if application.EnableSigninSession || application.HasPromptPage() {
 			// The prompt page needs the user to be signed in
 			c.SetSessionUsername(userId)
@@ -307,6 +329,7 @@ func (c *ApiController) HandleLoggedIn(application *object.Application, user *ob
 			expireInHours = 24
 		}
 		c.setExpireForSession(expireInHours)
+		c.setSessionAuthContext(userId, authContext)
 	}
 
 	if resp.Status == "ok" {
@@ -346,6 +369,42 @@ func (c *ApiController) HandleLoggedIn(application *object.Application, user *ob
 			c.ResponseError(err.Error(), nil)
 			return
 		}
+
+		sessionDevice := &object.SessionDevice{
+			Owner:       user.Owner,
+			SessionId:   c.Ctx.Input.CruSession.SessionID(context.Background()),
+			User:        user.Name,
+			Application: application.Name,
+			ClientIp:    clientIp,
+			UserAgent:   c.Ctx.Request.UserAgent(),
+		}
+		if authContext != nil {
+			sessionDevice.SigninMethod = strings.Join(authContext.Amr, ",")
+			sessionDevice.Provider = authContext.Provider
+		}
+		_, err = object.AddSessionDevice(sessionDevice)
+		if err != nil {
+			c.ResponseError(err.Error(), nil)
+			return
+		}
+		c.SetSession(object.SessionDeviceSessionKey, sessionDevice.Name)
+
+		if authContext != nil && authContext.Provider != "" {
+			providerOwner, providerName := util.GetOwnerAndNameFromIdNoCheck(authContext.Provider)
+			_, err = object.AddIdpSession(&object.IdpSession{
+				Owner:        providerOwner,
+				Provider:     providerName,
+				Organization: user.Owner,
+				User:         user.Name,
+				SessionId:    c.Ctx.Input.CruSession.SessionID(context.Background()),
+				Subject:      authContext.IdpSubject,
+				Sid:          authContext.IdpSid,
+			})
+			if err != nil {
+				c.ResponseError(err.Error(), nil)
+				return
+			}
+		}
 	}
 
 	return resp
@@ -371,12 +430,13 @@ func (c *ApiController) GetApplicationLogin() {
 	id := c.Ctx.Input.Query("id")
 	loginType := c.Ctx.Input.Query("type")
 	userCode := c.Ctx.Input.Query("userCode")
+	requestUri := c.Ctx.Input.Query("request_uri")
 
 	var application *object.Application
 	var msg string
 	var err error
 	if loginType == "code" {
-		msg, application, err = object.CheckOAuthLogin(clientId, responseType, redirectUri, scope, state, c.GetAcceptLanguage())
+		msg, application, err = object.CheckOAuthLogin(clientId, responseType, redirectUri, scope, state, requestUri, c.GetAcceptLanguage())
 		if err != nil {
 			c.ResponseError(err.Error())
 			return
@@ -454,21 +514,25 @@ func isProxyProviderType(providerType string) bool {
 	return false
 }
 
-func checkMfaEnable(c *ApiController, user *object.User, organization *object.Organization, verificationType string) bool {
+func checkMfaEnable(c *ApiController, user *object.User, organization *object.Organization, verificationType string, authContext *object.AuthContext) bool {
 	if object.IsNeedPromptMfa(organization, user) {
 		// The prompt page needs the user to be signed in
 		c.SetSessionUsername(user.GetId())
+		c.setSessionAuthContext(user.GetId(), authContext)
 		c.ResponseOk(object.RequiredMfa)
 		return true
 	}
 
 	if user.IsMfaEnabled() {
+		// the remembered MFA doesn't count when the request asks for a multi-factor authentication
 		currentTime := util.String2Time(util.GetCurrentTime())
 		mfaRememberDeadline := util.String2Time(user.MfaRememberDeadline)
-		if user.MfaRememberDeadline != "" && mfaRememberDeadline.After(currentTime) {
+		isMfaRequested := !object.IsAcrSatisfied(c.Ctx.Input.Query("acr_values"), object.AcrSingleFactor)
+		if user.MfaRememberDeadline != "" && mfaRememberDeadline.After(currentTime) && !isMfaRequested {
 			return false
 		}
 		c.setMfaUserSession(user.GetId())
+		c.setMfaAuthContext(authContext)
 		mfaList := object.GetAllMfaProps(user, true)
 		mfaAllowList := []*object.MfaProps{}
 		mfaRememberInHours := organization.MfaRememberInHours
@@ -582,6 +646,7 @@ func (c *ApiController) Login() {
 	}
 
 	verificationType := ""
+	var authContext *object.AuthContext
 
 	if authForm.Username != "" {
 		var user *object.User
@@ -639,6 +704,8 @@ func (c *ApiController) Login() {
 					return
 				}
 			}
+
+			authContext = object.NewAuthContext(object.AmrFace)
 		} else if authForm.Password == "" {
 			var application *object.Application
 			application, err = object.GetApplication(fmt.Sprintf("admin/%s", authForm.Application))
@@ -708,8 +775,10 @@ func (c *ApiController) Login() {
 
 			if verificationCodeType == object.VerifyTypePhone {
 				verificationType = "sms"
+				authContext = object.NewAuthContext(object.AmrSms)
 			} else {
 				verificationType = "email"
+				authContext = object.NewAuthContext(object.AmrOtp)
 				if !user.EmailVerified {
 					user.EmailVerified = true
 					_, err = object.UpdateUser(user.GetId(), user, []string{"email_verified"}, false)
@@ -801,6 +870,11 @@ func (c *ApiController) Login() {
 			}
 
 			user, err = object.CheckUserPassword(authForm.Organization, authForm.Username, password, c.GetAcceptLanguage(), enableCaptcha, isSigninViaLdap, isPasswordWithLdapEnabled)
+			if err == nil && user.Ldap == "" {
+				// a password found in a breach has to be updated before the user can sign in
+				err = object.CheckUserPasswordBreached(user, password)
+			}
+			authContext = object.NewAuthContext(object.AmrPassword)
 		}
 
 		if err != nil {
@@ -829,11 +903,11 @@ func (c *ApiController) Login() {
 				c.ResponseError(err.Error())
 			}
 
-			if checkMfaEnable(c, user, organization, verificationType) {
+			if checkMfaEnable(c, user, organization, verificationType, authContext) {
 				return
 			}
 
-			resp = c.HandleLoggedIn(application, user, &authForm)
+			resp = c.HandleLoggedIn(application, user, &authForm, authContext)
 
 			c.Ctx.Input.SetParam("recordUserId", user.GetId())
 		}
@@ -897,6 +971,7 @@ func (c *ApiController) Login() {
 				return
 			}
 			idpInfo.CodeVerifier = authForm.CodeVerifier
+			idpInfo.Nonce = authForm.IdpNonce
 			var idProvider idp.IdProvider
 			idProvider, err = idp.GetIdProvider(idpInfo, authForm.RedirectUri)
 			if err != nil {
@@ -972,11 +1047,13 @@ func (c *ApiController) Login() {
 					return
 				}
 
-				if checkMfaEnable(c, user, organization, verificationType) {
+				idpSubject, idpSid := idp.GetIdpSession(token, userInfo)
+				authContext = object.NewAuthContext(object.AmrFederated).WithIdpSession(provider.GetId(), idpSubject, idpSid)
+				if checkMfaEnable(c, user, organization, verificationType, authContext) {
 					return
 				}
 
-				resp = c.HandleLoggedIn(application, user, &authForm)
+				resp = c.HandleLoggedIn(application, user, &authForm, authContext)
 
 				c.Ctx.Input.SetParam("recordUserId", user.GetId())
 			} else if provider.Category == "OAuth" || provider.Category == "Web3" || provider.Category == "SAML" {
@@ -1123,7 +1200,9 @@ func (c *ApiController) Login() {
 					return
 				}
 
-				resp = c.HandleLoggedIn(application, user, &authForm)
+				idpSubject, idpSid := idp.GetIdpSession(token, userInfo)
+				authContext = object.NewAuthContext(object.AmrFederated).WithIdpSession(provider.GetId(), idpSubject, idpSid)
+				resp = c.HandleLoggedIn(application, user, &authForm, authContext)
 
 				c.Ctx.Input.SetParam("recordUserId", user.GetId())
 				c.Ctx.Input.SetParam("recordSignup", "true")
@@ -1212,6 +1291,7 @@ func (c *ApiController) Login() {
 			c.ResponseError(c.T(err.Error()))
 		}
 
+		var mfaAmr string
 		if authForm.Passcode != "" {
 			if authForm.MfaType == c.GetSession("verificationCodeType") {
 				c.ResponseError("Invalid multi-factor authentication type")
@@ -1251,6 +1331,7 @@ func (c *ApiController) Login() {
 				}
 			}
 			c.SetSession("verificationCodeType", "")
+			mfaAmr = object.GetAmrByMfaType(authForm.MfaType)
 		} else if authForm.RecoveryCode != "" {
 			err = object.MfaRecover(user, authForm.RecoveryCode)
 			if err != nil {
@@ -1258,13 +1339,16 @@ func (c *ApiController) Login() {
 				c.ResponseError(err.Error())
 				return
 			}
+			mfaAmr = object.AmrOtp
 		} else {
 			c.ResponseError("missing passcode or recovery code")
 			return
 		}
 
-		resp = c.HandleLoggedIn(application, user, &authForm)
+		authContext = c.getMfaAuthContext().WithSecondFactor(mfaAmr)
+		resp = c.HandleLoggedIn(application, user, &authForm, authContext)
 		c.setMfaUserSession("")
+		c.setMfaAuthContext(nil)
 
 		c.Ctx.Input.SetParam("recordUserId", user.GetId())
 	} else {
@@ -1306,7 +1390,15 @@ func (c *ApiController) Login() {
 				return
 			}
 
-			resp = c.HandleLoggedIn(application, user, &authForm)
+			// step-up authentication: the user signs in again when the session doesn't meet the
+			// acr_values or max_age of the authorization request
+			authContext = c.getSessionAuthContext(user.GetId())
+			if !authContext.IsSatisfied(c.Ctx.Input.Query("acr_values"), c.Ctx.Input.Query("max_age")) {
+				c.ResponseOk(object.RequiredReauthentication)
+				return
+			}
+
+			resp = c.HandleLoggedIn(application, user, &authForm, authContext)
 
 			c.Ctx.Input.SetParam("recordUserId", user.GetId())
 		} else {
@@ -1342,6 +1434,49 @@ func (c *ApiController) GetSamlLogin() {
 	c.ResponseOk(authURL, method)
 }
 
+// HandleOidcLogin
+// @Title HandleOidcLogin
+// @Tag Login API
+// @Description redirect to the authorization endpoint of an OIDC provider, which is looked up from its discovery document
+// @Param   id                query    string  true   "The id ( owner/name ) of the provider"
+// @Param   redirectUri       query    string  true   "The redirect URI of the login"
+// @Param   state             query    string  true   "The state of the login"
+// @Param   codeChallenge     query    string  false  "The PKCE code challenge"
+// @Param   nonce             query    string  true   "The nonce that the id_token must carry"
+// @router /oidc-login [get]
+func (c *ApiController) HandleOidcLogin() {
+	providerId := c.Ctx.Input.Query("id")
+	provider, err := object.GetProvider(providerId)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if provider == nil {
+		c.ResponseError(fmt.Sprintf(c.T("auth:The provider: %s does not exist"), providerId))
+		return
+	}
+	if provider.Type != "OIDC" {
+		c.ResponseError(fmt.Sprintf(c.T("storage:The provider type: %s is not supported"), provider.Type))
+		return
+	}
+
+	idpInfo, err := object.FromProviderToIdpInfo(c.Ctx, provider)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	idProvider := idp.NewOidcIdProvider(idpInfo, c.Ctx.Input.Query("redirectUri"))
+	setHttpClient(idProvider, provider)
+
+	authUrl, err := idProvider.GetAuthUrl(provider.Scopes, c.Ctx.Input.Query("state"), c.Ctx.Input.Query("codeChallenge"), c.Ctx.Input.Query("nonce"))
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	c.Redirect(authUrl, http.StatusFound)
+}
+
 func (c *ApiController) HandleSamlLogin() {
 	relayState := c.Ctx.Input.Query("RelayState")
 	samlResponse := c.Ctx.Input.Query("SAMLResponse")
@@ -1749,7 +1884,7 @@ func (c *ApiController) DeviceAuthComplete() {
 	authForm := form.AuthForm{
 		Type: responseType,
 	}
-	resp := c.HandleLoggedIn(application, user, &authForm)
+	resp := c.HandleLoggedIn(application, user, &authForm, nil)
 
 	c.Ctx.Input.SetParam("recordUserId", user.GetId())
 	c.Data["json"] = resp
@@ -42,6 +42,12 @@ type SessionData struct {
 	ExpireTime int64
 }
 
+// SessionAuthContext is the authentication context of the user signed in to the session
+type SessionAuthContext struct {
+	UserId string
+	*object.AuthContext
+}
+
 func (c *ApiController) IsGlobalAdmin() bool {
 	isGlobalAdmin, _ := c.isGlobalAdmin()
 
@@ -194,6 +200,8 @@ func (c *ApiController) GetSessionApplication() *object.Application {
 func (c *ApiController) ClearUserSession() {
 	c.SetSessionUsername("")
 	c.SetSessionData(nil)
+	c.DelSession(object.AuthContextSessionKey)
+	c.DelSession(object.SessionDeviceSessionKey)
 	_ = c.SessionRegenerateID()
 }
 
@@ -270,6 +278,61 @@ func (c *ApiController) getMfaUserSession() string {
 	return userId.(string)
 }
 
+// setSessionAuthContext remembers how the user has authenticated, it is only used as long as
+// the same user is signed in to the session.
+func (c *ApiController) setSessionAuthContext(userId string, authContext *object.AuthContext) {
+	if authContext == nil {
+		c.DelSession(object.AuthContextSessionKey)
+		return
+	}
+
+	c.SetSession(object.AuthContextSessionKey, util.StructToJson(&SessionAuthContext{UserId: userId, AuthContext: authContext}))
+}
+
+func (c *ApiController) getSessionAuthContext(userId string) *object.AuthContext {
+	session := c.GetSession(object.AuthContextSessionKey)
+	if session == nil {
+		return nil
+	}
+
+	sessionAuthContext := &SessionAuthContext{}
+	err := util.JsonToStruct(session.(string), sessionAuthContext)
+	if err != nil {
+		logs.Error("getSessionAuthContext failed, error: %s", err)
+		return nil
+	}
+
+	if sessionAuthContext.UserId != userId {
+		return nil
+	}
+	return sessionAuthContext.AuthContext
+}
+
+func (c *ApiController) setMfaAuthContext(authContext *object.AuthContext) {
+	if authContext == nil {
+		c.DelSession(object.MfaSessionAuthContext)
+		return
+	}
+
+	c.SetSession(object.MfaSessionAuthContext, util.StructToJson(authContext))
+}
+
+// getMfaAuthContext returns the context of the first factor of a login waiting for its MFA
+func (c *ApiController) getMfaAuthContext() *object.AuthContext {
+	session := c.GetSession(object.MfaSessionAuthContext)
+	if session == nil {
+		return nil
+	}
+
+	authContext := &object.AuthContext{}
+	err := util.JsonToStruct(session.(string), authContext)
+	if err != nil {
+		logs.Error("getMfaAuthContext failed, error: %s", err)
+		return nil
+	}
+	return authContext
+}
+
 func (c *ApiController) setExpireForSession(cookieExpireInHours int64) {
 	timestamp := time.Now().Unix()
 	if cookieExpireInHours == 0 {
@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/beego/beego/v2/core/utils/pagination"
 	"github.com/casdoor/casdoor/object"
@@ -256,3 +257,26 @@ func (c *ApiController) UpdateCertDomainExpire() {
 	c.Data["json"] = wrapActionResponse(object.UpdateCert(id, cert))
 	c.ServeJSON()
 }
+
+// RotateCert
+// @Title RotateCert
+// @Tag Cert API
+// @Description rotate the keys of a cert now, the previous key stays in the JWKS until the tokens it signed expire
+// @Param   id     query   string  true        "The id ( owner/name ) of the cert"
+// @Success 200 {object} controllers.Response The Response object
+// @router /rotate-cert [post]
+func (c *ApiController) RotateCert() {
+	id := c.Ctx.Input.Query("id")
+	cert, err := object.GetCert(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if cert == nil {
+		c.ResponseError(fmt.Sprintf(c.T("general:The cert: %s does not exist"), id))
+		return
+	}
+
+	c.Data["json"] = wrapActionResponse(object.RotateCert(cert))
+	c.ServeJSON()
+}
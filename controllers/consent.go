@@ -15,6 +15,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/casdoor/casdoor/object"
@@ -124,6 +125,9 @@ func (c *ApiController) GrantConsent() {
 		Nonce        string   `json:"nonce"`
 		Challenge    string   `json:"challenge"`
 		Resource     string   `json:"resource"`
+		AcrValues    string   `json:"acrValues"`
+		MaxAge       string   `json:"maxAge"`
+		RequestUri   string   `json:"requestUri"`
 	}
 
 	err := json.Unmarshal(c.Ctx.Input.RequestBody, &request)
@@ -214,8 +218,13 @@ func (c *ApiController) GrantConsent() {
 		request.Nonce,
 		request.Challenge,
 		request.Resource,
+		request.AcrValues,
+		request.MaxAge,
+		request.RequestUri,
 		c.Ctx.Request.Host,
 		c.GetAcceptLanguage(),
+		c.getSessionAuthContext(userId),
+		c.Ctx.Input.CruSession.SessionID(context.Background()),
 	)
 	if err != nil {
 		c.ResponseError(err.Error())
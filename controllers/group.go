@@ -144,6 +144,12 @@ func (c *ApiController) UpdateGroup() {
 		return
 	}
 
+	err = object.CheckRadiusReplyAttributes(group.RadiusReplyAttributes)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	c.Data["json"] = wrapActionResponse(object.UpdateGroup(id, &group, c.IsGlobalAdmin(), c.GetAcceptLanguage()))
 	c.ServeJSON()
 }
@@ -167,6 +173,12 @@ func (c *ApiController) AddGroup() {
 		return
 	}
 
+	err = object.CheckRadiusReplyAttributes(group.RadiusReplyAttributes)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	c.Data["json"] = wrapActionResponse(object.AddGroup(&group))
 	c.ServeJSON()
 }
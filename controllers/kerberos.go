@@ -97,7 +97,7 @@ func (c *ApiController) KerberosLogin() {
 		Organization: organization.Name,
 	}
 
-	resp := c.HandleLoggedIn(application, user, authForm)
+	resp := c.HandleLoggedIn(application, user, authForm, object.NewAuthContext(object.AmrKerberos))
 	if resp != nil {
 		c.Data["json"] = resp
 		c.ServeJSON()
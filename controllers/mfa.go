@@ -264,7 +264,7 @@ func (c *ApiController) MfaSetupEnable() {
 		return
 	}
 
-	err = mfaUtil.Enable(user)
+	err = object.EnableMultiFactorAuth(mfaUtil, user)
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -0,0 +1,130 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+)
+
+// HandleProviderLogout
+// @Title HandleProviderLogout
+// @Tag Login API
+// @Description the logout endpoint for an upstream identity provider, it ends the Casdoor sessions signed in through the upstream session and propagates the logout to the applications. It receives OIDC back-channel logout tokens, SAML LogoutRequests and OIDC front-channel logouts. A SAML LogoutRequest is answered by a signed LogoutResponse sent to the Single Logout Service of the IdP.
+// @Param   owner          path     string  true   "The owner of the provider"
+// @Param   provider       path     string  true   "The name of the provider"
+// @Param   logout_token   formData string  false  "The OIDC back-channel logout token"
+// @Param   SAMLRequest    query    string  false  "The SAML LogoutRequest"
+// @Param   RelayState     query    string  false  "The relay state of the SAML LogoutRequest, returned with the LogoutResponse"
+// @Param   sid            query    string  false  "The upstream session of an OIDC front-channel logout"
+// @router /provider-logout/:owner/:provider [get,post]
+func (c *ApiController) HandleProviderLogout() {
+	providerId := util.GetId(c.Ctx.Input.Param(":owner"), c.Ctx.Input.Param(":provider"))
+	provider, err := object.GetProvider(providerId)
+	if err != nil {
+		c.responseProviderLogoutError(err.Error())
+		return
+	}
+	if provider == nil {
+		c.responseProviderLogoutError(fmt.Sprintf(c.T("auth:The provider: %s does not exist"), providerId))
+		return
+	}
+
+	var idpSessions []*object.IdpSession
+	var samlLogoutRequest *object.SamlLogoutRequest
+	if logoutToken := c.Ctx.Input.Query("logout_token"); logoutToken != "" {
+		// the logout token is verified against the JWKS of the provider, so any session can be ended
+		idpSessions, err = object.ParseProviderLogoutToken(provider, logoutToken)
+	} else if samlRequest := c.Ctx.Input.Query("SAMLRequest"); samlRequest != "" {
+		querySignature := &object.SamlQuerySignature{
+			RelayState: c.Ctx.Input.Query("RelayState"),
+			SigAlg:     c.Ctx.Input.Query("SigAlg"),
+			Signature:  c.Ctx.Input.Query("Signature"),
+			RawQuery:   c.Ctx.Request.URL.RawQuery,
+		}
+		samlLogoutRequest, idpSessions, err = object.ParseProviderSamlLogoutRequest(provider, samlRequest, querySignature)
+	} else {
+		// a front-channel logout isn't signed, so only the session of the browser is ended,
+		// and only when it was signed in through this provider
+		var idpSession *object.IdpSession
+		idpSession, err = object.GetIdpSessionBySessionId(provider, c.Ctx.Input.CruSession.SessionID(context.Background()))
+		sid := c.Ctx.Input.Query("sid")
+		if idpSession != nil && (sid == "" || sid == idpSession.Sid) {
+			idpSessions = append(idpSessions, idpSession)
+			c.ClearUserSession()
+			c.ClearTokenSession()
+		}
+	}
+	if err != nil {
+		c.responseProviderLogoutError(err.Error())
+		return
+	}
+
+	// the SAML service providers without a SOAP endpoint are logged out through the browser of the IdP logout,
+	// so they are collected before their sessions are gone
+	var samlSessions []*object.SamlSession
+	if samlLogoutRequest != nil {
+		for _, idpSession := range idpSessions {
+			sessions, err := object.GetSamlFrontChannelSessions(idpSession.Organization, idpSession.User, idpSession.SessionId)
+			if err != nil {
+				c.responseProviderLogoutError(err.Error())
+				return
+			}
+			samlSessions = append(samlSessions, sessions...)
+		}
+	}
+
+	err = object.LogoutIdpSessions(idpSessions, c.Ctx.Request.Host)
+	if err != nil {
+		c.responseProviderLogoutError(err.Error())
+		return
+	}
+
+	for _, idpSession := range idpSessions {
+		util.LogInfo(c.Ctx, "API: [%s] logged out by the upstream logout of [%s]", util.GetId(idpSession.Organization, idpSession.User), providerId)
+	}
+
+	c.Ctx.Output.Header("Cache-Control", "no-store")
+	if samlLogoutRequest == nil {
+		c.ResponseOk(len(idpSessions))
+		return
+	}
+
+	// the IdP gets its LogoutResponse back by the binding it sent the LogoutRequest by when it supports it
+	requestBinding := object.SamlBindingRedirect
+	if c.Ctx.Request.Method == http.MethodPost {
+		requestBinding = object.SamlBindingPost
+	}
+	redirectUrl, form, err := object.GetProviderSamlLogoutResponse(provider, samlLogoutRequest, requestBinding, c.Ctx.Input.Query("RelayState"), c.Ctx.Request.Host)
+	if err != nil {
+		c.responseProviderLogoutError(err.Error())
+		return
+	}
+
+	redirectUrl, form = object.StartSamlLogoutChain(samlSessions, redirectUrl, form, c.Ctx.Request.Host)
+	c.responseSamlMessage(redirectUrl, form)
+}
+
+// responseProviderLogoutError answers a failed upstream logout with 400 Bad Request, as the OIDC
+// back-channel logout requires
+func (c *ApiController) responseProviderLogoutError(error string) {
+	c.Ctx.Output.Header("Cache-Control", "no-store")
+	c.Ctx.Output.SetStatus(http.StatusBadRequest)
+	c.ResponseError(error)
+}
@@ -102,6 +102,12 @@ func (c *ApiController) UpdateRole() {
 		return
 	}
 
+	err = object.CheckRadiusReplyAttributes(role.RadiusReplyAttributes)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	c.Data["json"] = wrapActionResponse(object.UpdateRole(id, &role, c.IsGlobalAdmin(), c.GetAcceptLanguage()))
 	c.ServeJSON()
 }
@@ -125,6 +131,12 @@ func (c *ApiController) AddRole() {
 		return
 	}
 
+	err = object.CheckRadiusReplyAttributes(role.RadiusReplyAttributes)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
 	c.Data["json"] = wrapActionResponse(object.AddRole(&role))
 	c.ServeJSON()
 }
@@ -15,11 +15,14 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 
 	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
 )
 
 func (c *ApiController) GetSamlMeta() {
@@ -68,3 +71,168 @@ func (c *ApiController) HandleSamlRedirect() {
 
 	c.Redirect(targetURL, http.StatusSeeOther)
 }
+
+// HandleSamlLogout
+// @Title HandleSamlLogout
+// @Tag Login API
+// @Description handle the SAML Single Logout of a service provider over the HTTP-Redirect or HTTP-POST binding
+// @Param   owner          path     string  true   "The owner of the application"
+// @Param   application    path     string  true   "The name of the application"
+// @Param   SAMLRequest    query    string  false  "The LogoutRequest of the service provider"
+// @Param   SAMLResponse   query    string  false  "The LogoutResponse of the service provider"
+// @Param   RelayState     query    string  false  "The relay state"
+// @router /saml/slo/:owner/:application [get,post]
+func (c *ApiController) HandleSamlLogout() {
+	id := util.GetId(c.Ctx.Input.Param(":owner"), c.Ctx.Input.Param(":application"))
+	application, err := object.GetApplication(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if application == nil {
+		c.ResponseError(fmt.Sprintf(c.T("saml:Application %s not found"), id))
+		return
+	}
+
+	relayState := c.GetString("RelayState")
+	samlRequest := c.GetString("SAMLRequest")
+	if samlRequest == "" {
+		// A LogoutResponse to a LogoutRequest of Casdoor, the session has been logged out already
+		logoutResponse, err := object.ParseSamlLogoutResponse(c.GetString("SAMLResponse"))
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		// The browser goes on with the next service provider of the logout it is going through
+		redirectUrl, form, ok := object.ContinueSamlLogoutChain(application, logoutResponse, relayState)
+		if ok {
+			c.responseSamlMessage(redirectUrl, form)
+			return
+		}
+
+		if relayState != "" && application.IsRedirectUriValid(relayState) {
+			c.Redirect(relayState, http.StatusFound)
+			return
+		}
+		c.ResponseOk()
+		return
+	}
+
+	querySignature := &object.SamlQuerySignature{RelayState: relayState, SigAlg: c.GetString("SigAlg"), Signature: c.GetString("Signature"), RawQuery: c.Ctx.Request.URL.RawQuery}
+	logoutRequest, err := object.ParseSamlLogoutRequest(application, samlRequest, querySignature)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	samlSessions, err := object.GetSamlSessionsByLogoutRequest(application, logoutRequest)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	// The Beego session is looked up from the SAML session rather than the cookie, which a cross-site
+	// POST of the service provider doesn't carry. The current session is cleared as well when it's the one.
+	// The other service providers of the session which can only be logged out through the browser are
+	// visited before the LogoutResponse goes back to the service provider that asked for the logout.
+	currentSessionId := c.Ctx.Input.CruSession.SessionID(context.Background())
+	frontChannelSessions := []*object.SamlSession{}
+	seen := map[string]bool{}
+	for _, samlSession := range samlSessions {
+		sessions, err := object.GetSamlFrontChannelSessions(samlSession.Organization, samlSession.User, samlSession.SessionId)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+		for _, session := range sessions {
+			if session.Application != application.Name && !seen[session.Name] {
+				seen[session.Name] = true
+				frontChannelSessions = append(frontChannelSessions, session)
+			}
+		}
+
+		if samlSession.SessionId == currentSessionId {
+			c.ClearUserSession()
+			c.ClearTokenSession()
+		}
+
+		err = object.LogoutSamlSession(samlSession, c.Ctx.Request.Host)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		util.LogInfo(c.Ctx, "API: [%s] logged out by the SAML single logout of [%s]", util.GetId(samlSession.Organization, samlSession.User), logoutRequest.Issuer)
+	}
+
+	redirectUrl, form, err := object.GetSamlLogoutResponse(application, logoutRequest, relayState, c.Ctx.Request.Host)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	redirectUrl, form = object.StartSamlLogoutChain(frontChannelSessions, redirectUrl, form, c.Ctx.Request.Host)
+	c.responseSamlMessage(redirectUrl, form)
+}
+
+// responseSamlMessage sends the browser to the URL of a SAML message over the HTTP-Redirect binding, or makes it
+// post the form of the message over the HTTP-POST binding when the form isn't nil
+func (c *ApiController) responseSamlMessage(redirectUrl string, form url.Values) {
+	if form == nil {
+		c.Redirect(redirectUrl, http.StatusFound)
+		return
+	}
+
+	html, err := object.GetSamlPostFormHtml(redirectUrl, form)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", "text/html; charset=utf-8")
+	err = c.Ctx.Output.Body([]byte(html))
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+}
+
+type samlSpMetadataForm struct {
+	Metadata    string `json:"metadata"`
+	MetadataUrl string `json:"metadataUrl"`
+}
+
+// ParseSamlSpMetadata
+// @Title ParseSamlSpMetadata
+// @Tag Application API
+// @Description parse the metadata of a SAML service provider, given either as XML or as a metadata URL
+// @Param   body    body   controllers.samlSpMetadataForm  true   "The metadata XML or the metadata URL"
+// @Success 200 {object} object.SamlSpMetadata The Response object
+// @router /parse-saml-sp-metadata [post]
+func (c *ApiController) ParseSamlSpMetadata() {
+	var form samlSpMetadataForm
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &form)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	metadata := form.Metadata
+	if form.MetadataUrl != "" {
+		metadata, err = object.FetchSamlSpMetadata(form.MetadataUrl)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	}
+
+	spMetadata, err := object.ParseSamlSpMetadata(metadata)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(spMetadata)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+)
+
+func (c *ApiController) getSessionDevices(owner string, user string) ([]*object.SessionDevice, error) {
+	sessionDevices, err := object.GetSessionDevices(owner, user)
+	if err != nil {
+		return nil, err
+	}
+
+	curSessionId := c.Ctx.Input.CruSession.SessionID(context.Background())
+	for _, sessionDevice := range sessionDevices {
+		sessionDevice.IsCurrent = sessionDevice.SessionId == curSessionId
+	}
+	return sessionDevices, nil
+}
+
+func (c *ApiController) revokeSessionDevice(owner string, user string, name string) {
+	sessionDevice, err := object.GetSessionDevice(owner, name)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+	if sessionDevice == nil || sessionDevice.User != user {
+		c.Data["json"] = wrapActionResponse(false)
+		c.ServeJSON()
+		return
+	}
+
+	if sessionDevice.SessionId == c.Ctx.Input.CruSession.SessionID(context.Background()) {
+		c.ResponseError(fmt.Sprintf(c.T("session:session id %s is the current session and cannot be deleted"), name))
+		return
+	}
+
+	affected, err := object.RevokeSessionDevice(sessionDevice, c.Ctx.Request.Host)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if affected {
+		util.LogInfo(c.Ctx, "API: [%s] signed out of device [%s] by [%s]", util.GetId(owner, user), name, c.GetSessionUsername())
+	}
+
+	c.Data["json"] = wrapActionResponse(affected)
+	c.ServeJSON()
+}
+
+// GetAccountSessionDevices
+// @Title GetAccountSessionDevices
+// @Tag Session API
+// @Description get the devices the signed-in user is signed in on
+// @Success 200 {array} object.SessionDevice The Response object
+// @router /get-account-session-devices [get]
+func (c *ApiController) GetAccountSessionDevices() {
+	user, ok := c.RequireSignedInUser()
+	if !ok {
+		return
+	}
+
+	sessionDevices, err := c.getSessionDevices(user.Owner, user.Name)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(sessionDevices)
+}
+
+// RevokeAccountSessionDevice
+// @Title RevokeAccountSessionDevice
+// @Tag Session API
+// @Description sign the signed-in user out of one of their devices, the tokens issued in that session are expired too
+// @Param   device        query    string  true        "The name of the device"
+// @Success 200 {object} controllers.Response The Response object
+// @router /revoke-account-session-device [post]
+func (c *ApiController) RevokeAccountSessionDevice() {
+	user, ok := c.RequireSignedInUser()
+	if !ok {
+		return
+	}
+
+	c.revokeSessionDevice(user.Owner, user.Name, c.Ctx.Input.Query("device"))
+}
+
+// GetSessionDevices
+// @Title GetSessionDevices
+// @Tag Session API
+// @Description get the devices a user is signed in on
+// @Param   owner     query    string  true        "The organization of the user"
+// @Param   user      query    string  true        "The name of the user"
+// @Success 200 {array} object.SessionDevice The Response object
+// @router /get-session-devices [get]
+func (c *ApiController) GetSessionDevices() {
+	owner := c.Ctx.Input.Query("owner")
+	user := c.Ctx.Input.Query("user")
+
+	sessionDevices, err := c.getSessionDevices(owner, user)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(sessionDevices)
+}
+
+// RevokeSessionDevice
+// @Title RevokeSessionDevice
+// @Tag Session API
+// @Description sign a user out of one of their devices, the tokens issued in that session are expired too
+// @Param   id            query    string  true        "The id ( owner/name ) of the user"
+// @Param   device        query    string  true        "The name of the device"
+// @Success 200 {object} controllers.Response The Response object
+// @router /revoke-session-device [post]
+func (c *ApiController) RevokeSessionDevice() {
+	owner, user, err := util.GetOwnerAndNameFromIdWithError(c.Ctx.Input.Query("id"))
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.revokeSessionDevice(owner, user, c.Ctx.Input.Query("device"))
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+)
+
+// GetSsfConfiguration
+// @Title GetSsfConfiguration
+// @Tag Shared Signals API
+// @Description Get the Shared Signals transmitter configuration metadata
+// @Success 200 {object} object.SsfTransmitterMetadata
+// @router /.well-known/ssf-configuration [get]
+func (c *RootController) GetSsfConfiguration() {
+	c.Ctx.Output.Header("Access-Control-Allow-Origin", "*")
+	c.Data["json"] = object.GetSsfTransmitterMetadata(c.Ctx.Request.Host)
+	c.ServeJSON()
+}
+
+// responseSsf answers a Shared Signals request, the SSF errors are told apart by the HTTP status
+func (c *ApiController) responseSsf(status int, data interface{}, ssfErr *object.SsfError, err error) {
+	c.Ctx.Output.Header("Cache-Control", "no-store")
+	if err != nil {
+		status = http.StatusInternalServerError
+		data = object.SsfError{Err: "server_error", Description: err.Error()}
+	} else if ssfErr != nil {
+		switch ssfErr.Err {
+		case "not_found":
+			status = http.StatusNotFound
+		case "authentication_failed":
+			status = http.StatusUnauthorized
+		default:
+			status = http.StatusBadRequest
+		}
+		data = ssfErr
+	}
+
+	if data == nil {
+		c.Ctx.Output.SetStatus(status)
+		_ = c.Ctx.Output.Body([]byte{})
+		return
+	}
+
+	c.Ctx.Output.Status = status
+	c.Data["json"] = data
+	c.ServeJSON()
+}
+
+// getSsfReceiverApplication returns the receiver application, the receivers call the stream
+// management endpoints with an access token of the client credentials grant
+func (c *ApiController) getSsfReceiverApplication() *object.Application {
+	userId := c.GetSessionUsername()
+	if !object.IsAppUser(userId) {
+		c.responseSsf(0, nil, &object.SsfError{Err: "authentication_failed", Description: "a client credentials access token of the receiver application is required"}, nil)
+		return nil
+	}
+
+	application, err := object.GetApplicationByUserId(userId)
+	if err != nil {
+		c.responseSsf(0, nil, nil, err)
+		return nil
+	}
+	if application == nil {
+		c.responseSsf(0, nil, &object.SsfError{Err: "authentication_failed", Description: fmt.Sprintf("the application: %s does not exist", userId)}, nil)
+		return nil
+	}
+
+	return application
+}
+
+// GetSsfStream
+// @Title GetSsfStream
+// @Tag Shared Signals API
+// @Description Get the configuration of a stream, or of all the streams of the receiver when stream_id is empty
+// @Param   stream_id     query    string  false        "The id of the stream"
+// @Success 200 {object} object.SsfStreamConfiguration
+// @router /api/ssf/stream [get]
+func (c *ApiController) GetSsfStream() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	streamId := c.Ctx.Input.Query("stream_id")
+	if streamId == "" {
+		configs, err := object.GetSsfStreamConfigurations(application)
+		c.responseSsf(http.StatusOK, configs, nil, err)
+		return
+	}
+
+	config, ssfErr, err := object.GetSsfStreamConfiguration(application, streamId)
+	c.responseSsf(http.StatusOK, config, ssfErr, err)
+}
+
+// AddSsfStream
+// @Title AddSsfStream
+// @Tag Shared Signals API
+// @Description Create a stream for the receiver, only the push delivery (RFC 8935) to a host of the redirect URIs of the receiver application is supported
+// @Param   body    body   object.SsfStreamConfiguration  true        "The configuration of the stream"
+// @Success 201 {object} object.SsfStreamConfiguration
+// @router /api/ssf/stream [post]
+func (c *ApiController) AddSsfStream() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	var config object.SsfStreamConfiguration
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &config)
+	if err != nil {
+		c.responseSsf(0, nil, &object.SsfError{Err: "invalid_request", Description: err.Error()}, nil)
+		return
+	}
+
+	res, ssfErr, err := object.AddSsfStream(application, &config, c.Ctx.Request.Host)
+	c.responseSsf(http.StatusCreated, res, ssfErr, err)
+}
+
+// UpdateSsfStream
+// @Title UpdateSsfStream
+// @Tag Shared Signals API
+// @Description Update (PATCH) or replace (PUT) the configuration of a stream
+// @Param   body    body   object.SsfStreamConfiguration  true        "The configuration of the stream"
+// @Success 200 {object} object.SsfStreamConfiguration
+// @router /api/ssf/stream [patch,put]
+func (c *ApiController) UpdateSsfStream() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	var config object.SsfStreamConfiguration
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &config)
+	if err != nil {
+		c.responseSsf(0, nil, &object.SsfError{Err: "invalid_request", Description: err.Error()}, nil)
+		return
+	}
+
+	res, ssfErr, err := object.UpdateSsfStream(application, &config, c.Ctx.Request.Method == http.MethodPut)
+	c.responseSsf(http.StatusOK, res, ssfErr, err)
+}
+
+// DeleteSsfStream
+// @Title DeleteSsfStream
+// @Tag Shared Signals API
+// @Description Delete a stream, its pending events are not delivered anymore
+// @Param   stream_id     query    string  true        "The id of the stream"
+// @Success 204
+// @router /api/ssf/stream [delete]
+func (c *ApiController) DeleteSsfStream() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	ssfErr, err := object.DeleteSsfStream(application, c.Ctx.Input.Query("stream_id"))
+	c.responseSsf(http.StatusNoContent, nil, ssfErr, err)
+}
+
+// GetSsfStreamStatus
+// @Title GetSsfStreamStatus
+// @Tag Shared Signals API
+// @Description Get the status of a stream
+// @Param   stream_id     query    string  true        "The id of the stream"
+// @Success 200 {object} object.SsfStreamStatus
+// @router /api/ssf/status [get]
+func (c *ApiController) GetSsfStreamStatus() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	status, ssfErr, err := object.GetSsfStreamStatus(application, c.Ctx.Input.Query("stream_id"))
+	c.responseSsf(http.StatusOK, status, ssfErr, err)
+}
+
+// UpdateSsfStreamStatus
+// @Title UpdateSsfStreamStatus
+// @Tag Shared Signals API
+// @Description Enable, pause or disable a stream
+// @Param   body    body   object.SsfStreamStatus  true        "The status of the stream"
+// @Success 200 {object} object.SsfStreamStatus
+// @router /api/ssf/status [post]
+func (c *ApiController) UpdateSsfStreamStatus() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	var status object.SsfStreamStatus
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &status)
+	if err != nil {
+		c.responseSsf(0, nil, &object.SsfError{Err: "invalid_request", Description: err.Error()}, nil)
+		return
+	}
+
+	res, ssfErr, err := object.UpdateSsfStreamStatus(application, &status)
+	c.responseSsf(http.StatusOK, res, ssfErr, err)
+}
+
+// VerifySsfStream
+// @Title VerifySsfStream
+// @Tag Shared Signals API
+// @Description Request a verification event on a stream
+// @Param   body    body   object.SsfVerificationRequest  true        "The stream and the state to send back"
+// @Success 204
+// @router /api/ssf/verify [post]
+func (c *ApiController) VerifySsfStream() {
+	application := c.getSsfReceiverApplication()
+	if application == nil {
+		return
+	}
+
+	var request object.SsfVerificationRequest
+	err := json.Unmarshal(c.Ctx.Input.RequestBody, &request)
+	if err != nil {
+		c.responseSsf(0, nil, &object.SsfError{Err: "invalid_request", Description: err.Error()}, nil)
+		return
+	}
+
+	ssfErr, err := object.VerifySsfStream(application, &request)
+	c.responseSsf(http.StatusNoContent, nil, ssfErr, err)
+}
+
+// HandleSsfReceiver
+// @Title HandleSsfReceiver
+// @Tag Shared Signals API
+// @Description the push endpoint (RFC 8935) for the security events of an upstream identity provider, a revoked session ends the Casdoor sessions signed in through it, a changed credential or a disabled account forces the linked users offline
+// @Param   owner          path     string  true   "The owner of the provider"
+// @Param   provider       path     string  true   "The name of the provider"
+// @Success 202
+// @router /api/ssf-receiver/:owner/:provider [post]
+func (c *ApiController) HandleSsfReceiver() {
+	providerId := util.GetId(c.Ctx.Input.Param(":owner"), c.Ctx.Input.Param(":provider"))
+	provider, err := object.GetProvider(providerId)
+	if err != nil {
+		c.responseSsf(0, nil, nil, err)
+		return
+	}
+	if provider == nil {
+		c.responseSsf(0, nil, &object.SsfError{Err: "invalid_issuer", Description: fmt.Sprintf(c.T("auth:The provider: %s does not exist"), providerId)}, nil)
+		return
+	}
+
+	securityEventToken := strings.TrimSpace(string(c.Ctx.Input.RequestBody))
+	if securityEventToken == "" {
+		// the body isn't copied when it isn't JSON or a form, depending on the copybody setting
+		body, err := io.ReadAll(io.LimitReader(c.Ctx.Request.Body, 1<<20))
+		if err != nil {
+			c.responseSsf(0, nil, nil, err)
+			return
+		}
+		securityEventToken = strings.TrimSpace(string(body))
+	}
+
+	count, ssfErr, err := object.HandleSsfSecurityEvent(provider, securityEventToken, c.Ctx.Request.Host)
+	if ssfErr == nil && err == nil {
+		util.LogInfo(c.Ctx, "API: [%d] users acted on by a security event of [%s]", count, providerId)
+	}
+
+	c.responseSsf(http.StatusAccepted, nil, ssfErr, err)
+}
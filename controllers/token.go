@@ -192,6 +192,7 @@ func (c *ApiController) GetOAuthToken() {
 	subjectTokenType := c.Ctx.Input.Query("subject_token_type")
 	audience := c.Ctx.Input.Query("audience")
 	resource := c.Ctx.Input.Query("resource")
+	authReqId := c.Ctx.Input.Query("auth_req_id")
 
 	if clientId == "" && clientSecret == "" {
 		clientId, clientSecret, _ = c.Ctx.Request.BasicAuth()
@@ -259,6 +260,9 @@ func (c *ApiController) GetOAuthToken() {
 			if assertion == "" {
 				assertion = tokenRequest.Assertion
 			}
+			if authReqId == "" {
+				authReqId = tokenRequest.AuthReqId
+			}
 		}
 	}
 
@@ -347,7 +351,7 @@ func (c *ApiController) GetOAuthToken() {
 		return
 	}
 
-	token, err := object.GetOAuthToken(grantType, clientId, clientSecret, code, verifier, scope, nonce, username, password, host, refreshToken, tag, avatar, c.GetAcceptLanguage(), subjectToken, subjectTokenType, assertion, clientAssertion, clientAssertionType, audience, resource, dpopProof)
+	token, err := object.GetOAuthToken(grantType, clientId, clientSecret, code, verifier, scope, nonce, username, password, host, refreshToken, tag, avatar, c.GetAcceptLanguage(), subjectToken, subjectTokenType, assertion, clientAssertion, clientAssertionType, audience, resource, dpopProof, authReqId, object.GetRequestClientCert(c.Ctx.Request))
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -408,7 +412,7 @@ func (c *ApiController) RefreshToken() {
 	}
 
 	dpopProof := c.Ctx.Request.Header.Get("DPoP")
-	refreshToken2, err := object.RefreshToken(application, grantType, refreshToken, scope, clientId, clientSecret, resource, host, dpopProof)
+	refreshToken2, err := object.RefreshToken(application, grantType, refreshToken, scope, clientId, clientSecret, resource, host, dpopProof, object.GetRequestClientCert(c.Ctx.Request))
 	if err != nil {
 		c.ResponseError(err.Error())
 		return
@@ -483,6 +487,16 @@ func (c *ApiController) ValidateOAuth(ignoreValidSecret bool) (ok bool, applicat
 		return
 	}
 
+	// RFC 8705: the client authenticates with its TLS client certificate instead of a secret
+	if application != nil && application.TlsClientAuthMethod != "" && clientSecret == "" {
+		err = object.CheckTlsClientAuth(application, object.GetRequestClientCert(c.Ctx.Request))
+		if err != nil {
+			c.ResponseTokenError(object.InvalidClient, err.Error())
+			return
+		}
+		clientSecret = application.ClientSecret
+	}
+
 	if application == nil || (application.ClientSecret != clientSecret && !ignoreValidSecret) {
 		c.ResponseTokenError(object.InvalidClient, c.T("token:Invalid application or wrong clientSecret"))
 		return
@@ -545,9 +559,6 @@ func (c *ApiController) IntrospectToken() {
 	if application.TokenFormat == "JWT-Standard" {
 		jwtToken, err := object.ParseStandardJwtTokenByApplication(tokenValue, application)
 		if err != nil {
-			// and token revoked case. but we not implement
-			// TODO: 2022-03-03 add token revoked check, when we implemented the Token Revocation(rfc7009) Specs.
-			// refs: https://tools.ietf.org/html/rfc7009
 			respondWithInactiveToken()
 			return
 		}
@@ -568,9 +579,6 @@ func (c *ApiController) IntrospectToken() {
 	} else {
 		jwtToken, err := object.ParseJwtTokenByApplication(tokenValue, application)
 		if err != nil {
-			// and token revoked case. but we not implement
-			// TODO: 2022-03-03 add token revoked check, when we implemented the Token Revocation(rfc7009) Specs.
-			// refs: https://tools.ietf.org/html/rfc7009
 			respondWithInactiveToken()
 			return
 		}
@@ -638,8 +646,199 @@ func (c *ApiController) IntrospectToken() {
 		if token.DPoPJkt != "" {
 			introspectionResponse.Cnf = &object.DPoPConfirmation{JKT: token.DPoPJkt}
 		}
+
+		// Expose client certificate binding in the introspection response (RFC 8705 §3.2).
+		if token.CertThumbprint != "" {
+			if introspectionResponse.Cnf == nil {
+				introspectionResponse.Cnf = &object.DPoPConfirmation{}
+			}
+			introspectionResponse.Cnf.X5tS256 = token.CertThumbprint
+		}
 	}
 
 	c.Data["json"] = introspectionResponse
 	c.ServeJSON()
 }
+
+// RevokeToken
+// @Title RevokeToken
+// @Tag Login API
+// @Description The revocation endpoint defined in RFC 7009, it invalidates an access token or a refresh token
+// issued to the authenticated client. Revoking either token of a grant invalidates both of them.
+// This endpoint support Basic Authorization and authorization defined in RFC 7523.
+//
+// @Param token formData string true "access_token's value or refresh_token's value"
+// @Param token_type_hint formData string false "the token type access_token or refresh_token"
+// @Success 200 {string} string "Empty response, also returned for unknown or already revoked tokens"
+// @Success 400 {object} object.TokenError The Response object
+// @Success 401 {object} object.TokenError The Response object
+// @router /login/oauth/revoke [post]
+func (c *ApiController) RevokeToken() {
+	tokenValue := c.Ctx.Input.Query("token")
+	tokenTypeHint := c.Ctx.Input.Query("token_type_hint")
+
+	ok, application, _, _, err := c.ValidateOAuth(false)
+	if err != nil || !ok {
+		return
+	}
+
+	_, tokenError, err := object.RevokeToken(application, tokenValue, tokenTypeHint)
+	if err != nil {
+		c.ResponseTokenError(object.EndpointError, err.Error())
+		return
+	}
+	if tokenError != nil {
+		c.ResponseTokenError(tokenError.Error, tokenError.ErrorDescription)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(200)
+	c.Ctx.Output.Body([]byte{})
+}
+
+// PushAuthorizationRequest
+// @Title PushAuthorizationRequest
+// @Tag Login API
+// @Description The pushed authorization request endpoint defined in RFC 9126, the client pushes the parameters
+// of its authorization request and only sends client_id and the returned request_uri to the authorization endpoint.
+// This endpoint support Basic Authorization and authorization defined in RFC 7523.
+//
+// @Param response_type formData string true "The response type"
+// @Param redirect_uri formData string true "The redirect uri"
+// @Param scope formData string false "The scope"
+// @Param state formData string false "The state"
+// @Param request formData string false "A signed request object (RFC 9101) carrying the parameters"
+// @Success 201 {object} object.PushedAuthResponse The Response object
+// @Success 400 {object} object.TokenError The Response object
+// @Success 401 {object} object.TokenError The Response object
+// @router /login/oauth/par [post]
+func (c *ApiController) PushAuthorizationRequest() {
+	ok, application, _, _, err := c.ValidateOAuth(false)
+	if err != nil || !ok {
+		return
+	}
+
+	params := map[string]string{}
+	for _, name := range append(object.PushedAuthRequestParams, "request", "request_uri") {
+		params[name] = c.Ctx.Input.Query(name)
+	}
+
+	resp, tokenError, err := object.PushAuthorizationRequest(application, params, c.Ctx.Request.Host, c.GetAcceptLanguage())
+	if err != nil {
+		c.ResponseTokenError(object.EndpointError, err.Error())
+		return
+	}
+	if tokenError != nil {
+		c.ResponseTokenError(tokenError.Error, tokenError.ErrorDescription)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(201)
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+// BackchannelAuthenticate
+// @Title BackchannelAuthenticate
+// @Tag Login API
+// @Description The backchannel authentication endpoint of OpenID Connect CIBA, the client asks Casdoor to authenticate
+// a user out of band, the user approves or denies the request on a device of their own.
+// This endpoint support Basic Authorization and authorization defined in RFC 7523.
+//
+// @Param scope formData string true "The scope, must contain openid"
+// @Param login_hint formData string false "The username, email or phone of the user"
+// @Param id_token_hint formData string false "An ID token previously issued to the client for the user"
+// @Param binding_message formData string false "A message shown to the user on both devices"
+// @Param client_notification_token formData string false "The bearer token of the client notification endpoint, required in ping mode"
+// @Param requested_expiry formData int false "The requested lifetime of the auth_req_id in seconds"
+// @Success 200 {object} object.CibaAuthResponse The Response object
+// @Success 400 {object} object.TokenError The Response object
+// @Success 401 {object} object.TokenError The Response object
+// @router /login/oauth/bc-authorize [post]
+func (c *ApiController) BackchannelAuthenticate() {
+	ok, application, _, _, err := c.ValidateOAuth(false)
+	if err != nil || !ok {
+		return
+	}
+
+	request := &object.CibaAuthRequest{
+		Scope:                   c.Ctx.Input.Query("scope"),
+		LoginHint:               c.Ctx.Input.Query("login_hint"),
+		IdTokenHint:             c.Ctx.Input.Query("id_token_hint"),
+		BindingMessage:          c.Ctx.Input.Query("binding_message"),
+		ClientNotificationToken: c.Ctx.Input.Query("client_notification_token"),
+	}
+
+	requestedExpiry := c.Ctx.Input.Query("requested_expiry")
+	if requestedExpiry != "" {
+		request.RequestedExpiry, err = util.ParseIntWithError(requestedExpiry)
+		if err != nil {
+			c.ResponseTokenError(object.InvalidRequest, "requested_expiry should be an integer")
+			return
+		}
+	}
+
+	resp, tokenError, err := object.StartCibaAuthentication(application, request, c.Ctx.Request.Host)
+	if err != nil {
+		c.ResponseTokenError(object.EndpointError, err.Error())
+		return
+	}
+	if tokenError != nil {
+		c.ResponseTokenError(tokenError.Error, tokenError.ErrorDescription)
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+// GetCibaRequest
+// @Title GetCibaRequest
+// @Tag Login API
+// @Description get a pending CIBA request addressed to the signed-in user
+// @Param   authReqId query string true "The auth_req_id of the request"
+// @Success 200 {object} object.CibaRequestInfo The Response object
+// @router /get-ciba-request [get]
+func (c *ApiController) GetCibaRequest() {
+	userId, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	info, err := object.GetCibaRequestInfo(c.Ctx.Input.Query("authReqId"), userId)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk(info)
+}
+
+// CompleteCibaRequest
+// @Title CompleteCibaRequest
+// @Tag Login API
+// @Description approve or deny a pending CIBA request addressed to the signed-in user
+// @Param   authReqId query string true "The auth_req_id of the request"
+// @Param   action    query string true "approve or deny"
+// @Success 200 {object} controllers.Response The Response object
+// @router /complete-ciba-request [post]
+func (c *ApiController) CompleteCibaRequest() {
+	userId, ok := c.RequireSignedIn()
+	if !ok {
+		return
+	}
+
+	action := c.Ctx.Input.Query("action")
+	if action != "approve" && action != "deny" {
+		c.ResponseError(fmt.Sprintf(c.T("general:Unknown action: %s"), action))
+		return
+	}
+
+	err := object.CompleteCibaAuthentication(c.Ctx.Input.Query("authReqId"), userId, action == "approve")
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.ResponseOk()
+}
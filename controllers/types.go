@@ -34,4 +34,5 @@ type TokenRequest struct {
 	SubjectTokenType    string `json:"subject_token_type"`
 	Audience            string `json:"audience"`
 	Resource            string `json:"resource"` // RFC 8707 Resource Indicator
+	AuthReqId           string `json:"auth_req_id"`
 }
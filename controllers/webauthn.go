@@ -234,7 +234,7 @@ func (c *ApiController) WebAuthnSigninFinish() {
 
 	var authForm form.AuthForm
 	authForm.Type = responseType
-	resp := c.HandleLoggedIn(application, user, &authForm)
+	resp := c.HandleLoggedIn(application, user, &authForm, object.NewAuthContext(object.AmrHardwareKey))
 	c.Data["json"] = resp
 	c.ServeJSON()
 }
@@ -0,0 +1,145 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+)
+
+// HandleWsFed
+// @Title HandleWsFed
+// @Tag Login API
+// @Description the WS-Federation passive requestor endpoint of the application, signing in with wsignin1.0 and signing out with wsignout1.0 or wsignoutcleanup1.0
+// @Param   owner          path     string  true   "The owner of the application"
+// @Param   application    path     string  true   "The name of the application"
+// @Param   wa             query    string  true   "The action: wsignin1.0, wsignout1.0 or wsignoutcleanup1.0"
+// @Param   wtrealm        query    string  false  "The realm of the relying party"
+// @Param   wreply         query    string  false  "The URL to post the token to or to return to after signing out"
+// @Param   wctx           query    string  false  "The context of the relying party, returned as is"
+// @router /wsfed/:owner/:application [get,post]
+func (c *ApiController) HandleWsFed() {
+	owner := c.Ctx.Input.Param(":owner")
+	applicationName := c.Ctx.Input.Param(":application")
+	id := util.GetId(owner, applicationName)
+	application, err := object.GetApplication(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if application == nil {
+		c.ResponseError(fmt.Sprintf(c.T("auth:The application: %s does not exist"), id))
+		return
+	}
+
+	wa := c.GetString("wa")
+	reply := c.GetString("wreply")
+	switch wa {
+	case object.WsFedActionSignin:
+		// the login page posts the token to the relying party once the user has signed in
+		targetUrl := object.GetWsFedRedirectAddress(owner, applicationName, c.GetString("wtrealm"), reply, c.GetString("wctx"), c.Ctx.Request.Host)
+		c.Redirect(targetUrl, http.StatusFound)
+	case object.WsFedActionSignout, object.WsFedActionSignoutCleanup:
+		if reply != "" && !application.IsRedirectUriValid(reply) {
+			c.ResponseError(fmt.Sprintf(c.T("token:Redirect URI: %s doesn't exist in the allowed Redirect URI list"), reply))
+			return
+		}
+
+		user := c.GetSessionUsername()
+		cleanupUrls := []string{}
+		if user != "" {
+			beegoSessionId := c.Ctx.Input.CruSession.SessionID(context.Background())
+
+			// the other relying parties of the session are signed out by the browser, in hidden frames
+			owner, username := util.GetOwnerAndNameFromIdNoCheck(user)
+			cleanupUrls, err = object.PopWsFedSignoutCleanupUrls(owner, username, beegoSessionId, c.GetString("wtrealm"))
+			if err != nil {
+				c.ResponseError(err.Error())
+				return
+			}
+
+			err = c.logoutUserSession(user, beegoSessionId)
+			if err != nil {
+				c.ResponseError(err.Error())
+				return
+			}
+
+			util.LogInfo(c.Ctx, "API: [%s] logged out by the WS-Federation sign-out of [%s]", user, c.GetString("wtrealm"))
+		}
+
+		if len(cleanupUrls) == 0 {
+			if reply != "" {
+				c.Redirect(reply, http.StatusFound)
+				return
+			}
+			c.ResponseOk(user)
+			return
+		}
+
+		html, err := object.GetWsFedSignoutHtml(cleanupUrls, reply)
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+
+		c.Ctx.Output.Header("Content-Type", "text/html; charset=utf-8")
+		err = c.Ctx.Output.Body([]byte(html))
+		if err != nil {
+			c.ResponseError(err.Error())
+			return
+		}
+	default:
+		c.ResponseError(fmt.Sprintf(c.T("auth:The WS-Federation action: %s is not supported"), wa))
+	}
+}
+
+// GetWsFedMetadata
+// @Title GetWsFedMetadata
+// @Tag Login API
+// @Description get the WS-Federation metadata (FederationMetadata.xml) of the application
+// @Param   owner          path     string  true   "The owner of the application"
+// @Param   application    path     string  true   "The name of the application"
+// @router /wsfed/:owner/:application/FederationMetadata.xml [get]
+func (c *ApiController) GetWsFedMetadata() {
+	id := util.GetId(c.Ctx.Input.Param(":owner"), c.Ctx.Input.Param(":application"))
+	application, err := object.GetApplication(id)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	if application == nil {
+		c.ResponseError(fmt.Sprintf(c.T("auth:The application: %s does not exist"), id))
+		return
+	}
+
+	metadata, err := object.GetWsFedMetadata(application, c.Ctx.Request.Host)
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", "application/xml; charset=utf-8")
+	err = c.Ctx.Output.Body([]byte(metadata))
+	if err != nil {
+		c.ResponseError(err.Error())
+		return
+	}
+}
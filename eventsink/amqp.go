@@ -0,0 +1,114 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type AmqpEventSinkProvider struct {
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	returns  chan amqp.Return
+	exchange string
+}
+
+// NewAmqpEventSinkProvider publishes to the exchange with the event type as the routing key, the
+// channel is in confirm mode so that a message is only delivered once the broker has taken it, and
+// the messages are mandatory so that a message no queue is bound for isn't dropped silently
+func NewAmqpEventSinkProvider(endpoint string, exchange string, username string, password string) (*AmqpEventSinkProvider, error) {
+	if username != "" {
+		endpointUrl, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		endpointUrl.User = url.UserPassword(username, password)
+		endpoint = endpointUrl.String()
+	}
+
+	conn, err := amqp.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	err = channel.Confirm(false)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// the broker returns an unroutable message before it acks it, only one message is in flight at a time
+	returns := channel.NotifyReturn(make(chan amqp.Return, 1))
+
+	return &AmqpEventSinkProvider{conn: conn, channel: channel, returns: returns, exchange: exchange}, nil
+}
+
+func (p *AmqpEventSinkProvider) Send(ctx context.Context, message *Message) error {
+	// a channel isn't safe for concurrent publishing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	confirmation, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, message.Type, true, false, amqp.Publishing{
+		MessageId:    message.Id,
+		Type:         message.Type,
+		ContentType:  message.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Headers:      amqp.Table{"partition-key": message.Key},
+		Body:         message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return fmt.Errorf("the message: %s is rejected by the broker", message.Id)
+	}
+
+	for {
+		select {
+		case returned, ok := <-p.returns:
+			if !ok {
+				return fmt.Errorf("the channel of the message: %s is closed", message.Id)
+			}
+			// a return left over by an earlier message whose confirmation has been given up is skipped
+			if returned.MessageId == message.Id {
+				return fmt.Errorf("the message: %s is returned by the broker as unroutable: %s", message.Id, returned.ReplyText)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *AmqpEventSinkProvider) Close() error {
+	return p.conn.Close()
+}
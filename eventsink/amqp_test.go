@@ -0,0 +1,276 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	amqpFrameMethod    = 1
+	amqpFrameHeader    = 2
+	amqpFrameBody      = 3
+	amqpFrameHeartbeat = 8
+	amqpFrameEnd       = 0xCE
+)
+
+// testAmqpBroker is an in-process AMQP 0-9-1 broker which speaks just enough of the protocol for a
+// publisher in confirm mode: it acks every message, and returns the mandatory messages whose routing
+// key isn't bound before it acks them, as RabbitMQ does
+type testAmqpBroker struct {
+	listener    net.Listener
+	routingKeys map[string]bool
+
+	mu        sync.Mutex
+	published []string
+}
+
+type testAmqpPublish struct {
+	exchange   string
+	routingKey string
+	mandatory  bool
+	header     []byte
+	bodySize   uint64
+	body       []byte
+}
+
+func newTestAmqpBroker(t *testing.T, routingKeys ...string) *testAmqpBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := &testAmqpBroker{listener: listener, routingKeys: map[string]bool{}}
+	for _, routingKey := range routingKeys {
+		broker.routingKeys[routingKey] = true
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+
+	t.Cleanup(func() { listener.Close() })
+	return broker
+}
+
+func (b *testAmqpBroker) getUrl() string {
+	return fmt.Sprintf("amqp://guest:guest@%s/", b.listener.Addr().String())
+}
+
+func (b *testAmqpBroker) getPublished() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string{}, b.published...)
+}
+
+func (b *testAmqpBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	protocolHeader := make([]byte, 8)
+	if _, err := io.ReadFull(reader, protocolHeader); err != nil || string(protocolHeader) != "AMQP\x00\x00\x09\x01" {
+		return
+	}
+
+	// connection.start is answered by start-ok, connection.tune by tune-ok and connection.open
+	handshake := []struct {
+		method  []byte
+		replies int
+	}{
+		{getAmqpMethod(10, 10, []byte{0, 9}, getAmqpTable(), getAmqpLongString("PLAIN"), getAmqpLongString("en_US")), 1},
+		{getAmqpMethod(10, 30, getAmqpShort(2047), getAmqpLong(131072), getAmqpShort(0)), 2},
+	}
+	for _, step := range handshake {
+		if err := writeAmqpFrame(conn, amqpFrameMethod, 0, step.method); err != nil {
+			return
+		}
+		for i := 0; i < step.replies; i++ {
+			if _, _, _, err := readAmqpFrame(reader); err != nil {
+				return
+			}
+		}
+	}
+	if err := writeAmqpFrame(conn, amqpFrameMethod, 0, getAmqpMethod(10, 41, getAmqpShortString(""))); err != nil {
+		return
+	}
+
+	deliveryTag := uint64(0)
+	var publish *testAmqpPublish
+	for {
+		frameType, channel, payload, err := readAmqpFrame(reader)
+		if err != nil {
+			return
+		}
+
+		switch frameType {
+		case amqpFrameHeartbeat:
+			continue
+		case amqpFrameHeader:
+			publish.header = payload
+			publish.bodySize = binary.BigEndian.Uint64(payload[4:12])
+		case amqpFrameBody:
+			publish.body = append(publish.body, payload...)
+		case amqpFrameMethod:
+			classId, methodId := binary.BigEndian.Uint16(payload[0:2]), binary.BigEndian.Uint16(payload[2:4])
+			switch {
+			case classId == 10 && methodId == 50:
+				writeAmqpFrame(conn, amqpFrameMethod, 0, getAmqpMethod(10, 51))
+				return
+			case classId == 20 && methodId == 10:
+				writeAmqpFrame(conn, amqpFrameMethod, channel, getAmqpMethod(20, 11, getAmqpLongString("")))
+			case classId == 20 && methodId == 40:
+				writeAmqpFrame(conn, amqpFrameMethod, channel, getAmqpMethod(20, 41))
+			case classId == 85 && methodId == 10:
+				writeAmqpFrame(conn, amqpFrameMethod, channel, getAmqpMethod(85, 11))
+			case classId == 60 && methodId == 40:
+				args := payload[6:]
+				exchange, args := readAmqpShortString(args)
+				routingKey, args := readAmqpShortString(args)
+				publish = &testAmqpPublish{exchange: exchange, routingKey: routingKey, mandatory: args[0]&1 != 0}
+			}
+		}
+
+		if publish == nil || publish.header == nil || uint64(len(publish.body)) < publish.bodySize {
+			continue
+		}
+
+		deliveryTag++
+		if publish.mandatory && !b.routingKeys[publish.routingKey] {
+			writeAmqpFrame(conn, amqpFrameMethod, channel, getAmqpMethod(60, 50, getAmqpShort(312), getAmqpShortString("NO_ROUTE"), getAmqpShortString(publish.exchange), getAmqpShortString(publish.routingKey)))
+			writeAmqpFrame(conn, amqpFrameHeader, channel, publish.header)
+			writeAmqpFrame(conn, amqpFrameBody, channel, publish.body)
+		} else {
+			b.mu.Lock()
+			b.published = append(b.published, string(publish.body))
+			b.mu.Unlock()
+		}
+		writeAmqpFrame(conn, amqpFrameMethod, channel, getAmqpMethod(60, 80, binary.BigEndian.AppendUint64(nil, deliveryTag), []byte{0}))
+		publish = nil
+	}
+}
+
+func readAmqpFrame(reader *bufio.Reader) (byte, uint16, []byte, error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, 0, nil, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[3:7])+1)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return 0, 0, nil, err
+	}
+	if payload[len(payload)-1] != amqpFrameEnd {
+		return 0, 0, nil, fmt.Errorf("the frame end is missing")
+	}
+
+	return header[0], binary.BigEndian.Uint16(header[1:3]), payload[:len(payload)-1], nil
+}
+
+func writeAmqpFrame(writer io.Writer, frameType byte, channel uint16, payload []byte) error {
+	frame := []byte{frameType}
+	frame = binary.BigEndian.AppendUint16(frame, channel)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(payload)))
+	frame = append(frame, payload...)
+	frame = append(frame, amqpFrameEnd)
+	_, err := writer.Write(frame)
+	return err
+}
+
+func getAmqpMethod(classId uint16, methodId uint16, args ...[]byte) []byte {
+	method := binary.BigEndian.AppendUint16(nil, classId)
+	method = binary.BigEndian.AppendUint16(method, methodId)
+	for _, arg := range args {
+		method = append(method, arg...)
+	}
+	return method
+}
+
+func getAmqpShort(value uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, value)
+}
+
+func getAmqpLong(value uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, value)
+}
+
+func getAmqpShortString(value string) []byte {
+	return append([]byte{byte(len(value))}, value...)
+}
+
+func getAmqpLongString(value string) []byte {
+	return append(getAmqpLong(uint32(len(value))), value...)
+}
+
+func getAmqpTable() []byte {
+	return getAmqpLong(0)
+}
+
+func readAmqpShortString(data []byte) (string, []byte) {
+	size := int(data[0])
+	return string(data[1 : 1+size]), data[1+size:]
+}
+
+func TestAmqpEventSinkProvider(t *testing.T) {
+	broker := newTestAmqpBroker(t, "user.updated")
+
+	provider, err := GetEventSinkProvider("AMQP", broker.getUrl(), "casdoor", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provider.Close()
+
+	messages := getTestMessages()
+	for _, message := range messages {
+		err = provider.Send(context.Background(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a message no queue is bound for is returned by the broker rather than acked silently
+	err = provider.Send(context.Background(), &Message{Id: "event-unroutable", Key: "built-in/admin", Type: "user.unknown", ContentType: "application/json", Body: []byte(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "unroutable") {
+		t.Errorf("an unroutable message is sent without an error: %v", err)
+	}
+
+	err = provider.Send(context.Background(), &Message{Id: "event-3", Key: "built-in/admin", Type: "user.updated", ContentType: "application/json", Body: []byte(`{"seq":3}`)})
+	if err != nil {
+		t.Errorf("a message sent after the returned one fails: %v", err)
+	}
+
+	published := broker.getPublished()
+	expected := []string{}
+	for _, message := range messages {
+		expected = append(expected, string(message.Body))
+	}
+	expected = append(expected, `{"seq":3}`)
+	if strings.Join(published, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected published messages: %v, expected: %v", published, expected)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+type KafkaEventSinkProvider struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaEventSinkProvider writes to the topic of the comma-separated brokers, the messages are
+// assigned to the partitions by the hash of their key
+func NewKafkaEventSinkProvider(endpoint string, topic string, username string, password string) (*KafkaEventSinkProvider, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("the Kafka topic should not be empty")
+	}
+
+	brokers := []string{}
+	for _, broker := range strings.Split(endpoint, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("the Kafka brokers should not be empty")
+	}
+
+	transport := &kafka.Transport{}
+	if username != "" {
+		transport.SASL = plain.Mechanism{Username: username, Password: password}
+	}
+
+	// the events are sent one at a time, so a batch isn't waited for to fill up
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		BatchTimeout: 10 * time.Millisecond,
+		RequiredAcks: kafka.RequireAll,
+		Transport:    transport,
+	}
+
+	return &KafkaEventSinkProvider{writer: writer}, nil
+}
+
+func (p *KafkaEventSinkProvider) Send(ctx context.Context, message *Message) error {
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(message.Key),
+		Value: message.Body,
+		Headers: []kafka.Header{
+			{Key: "id", Value: []byte(message.Id)},
+			{Key: "type", Value: []byte(message.Type)},
+			{Key: "content-type", Value: []byte(message.ContentType)},
+		},
+	})
+}
+
+func (p *KafkaEventSinkProvider) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+type NatsEventSinkProvider struct {
+	conn      *nats.Conn
+	jetStream nats.JetStreamContext
+	subject   string
+}
+
+// NewNatsEventSinkProvider publishes to the subject through JetStream, a stream has to capture the subject,
+// the id of the message lets JetStream drop the duplicates of a retried delivery
+func NewNatsEventSinkProvider(endpoint string, subject string, username string, password string) (*NatsEventSinkProvider, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("the NATS subject should not be empty")
+	}
+
+	options := []nats.Option{nats.Name("Casdoor")}
+	if username != "" {
+		options = append(options, nats.UserInfo(username, password))
+	}
+
+	conn, err := nats.Connect(endpoint, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	jetStream, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NatsEventSinkProvider{conn: conn, jetStream: jetStream, subject: subject}, nil
+}
+
+func (p *NatsEventSinkProvider) Send(ctx context.Context, message *Message) error {
+	msg := nats.NewMsg(p.subject)
+	msg.Header.Set("Nats-Msg-Id", message.Id)
+	msg.Header.Set("Casdoor-Event-Type", message.Type)
+	msg.Header.Set("Casdoor-Partition-Key", message.Key)
+	msg.Header.Set("Content-Type", message.ContentType)
+	msg.Data = message.Body
+
+	_, err := p.jetStream.PublishMsg(msg, nats.Context(ctx))
+	return err
+}
+
+func (p *NatsEventSinkProvider) Close() error {
+	p.conn.Close()
+	return nil
+}
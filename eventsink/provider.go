@@ -0,0 +1,53 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+)
+
+// Message is an event delivered to a broker, Key is the partition key, the messages of the same key
+// are kept in order by the brokers that partition their topics
+type Message struct {
+	Id          string
+	Key         string
+	Type        string
+	ContentType string
+	Body        []byte
+}
+
+// EventSinkProvider publishes the messages to a broker, a provider holds its connection until it is closed
+type EventSinkProvider interface {
+	Send(ctx context.Context, message *Message) error
+	Close() error
+}
+
+// GetEventSinkProvider connects to the broker of the sink type, the topic is the Kafka topic, the NATS
+// subject, the AMQP exchange or the Redis stream
+func GetEventSinkProvider(sinkType string, endpoint string, topic string, username string, password string) (EventSinkProvider, error) {
+	switch sinkType {
+	case "Kafka":
+		return NewKafkaEventSinkProvider(endpoint, topic, username, password)
+	case "NATS":
+		return NewNatsEventSinkProvider(endpoint, topic, username, password)
+	case "AMQP":
+		return NewAmqpEventSinkProvider(endpoint, topic, username, password)
+	case "Redis Streams":
+		return NewRedisEventSinkProvider(endpoint, topic, username, password)
+	}
+
+	return nil, fmt.Errorf("unsupported event sink type: %s", sinkType)
+}
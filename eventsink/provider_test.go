@@ -0,0 +1,239 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/twmb/franz-go/pkg/kfake"
+)
+
+func getTestMessages() []*Message {
+	messages := []*Message{}
+	for i := 0; i < 3; i++ {
+		messages = append(messages, &Message{
+			Id:          fmt.Sprintf("event-%d", i),
+			Key:         "built-in/admin",
+			Type:        "user.updated",
+			ContentType: "application/json",
+			Body:        []byte(fmt.Sprintf(`{"seq":%d}`, i)),
+		})
+	}
+	return messages
+}
+
+func TestNatsEventSinkProvider(t *testing.T) {
+	natsServer, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go natsServer.Start()
+	defer natsServer.Shutdown()
+	if !natsServer.ReadyForConnections(10 * time.Second) {
+		t.Fatal("the NATS server isn't ready")
+	}
+
+	conn, err := nats.Connect(natsServer.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	jetStream, err := conn.JetStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jetStream.AddStream(&nats.StreamConfig{Name: "CASDOOR", Subjects: []string{"casdoor.events"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := GetEventSinkProvider("NATS", natsServer.ClientURL(), "casdoor.events", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provider.Close()
+
+	messages := getTestMessages()
+	for _, message := range messages {
+		err = provider.Send(context.Background(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a retried delivery is dropped by JetStream
+	err = provider.Send(context.Background(), messages[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := jetStream.SubscribeSync("casdoor.events", nats.DeliverAll())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range messages {
+		msg, err := subscription.NextMsg(5 * time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(msg.Data) != string(message.Body) || msg.Header.Get("Nats-Msg-Id") != message.Id || msg.Header.Get("Casdoor-Event-Type") != message.Type {
+			t.Errorf("unexpected message: %s %v, expected: %s", string(msg.Data), msg.Header, string(message.Body))
+		}
+	}
+
+	_, err = subscription.NextMsg(200 * time.Millisecond)
+	if err != nats.ErrTimeout {
+		t.Errorf("the duplicate of a message is delivered: %v", err)
+	}
+}
+
+func TestRedisEventSinkProvider(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+
+	provider, err := GetEventSinkProvider("Redis Streams", redisServer.Addr(), "casdoor-events", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provider.Close()
+
+	messages := getTestMessages()
+	for _, message := range messages {
+		err = provider.Send(context.Background(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := redisServer.Stream("casdoor-events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(messages) {
+		t.Fatalf("unexpected stream length: %d, expected: %d", len(entries), len(messages))
+	}
+
+	for i, entry := range entries {
+		values := map[string]string{}
+		for j := 0; j+1 < len(entry.Values); j += 2 {
+			values[entry.Values[j]] = entry.Values[j+1]
+		}
+		if values["id"] != messages[i].Id || values["key"] != messages[i].Key || values["body"] != string(messages[i].Body) {
+			t.Errorf("unexpected stream entry: %v, expected: %s", values, messages[i].Id)
+		}
+	}
+}
+
+func TestKafkaEventSinkProvider(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, "casdoor-events"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Close()
+
+	provider, err := GetEventSinkProvider("Kafka", cluster.ListenAddrs()[0], "casdoor-events", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provider.Close()
+
+	messages := getTestMessages()
+	for i := 0; i < 3; i++ {
+		messages = append(messages, &Message{
+			Id:          fmt.Sprintf("event-%d", len(messages)),
+			Key:         "built-in/alice",
+			Type:        "user.deleted",
+			ContentType: "application/json",
+			Body:        []byte(fmt.Sprintf(`{"seq":%d}`, i)),
+		})
+	}
+	for _, message := range messages {
+		err = provider.Send(context.Background(), message)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the messages of a key are all written to one partition, in the order they are sent
+	received := map[string][]kafka.Message{}
+	partitions := map[string]int{}
+	for partition := 0; partition < 3; partition++ {
+		conn, err := kafka.DialLeader(context.Background(), "tcp", cluster.ListenAddrs()[0], "casdoor-events", partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lastOffset, err := conn.ReadLastOffset()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for offset := int64(0); offset < lastOffset; offset++ {
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			msg, err := conn.ReadMessage(1 << 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key := string(msg.Key)
+			if previous, ok := partitions[key]; ok && previous != partition {
+				t.Errorf("the messages of key: %s are written to partitions %d and %d", key, previous, partition)
+			}
+			partitions[key] = partition
+			received[key] = append(received[key], msg)
+		}
+		conn.Close()
+	}
+
+	for _, key := range []string{"built-in/admin", "built-in/alice"} {
+		expected := []*Message{}
+		for _, message := range messages {
+			if message.Key == key {
+				expected = append(expected, message)
+			}
+		}
+		if len(received[key]) != len(expected) {
+			t.Fatalf("unexpected message count of key: %s: %d, expected: %d", key, len(received[key]), len(expected))
+		}
+
+		for i, msg := range received[key] {
+			headers := map[string]string{}
+			for _, header := range msg.Headers {
+				headers[header.Key] = string(header.Value)
+			}
+			if string(msg.Value) != string(expected[i].Body) || headers["id"] != expected[i].Id || headers["type"] != expected[i].Type {
+				t.Errorf("unexpected message: %s %v, expected: %s", string(msg.Value), headers, string(expected[i].Body))
+			}
+		}
+	}
+}
+
+func TestGetEventSinkProvider(t *testing.T) {
+	_, err := GetEventSinkProvider("Kafka", "127.0.0.1:9092", "", "", "")
+	if err == nil {
+		t.Errorf("a Kafka sink without a topic is accepted")
+	}
+
+	_, err = GetEventSinkProvider("SQS", "", "", "", "")
+	if err == nil {
+		t.Errorf("an unsupported sink type is accepted")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisEventSinkProvider struct {
+	client *redis.Client
+	stream string
+}
+
+// NewRedisEventSinkProvider appends to the stream, the endpoint is a redis:// URL or a host:port address
+func NewRedisEventSinkProvider(endpoint string, stream string, username string, password string) (*RedisEventSinkProvider, error) {
+	if stream == "" {
+		return nil, fmt.Errorf("the Redis stream should not be empty")
+	}
+
+	var options *redis.Options
+	if strings.Contains(endpoint, "://") {
+		var err error
+		options, err = redis.ParseURL(endpoint)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		options = &redis.Options{Addr: endpoint}
+	}
+
+	if username != "" {
+		options.Username = username
+	}
+	if password != "" {
+		options.Password = password
+	}
+
+	return &RedisEventSinkProvider{client: redis.NewClient(options), stream: stream}, nil
+}
+
+func (p *RedisEventSinkProvider) Send(ctx context.Context, message *Message) error {
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]interface{}{
+			"id":           message.Id,
+			"type":         message.Type,
+			"key":          message.Key,
+			"content-type": message.ContentType,
+			"body":         message.Body,
+		},
+	}).Err()
+}
+
+func (p *RedisEventSinkProvider) Close() error {
+	return p.client.Close()
+}
@@ -47,6 +47,7 @@ type AuthForm struct {
 	RedirectUri  string `json:"redirectUri"`
 	Method       string `json:"method"`
 	CodeVerifier string `json:"codeVerifier"`
+	IdpNonce     string `json:"idpNonce"`
 
 	EmailCode   string `json:"emailCode"`
 	PhoneCode   string `json:"phoneCode"`
@@ -56,7 +57,12 @@ type AuthForm struct {
 
 	RelayState   string `json:"relayState"`
 	SamlRequest  string `json:"samlRequest"`
+	SigAlg       string `json:"sigAlg"`
+	Signature    string `json:"signature"`
+	SamlQuery    string `json:"samlQuery"`
 	SamlResponse string `json:"samlResponse"`
+	WsFedRealm   string `json:"wsFedRealm"`
+	WsFedReply   string `json:"wsFedReply"`
 
 	CaptchaType  string `json:"captchaType"`
 	CaptchaToken string `json:"captchaToken"`
@@ -0,0 +1,584 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/casdoor/casdoor/util"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcDiscoveryCacheTtl = time.Hour
+	oidcJwksCacheTtl      = 10 * time.Minute
+	// an unknown kid forces the JWKS to be downloaded again, but not more often than this,
+	// so that tokens with made-up kids can't be used to flood the identity provider
+	oidcJwksMinRefreshInterval = time.Minute
+
+	OidcBackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+)
+
+// OidcIdTokenSigningAlgs are the algorithms accepted for the id_token of an OIDC provider,
+// the HMAC ones are verified with the client secret, "none" is never accepted.
+var OidcIdTokenSigningAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "HS256", "HS384", "HS512"}
+
+// oidcStandardClaims maps the fields of UserInfo to the standard claims of OpenID Connect,
+// they are used when the user mapping of the provider doesn't resolve a field.
+// Refs: https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+var oidcStandardClaims = map[string]string{
+	"id":          "sub",
+	"username":    "preferred_username",
+	"displayName": "name",
+	"email":       "email",
+	"avatarUrl":   "picture",
+	"phone":       "phone_number",
+}
+
+// oidcProtocolClaims are the claims of the id_token about the token itself rather than the user
+var oidcProtocolClaims = map[string]bool{
+	"iss": true, "aud": true, "exp": true, "iat": true, "nbf": true, "jti": true, "nonce": true,
+	"azp": true, "at_hash": true, "c_hash": true, "auth_time": true, "sid": true, "acr": true, "amr": true,
+}
+
+type OidcDiscovery struct {
+	Issuer                           string   `json:"issuer"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint"`
+	JwksUri                          string   `json:"jwks_uri"`
+	IdTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+type oidcDiscoveryCacheItem struct {
+	discovery *OidcDiscovery
+	fetchedAt time.Time
+}
+
+type oidcJwksCacheItem struct {
+	jwks      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+var (
+	oidcDiscoveryCache      = map[string]*oidcDiscoveryCacheItem{}
+	oidcDiscoveryCacheMutex sync.Mutex
+	oidcJwksCache           = map[string]*oidcJwksCacheItem{}
+	oidcJwksCacheMutex      sync.Mutex
+)
+
+type OidcIdProvider struct {
+	Client *http.Client
+	Config *oauth2.Config
+
+	Issuer       string
+	UserMapping  map[string]string
+	CodeVerifier string
+	Nonce        string
+}
+
+func NewOidcIdProvider(idpInfo *ProviderInfo, redirectUrl string) *OidcIdProvider {
+	idp := &OidcIdProvider{}
+
+	idp.Config = &oauth2.Config{
+		ClientID:     idpInfo.ClientId,
+		ClientSecret: idpInfo.ClientSecret,
+		RedirectURL:  redirectUrl,
+	}
+	idp.Issuer = strings.TrimSuffix(idpInfo.HostUrl, "/")
+	idp.UserMapping = idpInfo.UserMapping
+
+	idp.CodeVerifier = idpInfo.CodeVerifier
+	idp.Nonce = idpInfo.Nonce
+	return idp
+}
+
+func (idp *OidcIdProvider) SetHttpClient(client *http.Client) {
+	idp.Client = client
+}
+
+func (idp *OidcIdProvider) getHttpClient() *http.Client {
+	if idp.Client == nil {
+		return &http.Client{Timeout: 10 * time.Second}
+	}
+	return idp.Client
+}
+
+func getOidcJson(client *http.Client, targetUrl string, accessToken string, v interface{}) error {
+	request, err := http.NewRequest("GET", targetUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Add("Accept", "application/json")
+	if accessToken != "" {
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	}
+	resp, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s, status code: %d", targetUrl, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// GetOidcDiscovery fetches the OpenID provider metadata of the issuer, the result is cached so
+// that the endpoints of the provider are not looked up for every login.
+// Refs: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+func GetOidcDiscovery(client *http.Client, issuer string) (*OidcDiscovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	if issuer == "" {
+		return nil, fmt.Errorf("the issuer URL of the OIDC provider is empty")
+	}
+
+	oidcDiscoveryCacheMutex.Lock()
+	item, ok := oidcDiscoveryCache[issuer]
+	oidcDiscoveryCacheMutex.Unlock()
+	if ok && time.Since(item.fetchedAt) < oidcDiscoveryCacheTtl {
+		return item.discovery, nil
+	}
+
+	discovery := &OidcDiscovery{}
+	err := getOidcJson(client, issuer+"/.well-known/openid-configuration", "", discovery)
+	if err != nil {
+		return nil, err
+	}
+
+	// the issuer in the metadata must be the one the metadata was fetched for, otherwise
+	// tokens of another issuer could be accepted
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("the issuer: %s in the OIDC discovery document doesn't match the issuer URL: %s", discovery.Issuer, issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return nil, fmt.Errorf("the OIDC discovery document of %s is missing the authorization_endpoint, token_endpoint or jwks_uri", issuer)
+	}
+
+	oidcDiscoveryCacheMutex.Lock()
+	oidcDiscoveryCache[issuer] = &oidcDiscoveryCacheItem{discovery: discovery, fetchedAt: time.Now()}
+	oidcDiscoveryCacheMutex.Unlock()
+	return discovery, nil
+}
+
+// getOidcJwks fetches the signing keys of the provider, forceRefresh is used when a kid is not
+// found, which happens right after the provider has rotated its keys.
+func getOidcJwks(client *http.Client, jwksUri string, forceRefresh bool) (*jose.JSONWebKeySet, error) {
+	oidcJwksCacheMutex.Lock()
+	item, ok := oidcJwksCache[jwksUri]
+	oidcJwksCacheMutex.Unlock()
+	if ok {
+		age := time.Since(item.fetchedAt)
+		if age < oidcJwksCacheTtl && (!forceRefresh || age < oidcJwksMinRefreshInterval) {
+			return item.jwks, nil
+		}
+	}
+
+	jwks := &jose.JSONWebKeySet{}
+	err := getOidcJson(client, jwksUri, "", jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcJwksCacheMutex.Lock()
+	oidcJwksCache[jwksUri] = &oidcJwksCacheItem{jwks: jwks, fetchedAt: time.Now()}
+	oidcJwksCacheMutex.Unlock()
+	return jwks, nil
+}
+
+func findOidcJwksKey(jwks *jose.JSONWebKeySet, kid string) interface{} {
+	var keys []jose.JSONWebKey
+	for _, key := range jwks.Keys {
+		if key.Use == "enc" {
+			continue
+		}
+		if kid == "" || key.KeyID == kid {
+			keys = append(keys, key)
+		}
+	}
+
+	// Without a kid the key set must be unambiguous
+	if len(keys) == 0 || (kid == "" && len(keys) > 1) {
+		return nil
+	}
+	return keys[0].Public().Key
+}
+
+// GetAuthUrl returns the URL of the authorization endpoint of the provider, the login is
+// bound to the browser by the state, the PKCE code challenge and the nonce.
+func (idp *OidcIdProvider) GetAuthUrl(scopes string, state string, codeChallenge string, nonce string) (string, error) {
+	discovery, err := GetOidcDiscovery(idp.getHttpClient(), idp.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	if scopes == "" {
+		scopes = "openid profile email"
+	}
+	if !util.InSlice(strings.Fields(scopes), "openid") {
+		scopes = "openid " + scopes
+	}
+
+	params := url.Values{}
+	params.Add("client_id", idp.Config.ClientID)
+	params.Add("redirect_uri", idp.Config.RedirectURL)
+	params.Add("response_type", "code")
+	params.Add("scope", scopes)
+	params.Add("state", state)
+	params.Add("nonce", nonce)
+	if codeChallenge != "" {
+		params.Add("code_challenge", codeChallenge)
+		params.Add("code_challenge_method", "S256")
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (idp *OidcIdProvider) GetToken(code string) (*oauth2.Token, error) {
+	discovery, err := GetOidcDiscovery(idp.getHttpClient(), idp.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	idp.Config.Endpoint = oauth2.Endpoint{
+		AuthURL:  discovery.AuthorizationEndpoint,
+		TokenURL: discovery.TokenEndpoint,
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, idp.getHttpClient())
+	var oauth2Opts []oauth2.AuthCodeOption
+	if idp.CodeVerifier != "" {
+		oauth2Opts = append(oauth2Opts, oauth2.VerifierOption(idp.CodeVerifier))
+	}
+	return idp.Config.Exchange(ctx, code, oauth2Opts...)
+}
+
+func (idp *OidcIdProvider) getVerificationKey(discovery *OidcDiscovery, token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if idp.Config.ClientSecret == "" {
+			return nil, fmt.Errorf("the client secret is empty")
+		}
+		return []byte(idp.Config.ClientSecret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	jwks, err := getOidcJwks(idp.getHttpClient(), discovery.JwksUri, false)
+	if err != nil {
+		return nil, err
+	}
+
+	key := findOidcJwksKey(jwks, kid)
+	if key == nil {
+		jwks, err = getOidcJwks(idp.getHttpClient(), discovery.JwksUri, true)
+		if err != nil {
+			return nil, err
+		}
+		key = findOidcJwksKey(jwks, kid)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("the key: %s is not found in the JWKS of %s", kid, discovery.Issuer)
+	}
+	return key, nil
+}
+
+// ValidateIdToken verifies the signature of the id_token with the JWKS of the provider and
+// checks its iss, aud, azp, exp and nonce claims.
+// Refs: https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+func (idp *OidcIdProvider) ValidateIdToken(rawIdToken string) (jwt.MapClaims, error) {
+	discovery, err := GetOidcDiscovery(idp.getHttpClient(), idp.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIdToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return idp.getVerificationKey(discovery, token)
+	}, jwt.WithValidMethods(OidcIdTokenSigningAlgs),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(idp.Config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute))
+	if err != nil {
+		return nil, fmt.Errorf("the id_token is invalid: %s", err.Error())
+	}
+
+	audience, err := claims.GetAudience()
+	if err != nil {
+		return nil, err
+	}
+	azp, hasAzp := claims["azp"].(string)
+	if hasAzp && azp != idp.Config.ClientID {
+		return nil, fmt.Errorf("the id_token is invalid: the azp: %s is not the client ID", azp)
+	}
+	if len(audience) > 1 && !hasAzp {
+		return nil, fmt.Errorf("the id_token is invalid: the azp is required when there are multiple audiences")
+	}
+
+	// the nonce ties the id_token to the login started by this browser, it must always be there
+	nonce, _ := claims["nonce"].(string)
+	if idp.Nonce == "" || nonce != idp.Nonce {
+		return nil, fmt.Errorf("the id_token is invalid: the nonce doesn't match")
+	}
+
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, fmt.Errorf("the id_token is invalid: the sub is missing")
+	}
+	return claims, nil
+}
+
+// OidcLogoutToken is a validated logout token, at least one of Sub and Sid is set
+type OidcLogoutToken struct {
+	Sub      string
+	Sid      string
+	Jti      string
+	IssuedAt time.Time
+}
+
+// ValidateLogoutToken verifies a logout token the provider has sent to the back-channel logout
+// endpoint, it returns the sub and the sid of the upstream session to end, with the jti and the iat
+// the token can be told apart by for replay detection.
+// Refs: https://openid.net/specs/openid-connect-backchannel-1_0.html#Validation
+func (idp *OidcIdProvider) ValidateLogoutToken(rawLogoutToken string) (*OidcLogoutToken, error) {
+	discovery, err := GetOidcDiscovery(idp.getHttpClient(), idp.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawLogoutToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return idp.getVerificationKey(discovery, token)
+	}, jwt.WithValidMethods(OidcIdTokenSigningAlgs),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(idp.Config.ClientID),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute))
+	if err != nil {
+		return nil, fmt.Errorf("the logout token is invalid: %s", err.Error())
+	}
+
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return nil, fmt.Errorf("the logout token is invalid: the iat is missing")
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, fmt.Errorf("the logout token is invalid: the jti is missing")
+	}
+	events, ok := claims["events"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the logout token is invalid: the events are missing")
+	}
+	if _, ok = events[OidcBackchannelLogoutEvent].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("the logout token is invalid: the back-channel logout event is missing")
+	}
+	// a nonce is forbidden so that an id_token can't be used as a logout token
+	if _, ok = claims["nonce"]; ok {
+		return nil, fmt.Errorf("the logout token is invalid: it must not have a nonce")
+	}
+
+	sub, _ := claims["sub"].(string)
+	sid, _ := claims["sid"].(string)
+	if sub == "" && sid == "" {
+		return nil, fmt.Errorf("the logout token is invalid: both the sub and the sid are missing")
+	}
+	return &OidcLogoutToken{Sub: sub, Sid: sid, Jti: jti, IssuedAt: issuedAt.Time}, nil
+}
+
+// ValidateSecurityEventToken verifies a Security Event Token the provider has pushed to the Shared
+// Signals receiver endpoint, it returns the claims of the token, a SET has no exp so only its iat is checked.
+// Refs: https://datatracker.ietf.org/doc/html/rfc8417, https://datatracker.ietf.org/doc/html/rfc8935#section-2
+func (idp *OidcIdProvider) ValidateSecurityEventToken(rawToken string) (jwt.MapClaims, error) {
+	discovery, err := GetOidcDiscovery(idp.getHttpClient(), idp.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return idp.getVerificationKey(discovery, token)
+	}, jwt.WithValidMethods(OidcIdTokenSigningAlgs),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(idp.Config.ClientID),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute))
+	if err != nil {
+		return nil, fmt.Errorf("the security event token is invalid: %s", err.Error())
+	}
+
+	if _, ok := claims["iat"]; !ok {
+		return nil, fmt.Errorf("the security event token is invalid: the iat is missing")
+	}
+	if jti, _ := claims["jti"].(string); jti == "" {
+		return nil, fmt.Errorf("the security event token is invalid: the jti is missing")
+	}
+	events, ok := claims["events"].(map[string]interface{})
+	if !ok || len(events) == 0 {
+		return nil, fmt.Errorf("the security event token is invalid: the events are missing")
+	}
+	// a SET must not be usable as an id_token, and the other way round
+	if _, ok = claims["nonce"]; ok {
+		return nil, fmt.Errorf("the security event token is invalid: it must not have a nonce")
+	}
+
+	return claims, nil
+}
+
+// GetIdpSession returns the sub and the sid of the upstream session, which the logout tokens of the provider
+// name. They are read from the id_token of the token response for every provider based on OpenID Connect,
+// the id_token comes straight from the token endpoint, see 3.1.3.7 of OpenID Connect Core 1.0. The id of the
+// user info isn't always the sub, as it can be mapped to another claim, or be the Windows SID for ADFS. The
+// other providers fall back to the id and the sid of the user info, such as the NameID and the session index
+// of SAML.
+func GetIdpSession(token *oauth2.Token, userInfo *UserInfo) (string, string) {
+	if token != nil {
+		claims, err := parseIdTokenClaims(token)
+		if err == nil {
+			if sub, _ := claims["sub"].(string); sub != "" {
+				sid, _ := claims["sid"].(string)
+				return sub, sid
+			}
+		}
+	}
+
+	return userInfo.Id, userInfo.Sid
+}
+
+func (idp *OidcIdProvider) GetUserInfo(token *oauth2.Token) (*UserInfo, error) {
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, fmt.Errorf("id_token not found in token response")
+	}
+
+	claims, err := idp.ValidateIdToken(rawIdToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// the id_token of many providers only carries the sub, the rest of the profile comes
+	// from the userinfo endpoint
+	discovery, err := GetOidcDiscovery(idp.getHttpClient(), idp.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if discovery.UserinfoEndpoint != "" && token.AccessToken != "" {
+		userinfo := map[string]interface{}{}
+		err = getOidcJson(idp.getHttpClient(), discovery.UserinfoEndpoint, token.AccessToken, &userinfo)
+		if err != nil {
+			return nil, err
+		}
+
+		if userinfo["sub"] != claims["sub"] {
+			return nil, fmt.Errorf("the sub of the userinfo response doesn't match the id_token")
+		}
+		for k, v := range userinfo {
+			claims[k] = v
+		}
+	}
+
+	return getOidcUserInfo(claims, idp.UserMapping)
+}
+
+func getOidcClaimString(claims map[string]interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	value, err := getNestedValue(claims, path)
+	if err != nil {
+		return ""
+	}
+	return oidcClaimToString(value)
+}
+
+func oidcClaimToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// getOidcUserInfo maps the claims to the user with the user mapping of the provider, a field
+// whose mapped claim is absent falls back to the standard claim.
+func getOidcUserInfo(claims map[string]interface{}, userMapping map[string]string) (*UserInfo, error) {
+	getField := func(field string) string {
+		value := getOidcClaimString(claims, userMapping[field])
+		if value == "" {
+			value = getOidcClaimString(claims, oidcStandardClaims[field])
+		}
+		return value
+	}
+
+	userInfo := &UserInfo{
+		Id:          getField("id"),
+		Username:    getField("username"),
+		DisplayName: getField("displayName"),
+		Email:       getField("email"),
+		Phone:       getField("phone"),
+		AvatarUrl:   getField("avatarUrl"),
+		Extra:       map[string]string{},
+	}
+	if userInfo.Id == "" {
+		return nil, fmt.Errorf("the id_token is missing the sub")
+	}
+	if userInfo.Username == "" {
+		userInfo.Username = userInfo.Id
+	}
+
+	userInfo.Sid, _ = claims["sid"].(string)
+
+	// the other mapped fields such as firstName are applied from the claims
+	for k, v := range claims {
+		if oidcProtocolClaims[k] {
+			continue
+		}
+		if value := oidcClaimToString(v); value != "" {
+			userInfo.Extra[k] = value
+		}
+	}
+	return userInfo, nil
+}
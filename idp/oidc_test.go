@@ -0,0 +1,383 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+type oidcTestIssuer struct {
+	server   *httptest.Server
+	mutex    sync.Mutex
+	kid      string
+	key      *rsa.PrivateKey
+	userinfo map[string]interface{}
+}
+
+func newOidcTestIssuer(t *testing.T) *oidcTestIssuer {
+	issuer := &oidcTestIssuer{}
+	issuer.rotateKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(OidcDiscovery{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/authorize",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			UserinfoEndpoint:      issuer.server.URL + "/userinfo",
+			JwksUri:               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mutex.Lock()
+		defer issuer.mutex.Unlock()
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &issuer.key.PublicKey, KeyID: issuer.kid, Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(issuer.userinfo)
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (issuer *oidcTestIssuer) rotateKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer.mutex.Lock()
+	issuer.kid = kid
+	issuer.key = key
+	issuer.mutex.Unlock()
+}
+
+func (issuer *oidcTestIssuer) signIdToken(t *testing.T, claims jwt.MapClaims) string {
+	issuer.mutex.Lock()
+	defer issuer.mutex.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = issuer.kid
+	idToken, err := token.SignedString(issuer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idToken
+}
+
+func (issuer *oidcTestIssuer) newClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   issuer.server.URL,
+		"sub":   "user-1",
+		"aud":   "casdoor",
+		"exp":   now.Add(time.Hour).Unix(),
+		"iat":   now.Unix(),
+		"nonce": "nonce-1",
+	}
+}
+
+func newOidcTestIdProvider(issuer *oidcTestIssuer, userMapping map[string]string) *OidcIdProvider {
+	idp := NewOidcIdProvider(&ProviderInfo{
+		Type:         "OIDC",
+		ClientId:     "casdoor",
+		ClientSecret: "secret",
+		HostUrl:      issuer.server.URL + "/",
+		UserMapping:  userMapping,
+		Nonce:        "nonce-1",
+	}, "https://casdoor.example.com/callback")
+	idp.SetHttpClient(issuer.server.Client())
+	return idp
+}
+
+func TestOidcValidateIdToken(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	idp := newOidcTestIdProvider(issuer, nil)
+
+	_, err := idp.ValidateIdToken(issuer.signIdToken(t, issuer.newClaims()))
+	if err != nil {
+		t.Fatalf("a valid id_token is rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "wrong issuer", modify: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", modify: func(claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+		{name: "multiple audiences without azp", modify: func(claims jwt.MapClaims) { claims["aud"] = []string{"casdoor", "other-client"} }},
+		{name: "wrong azp", modify: func(claims jwt.MapClaims) { claims["azp"] = "other-client" }},
+		{name: "wrong nonce", modify: func(claims jwt.MapClaims) { claims["nonce"] = "nonce-2" }},
+		{name: "missing nonce", modify: func(claims jwt.MapClaims) { delete(claims, "nonce") }},
+		{name: "expired", modify: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "missing expiry", modify: func(claims jwt.MapClaims) { delete(claims, "exp") }},
+	}
+	for _, test := range tests {
+		claims := issuer.newClaims()
+		test.modify(claims)
+		_, err = idp.ValidateIdToken(issuer.signIdToken(t, claims))
+		if err == nil {
+			t.Errorf("an id_token with %s is accepted", test.name)
+		}
+	}
+
+	// an unsigned id_token must never be accepted
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, issuer.newClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = idp.ValidateIdToken(unsigned); err == nil {
+		t.Errorf("an unsigned id_token is accepted")
+	}
+
+	// the payload of a signed id_token is swapped for another one
+	idToken := issuer.signIdToken(t, issuer.newClaims())
+	parts := strings.Split(idToken, ".")
+	forged := issuer.signIdToken(t, jwt.MapClaims{"sub": "admin"})
+	if _, err = idp.ValidateIdToken(parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]); err == nil {
+		t.Errorf("an id_token with a tampered payload is accepted")
+	}
+
+	idp.Nonce = ""
+	if _, err = idp.ValidateIdToken(idToken); err == nil {
+		t.Errorf("an id_token is accepted while the login has no nonce")
+	}
+}
+
+func TestOidcJwksRotation(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	idp := newOidcTestIdProvider(issuer, nil)
+
+	_, err := idp.ValidateIdToken(issuer.signIdToken(t, issuer.newClaims()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// right after a download the JWKS isn't downloaded again for an unknown kid
+	issuer.rotateKey(t, "key-2")
+	idToken := issuer.signIdToken(t, issuer.newClaims())
+	if _, err = idp.ValidateIdToken(idToken); err == nil {
+		t.Fatalf("the JWKS is downloaded again within the minimum refresh interval")
+	}
+
+	oidcJwksCacheMutex.Lock()
+	oidcJwksCache[issuer.server.URL+"/jwks"].fetchedAt = time.Now().Add(-2 * oidcJwksMinRefreshInterval)
+	oidcJwksCacheMutex.Unlock()
+
+	if _, err = idp.ValidateIdToken(idToken); err != nil {
+		t.Errorf("an id_token signed by the rotated key is rejected: %v", err)
+	}
+}
+
+func TestOidcGetUserInfo(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	issuer.userinfo = map[string]interface{}{
+		"sub":                "user-1",
+		"preferred_username": "alice",
+		"name":               "Alice Liddell",
+		"email":              "alice@example.com",
+		"given_name":         "Alice",
+		"profile":            map[string]interface{}{"nickname": "ali"},
+	}
+	idp := newOidcTestIdProvider(issuer, map[string]string{
+		"id":          "id",
+		"displayName": "profile.nickname",
+		"email":       "mail",
+		"firstName":   "given_name",
+	})
+
+	token := (&oauth2.Token{AccessToken: "access-token"}).WithExtra(map[string]interface{}{
+		"id_token": issuer.signIdToken(t, issuer.newClaims()),
+	})
+	userInfo, err := idp.GetUserInfo(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if userInfo.Id != "user-1" || userInfo.Username != "alice" || userInfo.DisplayName != "ali" || userInfo.Email != "alice@example.com" {
+		t.Errorf("unexpected user info: %+v", userInfo)
+	}
+	if userInfo.Extra["given_name"] != "Alice" {
+		t.Errorf("the claims are missing from the extra of the user info: %v", userInfo.Extra)
+	}
+
+	issuer.userinfo["sub"] = "user-2"
+	if _, err = idp.GetUserInfo(token); err == nil {
+		t.Errorf("a userinfo response of another user is accepted")
+	}
+}
+
+func TestOidcGetAuthUrl(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	idp := newOidcTestIdProvider(issuer, nil)
+
+	authUrl, err := idp.GetAuthUrl("profile email", "state-1", "challenge-1", "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := issuer.server.URL + "/authorize?client_id=casdoor&code_challenge=challenge-1&code_challenge_method=S256&nonce=nonce-1" +
+		"&redirect_uri=https%3A%2F%2Fcasdoor.example.com%2Fcallback&response_type=code&scope=openid+profile+email&state=state-1"
+	if authUrl != expected {
+		t.Errorf("GetAuthUrl() = %s, want %s", authUrl, expected)
+	}
+}
+
+func TestOidcValidateLogoutToken(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	idp := newOidcTestIdProvider(issuer, nil)
+
+	newLogoutClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":    issuer.server.URL,
+			"sub":    "user-1",
+			"sid":    "session-1",
+			"aud":    "casdoor",
+			"iat":    time.Now().Unix(),
+			"jti":    "logout-1",
+			"events": map[string]interface{}{OidcBackchannelLogoutEvent: map[string]interface{}{}},
+		}
+	}
+
+	logoutToken, err := idp.ValidateLogoutToken(issuer.signIdToken(t, newLogoutClaims()))
+	if err != nil {
+		t.Fatalf("a valid logout token is rejected: %v", err)
+	}
+	if logoutToken.Sub != "user-1" || logoutToken.Sid != "session-1" || logoutToken.Jti != "logout-1" {
+		t.Errorf("ValidateLogoutToken() = %+v, want user-1, session-1, logout-1", logoutToken)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "wrong issuer", modify: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", modify: func(claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+		{name: "missing iat", modify: func(claims jwt.MapClaims) { delete(claims, "iat") }},
+		{name: "missing jti", modify: func(claims jwt.MapClaims) { delete(claims, "jti") }},
+		{name: "missing events", modify: func(claims jwt.MapClaims) { delete(claims, "events") }},
+		{name: "another event", modify: func(claims jwt.MapClaims) {
+			claims["events"] = map[string]interface{}{"https://example.com/event": map[string]interface{}{}}
+		}},
+		{name: "nonce", modify: func(claims jwt.MapClaims) { claims["nonce"] = "nonce-1" }},
+		{name: "neither sub nor sid", modify: func(claims jwt.MapClaims) { delete(claims, "sub"); delete(claims, "sid") }},
+	}
+	for _, test := range tests {
+		claims := newLogoutClaims()
+		test.modify(claims)
+		if _, err = idp.ValidateLogoutToken(issuer.signIdToken(t, claims)); err == nil {
+			t.Errorf("a logout token with %s is accepted", test.name)
+		}
+	}
+
+	// an id_token must never be accepted as a logout token
+	if _, err = idp.ValidateLogoutToken(issuer.signIdToken(t, issuer.newClaims())); err == nil {
+		t.Errorf("an id_token is accepted as a logout token")
+	}
+}
+
+func TestOidcValidateSecurityEventToken(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	idp := newOidcTestIdProvider(issuer, nil)
+
+	sessionRevokedEvent := "https://schemas.openid.net/secevent/caep/event-type/session-revoked"
+	newSetClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":    issuer.server.URL,
+			"aud":    "casdoor",
+			"iat":    time.Now().Unix(),
+			"jti":    "set-1",
+			"sub_id": map[string]interface{}{"format": "iss_sub", "iss": issuer.server.URL, "sub": "user-1"},
+			"events": map[string]interface{}{sessionRevokedEvent: map[string]interface{}{"event_timestamp": time.Now().Unix()}},
+		}
+	}
+
+	claims, err := idp.ValidateSecurityEventToken(issuer.signIdToken(t, newSetClaims()))
+	if err != nil {
+		t.Fatalf("a valid security event token is rejected: %v", err)
+	}
+	if _, ok := claims["events"].(map[string]interface{})[sessionRevokedEvent]; !ok {
+		t.Errorf("the session revoked event is missing from the claims: %v", claims)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "wrong issuer", modify: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", modify: func(claims jwt.MapClaims) { claims["aud"] = "other-client" }},
+		{name: "missing iat", modify: func(claims jwt.MapClaims) { delete(claims, "iat") }},
+		{name: "missing jti", modify: func(claims jwt.MapClaims) { delete(claims, "jti") }},
+		{name: "missing events", modify: func(claims jwt.MapClaims) { delete(claims, "events") }},
+		{name: "empty events", modify: func(claims jwt.MapClaims) { claims["events"] = map[string]interface{}{} }},
+		{name: "nonce", modify: func(claims jwt.MapClaims) { claims["nonce"] = "nonce-1" }},
+	}
+	for _, test := range tests {
+		claims := newSetClaims()
+		test.modify(claims)
+		if _, err = idp.ValidateSecurityEventToken(issuer.signIdToken(t, claims)); err == nil {
+			t.Errorf("a security event token with %s is accepted", test.name)
+		}
+	}
+
+	// an id_token must never be accepted as a security event token
+	if _, err = idp.ValidateSecurityEventToken(issuer.signIdToken(t, issuer.newClaims())); err == nil {
+		t.Errorf("an id_token is accepted as a security event token")
+	}
+}
+
+func TestGetIdpSession(t *testing.T) {
+	issuer := newOidcTestIssuer(t)
+	claims := issuer.newClaims()
+	claims["sid"] = "session-1"
+
+	// the id of ADFS is the Windows SID, the upstream session is taken from the id_token
+	userInfo := &UserInfo{Id: "S-1-5-21-1004", Sid: ""}
+	token := (&oauth2.Token{AccessToken: "access-token"}).WithExtra(map[string]interface{}{
+		"id_token": issuer.signIdToken(t, claims),
+	})
+	subject, sid := GetIdpSession(token, userInfo)
+	if subject != claims["sub"] || sid != "session-1" {
+		t.Errorf("unexpected upstream session: %s, %s", subject, sid)
+	}
+
+	// without an id_token, such as for SAML, the user info names the session
+	userInfo = &UserInfo{Id: "alice", Sid: "_index"}
+	for _, token = range []*oauth2.Token{nil, {AccessToken: "access-token"}} {
+		subject, sid = GetIdpSession(token, userInfo)
+		if subject != "alice" || sid != "_index" {
+			t.Errorf("unexpected upstream session without an id_token: %s, %s", subject, sid)
+		}
+	}
+}
@@ -32,6 +32,10 @@ type UserInfo struct {
 	CountryCode string
 	AvatarUrl   string
 	Extra       map[string]string
+
+	// Sid is the session at the provider, a SAML session index or an OIDC sid,
+	// which the provider names when it logs the user out
+	Sid string
 }
 
 type ProviderInfo struct {
@@ -46,6 +50,7 @@ type ProviderInfo struct {
 	RedirectUrl   string
 	DisableSsl    bool
 	CodeVerifier  string
+	Nonce         string
 
 	TokenURL    string
 	AuthURL     string
@@ -109,6 +114,8 @@ func GetIdProvider(idpInfo *ProviderInfo, redirectUrl string) (IdProvider, error
 		return NewAlipayIdProvider(idpInfo.ClientId, idpInfo.ClientSecret, redirectUrl, idpInfo.AppCertificate, idpInfo.RootCertificate)
 	case "Custom", "Custom Flexible":
 		return NewCustomIdProvider(idpInfo, redirectUrl), nil
+	case "OIDC":
+		return NewOidcIdProvider(idpInfo, redirectUrl), nil
 	case "Infoflow":
 		if idpInfo.SubType == "Internal" {
 			return NewInfoflowInternalIdProvider(idpInfo.ClientId, idpInfo.ClientSecret, idpInfo.AppId, redirectUrl), nil
@@ -0,0 +1,409 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/lor00x/goldap/message"
+
+	ldap "github.com/casdoor/ldapserver"
+)
+
+const (
+	// RFC 2696
+	controlTypePaging = "1.2.840.113556.1.4.319"
+	// RFC 2891
+	controlTypeSortRequest  = "1.2.840.113556.1.4.473"
+	controlTypeSortResponse = "1.2.840.113556.1.4.474"
+	// draft-ietf-ldapext-psearch
+	controlTypePersistentSearch   = "2.16.840.1.113730.3.4.3"
+	controlTypeEntryChangeNotice  = "2.16.840.1.113730.3.4.7"
+	persistentSearchChangeAdd     = 1
+	persistentSearchChangeModify  = 4
+	persistentSearchChangeTypeAll = 15
+)
+
+// supportedControls are published in the supportedControl attribute of the root DSE.
+var supportedControls = []string{controlTypePaging, controlTypeSortRequest, controlTypePersistentSearch}
+
+type pagingControl struct {
+	size   int
+	cookie []byte
+}
+
+type sortKey struct {
+	attribute string
+	reverse   bool
+}
+
+type persistentSearchControl struct {
+	changeTypes int
+	changesOnly bool
+	returnECs   bool
+}
+
+// searchControls are the request controls of a search that the server understands.
+type searchControls struct {
+	paging             *pagingControl
+	sortKeys           []sortKey
+	isSortCritical     bool
+	persistentSearch   *persistentSearchControl
+	isPersistentSearch bool
+}
+
+func getSearchControls(m *ldap.Message) (*searchControls, error) {
+	res := &searchControls{}
+	if m.Controls() == nil {
+		return res, nil
+	}
+
+	for _, control := range *m.Controls() {
+		var value []byte
+		if control.ControlValue() != nil {
+			value = []byte(*control.ControlValue())
+		}
+
+		var err error
+		switch string(control.ControlType()) {
+		case controlTypePaging:
+			res.paging, err = parsePagingControl(value)
+		case controlTypeSortRequest:
+			res.sortKeys, err = parseSortControl(value)
+			res.isSortCritical = bool(control.Criticality())
+		case controlTypePersistentSearch:
+			res.persistentSearch, err = parsePersistentSearchControl(value)
+			res.isPersistentSearch = true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid control %s: %v", control.ControlType(), err)
+		}
+	}
+	return res, nil
+}
+
+func decodeControlValue(value []byte, minChildren int) (*ber.Packet, error) {
+	packet, err := ber.DecodePacketErr(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(packet.Children) < minChildren {
+		return nil, fmt.Errorf("the value has %d elements instead of %d", len(packet.Children), minChildren)
+	}
+	return packet, nil
+}
+
+func getPacketInt(packet *ber.Packet) (int, error) {
+	value, err := ber.ParseInt64(packet.Data.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return int(value), nil
+}
+
+// parsePagingControl parses realSearchControlValue ::= SEQUENCE { size INTEGER, cookie OCTET STRING }.
+func parsePagingControl(value []byte) (*pagingControl, error) {
+	packet, err := decodeControlValue(value, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := getPacketInt(packet.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("negative page size")
+	}
+	return &pagingControl{size: size, cookie: packet.Children[1].Data.Bytes()}, nil
+}
+
+// parseSortControl parses SortKeyList ::= SEQUENCE OF SEQUENCE { attributeType, orderingRule [0]
+// OPTIONAL, reverseOrder [1] BOOLEAN DEFAULT FALSE }, the ordering rules aren't supported.
+func parseSortControl(value []byte) ([]sortKey, error) {
+	packet, err := decodeControlValue(value, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []sortKey{}
+	for _, child := range packet.Children {
+		if len(child.Children) == 0 {
+			return nil, fmt.Errorf("empty sort key")
+		}
+
+		key := sortKey{attribute: child.Children[0].Data.String()}
+		for _, option := range child.Children[1:] {
+			if option.ClassType == ber.ClassContext && option.Tag == 1 {
+				key.reverse = len(option.Data.Bytes()) > 0 && option.Data.Bytes()[0] != 0
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parsePersistentSearchControl parses PersistentSearch ::= SEQUENCE { changeTypes INTEGER,
+// changesOnly BOOLEAN, returnECs BOOLEAN }.
+func parsePersistentSearchControl(value []byte) (*persistentSearchControl, error) {
+	packet, err := decodeControlValue(value, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	changeTypes, err := getPacketInt(packet.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	if changeTypes <= 0 || changeTypes > persistentSearchChangeTypeAll {
+		return nil, fmt.Errorf("invalid change types: %d", changeTypes)
+	}
+
+	getBool := func(packet *ber.Packet) bool {
+		return len(packet.Data.Bytes()) > 0 && packet.Data.Bytes()[0] != 0
+	}
+	return &persistentSearchControl{
+		changeTypes: changeTypes,
+		changesOnly: getBool(packet.Children[1]),
+		returnECs:   getBool(packet.Children[2]),
+	}, nil
+}
+
+func newControl(controlType string, value *ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Control")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, controlType, "Control Type"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(value.Bytes()), "Control Value"))
+	return packet
+}
+
+func newPagingResponseControl(size int, cookie []byte) *ber.Packet {
+	value := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Paging")
+	value.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, int64(size), "Size"))
+	value.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(cookie), "Cookie"))
+	return newControl(controlTypePaging, value)
+}
+
+func newSortResponseControl(code int, attribute string) *ber.Packet {
+	value := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Sort Result")
+	value.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Sort Result Code"))
+	if attribute != "" {
+		value.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, attribute, "Attribute Type"))
+	}
+	return newControl(controlTypeSortResponse, value)
+}
+
+func newEntryChangeNoticeControl(changeType int) *ber.Packet {
+	value := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Entry Change Notice")
+	value.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(changeType), "Change Type"))
+	return newControl(controlTypeEntryChangeNotice, value)
+}
+
+// controlListener wraps the connections of the LDAP server with controlConn.
+type controlListener struct {
+	net.Listener
+}
+
+func (l *controlListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return newControlConn(conn), nil
+}
+
+func withResponseControls(s *ldap.Server) {
+	s.Listener = &controlListener{Listener: s.Listener}
+}
+
+// controlConn adds response controls to the LDAP messages written by the server, which the
+// ResponseWriter of ldapserver can't do. The handlers register the controls of the next message
+// of a request with expect, then the message is rewritten when it reaches the connection.
+type controlConn struct {
+	net.Conn
+
+	mutex    sync.Mutex
+	expected map[int][][]byte
+
+	writeMutex sync.Mutex
+	buffer     []byte
+}
+
+func newControlConn(conn net.Conn) *controlConn {
+	return &controlConn{
+		Conn:     conn,
+		expected: map[int][][]byte{},
+	}
+}
+
+// expect registers the controls of the next message written for the request, the handler
+// registers every message of the request once it uses it, so that they are matched in order.
+func (c *controlConn) expect(messageID int, controls []*ber.Packet) {
+	var data []byte
+	if len(controls) > 0 {
+		packet := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		for _, control := range controls {
+			packet.AppendChild(control)
+		}
+		data = packet.Bytes()
+	}
+
+	c.mutex.Lock()
+	c.expected[messageID] = append(c.expected[messageID], data)
+	c.mutex.Unlock()
+}
+
+func (c *controlConn) takeControls(messageID int) []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	queue, ok := c.expected[messageID]
+	if !ok {
+		return nil
+	}
+	if len(queue) == 1 {
+		delete(c.expected, messageID)
+	} else {
+		c.expected[messageID] = queue[1:]
+	}
+	return queue[0]
+}
+
+// Write splits the stream into LDAP messages, a message can be split across several writes by
+// the buffered writer of the client.
+func (c *controlConn) Write(b []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	c.buffer = append(c.buffer, b...)
+	for len(c.buffer) > 0 {
+		headerLength, length := getBerLength(c.buffer)
+		if length < 0 {
+			// not a message we understand, pass it through
+			length = len(c.buffer)
+		} else if length > len(c.buffer) {
+			break
+		}
+
+		data := c.buffer[:length]
+		if length > headerLength && headerLength > 0 {
+			data = c.addControls(data, headerLength)
+		}
+		_, err := c.Conn.Write(data)
+		c.buffer = c.buffer[length:]
+		if err != nil {
+			c.buffer = nil
+			return 0, err
+		}
+	}
+
+	c.buffer = append([]byte(nil), c.buffer...)
+	return len(b), nil
+}
+
+// addControls appends the registered controls at the end of the LDAPMessage sequence.
+func (c *controlConn) addControls(data []byte, headerLength int) []byte {
+	content := data[headerLength:]
+	messageID, ok := getMessageID(content)
+	if !ok {
+		return data
+	}
+
+	controls := c.takeControls(messageID)
+	if controls == nil {
+		return data
+	}
+
+	res := []byte{data[0]}
+	res = append(res, encodeBerLength(len(content)+len(controls))...)
+	res = append(res, content...)
+	return append(res, controls...)
+}
+
+// getBerLength returns the length of the header and the total length of the first BER element,
+// the total length is -1 when the element isn't a valid LDAPMessage and 0 when the header is
+// incomplete.
+func getBerLength(data []byte) (int, int) {
+	if data[0] != 0x30 {
+		return 0, -1
+	}
+	if len(data) < 2 {
+		return 0, 0
+	}
+
+	length := int(data[1])
+	if length < 0x80 {
+		return 2, 2 + length
+	}
+
+	n := length & 0x7f
+	if n == 0 || n > 4 {
+		return 0, -1
+	}
+	if len(data) < 2+n {
+		return 0, 0
+	}
+
+	length = 0
+	for _, b := range data[2 : 2+n] {
+		length = length<<8 | int(b)
+	}
+	return 2 + n, 2 + n + length
+}
+
+func getMessageID(content []byte) (int, bool) {
+	if len(content) < 3 || content[0] != byte(ber.TagInteger) || content[1] == 0 || content[1] > 4 || len(content) < 2+int(content[1]) {
+		return 0, false
+	}
+
+	messageID := 0
+	for _, b := range content[2 : 2+int(content[1])] {
+		messageID = messageID<<8 | int(b)
+	}
+	return messageID, true
+}
+
+func encodeBerLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	res := []byte{}
+	for ; length > 0; length >>= 8 {
+		res = append([]byte{byte(length)}, res...)
+	}
+	return append([]byte{0x80 | byte(len(res))}, res...)
+}
+
+// controlWriter writes the responses of a request with their response controls.
+type controlWriter struct {
+	w         ldap.ResponseWriter
+	conn      *controlConn
+	messageID int
+}
+
+func newControlWriter(w ldap.ResponseWriter, m *ldap.Message) *controlWriter {
+	conn, _ := m.Client.GetConn().(*controlConn)
+	return &controlWriter{w: w, conn: conn, messageID: m.MessageID().Int()}
+}
+
+func (cw *controlWriter) Write(po message.ProtocolOp, controls ...*ber.Packet) {
+	if cw.conn != nil {
+		cw.conn.expect(cw.messageID, controls)
+	}
+	cw.w.Write(po)
+}
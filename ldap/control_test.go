@@ -0,0 +1,107 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+func buildSearchResultDone(messageID int64) []byte {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	done := ber.Encode(ber.ClassApplication, ber.TypeConstructed, 5, nil, "Search Result Done")
+	done.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, 0, "Result Code"))
+	done.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	done.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	packet.AppendChild(done)
+	return packet.Bytes()
+}
+
+func TestControlConn(t *testing.T) {
+	server, client := net.Pipe()
+	conn := newControlConn(server)
+
+	conn.expect(3, nil)
+	conn.expect(3, []*ber.Packet{newPagingResponseControl(0, []byte("next"))})
+
+	go func() {
+		_, _ = conn.Write(buildSearchResultDone(3))
+		// the buffered writer can split a message
+		data := buildSearchResultDone(3)
+		_, _ = conn.Write(data[:5])
+		_, _ = conn.Write(data[5:])
+		_, _ = conn.Write(buildSearchResultDone(4))
+	}()
+
+	packet, err := ber.ReadPacket(client)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(packet.Children))
+
+	packet, err = ber.ReadPacket(client)
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(packet.Children)) {
+		control, err := goldap.DecodeControl(packet.Children[2].Children[0])
+		assert.Nil(t, err)
+		paging, ok := control.(*goldap.ControlPaging)
+		if assert.True(t, ok) {
+			assert.Equal(t, []byte("next"), paging.Cookie)
+		}
+	}
+
+	packet, err = ber.ReadPacket(client)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(packet.Children))
+	assert.Empty(t, conn.expected)
+}
+
+func TestParseSearchControls(t *testing.T) {
+	paging := goldap.NewControlPaging(100)
+	paging.SetCookie(newPagingCookie(200, 7))
+	res, err := parsePagingControl(paging.Encode().Children[1].Data.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, 100, res.size)
+
+	offset, err := parsePagingCookie(res.cookie, 7)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, offset)
+	_, err = parsePagingCookie(res.cookie, 8)
+	assert.NotNil(t, err)
+
+	sorting := goldap.NewControlServerSideSortingWithSortKeys([]*goldap.SortKey{{AttributeType: "mail", Reverse: true}})
+	keys, err := parseSortControl(sorting.Encode().Children[1].Data.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, []sortKey{{attribute: "mail", reverse: true}}, keys)
+
+	field, order, err := getSortField(keys)
+	assert.Nil(t, err)
+	assert.Equal(t, "email", field)
+	assert.Equal(t, "descend", order)
+
+	_, _, err = getSortField([]sortKey{{attribute: "userPassword"}})
+	assert.NotNil(t, err)
+}
+
+func TestGetPositiveMin(t *testing.T) {
+	assert.Equal(t, 10, getPositiveMin(0, 10))
+	assert.Equal(t, 10, getPositiveMin(10, 0))
+	assert.Equal(t, 5, getPositiveMin(10, 5))
+	assert.Equal(t, 0, getPositiveMin(0, 0))
+}
@@ -0,0 +1,376 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/lor00x/goldap/message"
+
+	ldap "github.com/casdoor/ldapserver"
+)
+
+const (
+	// ldapSearchBatchSize is the number of rows read at once by a search without paging
+	ldapSearchBatchSize      = 1000
+	persistentSearchInterval = time.Second * 5
+)
+
+// userSearchRun is a user search being answered, it writes the entries and enforces the limits.
+type userSearchRun struct {
+	m        *ldap.Message
+	r        message.SearchRequest
+	cw       *controlWriter
+	search   *userSearch
+	attrs    []string
+	orgCache map[string]*object.Organization
+
+	sizeLimit int
+	deadline  time.Time
+	sortField string
+	sortOrder string
+}
+
+func getPositiveMin(a int, b int) int {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// getSearchLimits returns the size and time limits of the search, the limits of the organization
+// cap those requested by the client.
+func getSearchLimits(m *ldap.Message, r message.SearchRequest, owner string) (int, int) {
+	if owner == "" {
+		owner = m.Client.OrgName
+	}
+
+	sizeLimit, timeLimit := int(r.SizeLimit()), int(r.TimeLimit())
+	organization, err := object.GetOrganization(util.GetId("admin", owner))
+	if err != nil {
+		log.Printf("getSearchLimits() failed to get organization %s, err = %v", owner, err)
+	}
+	if organization != nil {
+		sizeLimit = getPositiveMin(sizeLimit, organization.LdapSizeLimit)
+		timeLimit = getPositiveMin(timeLimit, organization.LdapTimeLimit)
+	}
+	return sizeLimit, timeLimit
+}
+
+// getSortField maps the sort keys onto a column of the user table, only a single key is supported.
+func getSortField(keys []sortKey) (string, string, error) {
+	if len(keys) != 1 {
+		return "", "", fmt.Errorf("only a single sort key is supported")
+	}
+
+	rel, ok := ldapAttributesMapping[keys[0].attribute]
+	if !ok {
+		rel, ok = ldapAttributesMapping[strings.ToLower(keys[0].attribute)]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("attribute %s can't be sorted", keys[0].attribute)
+	}
+
+	field, err := rel.GetField()
+	if err != nil {
+		return "", "", err
+	}
+
+	sortOrder := "ascend"
+	if keys[0].reverse {
+		sortOrder = "descend"
+	}
+	return util.CamelToSnakeCase(field), sortOrder, nil
+}
+
+// getSearchSignature identifies a search, the paging cookies are only valid for the same search.
+func getSearchSignature(m *ldap.Message, r message.SearchRequest, controls *searchControls) uint32 {
+	return hash(fmt.Sprintf("%s/%s|%s|%d|%s|%v", m.Client.OrgName, m.Client.UserName, r.BaseObject(), r.Scope(), r.FilterString(), controls.sortKeys))
+}
+
+// newPagingCookie encodes the offset of the next page followed by the signature of the search.
+func newPagingCookie(offset int, signature uint32) []byte {
+	cookie := binary.BigEndian.AppendUint64(nil, uint64(offset))
+	return binary.BigEndian.AppendUint32(cookie, signature)
+}
+
+func parsePagingCookie(cookie []byte, signature uint32) (int, error) {
+	if len(cookie) == 0 {
+		return 0, nil
+	}
+	if len(cookie) != 12 || binary.BigEndian.Uint32(cookie[8:]) != signature {
+		return 0, fmt.Errorf("invalid paging cookie")
+	}
+	return int(binary.BigEndian.Uint64(cookie[:8])), nil
+}
+
+func isSearchAbandoned(m *ldap.Message) bool {
+	select {
+	case <-m.Done:
+		return true
+	default:
+		return false
+	}
+}
+
+func handleUserSearch(w ldap.ResponseWriter, m *ldap.Message, r message.SearchRequest) {
+	cw := newControlWriter(w, m)
+	res := ldap.NewSearchResultDoneResponse(ldap.LDAPResultSuccess)
+
+	controls, err := getSearchControls(m)
+	if err != nil {
+		log.Printf("handleSearch: %v", err)
+		res.SetResultCode(ldap.LDAPResultProtocolError)
+		cw.Write(res)
+		return
+	}
+
+	search, code := getUserSearch(m)
+	if code != ldap.LDAPResultSuccess {
+		res.SetResultCode(code)
+		cw.Write(res)
+		return
+	}
+
+	s := &userSearchRun{
+		m:        m,
+		r:        r,
+		cw:       cw,
+		search:   search,
+		attrs:    resolveRequestAttributes(r.Attributes()),
+		orgCache: map[string]*object.Organization{},
+	}
+
+	sizeLimit, timeLimit := getSearchLimits(m, r, search.owner)
+	s.sizeLimit = sizeLimit
+	if timeLimit > 0 {
+		s.deadline = time.Now().Add(time.Duration(timeLimit) * time.Second)
+	}
+
+	responseControls := []*ber.Packet{}
+	if controls.sortKeys != nil {
+		s.sortField, s.sortOrder, err = getSortField(controls.sortKeys)
+		if err != nil {
+			sortControl := newSortResponseControl(ldap.LDAPResultUnwillingToPerform, controls.sortKeys[0].attribute)
+			log.Printf("handleSearch: %v", err)
+			if controls.isSortCritical {
+				res.SetResultCode(ldap.LDAPResultUnavailableCriticalExtension)
+				cw.Write(res, sortControl)
+				return
+			}
+			responseControls = append(responseControls, sortControl)
+		} else {
+			responseControls = append(responseControls, newSortResponseControl(ldap.LDAPResultSuccess, ""))
+		}
+	}
+
+	if controls.paging != nil {
+		if controls.isPersistentSearch {
+			// paged results can't be combined with a persistent search
+			res.SetResultCode(ldap.LDAPResultUnwillingToPerform)
+			cw.Write(res)
+			return
+		}
+
+		s.writePage(res, controls, responseControls)
+		return
+	}
+
+	if controls.isPersistentSearch {
+		s.runPersistentSearch(res, controls.persistentSearch)
+		return
+	}
+
+	code, abandoned := s.writeAll()
+	if abandoned {
+		return
+	}
+	res.SetResultCode(code)
+	cw.Write(res, responseControls...)
+}
+
+func (s *userSearchRun) writeUser(user *object.User, controls ...*ber.Packet) {
+	if _, ok := s.orgCache[user.Owner]; !ok {
+		org, err := object.GetOrganizationByUser(user)
+		if err != nil {
+			log.Printf("handleSearch: failed to get organization for user %s: %v", user.Name, err)
+		}
+		s.orgCache[user.Owner] = org
+	}
+	org := s.orgCache[user.Owner]
+
+	e := buildUserSearchEntry(user, string(s.r.BaseObject()), s.attrs, org)
+	s.cw.Write(e, controls...)
+}
+
+func (s *userSearchRun) isTimeLimitExceeded() bool {
+	return !s.deadline.IsZero() && time.Now().After(s.deadline)
+}
+
+// writeAll writes the users a batch at a time until the size or the time limit is reached.
+func (s *userSearchRun) writeAll() (int, bool) {
+	count := 0
+	for offset := 0; ; offset += ldapSearchBatchSize {
+		users, rows, err := s.search.getUsers("", offset, ldapSearchBatchSize, s.sortField, s.sortOrder)
+		if err != nil {
+			log.Printf("handleSearch: failed to get users, err = %v", err)
+			return ldap.LDAPResultOperationsError, false
+		}
+
+		for _, user := range users {
+			if isSearchAbandoned(s.m) {
+				return ldap.LDAPResultSuccess, true
+			}
+			if s.sizeLimit > 0 && count >= s.sizeLimit {
+				return ldap.LDAPResultSizeLimitExceeded, false
+			}
+			if s.isTimeLimitExceeded() {
+				return ldap.LDAPResultTimeLimitExceeded, false
+			}
+
+			s.writeUser(user)
+			count++
+		}
+
+		if rows < ldapSearchBatchSize {
+			return ldap.LDAPResultSuccess, false
+		}
+	}
+}
+
+// writePage answers a search with the Simple Paged Results control. The cookie holds the offset
+// of the next page in the rows of the query, so a page can hold fewer entries than its size when
+// some rows are discarded by the in-memory part of the filter. The size limit of the organization
+// caps the size of the pages.
+func (s *userSearchRun) writePage(res message.SearchResultDone, controls *searchControls, responseControls []*ber.Packet) {
+	signature := getSearchSignature(s.m, s.r, controls)
+	offset, err := parsePagingCookie(controls.paging.cookie, signature)
+	if err != nil {
+		log.Printf("handleSearch: %v", err)
+		res.SetResultCode(ldap.LDAPResultUnwillingToPerform)
+		s.cw.Write(res, append(responseControls, newPagingResponseControl(0, nil))...)
+		return
+	}
+
+	// a size of 0 abandons the paged search
+	pageSize := controls.paging.size
+	if pageSize == 0 {
+		s.cw.Write(res, append(responseControls, newPagingResponseControl(0, nil))...)
+		return
+	}
+	pageSize = getPositiveMin(pageSize, s.sizeLimit)
+
+	users, rows, err := s.search.getUsers("", offset, pageSize, s.sortField, s.sortOrder)
+	if err != nil {
+		log.Printf("handleSearch: failed to get users, err = %v", err)
+		res.SetResultCode(ldap.LDAPResultOperationsError)
+		s.cw.Write(res, append(responseControls, newPagingResponseControl(0, nil))...)
+		return
+	}
+
+	for _, user := range users {
+		if isSearchAbandoned(s.m) {
+			return
+		}
+		if s.isTimeLimitExceeded() {
+			res.SetResultCode(ldap.LDAPResultTimeLimitExceeded)
+			s.cw.Write(res, append(responseControls, newPagingResponseControl(0, nil))...)
+			return
+		}
+
+		s.writeUser(user)
+	}
+
+	var cookie []byte
+	if rows == pageSize {
+		cookie = newPagingCookie(offset+rows, signature)
+	}
+	s.cw.Write(res, append(responseControls, newPagingResponseControl(0, cookie))...)
+}
+
+// runPersistentSearch sends the users as they are added or modified until the search is
+// abandoned. The changes are found by polling the update time of the users, so deleted users
+// aren't reported.
+func (s *userSearchRun) runPersistentSearch(res message.SearchResultDone, control *persistentSearchControl) {
+	since := util.GetCurrentTime()
+
+	if !control.changesOnly {
+		code, abandoned := s.writeAll()
+		if abandoned {
+			return
+		}
+		if code != ldap.LDAPResultSuccess {
+			res.SetResultCode(code)
+			s.cw.Write(res)
+			return
+		}
+	}
+
+	// the update times have a precision of a second, sent remembers the users already sent for
+	// the second the next poll starts from
+	sent := map[string]string{}
+	ticker := time.NewTicker(persistentSearchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.m.Done:
+			return
+		case <-ticker.C:
+		}
+
+		now := util.GetCurrentTime()
+		users, _, err := s.search.getUsers(since, 0, -1, "updated_time", "ascend")
+		if err != nil {
+			log.Printf("handleSearch: failed to poll the persistent search, err = %v", err)
+			continue
+		}
+
+		for _, user := range users {
+			if sent[user.GetId()] == user.UpdatedTime {
+				continue
+			}
+			sent[user.GetId()] = user.UpdatedTime
+
+			changeType := persistentSearchChangeModify
+			if user.CreatedTime >= since {
+				changeType = persistentSearchChangeAdd
+			}
+			if control.changeTypes&changeType == 0 {
+				continue
+			}
+
+			if control.returnECs {
+				s.writeUser(user, newEntryChangeNoticeControl(changeType))
+			} else {
+				s.writeUser(user)
+			}
+		}
+
+		since = now
+		for id, updatedTime := range sent {
+			if updatedTime < since {
+				delete(sent, id)
+			}
+		}
+	}
+}
@@ -37,6 +37,10 @@ func StartLdapServer() {
 
 	routes.Bind(handleBind)
 	routes.Search(handleSearch).Label(" SEARCH****")
+	routes.Add(handleAdd).Label(" ADD****")
+	routes.Modify(handleModify).Label(" MODIFY****")
+	routes.Delete(handleDelete).Label(" DELETE****")
+	routes.Extended(handlePasswordModify).RequestName(ldap.NoticeOfPasswordModify).Label(" PASSWORD MODIFY****")
 
 	server.Handle(routes)
 	serverSsl.Handle(routes)
@@ -44,7 +48,7 @@ func StartLdapServer() {
 		if ldapServerPort == "" || ldapServerPort == "0" {
 			return
 		}
-		err := server.ListenAndServe("0.0.0.0:" + ldapServerPort)
+		err := server.ListenAndServe("0.0.0.0:"+ldapServerPort, withResponseControls)
 		if err != nil {
 			log.Printf("StartLdapServer() failed, err = %s", err.Error())
 		}
@@ -66,7 +70,7 @@ func StartLdapServer() {
 		secureConn := func(s *ldap.Server) {
 			s.Listener = tls.NewListener(s.Listener, config)
 		}
-		err = serverSsl.ListenAndServe("0.0.0.0:"+ldapsServerPort, secureConn)
+		err = serverSsl.ListenAndServe("0.0.0.0:"+ldapsServerPort, secureConn, withResponseControls)
 		if err != nil {
 			log.Printf("StartLdapsServer() failed, err = %s", err.Error())
 		}
@@ -199,29 +203,7 @@ func handleSearch(w ldap.ResponseWriter, m *ldap.Message) {
 		return
 	}
 
-	users, code := GetFilteredUsers(m)
-	if code != ldap.LDAPResultSuccess {
-		res.SetResultCode(code)
-		w.Write(res)
-		return
-	}
-
-	orgCache := make(map[string]*object.Organization)
-
-	for _, user := range users {
-		if _, ok := orgCache[user.Owner]; !ok {
-			org, err := object.GetOrganizationByUser(user)
-			if err != nil {
-				log.Printf("handleSearch: failed to get organization for user %s: %v", user.Name, err)
-			}
-			orgCache[user.Owner] = org
-		}
-		org := orgCache[user.Owner]
-
-		e := buildUserSearchEntry(user, string(r.BaseObject()), resolveRequestAttributes(r.Attributes()), org)
-		w.Write(e)
-	}
-	w.Write(res)
+	handleUserSearch(w, m, r)
 }
 
 // resolveRequestAttributes expands the "*" wildcard to the full list of additional LDAP attributes.
@@ -361,6 +343,14 @@ func handleRootSearch(w ldap.ResponseWriter, r *message.SearchRequest, res *mess
 			}
 			e.AddAttribute("namingContexts", dnlist...)
 			w.Write(e)
+		} else if strings.EqualFold(firstAttr, "supportedControl") {
+			e := ldap.NewSearchResultEntry(string(r.BaseObject()))
+			controls := make([]message.AttributeValue, len(supportedControls))
+			for i, control := range supportedControls {
+				controls[i] = message.AttributeValue(control)
+			}
+			e.AddAttribute("supportedControl", controls...)
+			w.Write(e)
 		} else if strings.EqualFold(firstAttr, "subschemaSubentry") {
 			e := ldap.NewSearchResultEntry(string(r.BaseObject()))
 			e.AddAttribute("subschemaSubentry", message.AttributeValue("cn=Subschema"))
@@ -436,8 +436,17 @@ func buildSafeFilter(filter interface{}) *userSearchFilter {
 	return q
 }
 
-func GetFilteredUsers(m *ldap.Message) (filteredUsers []*object.User, code int) {
-	var err error
+// userSearch is a user search resolved against the rights of the bound user, its users are read
+// from the database a page at a time.
+type userSearch struct {
+	owner  string
+	tag    string
+	filter *userSearchFilter
+	// user is set when the search targets a single user
+	user *object.User
+}
+
+func getUserSearch(m *ldap.Message) (*userSearch, int) {
 	r := m.GetSearchRequest()
 
 	name, org, code := getNameAndOrgFromFilter(string(r.BaseObject()), r.FilterString())
@@ -449,19 +458,10 @@ func GetFilteredUsers(m *ldap.Message) (filteredUsers []*object.User, code int)
 
 	if name == "*" { // get all users from organization 'org'
 		if m.Client.IsGlobalAdmin && org == "*" {
-			filteredUsers, err = object.GetGlobalUsersWithFilter(filter.condition)
-			if err != nil {
-				panic(err)
-			}
-			return filter.apply(filteredUsers), ldap.LDAPResultSuccess
+			return &userSearch{filter: filter}, ldap.LDAPResultSuccess
 		}
 		if m.Client.IsGlobalAdmin || (m.Client.IsOrgAdmin && org == m.Client.OrgName) {
-			filteredUsers, err = object.GetUsersWithFilter(org, filter.condition)
-			if err != nil {
-				panic(err)
-			}
-
-			return filter.apply(filteredUsers), ldap.LDAPResultSuccess
+			return &userSearch{owner: org, filter: filter}, ldap.LDAPResultSuccess
 		} else {
 			return nil, ldap.LDAPResultInsufficientAccessRights
 		}
@@ -481,11 +481,7 @@ func GetFilteredUsers(m *ldap.Message) (filteredUsers []*object.User, code int)
 		}
 
 		if user != nil {
-			if !filter.matches(user) {
-				return nil, ldap.LDAPResultSuccess
-			}
-			filteredUsers = append(filteredUsers, user)
-			return filteredUsers, ldap.LDAPResultSuccess
+			return &userSearch{owner: org, filter: filter, user: user}, ldap.LDAPResultSuccess
 		}
 
 		organization, err := object.GetOrganization(util.GetId("admin", org))
@@ -501,14 +497,59 @@ func GetFilteredUsers(m *ldap.Message) (filteredUsers []*object.User, code int)
 			return nil, ldap.LDAPResultNoSuchObject
 		}
 
-		users, err := object.GetUsersByTagWithFilter(org, name, filter.condition)
-		if err != nil {
-			panic(err)
+		return &userSearch{owner: org, tag: name, filter: filter}, ldap.LDAPResultSuccess
+	}
+}
+
+// getUsers reads limit rows from offset and returns the users among them that match the filter,
+// with the number of rows read. since keeps the users updated at or after that time.
+func (s *userSearch) getUsers(since string, offset, limit int, sortField, sortOrder string) ([]*object.User, int, error) {
+	if s.user != nil {
+		if offset > 0 {
+			return nil, 0, nil
+		}
+
+		user := s.user
+		if since != "" {
+			var err error
+			user, err = object.GetUser(s.user.GetId())
+			if err != nil {
+				return nil, 0, err
+			}
+			if user == nil || user.UpdatedTime < since {
+				return nil, 1, nil
+			}
 		}
 
-		filteredUsers = append(filteredUsers, filter.apply(users)...)
-		return filteredUsers, ldap.LDAPResultSuccess
+		if !s.filter.matches(user) {
+			return nil, 1, nil
+		}
+		return []*object.User{user}, 1, nil
+	}
+
+	cond := s.filter.condition
+	if since != "" {
+		cond = builder.And(cond, builder.Gte{"updated_time": since})
+	}
+
+	users, err := object.GetPaginationUsersWithFilter(s.owner, s.tag, cond, offset, limit, sortField, sortOrder)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.filter.apply(users), len(users), nil
+}
+
+func GetFilteredUsers(m *ldap.Message) (filteredUsers []*object.User, code int) {
+	search, code := getUserSearch(m)
+	if code != ldap.LDAPResultSuccess {
+		return nil, code
+	}
+
+	filteredUsers, _, err := search.getUsers("", 0, -1, "", "")
+	if err != nil {
+		panic(err)
 	}
+	return filteredUsers, ldap.LDAPResultSuccess
 }
 
 // GetFilteredGroups returns the groups of the organization the search is scoped
@@ -0,0 +1,793 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/lor00x/goldap/message"
+
+	ldap "github.com/casdoor/ldapserver"
+)
+
+// ldapError is an error of a write operation with the LDAP result code to answer.
+type ldapError struct {
+	code int
+	msg  string
+}
+
+func (e *ldapError) Error() string {
+	return e.msg
+}
+
+func newLdapError(code int, format string, a ...interface{}) *ldapError {
+	return &ldapError{code: code, msg: fmt.Sprintf(format, a...)}
+}
+
+func getLdapErrorCode(err error) int {
+	if e, ok := err.(*ldapError); ok {
+		return e.code
+	}
+	return ldap.LDAPResultOther
+}
+
+// ldapUserAttribute is a user attribute that the write operations can change.
+type ldapUserAttribute struct {
+	columns     []string
+	multiValued bool
+	get         func(user *object.User) []string
+	set         func(user *object.User, values []string) error
+}
+
+func newLdapStringAttribute(column string, field func(user *object.User) *string) ldapUserAttribute {
+	return ldapUserAttribute{
+		columns: []string{column},
+		get: func(user *object.User) []string {
+			if value := *field(user); value != "" {
+				return []string{value}
+			}
+			return nil
+		},
+		set: func(user *object.User, values []string) error {
+			*field(user) = strings.Join(values, "")
+			return nil
+		},
+	}
+}
+
+func newLdapPropertyAttribute(key string) ldapUserAttribute {
+	return ldapUserAttribute{
+		columns: []string{"properties"},
+		get: func(user *object.User) []string {
+			if value := user.Properties[key]; value != "" {
+				return []string{value}
+			}
+			return nil
+		},
+		set: func(user *object.User, values []string) error {
+			if user.Properties == nil {
+				user.Properties = map[string]string{}
+			}
+			if len(values) == 0 {
+				delete(user.Properties, key)
+			} else {
+				user.Properties[key] = values[0]
+			}
+			return nil
+		},
+	}
+}
+
+// ldapUserAttributes are the writable attributes of the user entries, keyed by lowercased name.
+// They mirror the attributes published by ldapAttributesMapping.
+var ldapUserAttributes = map[string]ldapUserAttribute{
+	"displayname": newLdapStringAttribute("display_name", func(user *object.User) *string { return &user.DisplayName }),
+	"gecos":       newLdapStringAttribute("display_name", func(user *object.User) *string { return &user.DisplayName }),
+	"mail":        newLdapStringAttribute("email", func(user *object.User) *string { return &user.Email }),
+	"email":       newLdapStringAttribute("email", func(user *object.User) *string { return &user.Email }),
+	"mobile":      newLdapStringAttribute("phone", func(user *object.User) *string { return &user.Phone }),
+	"sn":          newLdapStringAttribute("last_name", func(user *object.User) *string { return &user.LastName }),
+	"givenname":   newLdapStringAttribute("first_name", func(user *object.User) *string { return &user.FirstName }),
+	"title":       newLdapStringAttribute("tag", func(user *object.User) *string { return &user.Tag }),
+	"c":           newLdapStringAttribute("region", func(user *object.User) *string { return &user.Region }),
+	"co":          newLdapStringAttribute("region", func(user *object.User) *string { return &user.Region }),
+	"userpassword": {
+		columns: []string{"password", "password_salt", "password_type", "need_update_password", "last_change_password_time", "password_history"},
+		get: func(user *object.User) []string {
+			return nil
+		},
+		set: func(user *object.User, values []string) error {
+			if len(values) == 0 {
+				return newLdapError(ldap.LDAPResultUnwillingToPerform, "userPassword can't be removed")
+			}
+			user.Password = values[0]
+			return nil
+		},
+	},
+	"uidnumber": {
+		columns: []string{"uid_number"},
+		get: func(user *object.User) []string {
+			if user.UidNumber != 0 {
+				return []string{strconv.Itoa(user.UidNumber)}
+			}
+			return nil
+		},
+		set: func(user *object.User, values []string) error {
+			user.UidNumber = 0
+			if len(values) == 0 {
+				return nil
+			}
+
+			uidNumber, err := strconv.Atoi(values[0])
+			if err != nil || uidNumber <= 0 {
+				return newLdapError(ldap.LDAPResultInvalidAttributeSyntax, "invalid uidNumber: %s", values[0])
+			}
+			user.UidNumber = uidNumber
+			return nil
+		},
+	},
+	"loginshell":   newLdapPropertyAttribute("loginShell"),
+	"sshpublickey": newLdapPropertyAttribute("sshPublicKey"),
+	"memberof": {
+		columns:     []string{"groups"},
+		multiValued: true,
+		get: func(user *object.User) []string {
+			return user.Groups
+		},
+		set: func(user *object.User, values []string) error {
+			groups := make([]string, 0, len(values))
+			for _, value := range values {
+				groupId, err := getGroupIdFromDN(value, user.Owner)
+				if err != nil {
+					return err
+				}
+				groups = append(groups, groupId)
+			}
+			user.Groups = groups
+			return nil
+		},
+	},
+}
+
+// ldapComputedAttributes are published by the server from other fields, they are ignored when an
+// entry is added and can't be modified.
+var ldapComputedAttributes = []string{"objectclass", "cn", "uid", "gidnumber", "homedirectory"}
+
+// getGroupIdFromDN converts a memberOf value back into a Casdoor group id, it accepts the DN
+// built by groupToDN as well as a plain group id or name. The group must belong to the
+// organization of the user, the write permission is only checked on that organization.
+func getGroupIdFromDN(value string, owner string) (string, error) {
+	groupId := util.GetId(owner, value)
+	if strings.Contains(value, "=") {
+		name, org, err := getNameAndOrgFromDN(value)
+		if err != nil {
+			return "", newLdapError(ldap.LDAPResultInvalidAttributeSyntax, "invalid group DN: %s", value)
+		}
+		if !strings.Contains(strings.ToLower(value), "ou=") {
+			org = owner
+		}
+		groupId = util.GetId(org, name)
+	} else if strings.Contains(value, "/") {
+		groupId = value
+	}
+
+	org, _ := util.GetOwnerAndNameFromIdNoCheck(groupId)
+	if org != owner {
+		return "", newLdapError(ldap.LDAPResultInsufficientAccessRights, "group %s doesn't belong to the organization %s", value, owner)
+	}
+	return groupId, nil
+}
+
+// applyLdapModification returns the values of an attribute after a Modify change.
+func applyLdapModification(current []string, operation int, values []string) []string {
+	switch operation {
+	case message.ModifyRequestChangeOperationAdd:
+		res := append([]string{}, current...)
+		for _, value := range values {
+			if !util.InSlice(res, value) {
+				res = append(res, value)
+			}
+		}
+		return res
+	case message.ModifyRequestChangeOperationDelete:
+		if len(values) == 0 {
+			return []string{}
+		}
+		res := []string{}
+		for _, value := range current {
+			if !util.InSlice(values, value) {
+				res = append(res, value)
+			}
+		}
+		return res
+	default:
+		return append([]string{}, values...)
+	}
+}
+
+func getAttributeValues(vals []message.AttributeValue) []string {
+	values := make([]string, 0, len(vals))
+	for _, val := range vals {
+		values = append(values, string(val))
+	}
+	return values
+}
+
+// setUserAttribute changes an attribute of the user and returns the columns to update.
+func setUserAttribute(user *object.User, name string, operation int, values []string) ([]string, error) {
+	name = strings.ToLower(name)
+	if util.InSlice(ldapComputedAttributes, name) {
+		return nil, newLdapError(ldap.LDAPResultConstraintViolation, "attribute %s can't be modified", name)
+	}
+
+	attribute, ok := ldapUserAttributes[name]
+	if !ok {
+		return nil, newLdapError(ldap.LDAPResultUndefinedAttributeType, "attribute %s is not supported", name)
+	}
+
+	values = applyLdapModification(attribute.get(user), operation, values)
+	if !attribute.multiValued && len(values) > 1 {
+		return nil, newLdapError(ldap.LDAPResultConstraintViolation, "attribute %s is single-valued", name)
+	}
+
+	err := attribute.set(user, values)
+	if err != nil {
+		return nil, err
+	}
+	return attribute.columns, nil
+}
+
+// checkWritePermission tells whether the bound user can change the entries of the organization.
+func checkWritePermission(m *ldap.Message, org string) int {
+	if !m.Client.IsAuthenticated {
+		return ldap.LDAPResultUnwillingToPerform
+	}
+	if m.Client.IsGlobalAdmin || (m.Client.IsOrgAdmin && org == m.Client.OrgName) {
+		return ldap.LDAPResultSuccess
+	}
+	return ldap.LDAPResultInsufficientAccessRights
+}
+
+// getEntryFromDN returns the user or the group that a DN refers to, the users take precedence
+// since both are named by cn.
+func getEntryFromDN(dn string) (*object.User, *object.Group, error) {
+	name, org, err := getNameAndOrgFromDN(dn)
+	if err != nil {
+		return nil, nil, newLdapError(ldap.LDAPResultInvalidDNSyntax, "%s", err.Error())
+	}
+
+	user, err := object.GetUser(util.GetId(org, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	if user != nil {
+		return user, nil, nil
+	}
+
+	group, err := object.GetGroup(util.GetId(org, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	if group == nil {
+		return nil, nil, newLdapError(ldap.LDAPResultNoSuchObject, "entry %s doesn't exist", dn)
+	}
+	return nil, group, nil
+}
+
+// updateUserPassword hashes the new password of a user, as SetPassword does. The old user holds
+// the current password which is checked against the reuse policy and moved to the password history.
+func updateUserPassword(oldUser *object.User, user *object.User, isAdmin bool) error {
+	msg := object.CheckPasswordComplexity(user, user.Password, "en")
+	if msg != "" {
+		return newLdapError(ldap.LDAPResultConstraintViolation, "%s", msg)
+	}
+
+	organization, err := object.GetOrganizationByUser(user)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return fmt.Errorf("the organization: %s is not found", user.Owner)
+	}
+
+	err = object.CheckPasswordReuse(oldUser, user.Password, organization, "en")
+	if err == nil && !isAdmin {
+		err = object.CheckPasswordMinAge(oldUser, organization, "en")
+	}
+	if err != nil {
+		return newLdapError(ldap.LDAPResultConstraintViolation, "%s", err.Error())
+	}
+
+	history := *oldUser
+	history.AddPasswordHistory(organization)
+	user.PasswordHistory = history.PasswordHistory
+
+	user.UpdateUserPassword(organization)
+	user.NeedUpdatePassword = false
+	user.LastChangePasswordTime = util.GetCurrentTime()
+	return nil
+}
+
+func handleAdd(w ldap.ResponseWriter, m *ldap.Message) {
+	res := ldap.NewAddResponse(ldap.LDAPResultSuccess)
+	r := m.GetAddRequest()
+
+	err := addEntry(m, string(r.Entry()), r.Attributes())
+	if err != nil {
+		log.Printf("handleAdd() failed to add %s, err = %v", string(r.Entry()), err)
+		res.SetResultCode(getLdapErrorCode(err))
+	}
+	w.Write(res)
+}
+
+// addEntry adds a group when the entry is a posixGroup or a groupOfNames, otherwise a user.
+func addEntry(m *ldap.Message, dn string, attributeList message.AttributeList) error {
+	name, org, err := getNameAndOrgFromDN(dn)
+	if err != nil {
+		return newLdapError(ldap.LDAPResultInvalidDNSyntax, "%s", err.Error())
+	}
+
+	if code := checkWritePermission(m, org); code != ldap.LDAPResultSuccess {
+		return newLdapError(code, "the bound user can't add entries to %s", org)
+	}
+
+	attributes := map[string][]string{}
+	for _, attribute := range attributeList {
+		attributes[strings.ToLower(string(attribute.Type_()))] = getAttributeValues(attribute.Vals())
+	}
+
+	for _, objectClass := range attributes["objectclass"] {
+		if strings.EqualFold(objectClass, "posixGroup") || strings.EqualFold(objectClass, "groupOfNames") {
+			return addGroupEntry(org, name, attributes)
+		}
+	}
+	return addUserEntry(m, org, name, attributes)
+}
+
+func addUserEntry(m *ldap.Message, org string, name string, attributes map[string][]string) error {
+	user, err := object.GetUser(util.GetId(org, name))
+	if err != nil {
+		return err
+	}
+	if user != nil {
+		return newLdapError(ldap.LDAPResultEntryAlreadyExists, "user %s already exists", user.GetId())
+	}
+
+	user = &object.User{
+		Owner:          org,
+		Name:           name,
+		CreatedTime:    util.GetCurrentTime(),
+		Type:           "normal-user",
+		Properties:     map[string]string{},
+		Groups:         []string{},
+		RegisterType:   "LDAP",
+		RegisterSource: util.GetId(m.Client.OrgName, m.Client.UserName),
+	}
+	for attributeName, values := range attributes {
+		if util.InSlice(ldapComputedAttributes, attributeName) {
+			continue
+		}
+
+		_, err = setUserAttribute(user, attributeName, message.ModifyRequestChangeOperationReplace, values)
+		if err != nil {
+			return err
+		}
+	}
+
+	if user.Password != "" {
+		msg := object.CheckPasswordComplexity(user, user.Password, "en")
+		if msg != "" {
+			return newLdapError(ldap.LDAPResultConstraintViolation, "%s", msg)
+		}
+	}
+
+	msg := object.CheckUpdateUser(&object.User{}, user, "en")
+	if msg != "" {
+		return newLdapError(ldap.LDAPResultConstraintViolation, "%s", msg)
+	}
+
+	_, err = object.AddUser(user, "en")
+	return err
+}
+
+func getGroupMemberNames(attributes map[string][]string) []string {
+	names := append([]string{}, attributes["memberuid"]...)
+	for _, dn := range append(attributes["member"], attributes["uniquemember"]...) {
+		if name, _, err := getNameAndOrgFromDN(dn); err == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func addGroupEntry(org string, name string, attributes map[string][]string) error {
+	group, err := object.GetGroup(util.GetId(org, name))
+	if err != nil {
+		return err
+	}
+	if group != nil {
+		return newLdapError(ldap.LDAPResultEntryAlreadyExists, "group %s already exists", group.GetId())
+	}
+
+	group = &object.Group{
+		Owner:       org,
+		Name:        name,
+		CreatedTime: util.GetCurrentTime(),
+		UpdatedTime: util.GetCurrentTime(),
+		DisplayName: name,
+		ParentId:    org,
+		IsTopGroup:  true,
+		IsEnabled:   true,
+		Type:        "Virtual",
+	}
+	if values := attributes["description"]; len(values) > 0 {
+		group.DisplayName = values[0]
+	}
+	if values := attributes["gidnumber"]; len(values) > 0 {
+		group.GidNumber, err = strconv.Atoi(values[0])
+		if err != nil {
+			return newLdapError(ldap.LDAPResultInvalidAttributeSyntax, "invalid gidNumber: %s", values[0])
+		}
+	}
+
+	_, err = object.AddGroup(group)
+	if err != nil {
+		return err
+	}
+
+	return updateGroupMembers(group, getGroupMemberNames(attributes), nil)
+}
+
+// updateGroupMembers adds and removes the users of a group, the membership is stored on the users.
+func updateGroupMembers(group *object.Group, added []string, removed []string) error {
+	groupId := group.GetId()
+	for _, name := range append(added, removed...) {
+		user, err := object.GetUser(util.GetId(group.Owner, name))
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return newLdapError(ldap.LDAPResultConstraintViolation, "member %s doesn't exist", name)
+		}
+
+		isAdded := util.InSlice(added, name)
+		if isAdded == util.InSlice(user.Groups, groupId) {
+			continue
+		}
+
+		if isAdded {
+			user.Groups = append(user.Groups, groupId)
+		} else {
+			user.Groups = applyLdapModification(user.Groups, message.ModifyRequestChangeOperationDelete, []string{groupId})
+		}
+		_, err = object.UpdateUser(user.GetId(), user, []string{"groups"}, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleModify(w ldap.ResponseWriter, m *ldap.Message) {
+	res := ldap.NewModifyResponse(ldap.LDAPResultSuccess)
+	r := m.GetModifyRequest()
+
+	err := modifyEntry(m, string(r.Object()), r.Changes())
+	if err != nil {
+		log.Printf("handleModify() failed to modify %s, err = %v", string(r.Object()), err)
+		res.SetResultCode(getLdapErrorCode(err))
+	}
+	w.Write(res)
+}
+
+func modifyEntry(m *ldap.Message, dn string, changes []message.ModifyRequestChange) error {
+	if !m.Client.IsAuthenticated {
+		return newLdapError(ldap.LDAPResultUnwillingToPerform, "the client isn't bound")
+	}
+
+	user, group, err := getEntryFromDN(dn)
+	if err != nil {
+		return err
+	}
+
+	if user != nil {
+		if code := checkWritePermission(m, user.Owner); code != ldap.LDAPResultSuccess {
+			return newLdapError(code, "the bound user can't modify %s", user.GetId())
+		}
+		return modifyUserEntry(user, changes)
+	}
+
+	if code := checkWritePermission(m, group.Owner); code != ldap.LDAPResultSuccess {
+		return newLdapError(code, "the bound user can't modify %s", group.GetId())
+	}
+	return modifyGroupEntry(group, changes)
+}
+
+func modifyUserEntry(user *object.User, changes []message.ModifyRequestChange) error {
+	oldUser := *user
+	columns := []string{}
+	for _, change := range changes {
+		modification := change.Modification()
+		name := string(modification.Type_())
+		if strings.EqualFold(name, "cn") || strings.EqualFold(name, "uid") {
+			return newLdapError(ldap.LDAPResultNotAllowedOnRDN, "the name of an entry can't be modified")
+		}
+
+		attributeColumns, err := setUserAttribute(user, name, int(change.Operation()), getAttributeValues(modification.Vals()))
+		if err != nil {
+			return err
+		}
+		for _, column := range attributeColumns {
+			if !util.InSlice(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	if util.InSlice(columns, "password") {
+		err := updateUserPassword(&oldUser, user, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	msg := object.CheckUpdateUser(&oldUser, user, "en")
+	if msg != "" {
+		return newLdapError(ldap.LDAPResultConstraintViolation, "%s", msg)
+	}
+	if util.InSlice(columns, "phone") {
+		columns = append(columns, "country_code")
+	}
+
+	_, err := object.UpdateUser(user.GetId(), user, columns, false)
+	return err
+}
+
+func modifyGroupEntry(group *object.Group, changes []message.ModifyRequestChange) error {
+	members := []string{}
+	for _, user := range object.GetGroupUsersWithoutError(group.GetId()) {
+		members = append(members, user.Name)
+	}
+	oldMembers := members
+
+	for _, change := range changes {
+		modification := change.Modification()
+		values := getAttributeValues(modification.Vals())
+		switch name := strings.ToLower(string(modification.Type_())); name {
+		case "memberuid", "member", "uniquemember":
+			members = applyLdapModification(members, int(change.Operation()), getGroupMemberNames(map[string][]string{name: values}))
+		case "description":
+			current := []string{group.DisplayName}
+			values = applyLdapModification(current, int(change.Operation()), values)
+			group.DisplayName = strings.Join(values, "")
+		case "gidnumber":
+			values = applyLdapModification(nil, int(change.Operation()), values)
+			group.GidNumber = 0
+			if len(values) > 0 {
+				gidNumber, err := strconv.Atoi(values[0])
+				if err != nil {
+					return newLdapError(ldap.LDAPResultInvalidAttributeSyntax, "invalid gidNumber: %s", values[0])
+				}
+				group.GidNumber = gidNumber
+			}
+		case "cn":
+			return newLdapError(ldap.LDAPResultNotAllowedOnRDN, "the name of an entry can't be modified")
+		default:
+			return newLdapError(ldap.LDAPResultUndefinedAttributeType, "attribute %s is not supported", name)
+		}
+	}
+
+	group.UpdatedTime = util.GetCurrentTime()
+	_, err := object.UpdateGroup(group.GetId(), group, false, "en")
+	if err != nil {
+		return err
+	}
+
+	added := applyLdapModification(members, message.ModifyRequestChangeOperationDelete, oldMembers)
+	removed := applyLdapModification(oldMembers, message.ModifyRequestChangeOperationDelete, members)
+	return updateGroupMembers(group, added, removed)
+}
+
+func handleDelete(w ldap.ResponseWriter, m *ldap.Message) {
+	res := ldap.NewDeleteResponse(ldap.LDAPResultSuccess)
+	dn := string(m.GetDeleteRequest())
+
+	err := deleteEntry(m, dn)
+	if err != nil {
+		log.Printf("handleDelete() failed to delete %s, err = %v", dn, err)
+		res.SetResultCode(getLdapErrorCode(err))
+	}
+	w.Write(res)
+}
+
+func deleteEntry(m *ldap.Message, dn string) error {
+	if !m.Client.IsAuthenticated {
+		return newLdapError(ldap.LDAPResultUnwillingToPerform, "the client isn't bound")
+	}
+
+	user, group, err := getEntryFromDN(dn)
+	if err != nil {
+		return err
+	}
+
+	if user != nil {
+		if code := checkWritePermission(m, user.Owner); code != ldap.LDAPResultSuccess {
+			return newLdapError(code, "the bound user can't delete %s", user.GetId())
+		}
+		if user.Owner == "built-in" && user.Name == "admin" {
+			return newLdapError(ldap.LDAPResultUnwillingToPerform, "the built-in admin can't be deleted")
+		}
+
+		_, err = object.DeleteUser(user)
+		return err
+	}
+
+	if code := checkWritePermission(m, group.Owner); code != ldap.LDAPResultSuccess {
+		return newLdapError(code, "the bound user can't delete %s", group.GetId())
+	}
+
+	_, err = object.DeleteGroup(group)
+	if err != nil {
+		return newLdapError(ldap.LDAPResultNotAllowedOnNonLeaf, "%s", err.Error())
+	}
+	return nil
+}
+
+// passwordModifyRequest is the value of the Password Modify extended operation (RFC 3062).
+type passwordModifyRequest struct {
+	userIdentity string
+	oldPassword  string
+	newPassword  string
+}
+
+func parsePasswordModifyRequest(value []byte) (*passwordModifyRequest, error) {
+	req := &passwordModifyRequest{}
+	if len(value) == 0 {
+		return req, nil
+	}
+
+	packet, err := ber.DecodePacketErr(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range packet.Children {
+		if child.ClassType != ber.ClassContext {
+			return nil, fmt.Errorf("invalid Password Modify request")
+		}
+
+		data := child.Data.String()
+		switch child.Tag {
+		case 0:
+			req.userIdentity = data
+		case 1:
+			req.oldPassword = data
+		case 2:
+			req.newPassword = data
+		default:
+			return nil, fmt.Errorf("invalid Password Modify request")
+		}
+	}
+	return req, nil
+}
+
+// getPasswordModifyTarget returns the name and organization of the user identity, which is a DN
+// or an authorization identity like "dn:..." or "u:name". An empty identity is the bound user.
+func getPasswordModifyTarget(m *ldap.Message, userIdentity string) (string, string, error) {
+	switch {
+	case userIdentity == "":
+		return m.Client.UserName, m.Client.OrgName, nil
+	case strings.HasPrefix(userIdentity, "u:"):
+		return strings.TrimPrefix(userIdentity, "u:"), m.Client.OrgName, nil
+	default:
+		return getNameAndOrgFromDN(strings.TrimPrefix(userIdentity, "dn:"))
+	}
+}
+
+func handlePasswordModify(w ldap.ResponseWriter, m *ldap.Message) {
+	res := ldap.NewExtendedResponse(ldap.LDAPResultSuccess)
+	r := m.GetExtendedRequest()
+
+	var value []byte
+	if r.RequestValue() != nil {
+		value = r.RequestValue().Bytes()
+	}
+
+	err := modifyPassword(m, value)
+	if err != nil {
+		log.Printf("handlePasswordModify() failed, err = %v", err)
+		res.SetResultCode(getLdapErrorCode(err))
+		res.SetDiagnosticMessage(err.Error())
+	}
+	w.Write(res)
+}
+
+func modifyPassword(m *ldap.Message, value []byte) error {
+	if !m.Client.IsAuthenticated {
+		return newLdapError(ldap.LDAPResultUnwillingToPerform, "the client isn't bound")
+	}
+
+	req, err := parsePasswordModifyRequest(value)
+	if err != nil {
+		return newLdapError(ldap.LDAPResultProtocolError, "%s", err.Error())
+	}
+	if req.newPassword == "" {
+		return newLdapError(ldap.LDAPResultUnwillingToPerform, "generating a password is not supported, please provide the new password")
+	}
+
+	name, org, err := getPasswordModifyTarget(m, req.userIdentity)
+	if err != nil {
+		return newLdapError(ldap.LDAPResultInvalidDNSyntax, "%s", err.Error())
+	}
+
+	user, err := object.GetUser(util.GetId(org, name))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return newLdapError(ldap.LDAPResultNoSuchObject, "user %s doesn't exist", util.GetId(org, name))
+	}
+
+	isAdmin := checkWritePermission(m, user.Owner) == ldap.LDAPResultSuccess
+	isSelf := user.Owner == m.Client.OrgName && user.Name == m.Client.UserName
+	if !isAdmin && !isSelf {
+		return newLdapError(ldap.LDAPResultInsufficientAccessRights, "the bound user can't change the password of %s", user.GetId())
+	}
+
+	// the users changing their own password have to prove the old one, as SetPassword requires
+	if !isAdmin || req.oldPassword != "" {
+		if user.Ldap != "" {
+			err = object.CheckLdapUserPassword(user, req.oldPassword, "en")
+		} else {
+			err = object.CheckPassword(user, req.oldPassword, "en")
+		}
+		if err != nil {
+			return newLdapError(ldap.LDAPResultInvalidCredentials, "%s", err.Error())
+		}
+	}
+
+	if user.Ldap != "" {
+		oldPassword := req.oldPassword
+		if isAdmin {
+			oldPassword = ""
+		}
+
+		msg := object.CheckPasswordComplexity(user, req.newPassword, "en")
+		if msg != "" {
+			return newLdapError(ldap.LDAPResultConstraintViolation, "%s", msg)
+		}
+		return object.ResetLdapPassword(user, oldPassword, req.newPassword, "en")
+	}
+
+	oldUser := *user
+	user.Password = req.newPassword
+	err = updateUserPassword(&oldUser, user, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	_, err = object.UpdateUser(user.GetId(), user, ldapUserAttributes["userpassword"].columns, false)
+	return err
+}
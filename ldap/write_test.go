@@ -0,0 +1,93 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/lor00x/goldap/message"
+
+	ldap "github.com/casdoor/ldapserver"
+
+	"github.com/casdoor/casdoor/object"
+)
+
+func TestApplyLdapModification(t *testing.T) {
+	scenarios := []struct {
+		description string
+		operation   int
+		values      []string
+		expected    []string
+	}{
+		{"Should add the missing values", message.ModifyRequestChangeOperationAdd, []string{"b", "c"}, []string{"a", "b", "c"}},
+		{"Should delete the given values", message.ModifyRequestChangeOperationDelete, []string{"a"}, []string{"b"}},
+		{"Should delete all the values", message.ModifyRequestChangeOperationDelete, nil, []string{}},
+		{"Should replace the values", message.ModifyRequestChangeOperationReplace, []string{"c"}, []string{"c"}},
+	}
+
+	for _, scenery := range scenarios {
+		t.Run(scenery.description, func(t *testing.T) {
+			res := applyLdapModification([]string{"a", "b"}, scenery.operation, scenery.values)
+			assert.Equal(t, scenery.expected, res)
+		})
+	}
+}
+
+func TestSetUserAttribute(t *testing.T) {
+	user := &object.User{Owner: "org", Name: "alice", Email: "old@example.com", Groups: []string{}}
+
+	columns, err := setUserAttribute(user, "mail", message.ModifyRequestChangeOperationReplace, []string{"alice@example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"email"}, columns)
+	assert.Equal(t, "alice@example.com", user.Email)
+
+	columns, err = setUserAttribute(user, "memberOf", message.ModifyRequestChangeOperationAdd, []string{"cn=dev,ou=org", "ops"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"groups"}, columns)
+	assert.Equal(t, []string{"org/dev", "org/ops"}, user.Groups)
+
+	for _, value := range []string{"cn=admins,ou=built-in", "built-in/admins"} {
+		_, err = setUserAttribute(user, "memberOf", message.ModifyRequestChangeOperationAdd, []string{value})
+		assert.Equal(t, ldap.LDAPResultInsufficientAccessRights, getLdapErrorCode(err))
+	}
+	assert.Equal(t, []string{"org/dev", "org/ops"}, user.Groups)
+
+	_, err = setUserAttribute(user, "mail", message.ModifyRequestChangeOperationAdd, []string{"other@example.com"})
+	assert.Equal(t, ldap.LDAPResultConstraintViolation, getLdapErrorCode(err))
+
+	_, err = setUserAttribute(user, "uid", message.ModifyRequestChangeOperationReplace, []string{"bob"})
+	assert.Equal(t, ldap.LDAPResultConstraintViolation, getLdapErrorCode(err))
+
+	_, err = setUserAttribute(user, "unknown", message.ModifyRequestChangeOperationReplace, []string{"x"})
+	assert.Equal(t, ldap.LDAPResultUndefinedAttributeType, getLdapErrorCode(err))
+}
+
+func TestParsePasswordModifyRequest(t *testing.T) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Password Modify Request")
+	packet.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 0, "cn=alice,ou=org", "User Identity"))
+	packet.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 1, "old", "Old Password"))
+	packet.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 2, "new", "New Password"))
+
+	req, err := parsePasswordModifyRequest(packet.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, &passwordModifyRequest{userIdentity: "cn=alice,ou=org", oldPassword: "old", newPassword: "new"}, req)
+
+	req, err = parsePasswordModifyRequest(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &passwordModifyRequest{}, req)
+}
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 
@@ -77,9 +78,16 @@ func main() {
 	object.InitUserManager()
 	object.InitFromFile()
 	object.InitCleanupTokens()
+	object.InitCertRotation()
 	object.InitCleanupRecords()
 	object.InitCleanupDeviceAuthMap()
+	object.InitCleanupPushedAuthRequestMap()
+	object.InitCleanupReceivedJtiMap()
+	object.InitCleanupSessionDeviceActiveTimes()
+	object.InitCleanupSamlSessions()
+	object.InitCleanupWsFedSessions()
 	object.InitExpirePermissions()
+	object.InitPermissionEnforcerCache()
 
 	object.InitSiteMap()
 	if len(object.SiteMap) != 0 {
@@ -152,5 +160,11 @@ func main() {
 		service.Start()
 	}
 
+	// the HTTPS listener requests a client certificate for the mutual-TLS client authentication
+	// (RFC 8705), it isn't verified in the handshake but against the application at the token endpoint
+	if web.BConfig.Listen.EnableHTTPS && !web.BConfig.Listen.EnableMutualHTTPS && !web.BConfig.Listen.AutoTLS {
+		web.BeeApp.Server.TLSConfig = &tls.Config{ClientAuth: tls.RequestClientCert}
+	}
+
 	web.Run(fmt.Sprintf(":%v", port))
 }
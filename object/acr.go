@@ -0,0 +1,198 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Authentication method references, see https://www.rfc-editor.org/rfc/rfc8176
+const (
+	AmrPassword     = "pwd"
+	AmrOtp          = "otp"
+	AmrSms          = "sms"
+	AmrHardwareKey  = "hwk"
+	AmrFace         = "face"
+	AmrFederated    = "fed"
+	AmrKerberos     = "wia"
+	AmrMultiChannel = "mca"
+	AmrMfa          = "mfa"
+)
+
+// Authentication context class references, from the weakest to the strongest
+const (
+	AcrSingleFactor      = "urn:casdoor:acr:sfa"
+	AcrMultiFactor       = "urn:casdoor:acr:mfa"
+	AcrPhishingResistant = "urn:casdoor:acr:phr"
+)
+
+const (
+	AuthContextSessionKey    = "authContext"
+	RequiredReauthentication = "RequiredReauthentication"
+)
+
+var AcrValuesSupported = []string{AcrSingleFactor, AcrMultiFactor, AcrPhishingResistant}
+
+var acrLevels = map[string]int{
+	AcrSingleFactor:      1,
+	AcrMultiFactor:       2,
+	AcrPhishingResistant: 3,
+}
+
+// AuthContext records how and when the user of a session has authenticated.
+type AuthContext struct {
+	Amr      []string `json:"amr"`
+	AuthTime int64    `json:"authTime"`
+
+	// The upstream session of a federated login, the Casdoor session ends when the
+	// provider logs it out, see IdpSession
+	Provider   string `json:"provider,omitempty"`
+	IdpSubject string `json:"idpSubject,omitempty"`
+	IdpSid     string `json:"idpSid,omitempty"`
+}
+
+func NewAuthContext(methods ...string) *AuthContext {
+	return &AuthContext{
+		Amr:      methods,
+		AuthTime: time.Now().Unix(),
+	}
+}
+
+// GetAmrByMfaType returns the method reference of a second factor.
+func GetAmrByMfaType(mfaType string) string {
+	switch mfaType {
+	case SmsType:
+		return AmrSms
+	case PushType:
+		return AmrMultiChannel
+	default:
+		return AmrOtp
+	}
+}
+
+func (ac *AuthContext) hasAmr(method string) bool {
+	for _, amr := range ac.Amr {
+		if amr == method {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSecondFactor returns the context of a login that has also verified the given method.
+func (ac *AuthContext) WithSecondFactor(method string) *AuthContext {
+	res := NewAuthContext()
+	if ac != nil {
+		res.Amr = append(res.Amr, ac.Amr...)
+		res.Provider, res.IdpSubject, res.IdpSid = ac.Provider, ac.IdpSubject, ac.IdpSid
+	}
+	for _, amr := range []string{method, AmrMfa} {
+		if !res.hasAmr(amr) {
+			res.Amr = append(res.Amr, amr)
+		}
+	}
+	return res
+}
+
+// WithIdpSession returns the context of a login through the provider, with the subject and
+// session id the provider has given to the user.
+func (ac *AuthContext) WithIdpSession(provider string, subject string, sid string) *AuthContext {
+	ac.Provider = provider
+	ac.IdpSubject = subject
+	ac.IdpSid = sid
+	return ac
+}
+
+// GetAcr returns the class of the authentication, a WebAuthn credential is phishing-resistant
+// and satisfies the multi-factor class too as it proves possession and user verification.
+func (ac *AuthContext) GetAcr() string {
+	if ac == nil || len(ac.Amr) == 0 {
+		return ""
+	}
+	if ac.hasAmr(AmrHardwareKey) {
+		return AcrPhishingResistant
+	}
+	if ac.hasAmr(AmrMfa) {
+		return AcrMultiFactor
+	}
+	return AcrSingleFactor
+}
+
+func (ac *AuthContext) GetAmr() []string {
+	if ac == nil {
+		return nil
+	}
+	return ac.Amr
+}
+
+func (ac *AuthContext) GetAuthTime() *jwt.NumericDate {
+	if ac == nil || ac.AuthTime == 0 {
+		return nil
+	}
+	return jwt.NewNumericDate(time.Unix(ac.AuthTime, 0))
+}
+
+// IsAcrSatisfied reports whether the class meets one of the space-separated acr_values,
+// the values that aren't known are ignored as they are voluntary claims.
+func IsAcrSatisfied(acrValues string, acr string) bool {
+	requested := false
+	for _, value := range strings.Fields(acrValues) {
+		level, ok := acrLevels[value]
+		if !ok {
+			continue
+		}
+		if level <= acrLevels[acr] {
+			return true
+		}
+		requested = true
+	}
+	return !requested
+}
+
+// IsSatisfied reports whether the context meets the acr_values and max_age of an authorization
+// request, otherwise the user has to authenticate again.
+func (ac *AuthContext) IsSatisfied(acrValues string, maxAge string) bool {
+	if !IsAcrSatisfied(acrValues, ac.GetAcr()) {
+		return false
+	}
+
+	if maxAge == "" {
+		return true
+	}
+	seconds, err := strconv.ParseInt(maxAge, 10, 64)
+	if err != nil || seconds < 0 {
+		return true
+	}
+	if ac == nil || ac.AuthTime == 0 {
+		return false
+	}
+	return time.Now().Unix()-ac.AuthTime <= seconds
+}
+
+func getAuthContextByClaims(amr []string, authTime *jwt.NumericDate) *AuthContext {
+	if len(amr) == 0 {
+		return nil
+	}
+
+	res := &AuthContext{Amr: amr}
+	if authTime != nil {
+		res.AuthTime = authTime.Unix()
+	}
+	return res
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthContextAcr(t *testing.T) {
+	password := NewAuthContext(AmrPassword)
+	totp := password.WithSecondFactor(GetAmrByMfaType(TotpType))
+	webauthn := NewAuthContext(AmrHardwareKey)
+
+	cases := []struct {
+		authContext *AuthContext
+		acr         string
+	}{
+		{nil, ""},
+		{password, AcrSingleFactor},
+		{totp, AcrMultiFactor},
+		{webauthn, AcrPhishingResistant},
+	}
+	for _, c := range cases {
+		if acr := c.authContext.GetAcr(); acr != c.acr {
+			t.Errorf("GetAcr(%v) = %s, want %s", c.authContext.GetAmr(), acr, c.acr)
+		}
+	}
+
+	if amr := totp.GetAmr(); len(amr) != 3 || amr[0] != AmrPassword || amr[1] != AmrOtp || amr[2] != AmrMfa {
+		t.Errorf("unexpected amr after the second factor: %v", amr)
+	}
+}
+
+func TestAuthContextIsSatisfied(t *testing.T) {
+	password := NewAuthContext(AmrPassword)
+	mfa := password.WithSecondFactor(AmrSms)
+	old := &AuthContext{Amr: []string{AmrHardwareKey}, AuthTime: time.Now().Add(-time.Hour).Unix()}
+
+	cases := []struct {
+		authContext *AuthContext
+		acrValues   string
+		maxAge      string
+		ok          bool
+	}{
+		{password, "", "", true},
+		{password, "unknown", "", true},
+		{password, AcrMultiFactor, "", false},
+		{password, AcrMultiFactor + " " + AcrSingleFactor, "", true},
+		{mfa, AcrMultiFactor, "", true},
+		{mfa, AcrPhishingResistant, "", false},
+		{old, AcrMultiFactor, "", true},
+		{old, "", "60", false},
+		{old, "", "7200", true},
+		{password, "", "0", true},
+		{nil, "", "", true},
+		{nil, "", "60", false},
+		{nil, AcrSingleFactor, "", false},
+	}
+	for i, c := range cases {
+		if ok := c.authContext.IsSatisfied(c.acrValues, c.maxAge); ok != c.ok {
+			t.Errorf("case %d: IsSatisfied(%q, %q) = %v, want %v", i, c.acrValues, c.maxAge, ok, c.ok)
+		}
+	}
+}
+
+func TestGetAuthorizationCodeTokenChecksAcrValues(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 20, "cert", "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&Cert{Owner: "admin", Name: "cert", Type: "x509", CryptoAlgorithm: "RS256", Certificate: certificate, PrivateKey: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := &Application{Owner: "admin", Name: "app", Organization: "org", Cert: "cert", ClientId: "client", ClientSecret: "secret", ExpireInHours: 1}
+	addCode := func(code string, authContext *AuthContext) {
+		t.Helper()
+		user := &User{Owner: "org", Name: "alice"}
+		accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", "openid", "", "localhost:8000", authContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = AddToken(&Token{
+			Owner:        "admin",
+			Name:         tokenName,
+			Application:  "app",
+			Organization: "org",
+			User:         "alice",
+			Code:         code,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+			CodeExpireIn: time.Now().Add(time.Minute).Unix(),
+			AcrValues:    AcrMultiFactor,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addCode("code-sfa", NewAuthContext(AmrPassword))
+	_, tokenError, err := GetAuthorizationCodeToken(application, "secret", "code-sfa", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenError == nil || tokenError.Error != InvalidGrant {
+		t.Errorf("a single-factor code is exchanged for acr_values: %s, got: %+v", AcrMultiFactor, tokenError)
+	}
+
+	addCode("code-mfa", NewAuthContext(AmrPassword).WithSecondFactor(GetAmrByMfaType(TotpType)))
+	token, tokenError, err := GetAuthorizationCodeToken(application, "secret", "code-mfa", "", "")
+	if err != nil || tokenError != nil || token == nil {
+		t.Errorf("a multi-factor code isn't exchanged: %+v, %v", tokenError, err)
+	}
+}
+
+func TestGetOAuthCodeChecksPushedAcrValues(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	_, err := ormer.Engine.Insert(&User{Owner: "org", Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	application := &Application{Owner: "admin", Name: "app", Organization: "org", ClientId: "client", ClientSecret: "secret", RedirectUris: []string{"https://app.example.com/callback"}, GrantTypes: []string{"authorization_code"}}
+	_, err = ormer.Engine.Insert(application)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestUri := PushedAuthRequestUriPrefix + "acr-test"
+	PushedAuthRequestMap.Store(requestUri, PushedAuthRequest{
+		ClientId: "client",
+		Params: map[string]string{
+			"response_type": "code",
+			"redirect_uri":  "https://app.example.com/callback",
+			"scope":         "openid",
+			"acr_values":    AcrMultiFactor,
+		},
+		ExpiresAt: time.Now().Add(time.Minute),
+		Activated: true,
+	})
+	defer PushedAuthRequestMap.Delete(requestUri)
+
+	// the browser dropped the acr_values of the pushed request
+	code, err := GetOAuthCode("org/alice", "client", "", "password", "code", "https://app.example.com/callback", "openid", "", "", "", "", "", "", requestUri, "localhost:8000", "en", NewAuthContext(AmrPassword), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.Code != "" {
+		t.Errorf("a code is issued for a single-factor sign-in to a request with acr_values: %s", AcrMultiFactor)
+	}
+}
@@ -121,6 +121,10 @@ func UpdateAdapter(id string, adapter *Adapter) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeAdapter, id)
+	}
+
 	return affected != 0, nil
 }
 
@@ -130,6 +134,11 @@ func AddAdapter(adapter *Adapter) (bool, error) {
 		return false, err
 	}
 
+	// a permission of a missing adapter is enforced with the permission_rule table
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeAdapter, adapter.GetId())
+	}
+
 	return affected != 0, nil
 }
 
@@ -139,6 +148,10 @@ func DeleteAdapter(adapter *Adapter) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeAdapter, adapter.GetId())
+	}
+
 	return affected != 0, nil
 }
 
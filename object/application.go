@@ -112,6 +112,12 @@ type Application struct {
 	EnableLinkWithEmail          bool            `json:"enableLinkWithEmail"`
 	OrgChoiceMode                string          `json:"orgChoiceMode"`
 	SamlReplyUrl                 string          `xorm:"varchar(500)" json:"samlReplyUrl"`
+	SamlSloUrl                   string          `xorm:"varchar(500)" json:"samlSloUrl"`
+	SamlSloBinding               string          `xorm:"varchar(100)" json:"samlSloBinding"`
+	SamlSloSoapUrl               string          `xorm:"varchar(500)" json:"samlSloSoapUrl"`
+	SamlSpCert                   string          `xorm:"mediumtext" json:"samlSpCert"`
+	SamlSpEncryptionCert         string          `xorm:"mediumtext" json:"samlSpEncryptionCert"`
+	SamlAuthnRequestsSigned      bool            `json:"samlAuthnRequestsSigned"`
 	Providers                    []*ProviderItem `xorm:"mediumtext" json:"providers"`
 	SigninMethods                []*SigninMethod `xorm:"varchar(2000)" json:"signinMethods"`
 	SignupItems                  []*SignupItem   `xorm:"varchar(3000)" json:"signupItems"`
@@ -172,6 +178,19 @@ type Application struct {
 	CertObj *Cert `xorm:"-"`
 
 	RegistrationAccessToken string `xorm:"varchar(100)" json:"registrationAccessToken"`
+
+	RequirePushedAuthorizationRequests bool   `json:"requirePushedAuthorizationRequests"`
+	ClientJwksUri                      string `xorm:"varchar(500)" json:"clientJwksUri"`
+	CibaTokenDeliveryMode              string `xorm:"varchar(20)" json:"cibaTokenDeliveryMode"`
+	CibaClientNotificationEndpoint     string `xorm:"varchar(500)" json:"cibaClientNotificationEndpoint"`
+	IdTokenEncryptedResponseAlg        string `xorm:"varchar(100)" json:"idTokenEncryptedResponseAlg"`
+	IdTokenEncryptedResponseEnc        string `xorm:"varchar(100)" json:"idTokenEncryptedResponseEnc"`
+	UserinfoEncryptedResponseAlg       string `xorm:"varchar(100)" json:"userinfoEncryptedResponseAlg"`
+	UserinfoEncryptedResponseEnc       string `xorm:"varchar(100)" json:"userinfoEncryptedResponseEnc"`
+
+	TlsClientAuthMethod                   string `xorm:"varchar(100)" json:"tlsClientAuthMethod"`
+	TlsClientAuthSubjectDn                string `xorm:"varchar(500)" json:"tlsClientAuthSubjectDn"`
+	TlsClientCertificateBoundAccessTokens bool   `json:"tlsClientCertificateBoundAccessTokens"`
 }
 
 func (application *Application) HasSigninMethod(name string) bool {
@@ -45,6 +45,16 @@ type Cert struct {
 
 	Certificate string `xorm:"mediumtext" json:"certificate"`
 	PrivateKey  string `xorm:"mediumtext" json:"privateKey"`
+
+	KeyId              string `xorm:"varchar(100)" json:"keyId"`
+	RotationInterval   int    `json:"rotationInterval"`
+	RotatedTime        string `xorm:"varchar(100)" json:"rotatedTime"`
+	NextKeyId          string `xorm:"varchar(100)" json:"nextKeyId"`
+	NextCertificate    string `xorm:"mediumtext" json:"nextCertificate"`
+	NextPrivateKey     string `xorm:"mediumtext" json:"nextPrivateKey"`
+	RetiredKeyId       string `xorm:"varchar(100)" json:"retiredKeyId"`
+	RetiredCertificate string `xorm:"mediumtext" json:"retiredCertificate"`
+	RetiredExpireTime  string `xorm:"varchar(100)" json:"retiredExpireTime"`
 }
 
 func GetMaskedCert(cert *Cert) *Cert {
@@ -55,6 +65,9 @@ func GetMaskedCert(cert *Cert) *Cert {
 	if cert.PrivateKey != "" {
 		cert.PrivateKey = "***"
 	}
+	if cert.NextPrivateKey != "" {
+		cert.NextPrivateKey = "***"
+	}
 	if cert.AccessSecret != "" {
 		cert.AccessSecret = "***"
 	}
@@ -182,12 +195,16 @@ func UpdateCert(id string, cert *Cert) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	if c, err := getCert(owner, name); err != nil {
+	c, err := getCert(owner, name)
+	if err != nil {
 		return false, err
 	} else if c == nil {
 		return false, nil
 	}
 
+	// The rotated keys are managed by the rotation job only
+	c.copyRotationState(cert)
+
 	if name != cert.Name {
 		err = certChangeTrigger(name, cert.Name)
 		if err != nil {
@@ -263,10 +280,21 @@ func (p *Cert) populateContent() error {
 		p.CryptoAlgorithm = "RS256"
 	}
 
+	certificate, privateKey, err := p.generateKeys()
+	if err != nil {
+		return err
+	}
+
+	p.Certificate = certificate
+	p.PrivateKey = privateKey
+	return nil
+}
+
+func (p *Cert) generateKeys() (string, string, error) {
 	sigAlgorithm := p.CryptoAlgorithm[:2]
 	shaSize, err := util.ParseIntWithError(p.CryptoAlgorithm[2:])
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	var certificate, privateKey string
@@ -279,13 +307,7 @@ func (p *Cert) populateContent() error {
 	} else {
 		err = fmt.Errorf("populateContent() error, unsupported signature algorithm: %s", sigAlgorithm)
 	}
-	if err != nil {
-		return err
-	}
-
-	p.Certificate = certificate
-	p.PrivateKey = privateKey
-	return nil
+	return certificate, privateKey, err
 }
 
 func RenewCert(cert *Cert) (bool, error) {
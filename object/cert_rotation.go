@@ -0,0 +1,289 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/casdoor/casdoor/util"
+	"github.com/robfig/cron/v3"
+	"github.com/xorm-io/core"
+)
+
+// defaultRetiredKeyLifetime is how long a retired key stays verifiable when no application using
+// the cert configures a token lifetime.
+const defaultRetiredKeyLifetime = 168 * time.Hour
+
+// certKey is one of the keys of a cert, a cert under rotation has up to three of them: the next key
+// that is published before it becomes active, the active key that signs the tokens, and the
+// retired key that is kept until the tokens signed by it have expired.
+type certKey struct {
+	KeyId       string
+	Certificate string
+}
+
+// GetKeyId returns the kid of the active key. Certs that have never been rotated use their name,
+// which is the kid of all the tokens issued before rotation was introduced.
+func (p *Cert) GetKeyId() string {
+	if p.KeyId == "" {
+		return p.Name
+	}
+	return p.KeyId
+}
+
+func (p *Cert) isRetiredKeyExpired() bool {
+	if p.RetiredExpireTime == "" {
+		return true
+	}
+
+	expireTime, err := time.Parse(time.RFC3339, p.RetiredExpireTime)
+	if err != nil {
+		return true
+	}
+	return expireTime.Before(time.Now())
+}
+
+// getPublishedKeys returns the keys that are published in the JWKS, the active one first.
+func (p *Cert) getPublishedKeys() []certKey {
+	keys := []certKey{{KeyId: p.GetKeyId(), Certificate: p.Certificate}}
+	if p.NextCertificate != "" {
+		keys = append(keys, certKey{KeyId: p.NextKeyId, Certificate: p.NextCertificate})
+	}
+	if p.RetiredCertificate != "" && !p.isRetiredKeyExpired() {
+		keys = append(keys, certKey{KeyId: p.RetiredKeyId, Certificate: p.RetiredCertificate})
+	}
+	return keys
+}
+
+// getVerificationCertificate returns the certificate that verifies a token with the given kid.
+// Tokens without a kid, or with a kid that the cert doesn't know (e.g. a client cert), are
+// verified with the active key.
+func (p *Cert) getVerificationCertificate(kid string) (string, error) {
+	if kid == "" {
+		return p.Certificate, nil
+	}
+
+	for _, key := range p.getPublishedKeys() {
+		if key.KeyId == kid {
+			return key.Certificate, nil
+		}
+	}
+
+	if kid == p.RetiredKeyId {
+		return "", fmt.Errorf("the key: %s of the cert: %s has been retired", kid, p.GetId())
+	}
+	return p.Certificate, nil
+}
+
+func (p *Cert) copyRotationState(cert *Cert) {
+	cert.KeyId = p.KeyId
+	cert.RotatedTime = p.RotatedTime
+	cert.NextKeyId = p.NextKeyId
+	cert.NextCertificate = p.NextCertificate
+	cert.NextPrivateKey = p.NextPrivateKey
+	cert.RetiredKeyId = p.RetiredKeyId
+	cert.RetiredCertificate = p.RetiredCertificate
+	cert.RetiredExpireTime = p.RetiredExpireTime
+}
+
+func (p *Cert) newKeyId() string {
+	return fmt.Sprintf("%s-%s", p.Name, util.GetRandomName())
+}
+
+// prepareNextKey generates the next key so that it is published in the JWKS for a whole rotation
+// interval before it starts signing tokens.
+func (p *Cert) prepareNextKey() error {
+	certificate, privateKey, err := p.generateKeys()
+	if err != nil {
+		return err
+	}
+
+	p.NextKeyId = p.newKeyId()
+	p.NextCertificate = certificate
+	p.NextPrivateKey = privateKey
+	if p.RotatedTime == "" {
+		p.RotatedTime = util.GetCurrentTime()
+	}
+	return nil
+}
+
+// getCertTokenLifetime returns the longest lifetime of the tokens signed by the cert, it is how
+// long a key stays verifiable after it has been retired.
+func getCertTokenLifetime(cert *Cert) (time.Duration, error) {
+	applications := []*Application{}
+	session := ormer.Engine.Where("cert = ?", cert.Name)
+	if cert.Owner == "admin" && cert.Name == "cert-built-in" {
+		session = session.Or("cert = ?", "")
+	}
+	err := session.Cols("expire_in_hours", "refresh_expire_in_hours").Find(&applications)
+	if err != nil {
+		return 0, err
+	}
+
+	hours := 0.0
+	for _, application := range applications {
+		hours = max(hours, application.ExpireInHours, application.RefreshExpireInHours)
+	}
+	if hours == 0 {
+		return defaultRetiredKeyLifetime, nil
+	}
+	return time.Duration(hours * float64(time.Hour)), nil
+}
+
+// rotateKeys retires the active key, activates the next key and prepares a new next key.
+func (p *Cert) rotateKeys(retiredKeyLifetime time.Duration) error {
+	if p.NextCertificate == "" {
+		err := p.prepareNextKey()
+		if err != nil {
+			return err
+		}
+	}
+
+	p.RetiredKeyId = p.GetKeyId()
+	p.RetiredCertificate = p.Certificate
+	p.RetiredExpireTime = time.Now().Add(retiredKeyLifetime).Format(time.RFC3339)
+
+	p.KeyId = p.NextKeyId
+	p.Certificate = p.NextCertificate
+	p.PrivateKey = p.NextPrivateKey
+	p.RotatedTime = util.GetCurrentTime()
+
+	expireTime, err := util.GetCertExpireTime(p.Certificate)
+	if err == nil {
+		p.ExpireTime = expireTime
+	}
+
+	return p.prepareNextKey()
+}
+
+// updateRotatedCert saves the rotated keys, the update only happens when the active key is still
+// the one that was read so that concurrent instances don't rotate the same cert twice.
+func updateRotatedCert(cert *Cert, oldKeyId string) (bool, error) {
+	session := ormer.Engine.ID(core.PK{cert.Owner, cert.Name})
+	if oldKeyId == "" {
+		session = session.Where("key_id = ? or key_id is null", "")
+	} else {
+		session = session.Where("key_id = ?", oldKeyId)
+	}
+
+	affected, err := session.AllCols().Update(cert)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// RotateCert immediately rotates the keys of a cert, the tokens signed by the current key stay
+// valid until they expire.
+func RotateCert(cert *Cert) (bool, error) {
+	if cert.Type != "x509" {
+		return false, fmt.Errorf("only x509 certs can be rotated")
+	}
+
+	lifetime, err := getCertTokenLifetime(cert)
+	if err != nil {
+		return false, err
+	}
+
+	oldKeyId := cert.KeyId
+	err = cert.rotateKeys(lifetime)
+	if err != nil {
+		return false, err
+	}
+
+	return updateRotatedCert(cert, oldKeyId)
+}
+
+func isCertRotationDue(cert *Cert) bool {
+	if cert.RotatedTime == "" {
+		return false
+	}
+
+	rotatedTime, err := time.Parse(time.RFC3339, cert.RotatedTime)
+	if err != nil {
+		return false
+	}
+	return rotatedTime.Add(time.Duration(cert.RotationInterval) * 24 * time.Hour).Before(time.Now())
+}
+
+func rotateCertIfNeeded(cert *Cert) error {
+	oldKeyId := cert.KeyId
+
+	if cert.NextCertificate == "" {
+		err := cert.prepareNextKey()
+		if err != nil {
+			return err
+		}
+	} else if isCertRotationDue(cert) {
+		lifetime, err := getCertTokenLifetime(cert)
+		if err != nil {
+			return err
+		}
+
+		err = cert.rotateKeys(lifetime)
+		if err != nil {
+			return err
+		}
+	} else if cert.RetiredCertificate != "" && cert.isRetiredKeyExpired() {
+		cert.RetiredKeyId = ""
+		cert.RetiredCertificate = ""
+		cert.RetiredExpireTime = ""
+	} else {
+		return nil
+	}
+
+	_, err := updateRotatedCert(cert, oldKeyId)
+	return err
+}
+
+func RotateCerts() error {
+	certs := []*Cert{}
+	err := ormer.Engine.Where("type = ? and rotation_interval > ?", "x509", 0).Find(&certs)
+	if err != nil {
+		return err
+	}
+
+	for _, cert := range certs {
+		err = rotateCertIfNeeded(cert)
+		if err != nil {
+			fmt.Printf("Error rotating cert %s: %v\n", cert.GetId(), err)
+		}
+	}
+	return nil
+}
+
+func InitCertRotation() {
+	schedule := "0 * * * *"
+
+	go func() {
+		if err := RotateCerts(); err != nil {
+			fmt.Printf("Error rotating certs at startup: %v\n", err)
+		}
+	}()
+
+	cronJob := cron.New()
+	_, err := cronJob.AddFunc(schedule, func() {
+		if err := RotateCerts(); err != nil {
+			fmt.Printf("Error rotating certs: %v\n", err)
+		}
+	})
+	if err != nil {
+		fmt.Printf("Error scheduling cert rotation: %v\n", err)
+		return
+	}
+	cronJob.Start()
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signTestToken(t *testing.T, cert *Cert) string {
+	t.Helper()
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(cert.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"owner": "built-in",
+		"name":  "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = cert.GetKeyId()
+	tokenString, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenString
+}
+
+func TestCertRotation(t *testing.T) {
+	cert := &Cert{Owner: "admin", Name: "cert-rotation", Type: "x509", CryptoAlgorithm: "RS256", BitSize: 2048, ExpireInYears: 1}
+	err := cert.populateContent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldToken := signTestToken(t, cert)
+	if cert.GetKeyId() != cert.Name {
+		t.Errorf("a cert that has never been rotated should use its name as kid, got: %s", cert.GetKeyId())
+	}
+
+	err = cert.prepareNextKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := cert.getPublishedKeys(); len(keys) != 2 || keys[1].KeyId != cert.NextKeyId {
+		t.Errorf("the next key should be published before it becomes active, got: %v", keys)
+	}
+
+	nextKeyId := cert.NextKeyId
+	err = cert.rotateKeys(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.KeyId != nextKeyId || cert.RetiredKeyId != "cert-rotation" || cert.NextKeyId == cert.KeyId {
+		t.Errorf("unexpected rotation state: active %s, retired %s", cert.KeyId, cert.RetiredKeyId)
+	}
+	if keys := cert.getPublishedKeys(); len(keys) != 3 {
+		t.Errorf("the active, next and retired keys should be published, got %d keys", len(keys))
+	}
+
+	if _, err = ParseJwtToken(oldToken, cert); err != nil {
+		t.Errorf("a token signed by the retired key should still be valid: %v", err)
+	}
+	if _, err = ParseJwtToken(signTestToken(t, cert), cert); err != nil {
+		t.Errorf("a token signed by the active key should be valid: %v", err)
+	}
+
+	cert.RetiredExpireTime = time.Now().Add(-time.Minute).Format(time.RFC3339)
+	if _, err = ParseJwtToken(oldToken, cert); err == nil {
+		t.Error("a token signed by an expired retired key should be rejected")
+	}
+	if keys := cert.getPublishedKeys(); len(keys) != 2 {
+		t.Errorf("an expired retired key should not be published, got %d keys", len(keys))
+	}
+}
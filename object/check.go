@@ -294,6 +294,42 @@ func CheckPassword(user *User, password string, lang string, options ...bool) er
 	return resetUserSigninErrorTimes(user)
 }
 
+// checkPasswordWithChallenge checks the password of a user for challenge-response protocols like
+// MS-CHAPv2, they compute the response from the password in clear so only the users whose
+// password is stored with the plain password type are supported.
+func checkPasswordWithChallenge(user *User, isPasswordCorrect func(password string) bool, lang string) error {
+	err := checkSigninErrorTimes(user, lang)
+	if err != nil {
+		return err
+	}
+
+	organization, err := GetOrganizationByUser(user)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return errors.New(i18n.Translate(lang, "check:Organization does not exist"))
+	}
+
+	passwordType := user.PasswordType
+	if passwordType == "" {
+		passwordType = organization.PasswordType
+	}
+	if passwordType != "plain" {
+		return fmt.Errorf(i18n.Translate(lang, "check:unsupported password type: %s"), passwordType)
+	}
+
+	if organization.MasterPassword != "" && isPasswordCorrect(organization.MasterPassword) {
+		return resetUserSigninErrorTimes(user)
+	}
+
+	if user.Password == "" || !isPasswordCorrect(user.Password) {
+		return recordSigninErrorInfo(user, lang)
+	}
+
+	return resetUserSigninErrorTimes(user)
+}
+
 func CheckPasswordComplexityByOrg(organization *Organization, password string, lang string) string {
 	errorMsg := checkPasswordComplexity(password, organization.PasswordOptions, lang)
 	return errorMsg
@@ -432,6 +468,43 @@ func CheckUserPassword(organization string, username string, password string, la
 	return user, nil
 }
 
+// CheckUserPasswordWithChallenge is the counterpart of CheckUserPassword for challenge-response
+// protocols, isPasswordCorrect tells whether the response of the peer matches a password.
+func CheckUserPasswordWithChallenge(organization string, username string, isPasswordCorrect func(password string) bool, lang string) (*User, error) {
+	user, err := GetUserByFields(organization, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.IsDeleted {
+		return nil, newSigninError(SigninReasonUserNotFound, fmt.Sprintf(i18n.Translate(lang, "general:The user: %s doesn't exist"), util.GetId(organization, username)))
+	}
+
+	if user.IsForbidden {
+		return nil, newSigninError(SigninReasonAccountDisabled, i18n.Translate(lang, "check:The user is forbidden to sign in, please contact the administrator"))
+	}
+
+	if user.Tag == "guest-user" {
+		return nil, newSigninError(SigninReasonAccountDisabled, i18n.Translate(lang, "check:Guest users must upgrade their account by setting a username and password before they can sign in directly"))
+	}
+
+	if user.Ldap != "" {
+		return nil, errors.New(i18n.Translate(lang, "check:password or code is incorrect"))
+	}
+
+	err = checkPasswordWithChallenge(user, isPasswordCorrect, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkPasswordExpired(user, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func CheckUserPermission(requestUserId, userId string, strict bool, lang string) (bool, error) {
 	if requestUserId == "" {
 		return false, errors.New(i18n.Translate(lang, "general:Please login first"))
@@ -514,7 +587,7 @@ func CheckApiPermission(userId string, organization string, path string, method
 			continue
 		}
 
-		enforcer, err := getPermissionEnforcer(permission)
+		enforcer, err := getCachedPermissionEnforcer(permission)
 		if err != nil {
 			return false, err
 		}
@@ -655,7 +728,7 @@ func CheckLoginPermission(userId string, application *Application) (bool, error)
 			continue
 		}
 
-		enforcer, err := getPermissionEnforcer(permission)
+		enforcer, err := getCachedPermissionEnforcer(permission)
 		if err != nil {
 			return false, err
 		}
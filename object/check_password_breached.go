@@ -0,0 +1,226 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/conf"
+	"github.com/casdoor/casdoor/i18n"
+	"github.com/casdoor/casdoor/util"
+)
+
+const PasswordOptionNotBreached = "NotBreached"
+
+var regexSha1Line = regexp.MustCompile(`^[0-9A-Fa-f]{40}(:\d+)?$`)
+
+// breachedPasswordCorpus looks up the SHA-1 hashes of passwords in a corpus of breached passwords.
+// A list file is loaded in memory, it holds a password or a SHA-1 hash (optionally followed by
+// ":count" as in the HIBP dumps) per line. A directory holds HIBP range files named by the
+// 5-character prefix of the hashes, like the responses of the k-anonymity range API.
+type breachedPasswordCorpus struct {
+	hashes   map[[sha1.Size]byte]struct{}
+	rangeDir string
+	apiUrl   string
+	client   *http.Client
+}
+
+var (
+	breachedPasswordCorpusOnce    sync.Once
+	defaultBreachedPasswordCorpus *breachedPasswordCorpus
+)
+
+func newBreachedPasswordCorpus(path string, apiUrl string) (*breachedPasswordCorpus, error) {
+	corpus := &breachedPasswordCorpus{
+		apiUrl: strings.TrimSuffix(apiUrl, "/"),
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+	if path == "" {
+		return corpus, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		corpus.rangeDir = path
+		return corpus, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	corpus.hashes = map[[sha1.Size]byte]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var hash [sha1.Size]byte
+		if regexSha1Line.MatchString(line) {
+			_, err = hex.Decode(hash[:], []byte(line[:2*sha1.Size]))
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			hash = sha1.Sum([]byte(line))
+		}
+		corpus.hashes[hash] = struct{}{}
+	}
+	return corpus, scanner.Err()
+}
+
+func getBreachedPasswordCorpus() *breachedPasswordCorpus {
+	breachedPasswordCorpusOnce.Do(func() {
+		corpus, err := newBreachedPasswordCorpus(conf.GetConfigString("breachedPasswordFile"), conf.GetConfigString("breachedPasswordApiUrl"))
+		if err != nil {
+			logs.Error("failed to load the breached password corpus: %v", err)
+			return
+		}
+		defaultBreachedPasswordCorpus = corpus
+	})
+	return defaultBreachedPasswordCorpus
+}
+
+// containsHashSuffix scans the lines "SUFFIX:COUNT" of a range, the padding lines of the range
+// API have a count of 0 and don't match.
+func containsHashSuffix(r io.Reader, suffix string) (bool, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		hashSuffix, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !strings.EqualFold(hashSuffix, suffix) {
+			continue
+		}
+
+		if !found {
+			return true, nil
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || n > 0 {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+func (corpus *breachedPasswordCorpus) isBreached(password string) (bool, error) {
+	hash := sha1.Sum([]byte(password))
+	if _, ok := corpus.hashes[hash]; ok {
+		return true, nil
+	}
+
+	hexHash := strings.ToUpper(hex.EncodeToString(hash[:]))
+	prefix, suffix := hexHash[:5], hexHash[5:]
+
+	if corpus.rangeDir != "" {
+		file, err := os.Open(filepath.Join(corpus.rangeDir, prefix+".txt"))
+		if err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+		if err == nil {
+			defer file.Close()
+
+			breached, err := containsHashSuffix(file, suffix)
+			if err != nil || breached {
+				return breached, err
+			}
+		}
+	}
+
+	if corpus.apiUrl != "" {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", corpus.apiUrl, prefix), nil)
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Add-Padding", "true")
+
+		resp, err := corpus.client.Do(req)
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return false, fmt.Errorf("the breached password API returned status: %s", resp.Status)
+		}
+		return containsHashSuffix(resp.Body, suffix)
+	}
+
+	return false, nil
+}
+
+// IsPasswordBreached checks if the password is found in the breached password corpus configured by
+// breachedPasswordFile and breachedPasswordApiUrl.
+func IsPasswordBreached(password string) (bool, error) {
+	corpus := getBreachedPasswordCorpus()
+	if corpus == nil {
+		return false, fmt.Errorf("the breached password corpus isn't loaded")
+	}
+	return corpus.isBreached(password)
+}
+
+// isValidOption_NotBreached lets the password through when the corpus can't be queried, so an
+// unreachable mirror doesn't block every signup and password change.
+func isValidOption_NotBreached(password string, lang string) string {
+	breached, err := IsPasswordBreached(password)
+	if err != nil {
+		logs.Warn("failed to check the password against the breached password corpus: %v", err)
+		return ""
+	}
+
+	if breached {
+		return i18n.Translate(lang, "check:The password has appeared in a data breach, please choose a different password")
+	}
+	return ""
+}
+
+// CheckUserPasswordBreached requires the user to update the password at the next sign-in when it's
+// found in the breached password corpus and the organization screens breached passwords.
+func CheckUserPasswordBreached(user *User, password string) error {
+	organization, err := GetOrganizationByUser(user)
+	if err != nil {
+		return err
+	}
+	if organization == nil || user.NeedUpdatePassword || !util.InSlice(organization.PasswordOptions, PasswordOptionNotBreached) {
+		return nil
+	}
+
+	if isValidOption_NotBreached(password, "en") == "" {
+		return nil
+	}
+
+	user.NeedUpdatePassword = true
+	_, err = UpdateUser(user.GetId(), user, []string{"need_update_password"}, false)
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// the SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const breachedPasswordSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+
+func checkBreachedPasswords(t *testing.T, corpus *breachedPasswordCorpus, cases map[string]bool) {
+	for password, want := range cases {
+		breached, err := corpus.isBreached(password)
+		if err != nil {
+			t.Fatalf("isBreached(%s) failed: %v", password, err)
+		}
+		if breached != want {
+			t.Errorf("isBreached(%s) = %v, want %v", password, breached, want)
+		}
+	}
+}
+
+func TestBreachedPasswordListFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\n\nletmein\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	corpus, err := newBreachedPasswordCorpus(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBreachedPasswords(t, corpus, map[string]bool{"password": true, "letmein": true, "correct horse battery staple": false})
+}
+
+func TestBreachedPasswordRangeDir(t *testing.T) {
+	dir := t.TempDir()
+	content := fmt.Sprintf("0018A45C4D1DEF81644B54AB7F969B88D65:1\n%s:3861493\n", breachedPasswordSuffix)
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	corpus, err := newBreachedPasswordCorpus(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBreachedPasswords(t, corpus, map[string]bool{"password": true, "letmein": false})
+}
+
+func TestBreachedPasswordRangeApi(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/range/B7A87" {
+			// the suffix of "letmein" as a padding line, its count of 0 doesn't match
+			_, _ = fmt.Fprint(w, "5FC1EA228B9061041B7CEC4BD3C52AB3CE3:0\n")
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%s:3861493\n", breachedPasswordSuffix)
+	}))
+	defer server.Close()
+
+	corpus, err := newBreachedPasswordCorpus("", server.URL+"/range/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBreachedPasswords(t, corpus, map[string]bool{"password": true, "letmein": false})
+}
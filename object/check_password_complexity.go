@@ -86,6 +86,7 @@ func checkPasswordComplexity(password string, options []string, lang string) str
 		"Aa123":       isValidOption_Aa123,
 		"SpecialChar": isValidOption_SpecialChar,
 		"NoRepeat":    isValidOption_NoRepeat,
+		"NotBreached": isValidOption_NotBreached,
 	}
 
 	for _, option := range options {
@@ -0,0 +1,113 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/casdoor/casdoor/cred"
+	"github.com/casdoor/casdoor/i18n"
+	"github.com/casdoor/casdoor/util"
+)
+
+// PasswordHistoryItem is a previous password of a user, hashed by the cred.CredManager of its password type
+type PasswordHistoryItem struct {
+	Password     string `json:"password"`
+	PasswordType string `json:"passwordType"`
+	PasswordSalt string `json:"passwordSalt"`
+	ChangedTime  string `json:"changedTime"`
+}
+
+// CheckPasswordNotInHistory checks if the new password is different from the previous passwords of the user,
+// the history depth of the organization counts the current password too
+func CheckPasswordNotInHistory(user *User, newPassword string, organization *Organization) bool {
+	for i, item := range user.PasswordHistory {
+		if i >= organization.PasswordHistoryDepth-1 {
+			break
+		}
+
+		credManager := cred.GetCredManager(item.PasswordType)
+		if credManager == nil {
+			continue
+		}
+
+		if credManager.IsPasswordCorrect(newPassword, item.Password, item.PasswordSalt) ||
+			credManager.IsPasswordCorrect(newPassword, item.Password, organization.PasswordSalt) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckPasswordMinAge checks if the password of the user is old enough to be changed again,
+// a password that has to be updated can always be changed
+func CheckPasswordMinAge(user *User, organization *Organization, lang string) error {
+	if organization.PasswordMinAgeDays <= 0 || user.LastChangePasswordTime == "" || user.NeedUpdatePassword {
+		return nil
+	}
+
+	minAgeTime := util.String2Time(user.LastChangePasswordTime).AddDate(0, 0, organization.PasswordMinAgeDays)
+	if time.Now().Before(minAgeTime) {
+		return fmt.Errorf(i18n.Translate(lang, "check:The password can't be changed again until %s"), minAgeTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CheckPasswordReuse checks the new password against the current password and the password history of the user
+func CheckPasswordReuse(user *User, newPassword string, organization *Organization, lang string) error {
+	if !CheckPasswordNotSameAsCurrent(user, newPassword, organization) {
+		return fmt.Errorf("%s", i18n.Translate(lang, "user:The new password must be different from your current password"))
+	}
+
+	if !CheckPasswordNotInHistory(user, newPassword, organization) {
+		return fmt.Errorf(i18n.Translate(lang, "check:The new password must be different from your last %d passwords"), organization.PasswordHistoryDepth)
+	}
+	return nil
+}
+
+// AddPasswordHistory moves the current password of the user to its password history before it's replaced,
+// only as many passwords as the history depth of the organization needs are kept
+func (user *User) AddPasswordHistory(organization *Organization) {
+	depth := organization.PasswordHistoryDepth - 1
+	if depth <= 0 {
+		user.PasswordHistory = nil
+		return
+	}
+
+	if user.Password != "" {
+		passwordType := user.PasswordType
+		if passwordType == "" {
+			passwordType = organization.PasswordType
+		}
+		salt := user.PasswordSalt
+		if salt == "" {
+			salt = organization.PasswordSalt
+		}
+
+		item := &PasswordHistoryItem{
+			Password:     user.Password,
+			PasswordType: passwordType,
+			PasswordSalt: salt,
+			ChangedTime:  util.GetCurrentTime(),
+		}
+		user.PasswordHistory = append([]*PasswordHistoryItem{item}, user.PasswordHistory...)
+	}
+
+	if len(user.PasswordHistory) > depth {
+		user.PasswordHistory = user.PasswordHistory[:depth]
+	}
+}
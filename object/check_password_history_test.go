@@ -0,0 +1,109 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/casdoor/casdoor/util"
+)
+
+func TestPasswordHistory(t *testing.T) {
+	organization := &Organization{PasswordType: "salt", PasswordSalt: "org-salt", PasswordHistoryDepth: 3}
+	user := &User{}
+
+	for _, password := range []string{"first", "second", "third", "fourth"} {
+		if err := CheckPasswordReuse(user, password, organization, "en"); err != nil {
+			t.Fatalf("CheckPasswordReuse(%s) = %v, want nil", password, err)
+		}
+
+		user.AddPasswordHistory(organization)
+		user.Password = password
+		user.UpdateUserPassword(organization)
+	}
+
+	if len(user.PasswordHistory) != 2 {
+		t.Fatalf("the history holds %d passwords, want 2", len(user.PasswordHistory))
+	}
+
+	cases := []struct {
+		password string
+		ok       bool
+	}{
+		{"fourth", false},
+		{"third", false},
+		{"second", false},
+		{"first", true},
+	}
+	for _, c := range cases {
+		if err := CheckPasswordReuse(user, c.password, organization, "en"); (err == nil) != c.ok {
+			t.Errorf("CheckPasswordReuse(%s) = %v, want ok: %v", c.password, err, c.ok)
+		}
+	}
+
+	organization.PasswordHistoryDepth = 0
+	user.AddPasswordHistory(organization)
+	if user.PasswordHistory != nil {
+		t.Errorf("the history isn't cleared when the policy is disabled")
+	}
+}
+
+func TestCheckPasswordMinAge(t *testing.T) {
+	organization := &Organization{PasswordMinAgeDays: 1}
+	user := &User{LastChangePasswordTime: util.GetCurrentTime()}
+
+	if CheckPasswordMinAge(user, organization, "en") == nil {
+		t.Errorf("a password changed just now can be changed again")
+	}
+
+	user.NeedUpdatePassword = true
+	if err := CheckPasswordMinAge(user, organization, "en"); err != nil {
+		t.Errorf("a password that has to be updated can't be changed: %v", err)
+	}
+
+	user.NeedUpdatePassword = false
+	user.LastChangePasswordTime = util.Time2String(time.Now().AddDate(0, 0, -2))
+	if err := CheckPasswordMinAge(user, organization, "en"); err != nil {
+		t.Errorf("a password changed two days ago can't be changed: %v", err)
+	}
+}
+
+func TestUpdateUserForAllFieldsKeepsPasswordHistory(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	history := []*PasswordHistoryItem{{Password: "old-hash", PasswordType: "plain", ChangedTime: "2026-01-01T00:00:00Z"}}
+	_, err := ormer.Engine.Insert(&User{Owner: "org", Name: "alice", DisplayName: "Alice", PasswordHistory: history})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the user decoded from the JSON of a request has no password history
+	_, err = UpdateUserForAllFields("org/alice", &User{Owner: "org", Name: "alice", DisplayName: "Alice Liddell"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := getUser("org", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.DisplayName != "Alice Liddell" {
+		t.Errorf("the user isn't updated, the display name is: %s", user.DisplayName)
+	}
+	if len(user.PasswordHistory) != 1 || user.PasswordHistory[0].Password != "old-hash" {
+		t.Errorf("the password history is wiped: %v", user.PasswordHistory)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/util"
+)
+
+// The domain events are published when the state of an object changes, unlike the records they
+// don't depend on the API that made the change, webhooks subscribe to them by their type
+const (
+	DomainEventUserCreated         = "user.created"
+	DomainEventUserUpdated         = "user.updated"
+	DomainEventUserDeleted         = "user.deleted"
+	DomainEventUserPasswordChanged = "user.password_changed"
+	DomainEventUserMfaEnabled      = "user.mfa_enabled"
+	DomainEventUserMfaDisabled     = "user.mfa_disabled"
+	DomainEventSessionRevoked      = "session.revoked"
+	DomainEventPaymentPaid         = "payment.paid"
+	DomainEventSubscriptionExpired = "subscription.expired"
+)
+
+var DomainEventTypes = []string{
+	DomainEventUserCreated,
+	DomainEventUserUpdated,
+	DomainEventUserDeleted,
+	DomainEventUserPasswordChanged,
+	DomainEventUserMfaEnabled,
+	DomainEventUserMfaDisabled,
+	DomainEventSessionRevoked,
+	DomainEventPaymentPaid,
+	DomainEventSubscriptionExpired,
+}
+
+// DomainEvent is the payload of a domain event, Before is null for a created object and
+// After is null for a deleted one
+type DomainEvent struct {
+	Id           string          `json:"id"`
+	Type         string          `json:"type"`
+	Organization string          `json:"organization"`
+	Subject      string          `json:"subject"`
+	Time         string          `json:"time"`
+	Data         DomainEventData `json:"data"`
+}
+
+type DomainEventData struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// PublishDomainEvent queues the event for the webhooks and the event sinks subscribed to it, and the security
+// events it raises for the Shared Signals streams. A failure is only logged, the change that raised the event
+// has been made already and must not be reported as failed
+func PublishDomainEvent(organization string, eventType string, subject string, before interface{}, after interface{}) {
+	err := addEventSinkEventsFromDomainEvent(organization, eventType, subject, DomainEventData{Before: before, After: after})
+	if err != nil {
+		logs.Error("PublishDomainEvent() error: %s", err.Error())
+	}
+
+	err = addSsfEventsFromDomainEvent(organization, eventType, subject, before, after)
+	if err != nil {
+		logs.Error("PublishDomainEvent() error: %s", err.Error())
+	}
+
+	webhooks, err := getWebhooksByOrganization("")
+	if err != nil {
+		logs.Error("PublishDomainEvent() error: %s", err.Error())
+		return
+	}
+
+	for _, webhook := range getFilteredWebhooks(webhooks, organization, eventType) {
+		domainEvent := &DomainEvent{
+			Type:         eventType,
+			Organization: organization,
+			Subject:      subject,
+			Time:         util.GetCurrentTime(),
+			Data:         DomainEventData{Before: before, After: after},
+		}
+
+		_, err = CreateWebhookEventFromDomainEvent(webhook, domainEvent)
+		if err != nil {
+			logs.Error("PublishDomainEvent() error: webhook %s: %s", webhook.GetId(), err.Error())
+		}
+	}
+}
+
+// getDomainEventUser returns the snapshot of the user for a domain event, the secrets are masked
+// on a copy because the user is still used by the caller
+func getDomainEventUser(user *User) *User {
+	if user == nil {
+		return nil
+	}
+
+	userCopy := &User{}
+	err := json.Unmarshal([]byte(util.StructToJson(user)), userCopy)
+	if err != nil {
+		logs.Error("getDomainEventUser() error: %s", err.Error())
+		return nil
+	}
+
+	userCopy, _ = GetMaskedUser(userCopy, false)
+	return userCopy
+}
+
+// publishUserEvent publishes an event of the user, the snapshots are taken when it is called
+func publishUserEvent(eventType string, before *User, after *User) {
+	user := after
+	if user == nil {
+		user = before
+	}
+	if user == nil {
+		return
+	}
+
+	PublishDomainEvent(user.Owner, eventType, user.GetId(), getDomainEventUser(before), getDomainEventUser(after))
+}
+
+func getDomainEventSource(organization string) string {
+	return fmt.Sprintf("/organizations/%s", organization)
+}
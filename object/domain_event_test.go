@@ -0,0 +1,180 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func addDomainEventTestWebhook(t *testing.T, organization string, events []string) {
+	t.Helper()
+
+	_, err := ormer.Engine.Insert(&Webhook{Owner: "admin", Name: "webhook-events", Organization: organization, Url: "https://example.com/webhook", Events: events, IsEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getDomainEventTestTypes(t *testing.T) []string {
+	t.Helper()
+
+	webhookEvents := []*WebhookEvent{}
+	err := ormer.Engine.Asc("created_time").Find(&webhookEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventTypes := []string{}
+	for _, webhookEvent := range webhookEvents {
+		eventTypes = append(eventTypes, webhookEvent.EventType)
+	}
+	return eventTypes
+}
+
+func TestSoftDeleteUserPublishesDeletedOnly(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	_, err := ormer.Engine.Insert(&Organization{Owner: "admin", Name: "org-soft", EnableSoftDeletion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&User{Owner: "org-soft", Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addDomainEventTestWebhook(t, "org-soft", []string{DomainEventUserUpdated, DomainEventUserDeleted})
+
+	user, err := getUser("org-soft", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	affected, err := DeleteUser(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !affected {
+		t.Fatal("the user isn't deleted")
+	}
+
+	user, err = getUser("org-soft", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user == nil || !user.IsDeleted || user.DeletedTime == "" {
+		t.Fatalf("the user isn't soft deleted: %+v", user)
+	}
+
+	eventTypes := getDomainEventTestTypes(t)
+	if len(eventTypes) != 1 || eventTypes[0] != DomainEventUserDeleted {
+		t.Errorf("the soft deletion has published %v, want only %s", eventTypes, DomainEventUserDeleted)
+	}
+}
+
+func TestPayOrderWithoutBalancePublishesNoPaidEvent(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	inserts := []interface{}{
+		&Organization{Owner: "admin", Name: "org-pay"},
+		&User{Owner: "org-pay", Name: "alice"},
+		&Provider{Owner: "org-pay", Name: "provider-balance", Category: "Payment", Type: "Balance"},
+		&Product{Owner: "org-pay", Name: "product-1", Price: 10, Currency: "USD", Quantity: 10, Providers: []string{"provider-balance"}, State: "Published"},
+		&Order{Owner: "org-pay", Name: "order-1", Products: []string{"product-1"}, User: "alice", Price: 10, Currency: "USD", State: "Created"},
+	}
+	for _, insert := range inserts {
+		_, err := ormer.Engine.Insert(insert)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	addDomainEventTestWebhook(t, "org-pay", []string{DomainEventPaymentPaid})
+
+	order, err := getOrder("org-pay", "order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = PayOrder("provider-balance", "casdoor.example.com", "", order, "en")
+	if err == nil {
+		t.Fatal("a payment beyond the balance of the user is accepted")
+	}
+
+	eventTypes := getDomainEventTestTypes(t)
+	if len(eventTypes) != 0 {
+		t.Errorf("the failed payment has published %v", eventTypes)
+	}
+}
+
+func TestUpdateUserPublishesEvents(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	_, err := ormer.Engine.Insert(&Organization{Owner: "admin", Name: "org-update"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&User{Owner: "org-update", Name: "alice", Password: "hash-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addDomainEventTestWebhook(t, "org-update", []string{DomainEventUserUpdated, DomainEventUserPasswordChanged})
+
+	assertEventTypes := func(expected ...string) {
+		t.Helper()
+
+		eventTypes := getDomainEventTestTypes(t)
+		sort.Strings(eventTypes)
+		sort.Strings(expected)
+		if strings.Join(eventTypes, ",") != strings.Join(expected, ",") {
+			t.Errorf("unexpected events: %v, expected: %v", eventTypes, expected)
+		}
+
+		_, err := ormer.Engine.Where("1 = 1").Delete(&WebhookEvent{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the default columns of UpdateUser() don't write the password
+	user, err := getUser("org-update", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.DisplayName = "Alice"
+	user.Password = "hash-2"
+	_, err = UpdateUser(user.GetId(), user, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEventTypes(DomainEventUserUpdated)
+
+	// all the fields are written, so a changed password hash is a changed password
+	user, err = getUser("org-update", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.DisplayName = "Alice Liddell"
+	_, err = UpdateUserForAllFields(user.GetId(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEventTypes(DomainEventUserUpdated)
+
+	user.Password = "hash-2"
+	_, err = UpdateUserForAllFields(user.GetId(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEventTypes(DomainEventUserUpdated, DomainEventUserPasswordChanged)
+}
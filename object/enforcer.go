@@ -334,6 +334,8 @@ func UpdatePolicy(id string, ptype string, oldPolicy []string, newPolicy []strin
 		return false, err
 	}
 
+	defer enforcer.invalidatePermissionEnforcerCache()
+
 	if ptype == "p" {
 		return enforcer.UpdatePolicy(oldPolicy, newPolicy)
 	} else {
@@ -347,6 +349,8 @@ func AddPolicy(id string, ptype string, policy []string) (bool, error) {
 		return false, err
 	}
 
+	defer enforcer.invalidatePermissionEnforcerCache()
+
 	if ptype == "p" {
 		return enforcer.AddPolicy(policy)
 	} else {
@@ -360,6 +364,8 @@ func RemovePolicy(id string, ptype string, policy []string) (bool, error) {
 		return false, err
 	}
 
+	defer enforcer.invalidatePermissionEnforcerCache()
+
 	if ptype == "p" {
 		return enforcer.RemovePolicy(policy)
 	} else {
@@ -367,6 +373,18 @@ func RemovePolicy(id string, ptype string, policy []string) (bool, error) {
 	}
 }
 
+// invalidatePermissionEnforcerCache drops the cached permission enforcers that load
+// their policies from the table of the enforcer's adapter
+func (enforcer *Enforcer) invalidatePermissionEnforcerCache() {
+	adapter, err := GetAdapter(enforcer.Adapter)
+	if err != nil || adapter == nil {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeAll)
+		return
+	}
+
+	invalidatePermissionEnforcerCache(permissionEnforcerScopeTable, adapter.Table)
+}
+
 func (enforcer *Enforcer) LoadModelCfg() error {
 	if enforcer.ModelCfg != nil {
 		return nil
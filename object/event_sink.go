@@ -0,0 +1,215 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/casdoor/casdoor/i18n"
+	"github.com/casdoor/casdoor/util"
+	"github.com/xorm-io/core"
+)
+
+// EventSink publishes the records and the domain events to a message broker, like a webhook does
+// over HTTP. Its deliveries go through the webhook event outbox, so they are retried and replayed
+// the same way.
+type EventSink struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	Organization string `xorm:"varchar(100) index" json:"organization"`
+
+	Type          string   `xorm:"varchar(100)" json:"type"`
+	Endpoint      string   `xorm:"varchar(500)" json:"endpoint"`
+	Topic         string   `xorm:"varchar(200)" json:"topic"`
+	Username      string   `xorm:"varchar(100)" json:"username"`
+	Password      string   `xorm:"varchar(200)" json:"password"`
+	Events        []string `xorm:"varchar(1000)" json:"events"`
+	PayloadFormat string   `xorm:"varchar(100)" json:"payloadFormat"`
+	PartitionKey  string   `xorm:"varchar(100)" json:"partitionKey"`
+	SingleOrgOnly bool     `json:"singleOrgOnly"`
+	IsEnabled     bool     `json:"isEnabled"`
+
+	// Retry configuration
+	MaxRetries            int  `xorm:"int default 3" json:"maxRetries"`
+	RetryInterval         int  `xorm:"int default 60" json:"retryInterval"` // seconds
+	UseExponentialBackoff bool `json:"useExponentialBackoff"`
+}
+
+func GetMaskedEventSink(eventSink *EventSink, isMaskEnabled bool) *EventSink {
+	if !isMaskEnabled {
+		return eventSink
+	}
+
+	if eventSink == nil {
+		return nil
+	}
+
+	if eventSink.Password != "" {
+		eventSink.Password = "***"
+	}
+	return eventSink
+}
+
+func GetMaskedEventSinks(eventSinks []*EventSink, isMaskEnabled bool) []*EventSink {
+	if !isMaskEnabled {
+		return eventSinks
+	}
+
+	for _, eventSink := range eventSinks {
+		eventSink = GetMaskedEventSink(eventSink, isMaskEnabled)
+	}
+	return eventSinks
+}
+
+func GetEventSinkCount(owner, organization, field, value string) (int64, error) {
+	session := GetSession(owner, -1, -1, field, value, "", "")
+	return session.Count(&EventSink{Organization: organization})
+}
+
+func GetEventSinks(owner string, organization string) ([]*EventSink, error) {
+	eventSinks := []*EventSink{}
+	err := ormer.Engine.Desc("created_time").Find(&eventSinks, &EventSink{Owner: owner, Organization: organization})
+	if err != nil {
+		return eventSinks, err
+	}
+
+	return eventSinks, nil
+}
+
+// HasAnyEventSinks reports whether the database has at least one event sink configuration.
+func HasAnyEventSinks() (bool, error) {
+	count, err := ormer.Engine.Count(&EventSink{})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func GetPaginationEventSinks(owner, organization string, offset, limit int, field, value, sortField, sortOrder string) ([]*EventSink, error) {
+	eventSinks := []*EventSink{}
+	session := GetSession(owner, offset, limit, field, value, sortField, sortOrder)
+	err := session.Find(&eventSinks, &EventSink{Organization: organization})
+	if err != nil {
+		return nil, err
+	}
+
+	return eventSinks, nil
+}
+
+func getEventSinksByOrganization(organization string) ([]*EventSink, error) {
+	eventSinks := []*EventSink{}
+	err := ormer.Engine.Desc("created_time").Find(&eventSinks, &EventSink{Organization: organization})
+	if err != nil {
+		return eventSinks, err
+	}
+
+	return eventSinks, nil
+}
+
+func getEventSink(owner string, name string) (*EventSink, error) {
+	if owner == "" || name == "" {
+		return nil, nil
+	}
+
+	eventSink := EventSink{Owner: owner, Name: name}
+	existed, err := ormer.Engine.Get(&eventSink)
+	if err != nil {
+		return &eventSink, err
+	}
+
+	if existed {
+		return &eventSink, nil
+	} else {
+		return nil, nil
+	}
+}
+
+func GetEventSink(id string) (*EventSink, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getEventSink(owner, name)
+}
+
+func GetEventSinkByOrganization(id string, organization string) (*EventSink, error) {
+	eventSink, err := GetEventSink(id)
+	if err != nil {
+		return nil, err
+	}
+	if eventSink == nil || eventSink.Organization != organization {
+		return nil, nil
+	}
+
+	return eventSink, nil
+}
+
+func UpdateEventSink(id string, eventSink *EventSink, isGlobalAdmin bool, lang string) (bool, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return false, err
+	}
+	s, err := getEventSink(owner, name)
+	if err != nil {
+		return false, err
+	} else if s == nil {
+		return false, nil
+	} else if !isGlobalAdmin && s.Organization != eventSink.Organization {
+		return false, errors.New(i18n.Translate(lang, "auth:Unauthorized operation"))
+	}
+
+	session := ormer.Engine.ID(core.PK{owner, name}).Where("organization = ?", s.Organization).AllCols()
+	if eventSink.Password == "***" {
+		session = session.Omit("password")
+	}
+
+	affected, err := session.Update(eventSink)
+	if err != nil {
+		return false, err
+	}
+
+	// the next delivery connects with the new configuration
+	closeEventSinkProvider(s.GetId())
+	return affected != 0, nil
+}
+
+func AddEventSink(eventSink *EventSink) (bool, error) {
+	affected, err := ormer.Engine.Insert(eventSink)
+	if err != nil {
+		return false, err
+	}
+
+	if affected != 0 {
+		StartWebhookDeliveryWorker()
+	}
+	return affected != 0, nil
+}
+
+func DeleteEventSink(eventSink *EventSink) (bool, error) {
+	affected, err := ormer.Engine.ID(core.PK{eventSink.Owner, eventSink.Name}).Where("organization = ?", eventSink.Organization).Delete(&EventSink{})
+	if err != nil {
+		return false, err
+	}
+
+	closeEventSinkProvider(eventSink.GetId())
+	return affected != 0, nil
+}
+
+func (s *EventSink) GetId() string {
+	return fmt.Sprintf("%s/%s", s.Owner, s.Name)
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+
+	"github.com/xorm-io/xorm"
+)
+
+// EventSinkSequence is the counter the events of an event sink are numbered by. It is kept in the
+// database, so the events created by different instances are ordered without relying on their clocks.
+type EventSinkSequence struct {
+	Name  string `xorm:"varchar(200) notnull pk" json:"name"`
+	Value int64  `xorm:"bigint" json:"value"`
+}
+
+// addEventSinkEvent numbers the event with the next sequence of its event sink and inserts it in the
+// same transaction. The sequence row stays locked until the event is inserted, so an event can't be
+// delivered before an earlier one of its partition has been stored.
+func addEventSinkEvent(event *WebhookEvent) (bool, error) {
+	var err error
+	// the first events of a sink may race to create its sequence, the loser retries with the created one
+	for i := 0; i < 3; i++ {
+		var affected bool
+		affected, err = addEventSinkEventWithSequence(event)
+		if err == nil {
+			return affected, nil
+		}
+	}
+	return false, err
+}
+
+func addEventSinkEventWithSequence(event *WebhookEvent) (bool, error) {
+	session := ormer.Engine.NewSession()
+	defer session.Close()
+
+	err := session.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	event.Sequence, err = getNextEventSinkSequence(session, event.EventSink)
+	if err != nil {
+		session.Rollback()
+		return false, err
+	}
+
+	affected, err := session.Insert(event)
+	if err != nil {
+		session.Rollback()
+		return false, err
+	}
+
+	err = session.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func getNextEventSinkSequence(session *xorm.Session, eventSinkId string) (int64, error) {
+	affected, err := session.Where("name = ?", eventSinkId).Incr("value").Update(&EventSinkSequence{})
+	if err != nil {
+		return 0, err
+	}
+
+	if affected == 0 {
+		_, err = session.Insert(&EventSinkSequence{Name: eventSinkId, Value: 1})
+		if err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	sequence := EventSinkSequence{Name: eventSinkId}
+	existed, err := session.Get(&sequence)
+	if err != nil {
+		return 0, err
+	}
+	if !existed {
+		return 0, fmt.Errorf("the sequence of event sink %s is not found", eventSinkId)
+	}
+
+	return sequence.Value, nil
+}
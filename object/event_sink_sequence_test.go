@@ -0,0 +1,92 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+
+	"github.com/xorm-io/core"
+)
+
+func TestEventSinkEventPartitionOrdering(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	eventSink := &EventSink{Owner: "admin", Name: "sink-ordering", Organization: "org-ordering", PartitionKey: EventSinkPartitionKeySubject}
+	otherEventSink := &EventSink{Owner: "admin", Name: "sink-other", Organization: "org-ordering", PartitionKey: EventSinkPartitionKeySubject}
+
+	events := []*WebhookEvent{}
+	for _, subject := range []string{"org-ordering/alice", "org-ordering/bob", "org-ordering/alice", "org-ordering/alice"} {
+		event, err := CreateEventSinkEventFromDomainEvent(eventSink, &DomainEvent{Organization: "org-ordering", Type: DomainEventUserUpdated, Subject: subject})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+
+	otherEvent, err := CreateEventSinkEventFromDomainEvent(otherEventSink, &DomainEvent{Organization: "org-ordering", Type: DomainEventUserUpdated, Subject: "org-ordering/alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each sink numbers its events on its own, in the order they are created
+	for i, event := range events {
+		if event.Sequence != int64(i+1) {
+			t.Errorf("unexpected sequence of event %d: %d, expected: %d", i, event.Sequence, i+1)
+		}
+	}
+	if otherEvent.Sequence != 1 {
+		t.Errorf("unexpected sequence of the other sink: %d, expected: 1", otherEvent.Sequence)
+	}
+
+	assertHeld := func(event *WebhookEvent, expected bool) {
+		t.Helper()
+
+		held, err := hasEarlierEventSinkEvent(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held != expected {
+			t.Errorf("unexpected hold of the event with sequence %d: %v, expected: %v", event.Sequence, held, expected)
+		}
+	}
+
+	setState := func(event *WebhookEvent, state WebhookEventStatus) {
+		t.Helper()
+
+		_, err := ormer.Engine.ID(core.PK{event.Owner, event.Name}).Cols("state").Update(&WebhookEvent{State: state})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// only the first event of a partition is delivered, the other partitions and sinks aren't held back
+	assertHeld(events[0], false)
+	assertHeld(events[1], false)
+	assertHeld(events[2], true)
+	assertHeld(events[3], true)
+	assertHeld(otherEvent, false)
+
+	// a delivered event releases the next one of its partition
+	setState(events[0], WebhookEventStatusSuccess)
+	assertHeld(events[2], false)
+	assertHeld(events[3], true)
+
+	// an event which is being retried still holds the partition back, a failed one doesn't
+	setState(events[2], WebhookEventStatusRetrying)
+	assertHeld(events[3], true)
+
+	setState(events[2], WebhookEventStatusFailed)
+	assertHeld(events[3], false)
+}
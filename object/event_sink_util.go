@@ -0,0 +1,255 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/eventsink"
+	"github.com/casdoor/casdoor/util"
+)
+
+const (
+	EventSinkPartitionKeyOrganization = "Organization"
+	EventSinkPartitionKeySubject      = "Subject"
+)
+
+type cachedEventSinkProvider struct {
+	config   string
+	provider eventsink.EventSinkProvider
+}
+
+// the broker connections are kept open between the deliveries, a sink updated on another
+// instance is reconnected as soon as its configuration is seen to differ
+var (
+	eventSinkProvidersMu sync.Mutex
+	eventSinkProviders   = map[string]*cachedEventSinkProvider{}
+)
+
+func getEventSinkProvider(eventSink *EventSink) (eventsink.EventSinkProvider, error) {
+	config := util.StructToJson([]string{eventSink.Type, eventSink.Endpoint, eventSink.Topic, eventSink.Username, eventSink.Password})
+
+	eventSinkProvidersMu.Lock()
+	defer eventSinkProvidersMu.Unlock()
+
+	cached, ok := eventSinkProviders[eventSink.GetId()]
+	if ok && cached.config == config {
+		return cached.provider, nil
+	}
+	if ok {
+		_ = cached.provider.Close()
+		delete(eventSinkProviders, eventSink.GetId())
+	}
+
+	provider, err := eventsink.GetEventSinkProvider(eventSink.Type, eventSink.Endpoint, eventSink.Topic, eventSink.Username, eventSink.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	eventSinkProviders[eventSink.GetId()] = &cachedEventSinkProvider{config: config, provider: provider}
+	return provider, nil
+}
+
+func closeEventSinkProvider(id string) {
+	eventSinkProvidersMu.Lock()
+	defer eventSinkProvidersMu.Unlock()
+
+	cached, ok := eventSinkProviders[id]
+	if !ok {
+		return
+	}
+
+	err := cached.provider.Close()
+	if err != nil {
+		logs.Warning(fmt.Sprintf("failed to close event sink %s: %v", id, err))
+	}
+	delete(eventSinkProviders, id)
+}
+
+// getPartitionKey returns the key the events are ordered by, the subject is the user of a record
+// and the object of a domain event
+func (s *EventSink) getPartitionKey(organization string, subject string) string {
+	switch s.PartitionKey {
+	case EventSinkPartitionKeyOrganization:
+		return organization
+	case EventSinkPartitionKeySubject:
+		return subject
+	}
+	return ""
+}
+
+func getFilteredEventSinks(eventSinks []*EventSink, organization string, action string) []*EventSink {
+	res := []*EventSink{}
+	for _, eventSink := range eventSinks {
+		if !eventSink.IsEnabled {
+			continue
+		}
+
+		if eventSink.SingleOrgOnly {
+			if eventSink.Organization != organization {
+				continue
+			}
+		}
+
+		if util.InSlice(eventSink.Events, action) {
+			res = append(res, eventSink)
+		}
+	}
+	return res
+}
+
+func addEventSinkEventsFromRecord(record *Record) error {
+	eventSinks, err := getEventSinksByOrganization("")
+	if err != nil {
+		return err
+	}
+
+	for _, eventSink := range getFilteredEventSinks(eventSinks, record.Organization, record.Action) {
+		_, err = CreateEventSinkEventFromRecord(eventSink, record)
+		if err != nil {
+			return fmt.Errorf("event sink %s: failed to create event: %w", eventSink.GetId(), err)
+		}
+	}
+	return nil
+}
+
+func addEventSinkEventsFromDomainEvent(organization string, eventType string, subject string, data DomainEventData) error {
+	eventSinks, err := getEventSinksByOrganization("")
+	if err != nil {
+		return err
+	}
+
+	for _, eventSink := range getFilteredEventSinks(eventSinks, organization, eventType) {
+		domainEvent := &DomainEvent{
+			Type:         eventType,
+			Organization: organization,
+			Subject:      subject,
+			Time:         util.GetCurrentTime(),
+			Data:         data,
+		}
+
+		_, err = CreateEventSinkEventFromDomainEvent(eventSink, domainEvent)
+		if err != nil {
+			return fmt.Errorf("event sink %s: failed to create event: %w", eventSink.GetId(), err)
+		}
+	}
+	return nil
+}
+
+// hasEarlierEventSinkEvent reports whether an earlier event of the same partition is still waiting for
+// its delivery, the event is held back until then. A failed event doesn't hold the partition back.
+func hasEarlierEventSinkEvent(event *WebhookEvent) (bool, error) {
+	if event.PartitionKey == "" {
+		return false, nil
+	}
+
+	return ormer.Engine.Where("event_sink = ? and partition_key = ? and sequence < ?", event.EventSink, event.PartitionKey, event.Sequence).
+		In("state", WebhookEventStatusPending, WebhookEventStatusRetrying).
+		Exist(&WebhookEvent{})
+}
+
+func getEventSinkMessage(eventSink *EventSink, event *WebhookEvent) (*eventsink.Message, error) {
+	var body string
+	if event.IsDomainEvent {
+		var domainEvent DomainEvent
+		err := json.Unmarshal([]byte(event.Payload), &domainEvent)
+		if err != nil {
+			return nil, err
+		}
+
+		body = getDomainEventBody(&domainEvent, eventSink.PayloadFormat)
+	} else {
+		var record Record
+		err := json.Unmarshal([]byte(event.Payload), &record)
+		if err != nil {
+			return nil, err
+		}
+
+		body = util.StructToJson(record)
+		if eventSink.PayloadFormat == WebhookPayloadFormatCloudEvents {
+			body = getCloudEventBody(event.Name, record.Organization, record.Action, record.User, record.CreatedTime, body)
+		}
+	}
+
+	contentType := "application/json"
+	if eventSink.PayloadFormat == WebhookPayloadFormatCloudEvents {
+		contentType = cloudEventContentType
+	}
+
+	message := &eventsink.Message{
+		Id:          event.Name,
+		Key:         event.PartitionKey,
+		Type:        event.EventType,
+		ContentType: contentType,
+		Body:        []byte(body),
+	}
+	return message, nil
+}
+
+// deliverEventSinkEvent attempts to deliver a single event to its event sink
+func deliverEventSinkEvent(event *WebhookEvent) {
+	eventSink, err := GetEventSink(event.EventSink)
+	if err != nil {
+		logs.Error(fmt.Sprintf("failed to get event sink %s: %v", event.EventSink, err))
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("get event sink: %w", err))
+		return
+	}
+
+	if eventSink == nil {
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("event sink not found"))
+		return
+	}
+
+	if !eventSink.IsEnabled {
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("event sink is disabled"))
+		return
+	}
+
+	held, err := hasEarlierEventSinkEvent(event)
+	if err != nil {
+		logs.Error(fmt.Sprintf("failed to check the partition of event sink event %s: %v", event.GetId(), err))
+		return
+	}
+	if held {
+		return
+	}
+
+	message, err := getEventSinkMessage(eventSink, event)
+	if err != nil {
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("invalid payload: %w", err))
+		return
+	}
+
+	event.AttemptCount++
+
+	provider, err := getEventSinkProvider(eventSink)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err = provider.Send(ctx, message)
+		cancel()
+
+		if err != nil {
+			// the connection may be broken, the next attempt reconnects
+			closeEventSinkProvider(eventSink.GetId())
+		}
+	}
+
+	finishWebhookEventDelivery(event, err == nil, eventSink.MaxRetries, eventSink.RetryInterval, eventSink.UseExponentialBackoff, 0, "", err)
+}
@@ -50,6 +50,8 @@ type Group struct {
 	// GidNumber is the POSIX gid published by the built-in LDAP server, 0 when unassigned.
 	GidNumber  int               `xorm:"index" json:"gidNumber"`
 	Properties map[string]string `xorm:"mediumtext" json:"properties"`
+
+	RadiusReplyAttributes []*RadiusReplyAttribute `xorm:"mediumtext" json:"radiusReplyAttributes"`
 }
 
 type GroupNode struct{}
@@ -177,6 +179,10 @@ func UpdateGroup(id string, group *Group, isGlobalAdmin bool, lang string) (bool
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeGroup, owner, group.Owner)
+	}
+
 	return affected != 0, nil
 }
 
@@ -191,6 +197,10 @@ func AddGroup(group *Group) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeGroup, group.Owner)
+	}
+
 	return affected != 0, nil
 }
 
@@ -202,6 +212,10 @@ func AddGroups(groups []*Group) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeGroup, getGroupOwners(groups)...)
+	}
 	return affected != 0, nil
 }
 
@@ -237,6 +251,7 @@ func AddGroupsInBatch(groups []*Group) (bool, error) {
 		return false, err
 	}
 
+	invalidatePermissionEnforcerCache(permissionEnforcerScopeGroup, getGroupOwners(groups)...)
 	return true, nil
 }
 
@@ -246,6 +261,10 @@ func deleteGroup(group *Group) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeGroup, group.Owner)
+	}
+
 	return affected != 0, nil
 }
 
@@ -287,6 +306,14 @@ func checkGroupName(name string) error {
 	return nil
 }
 
+func getGroupOwners(groups []*Group) []string {
+	owners := []string{}
+	for _, group := range groups {
+		owners = append(owners, group.Owner)
+	}
+	return owners
+}
+
 func (group *Group) GetId() string {
 	return fmt.Sprintf("%s/%s", group.Owner, group.Name)
 }
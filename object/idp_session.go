@@ -0,0 +1,304 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/casdoor/casdoor/idp"
+	"github.com/casdoor/casdoor/util"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// a logout token is only accepted while it's recent, since it doesn't need an exp, and its jti is
+// remembered for as long so that it is accepted once
+const providerLogoutTokenMaxAge = 10 * time.Minute
+
+// IdpSession records that a Beego session was signed in through an upstream identity provider,
+// with the subject and the session id the provider knows the user by, so that the session can
+// be ended when the provider logs the user out.
+type IdpSession struct {
+	Owner        string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name         string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime  string `xorm:"varchar(100)" json:"createdTime"`
+	Provider     string `xorm:"varchar(100) index" json:"provider"`
+	Organization string `xorm:"varchar(100) index" json:"organization"`
+	User         string `xorm:"varchar(100) index" json:"user"`
+	SessionId    string `xorm:"varchar(100) index" json:"sessionId"`
+	Subject      string `xorm:"varchar(100) index" json:"subject"`
+	Sid          string `xorm:"varchar(100) index" json:"sid"`
+}
+
+// AddIdpSession records the upstream session of the Beego session, a Beego session reused by
+// a quick sign-in is recorded only once for the provider.
+func AddIdpSession(idpSession *IdpSession) (bool, error) {
+	existed, err := ormer.Engine.Exist(&IdpSession{Owner: idpSession.Owner, Provider: idpSession.Provider, SessionId: idpSession.SessionId})
+	if err != nil {
+		return false, err
+	}
+	if existed {
+		return false, nil
+	}
+
+	idpSession.Name = util.GenerateId()
+	idpSession.CreatedTime = util.GetCurrentTime()
+	affected, err := ormer.Engine.Insert(idpSession)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// GetIdpSessions returns the sessions signed in through the provider for the upstream session. When both
+// the subject and the sid are given, the sid only has to match for the sessions a sid was recorded for, as
+// the providers that don't give one at sign-in may still name it when they log the user out.
+func GetIdpSessions(provider *Provider, subject string, sid string) ([]*IdpSession, error) {
+	idpSessions := []*IdpSession{}
+	if subject == "" && sid == "" {
+		return idpSessions, nil
+	}
+
+	session := ormer.Engine.Where("owner = ? and provider = ?", provider.Owner, provider.Name)
+	if subject != "" {
+		session = session.And("subject = ?", subject)
+		if sid != "" {
+			session = session.And("(sid = ? or sid = '' or sid is null)", sid)
+		}
+	} else {
+		session = session.And("sid = ?", sid)
+	}
+
+	err := session.Find(&idpSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return idpSessions, nil
+}
+
+// GetIdpSessionBySessionId returns the upstream session of the provider the Beego session was signed in through
+func GetIdpSessionBySessionId(provider *Provider, sessionId string) (*IdpSession, error) {
+	idpSession := IdpSession{Owner: provider.Owner, Provider: provider.Name, SessionId: sessionId}
+	existed, err := ormer.Engine.Get(&idpSession)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	return &idpSession, nil
+}
+
+// DeleteIdpSessionsByUser drops the upstream sessions of the user, only the ones of the Beego session
+// when sessionId isn't empty, it is called when the user logs out of Casdoor
+func DeleteIdpSessionsByUser(organization string, user string, sessionId string) error {
+	session := ormer.Engine.Where(fmt.Sprintf("organization = ? and %s = ?", quoteColumn("user")), organization, user)
+	if sessionId != "" {
+		session = session.And("session_id = ?", sessionId)
+	}
+
+	_, err := session.Delete(&IdpSession{})
+	return err
+}
+
+// LogoutIdpSessions ends the Beego sessions signed in through the upstream sessions, then propagates
+// the logout to the OIDC applications and the SAML service providers of those sessions
+func LogoutIdpSessions(idpSessions []*IdpSession, host string) error {
+	for _, idpSession := range idpSessions {
+		// the upstream session is dropped along with the Beego session by DeleteUserSessionId
+		DeleteBeegoSession([]string{idpSession.SessionId})
+		err := DeleteUserSessionId(idpSession.Organization, idpSession.User, idpSession.SessionId)
+		if err != nil {
+			return err
+		}
+
+		SendBackchannelLogout(idpSession.Organization, idpSession.User, idpSession.SessionId, host)
+	}
+
+	return nil
+}
+
+// getProviderOidcIssuer returns the OpenID Connect issuer of an OAuth provider, the one that signs
+// its logout tokens
+func getProviderOidcIssuer(provider *Provider) string {
+	switch provider.Type {
+	case "Okta", "Casdoor":
+		return provider.Domain
+	case "ADFS":
+		return fmt.Sprintf("%s/adfs", strings.TrimSuffix(provider.Domain, "/"))
+	default:
+		return provider.IssuerUrl
+	}
+}
+
+// ParseProviderLogoutToken validates the logout token sent by the provider to the back-channel logout
+// endpoint and returns the upstream sessions it logs out
+func ParseProviderLogoutToken(provider *Provider, logoutToken string) ([]*IdpSession, error) {
+	if provider.Category != "OAuth" {
+		return nil, fmt.Errorf("the provider: %s doesn't support back-channel logout", provider.GetId())
+	}
+
+	idpInfo, err := FromProviderToIdpInfo(nil, provider)
+	if err != nil {
+		return nil, err
+	}
+	idpInfo.HostUrl = getProviderOidcIssuer(provider)
+
+	idProvider := idp.NewOidcIdProvider(idpInfo, "")
+	token, err := idProvider.ValidateLogoutToken(logoutToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkProviderLogoutTokenReplay(provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetIdpSessions(provider, token.Sub, token.Sid)
+}
+
+// checkProviderLogoutTokenReplay refuses a logout token of the provider which is too old or has been received already
+func checkProviderLogoutTokenReplay(provider *Provider, token *idp.OidcLogoutToken) error {
+	if time.Since(token.IssuedAt) > providerLogoutTokenMaxAge {
+		return fmt.Errorf("the logout token is invalid: it was issued more than %s ago", providerLogoutTokenMaxAge)
+	}
+
+	// the iat may be up to a minute ahead, see the leeway of ValidateLogoutToken()
+	expiresAt := token.IssuedAt.Add(providerLogoutTokenMaxAge + time.Minute)
+	if !ReceivedJtiMap.Add(fmt.Sprintf("logout:%s:%s", provider.GetId(), token.Jti), expiresAt) {
+		return fmt.Errorf("the logout token is invalid: the jti: %s has been used already", token.Jti)
+	}
+	return nil
+}
+
+// ParseProviderSamlLogoutRequest validates the LogoutRequest sent by a SAML identity provider, it has to be
+// signed by the IdP certificate, and returns it with the upstream sessions it logs out
+func ParseProviderSamlLogoutRequest(provider *Provider, samlRequest string, querySignature *SamlQuerySignature) (*SamlLogoutRequest, []*IdpSession, error) {
+	if provider.Category != "SAML" {
+		return nil, nil, fmt.Errorf("the provider: %s doesn't support SAML single logout", provider.GetId())
+	}
+
+	requestByte, err := decodeSamlMessage(samlRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("err: Failed to decode SAML request, %s", err.Error())
+	}
+
+	cert, err := parseSamlSpCertificate(provider.IdP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the IdP certificate of the provider: %s, %s", provider.GetId(), err.Error())
+	}
+
+	requestByte, err = validateSamlMessageSignature(cert, "SAMLRequest", samlRequest, requestByte, querySignature, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var logoutRequest SamlLogoutRequest
+	err = xml.Unmarshal(requestByte, &logoutRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("err: Failed to unmarshal LogoutRequest, please check the SAML request, %s", err.Error())
+	}
+
+	if provider.IssuerUrl != "" && logoutRequest.Issuer != provider.IssuerUrl {
+		return nil, nil, fmt.Errorf("the issuer: %s of the LogoutRequest is not the provider: %s", logoutRequest.Issuer, provider.GetId())
+	}
+	if logoutRequest.NameID == "" {
+		return nil, nil, fmt.Errorf("the LogoutRequest has no NameID")
+	}
+
+	if len(logoutRequest.SessionIndex) == 0 {
+		idpSessions, err := GetIdpSessions(provider, logoutRequest.NameID, "")
+		return &logoutRequest, idpSessions, err
+	}
+
+	idpSessions := []*IdpSession{}
+	for _, sessionIndex := range logoutRequest.SessionIndex {
+		sessions, err := GetIdpSessions(provider, logoutRequest.NameID, sessionIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		idpSessions = append(idpSessions, sessions...)
+	}
+	return &logoutRequest, idpSessions, nil
+}
+
+// getProviderSamlSloService returns the location and the binding of the Single Logout Service in the metadata of
+// the SAML identity provider that a LogoutResponse is sent to, the binding the LogoutRequest came by is preferred
+func getProviderSamlSloService(provider *Provider, requestBinding string) (string, string, error) {
+	var entityDescriptor struct {
+		IdpSsoDescriptor struct {
+			SingleLogoutServices []struct {
+				Binding          string `xml:"Binding,attr"`
+				Location         string `xml:"Location,attr"`
+				ResponseLocation string `xml:"ResponseLocation,attr"`
+			} `xml:"SingleLogoutService"`
+		} `xml:"IDPSSODescriptor"`
+	}
+	err := xml.Unmarshal([]byte(provider.Metadata), &entityDescriptor)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse the SAML metadata of the provider: %s, %s", provider.GetId(), err.Error())
+	}
+
+	location, binding := "", ""
+	for _, slo := range entityDescriptor.IdpSsoDescriptor.SingleLogoutServices {
+		sloBinding := strings.TrimPrefix(slo.Binding, samlBindingPrefix)
+		if sloBinding != SamlBindingRedirect && sloBinding != SamlBindingPost {
+			continue
+		}
+
+		// a LogoutResponse goes to the ResponseLocation when there is one
+		sloLocation := slo.ResponseLocation
+		if sloLocation == "" {
+			sloLocation = slo.Location
+		}
+		if location == "" || sloBinding == requestBinding {
+			location, binding = sloLocation, sloBinding
+		}
+		if sloBinding == requestBinding {
+			break
+		}
+	}
+
+	if location == "" {
+		return "", "", fmt.Errorf("the SAML metadata of the provider: %s has no Single Logout Service over HTTP-Redirect or HTTP-POST", provider.GetId())
+	}
+	return location, binding, nil
+}
+
+// GetProviderSamlLogoutResponse builds the LogoutResponse to the LogoutRequest of a SAML identity provider, signed with
+// the key Casdoor signs its AuthnRequests with, see encodeSamlLogoutMessage for the returned values
+func GetProviderSamlLogoutResponse(provider *Provider, logoutRequest *SamlLogoutRequest, requestBinding string, relayState string, host string) (string, url.Values, error) {
+	location, binding, err := getProviderSamlSloService(provider, requestBinding)
+	if err != nil {
+		return "", nil, err
+	}
+
+	keyStore, err := buildSpKeyStore()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the issuer is the one of the service provider of the AuthnRequests, see buildSp()
+	_, origin := getOriginFromHost(host)
+	logoutResponse := newSamlLogoutResponse(fmt.Sprintf("%s/api/acs", origin), location, logoutRequest.ID)
+	return encodeSamlMessage(dsig.NewDefaultSigningContext(keyStore), binding, location, logoutResponse, "SAMLResponse", relayState)
+}
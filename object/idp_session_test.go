@@ -0,0 +1,161 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGetIdpSessions(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	provider := &Provider{Owner: "admin", Name: "provider-okta"}
+	for _, idpSession := range []*IdpSession{
+		{SessionId: "session-without-sid", Subject: "user-1"},
+		{SessionId: "session-with-sid", Subject: "user-1", Sid: "sid-1"},
+		{SessionId: "session-with-other-sid", Subject: "user-1", Sid: "sid-2"},
+		{SessionId: "session-of-other-user", Subject: "user-2"},
+	} {
+		idpSession.Owner = provider.Owner
+		idpSession.Provider = provider.Name
+		_, err := AddIdpSession(idpSession)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	getSessionIds := func(subject string, sid string) string {
+		t.Helper()
+
+		idpSessions, err := GetIdpSessions(provider, subject, sid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessionIds := []string{}
+		for _, idpSession := range idpSessions {
+			sessionIds = append(sessionIds, idpSession.SessionId)
+		}
+		sort.Strings(sessionIds)
+		return strings.Join(sessionIds, ",")
+	}
+
+	cases := []struct {
+		subject  string
+		sid      string
+		expected string
+	}{
+		// a logout token with a sub and a sid logs out the sessions no sid was recorded for
+		{"user-1", "sid-1", "session-with-sid,session-without-sid"},
+		{"user-1", "", "session-with-other-sid,session-with-sid,session-without-sid"},
+		{"", "sid-2", "session-with-other-sid"},
+		{"user-2", "sid-1", "session-of-other-user"},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		if sessionIds := getSessionIds(c.subject, c.sid); sessionIds != c.expected {
+			t.Errorf("GetIdpSessions(%q, %q) = %s, want %s", c.subject, c.sid, sessionIds, c.expected)
+		}
+	}
+}
+
+func TestGetProviderSamlLogoutResponse(t *testing.T) {
+	// the LogoutResponse is signed with the key of the service provider, which is loaded relative to the repository
+	t.Chdir("..")
+
+	certificate, err := os.ReadFile("object/token_jwt_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := parseSamlSpCertificate(string(certificate))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &Provider{Owner: "admin", Name: "provider-saml", Category: "SAML", Metadata: `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:SOAP" Location="https://idp.example.com/slo/soap"/>
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/slo" ResponseLocation="https://idp.example.com/slo/response"/>
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/slo/post"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`}
+	logoutRequest := &SamlLogoutRequest{ID: "_request-1"}
+
+	// a LogoutRequest sent by HTTP-Redirect is answered at the ResponseLocation, with the query signed
+	redirectUrl, form, err := GetProviderSamlLogoutResponse(provider, logoutRequest, SamlBindingRedirect, "relay-1", "localhost:8000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form != nil || !strings.HasPrefix(redirectUrl, "https://idp.example.com/slo/response?") {
+		t.Fatalf("unexpected LogoutResponse over HTTP-Redirect: %s, %v", redirectUrl, form)
+	}
+
+	parsedUrl, err := url.Parse(redirectUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsedUrl.Query()
+	responseByte, err := decodeSamlMessage(query.Get("SAMLResponse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	querySignature := &SamlQuerySignature{RelayState: query.Get("RelayState"), SigAlg: query.Get("SigAlg"), Signature: query.Get("Signature"), RawQuery: parsedUrl.RawQuery}
+	_, err = validateSamlMessageSignature(cert, "SAMLResponse", query.Get("SAMLResponse"), responseByte, querySignature, true)
+	if err != nil {
+		t.Errorf("the signature of the LogoutResponse doesn't verify: %v", err)
+	}
+	if query.Get("RelayState") != "relay-1" {
+		t.Errorf("unexpected RelayState: %s", query.Get("RelayState"))
+	}
+
+	var logoutResponse SamlLogoutResponse
+	err = xml.Unmarshal(responseByte, &logoutResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logoutResponse.InResponseTo != "_request-1" || logoutResponse.Issuer != "http://localhost:8000/api/acs" {
+		t.Errorf("unexpected LogoutResponse: %+v", logoutResponse)
+	}
+
+	// a LogoutRequest sent by HTTP-POST is answered by a signed form posted to the Location
+	redirectUrl, form, err = GetProviderSamlLogoutResponse(provider, logoutRequest, SamlBindingPost, "", "localhost:8000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redirectUrl != "https://idp.example.com/slo/post" || form == nil || form.Has("RelayState") {
+		t.Fatalf("unexpected LogoutResponse over HTTP-POST: %s, %v", redirectUrl, form)
+	}
+
+	responseByte, err = base64.StdEncoding.DecodeString(form.Get("SAMLResponse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = validateSamlMessageSignature(cert, "SAMLResponse", form.Get("SAMLResponse"), responseByte, nil, true)
+	if err != nil {
+		t.Errorf("the signature of the LogoutResponse doesn't verify: %v", err)
+	}
+
+	// an IdP without a Single Logout Service over the browser can't be answered
+	provider.Metadata = `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata"><IDPSSODescriptor/></EntityDescriptor>`
+	_, _, err = GetProviderSamlLogoutResponse(provider, logoutRequest, SamlBindingRedirect, "", "localhost:8000")
+	if err == nil {
+		t.Errorf("a LogoutResponse is built for an IdP without a Single Logout Service")
+	}
+}
@@ -95,6 +95,7 @@ func GetDefaultAccountItems() []*AccountItem {
 		{Name: "Permissions", Visible: true, ViewRule: "Public", ModifyRule: "Immutable"},
 		{Name: "Groups", Visible: true, ViewRule: "Public", ModifyRule: "Admin"},
 		{Name: "Consents", Visible: true, ViewRule: "Self", ModifyRule: "Self"},
+		{Name: "Sessions", Visible: true, ViewRule: "Self", ModifyRule: "Self"},
 		{Name: "3rd-party logins", Visible: true, ViewRule: "Self", ModifyRule: "Self"},
 		{Name: "Properties", Visible: true, ViewRule: "Admin", ModifyRule: "Admin"},
 		{Name: "Is online", Visible: true, ViewRule: "Admin", ModifyRule: "Admin"},
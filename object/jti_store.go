@@ -0,0 +1,126 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/conf"
+	"github.com/casdoor/casdoor/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// receivedJtiStore remembers the jti of the tokens pushed to Casdoor by the upstream providers, such as
+// the logout tokens, so that a token captured on the way can't be replayed. The default implementation
+// is in-memory; when redisEndpoint is configured, a Redis-backed implementation is used so that a token
+// received by one replica is refused by the others.
+type receivedJtiStore interface {
+	// Add records the jti until expiresAt, it returns false when the jti has been recorded already
+	Add(jti string, expiresAt time.Time) bool
+	Cleanup(now time.Time)
+}
+
+const receivedJtiRedisPrefix = "casdoor:jti:"
+
+// ReceivedJtiMap stores the jti of the received tokens, keyed by the kind of the token and its issuer too.
+var ReceivedJtiMap receivedJtiStore = &memoryReceivedJtiStore{}
+
+// InitReceivedJtiStore switches ReceivedJtiMap to a Redis-backed store when
+// redisEndpoint is configured. On failure it logs a warning and keeps the in-memory store.
+func InitReceivedJtiStore() {
+	endpoint := conf.GetConfigString("redisEndpoint")
+	if endpoint == "" {
+		return
+	}
+
+	client, err := newRedisClient(endpoint)
+	if err != nil {
+		logs.Warn("jti_store: failed to connect to Redis (%s), falling back to in-memory store: %v", endpoint, err)
+		return
+	}
+
+	ReceivedJtiMap = &redisReceivedJtiStore{client: client}
+	logs.Info("jti_store: using Redis backend at %s", endpoint)
+}
+
+func InitCleanupReceivedJtiMap() {
+	InitReceivedJtiStore()
+	util.SafeGoroutine(func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			ReceivedJtiMap.Cleanup(time.Now())
+		}
+	})
+}
+
+// ── in-memory implementation (default) ──────────────────────────────────────
+
+type memoryReceivedJtiStore struct {
+	mu sync.Mutex
+	m  map[string]time.Time
+}
+
+func (s *memoryReceivedJtiStore) Add(jti string, expiresAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.m == nil {
+		s.m = map[string]time.Time{}
+	}
+	if existingExpiresAt, ok := s.m[jti]; ok && existingExpiresAt.After(time.Now()) {
+		return false
+	}
+	s.m[jti] = expiresAt
+	return true
+}
+
+func (s *memoryReceivedJtiStore) Cleanup(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jti, expiresAt := range s.m {
+		if expiresAt.Before(now) {
+			delete(s.m, jti)
+		}
+	}
+}
+
+// ── Redis implementation ─────────────────────────────────────────────────────
+
+type redisReceivedJtiStore struct {
+	client *redis.Client
+}
+
+func (s *redisReceivedJtiStore) Add(jti string, expiresAt time.Time) bool {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+
+	added, err := s.client.SetNX(context.Background(), receivedJtiRedisPrefix+jti, 1, ttl).Result()
+	if err != nil {
+		// a token is refused rather than possibly replayed while Redis is unavailable
+		logs.Warn("jti_store: failed to record the jti %s: %v", jti, err)
+		return false
+	}
+	return added
+}
+
+// Cleanup is a no-op, Redis expires the keys by itself
+func (s *redisReceivedJtiStore) Cleanup(now time.Time) {}
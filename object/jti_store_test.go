@@ -0,0 +1,78 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/casdoor/casdoor/idp"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestReceivedJtiStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	stores := map[string]receivedJtiStore{
+		"memory": &memoryReceivedJtiStore{},
+		"redis":  &redisReceivedJtiStore{client: redis.NewClient(&redis.Options{Addr: server.Addr()})},
+	}
+
+	for name, store := range stores {
+		expiresAt := time.Now().Add(time.Minute)
+		if !store.Add("jti-1", expiresAt) {
+			t.Errorf("%s: a new jti is refused", name)
+		}
+		if store.Add("jti-1", expiresAt) {
+			t.Errorf("%s: a jti is accepted twice", name)
+		}
+		if !store.Add("jti-2", expiresAt) {
+			t.Errorf("%s: another jti is refused", name)
+		}
+	}
+
+	memoryStore := stores["memory"].(*memoryReceivedJtiStore)
+	memoryStore.Cleanup(time.Now().Add(2 * time.Minute))
+	if len(memoryStore.m) != 0 {
+		t.Errorf("%d expired jti are left after the cleanup", len(memoryStore.m))
+	}
+}
+
+func TestCheckProviderLogoutTokenReplay(t *testing.T) {
+	oldReceivedJtiMap := ReceivedJtiMap
+	ReceivedJtiMap = &memoryReceivedJtiStore{}
+	t.Cleanup(func() {
+		ReceivedJtiMap = oldReceivedJtiMap
+	})
+
+	provider := &Provider{Owner: "admin", Name: "provider-oidc"}
+	token := &idp.OidcLogoutToken{Sub: "user-1", Jti: "logout-1", IssuedAt: time.Now()}
+	if err := checkProviderLogoutTokenReplay(provider, token); err != nil {
+		t.Fatalf("a new logout token is refused: %v", err)
+	}
+	if err := checkProviderLogoutTokenReplay(provider, token); err == nil {
+		t.Errorf("a replayed logout token is accepted")
+	}
+
+	otherProvider := &Provider{Owner: "admin", Name: "provider-other"}
+	if err := checkProviderLogoutTokenReplay(otherProvider, token); err != nil {
+		t.Errorf("the logout token of another provider with the same jti is refused: %v", err)
+	}
+
+	oldToken := &idp.OidcLogoutToken{Sub: "user-1", Jti: "logout-2", IssuedAt: time.Now().Add(-providerLogoutTokenMaxAge - time.Minute)}
+	if err := checkProviderLogoutTokenReplay(provider, oldToken); err == nil {
+		t.Errorf("an old logout token is accepted")
+	}
+}
@@ -47,9 +47,10 @@ const (
 )
 
 const (
-	MfaSessionUserId = "MfaSessionUserId"
-	NextMfa          = "NextMfa"
-	RequiredMfa      = "RequiredMfa"
+	MfaSessionUserId      = "MfaSessionUserId"
+	MfaSessionAuthContext = "MfaSessionAuthContext"
+	NextMfa               = "NextMfa"
+	RequiredMfa           = "RequiredMfa"
 )
 
 func GetMfaUtil(mfaType string, config *MfaProps) MfaInterface {
@@ -203,7 +204,21 @@ func (user *User) GetMfaProps(mfaType string, masked bool) *MfaProps {
 	return mfaProps
 }
 
+// EnableMultiFactorAuth enables the verified multi-factor authentication for the user
+func EnableMultiFactorAuth(mfaUtil MfaInterface, user *User) error {
+	before := getDomainEventUser(user)
+
+	err := mfaUtil.Enable(user)
+	if err != nil {
+		return err
+	}
+
+	PublishDomainEvent(user.Owner, DomainEventUserMfaEnabled, user.GetId(), before, getDomainEventUser(user))
+	return nil
+}
+
 func DisabledMultiFactorAuth(user *User) error {
+	before := getDomainEventUser(user)
 	user.PreferredMfaType = ""
 	user.RecoveryCodes = []string{}
 	user.MfaPhoneEnabled = false
@@ -220,6 +235,8 @@ func DisabledMultiFactorAuth(user *User) error {
 	if err != nil {
 		return err
 	}
+
+	PublishDomainEvent(user.Owner, DomainEventUserMfaDisabled, user.GetId(), before, getDomainEventUser(user))
 	return nil
 }
 
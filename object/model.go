@@ -141,6 +141,10 @@ func UpdateModel(id string, modelObj *Model) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeModel, id)
+	}
+
 	return affected != 0, err
 }
 
@@ -160,6 +164,11 @@ func AddModel(model *Model) (bool, error) {
 		return false, err
 	}
 
+	// a permission of a missing model is enforced with the built-in model
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeModel, model.GetId())
+	}
+
 	return affected != 0, nil
 }
 
@@ -169,6 +178,10 @@ func DeleteModel(model *Model) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeModel, model.GetId())
+	}
+
 	return affected != 0, nil
 }
 
@@ -16,6 +16,8 @@ package object
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"time"
 
 	"github.com/casdoor/casdoor/util"
@@ -35,6 +37,12 @@ type DynamicClientRegistrationRequest struct {
 	PolicyUri               string   `json:"policy_uri,omitempty"`
 	TosUri                  string   `json:"tos_uri,omitempty"`
 	Scope                   string   `json:"scope,omitempty"`
+
+	JwksUri                      string `json:"jwks_uri,omitempty"`
+	IdTokenEncryptedResponseAlg  string `json:"id_token_encrypted_response_alg,omitempty"`
+	IdTokenEncryptedResponseEnc  string `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoEncryptedResponseAlg string `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc string `json:"userinfo_encrypted_response_enc,omitempty"`
 }
 
 // DynamicClientRegistrationResponse represents an RFC 7591/7592 client registration response
@@ -57,6 +65,61 @@ type DynamicClientRegistrationResponse struct {
 	Scope                   string   `json:"scope,omitempty"`
 	RegistrationClientUri   string   `json:"registration_client_uri,omitempty"`
 	RegistrationAccessToken string   `json:"registration_access_token,omitempty"`
+
+	JwksUri                      string `json:"jwks_uri,omitempty"`
+	IdTokenEncryptedResponseAlg  string `json:"id_token_encrypted_response_alg,omitempty"`
+	IdTokenEncryptedResponseEnc  string `json:"id_token_encrypted_response_enc,omitempty"`
+	UserinfoEncryptedResponseAlg string `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserinfoEncryptedResponseEnc string `json:"userinfo_encrypted_response_enc,omitempty"`
+}
+
+// checkDynamicClientEncryption validates the ID token and userinfo encryption metadata of a DCR request
+func checkDynamicClientEncryption(req *DynamicClientRegistrationRequest) *DcrError {
+	err := CheckEncryptionSetting(req.IdTokenEncryptedResponseAlg, req.IdTokenEncryptedResponseEnc)
+	if err == nil {
+		err = CheckEncryptionSetting(req.UserinfoEncryptedResponseAlg, req.UserinfoEncryptedResponseEnc)
+	}
+	if err != nil {
+		return &DcrError{
+			Error:            "invalid_client_metadata",
+			ErrorDescription: err.Error(),
+		}
+	}
+	return nil
+}
+
+// checkDynamicClientJwksUri validates the jwks_uri of a DCR request. Casdoor fetches it to verify
+// request objects and encrypt tokens, so an anonymous client can't point it at an internal host.
+// The address is checked again by clientJwksHttpClient when the URI is fetched.
+func checkDynamicClientJwksUri(jwksUri string) *DcrError {
+	if jwksUri == "" {
+		return nil
+	}
+
+	u, err := url.Parse(jwksUri)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return &DcrError{
+			Error:            "invalid_client_metadata",
+			ErrorDescription: "jwks_uri must be an https URL",
+		}
+	}
+
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil || len(ips) == 0 {
+		return &DcrError{
+			Error:            "invalid_client_metadata",
+			ErrorDescription: fmt.Sprintf("the host of jwks_uri: %s can't be resolved", u.Hostname()),
+		}
+	}
+	for _, ip := range ips {
+		if !isPublicIp(ip.String()) {
+			return &DcrError{
+				Error:            "invalid_client_metadata",
+				ErrorDescription: fmt.Sprintf("the host of jwks_uri: %s is not a public address", u.Hostname()),
+			}
+		}
+	}
+	return nil
 }
 
 // DcrError represents an RFC 7591/7592 error response
@@ -95,6 +158,13 @@ func RegisterDynamicClient(req *DynamicClientRegistrationRequest, organization s
 		}, nil
 	}
 
+	if dcrError := checkDynamicClientEncryption(req); dcrError != nil {
+		return nil, dcrError, nil
+	}
+	if dcrError := checkDynamicClientJwksUri(req.JwksUri); dcrError != nil {
+		return nil, dcrError, nil
+	}
+
 	// Set defaults
 	if req.ClientName == "" {
 		clientIdPrefix := util.GenerateClientId()
@@ -185,6 +255,12 @@ func RegisterDynamicClient(req *DynamicClientRegistrationRequest, organization s
 		Providers:               inheritedProviders,
 		SigninMethods:           inheritedSigninMethods,
 		RegistrationAccessToken: registrationAccessToken,
+
+		ClientJwksUri:                req.JwksUri,
+		IdTokenEncryptedResponseAlg:  req.IdTokenEncryptedResponseAlg,
+		IdTokenEncryptedResponseEnc:  req.IdTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg: req.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: req.UserinfoEncryptedResponseEnc,
 	}
 
 	// Add the application
@@ -219,6 +295,12 @@ func RegisterDynamicClient(req *DynamicClientRegistrationRequest, organization s
 		Scope:                   req.Scope,
 		RegistrationClientUri:   fmt.Sprintf("%s/%s", registrationClientUri, clientId),
 		RegistrationAccessToken: registrationAccessToken,
+
+		JwksUri:                      req.JwksUri,
+		IdTokenEncryptedResponseAlg:  req.IdTokenEncryptedResponseAlg,
+		IdTokenEncryptedResponseEnc:  req.IdTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg: req.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: req.UserinfoEncryptedResponseEnc,
 	}
 
 	return response, nil, nil
@@ -256,6 +338,12 @@ func GetDynamicClientRegistrationResponse(app *Application, registrationClientUr
 		TosUri:                  app.TermsOfUse,
 		RegistrationClientUri:   fmt.Sprintf("%s/%s", registrationClientUri, app.ClientId),
 		RegistrationAccessToken: app.RegistrationAccessToken,
+
+		JwksUri:                      app.ClientJwksUri,
+		IdTokenEncryptedResponseAlg:  app.IdTokenEncryptedResponseAlg,
+		IdTokenEncryptedResponseEnc:  app.IdTokenEncryptedResponseEnc,
+		UserinfoEncryptedResponseAlg: app.UserinfoEncryptedResponseAlg,
+		UserinfoEncryptedResponseEnc: app.UserinfoEncryptedResponseEnc,
 	}
 }
 
@@ -268,6 +356,13 @@ func UpdateDynamicClient(app *Application, req *DynamicClientRegistrationRequest
 		}, nil
 	}
 
+	if dcrError := checkDynamicClientEncryption(req); dcrError != nil {
+		return nil, dcrError, nil
+	}
+	if dcrError := checkDynamicClientJwksUri(req.JwksUri); dcrError != nil {
+		return nil, dcrError, nil
+	}
+
 	app.DisplayName = firstNonEmpty(req.ClientName, app.DisplayName)
 	app.RedirectUris = req.RedirectUris
 	if len(req.GrantTypes) > 0 {
@@ -282,6 +377,15 @@ func UpdateDynamicClient(app *Application, req *DynamicClientRegistrationRequest
 	if req.TosUri != "" {
 		app.TermsOfUse = req.TosUri
 	}
+	if req.JwksUri != "" {
+		app.ClientJwksUri = req.JwksUri
+	}
+
+	// RFC 7592 replaces the whole client metadata, an omitted encryption setting disables encryption
+	app.IdTokenEncryptedResponseAlg = req.IdTokenEncryptedResponseAlg
+	app.IdTokenEncryptedResponseEnc = req.IdTokenEncryptedResponseEnc
+	app.UserinfoEncryptedResponseAlg = req.UserinfoEncryptedResponseAlg
+	app.UserinfoEncryptedResponseEnc = req.UserinfoEncryptedResponseEnc
 
 	_, err := UpdateApplication(util.GetId(app.Owner, app.Name), app, true, "", nil)
 	if err != nil {
@@ -0,0 +1,40 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import "testing"
+
+func TestCheckDynamicClientJwksUri(t *testing.T) {
+	if dcrError := checkDynamicClientJwksUri(""); dcrError != nil {
+		t.Errorf("an empty jwks_uri is rejected: %+v", dcrError)
+	}
+	if dcrError := checkDynamicClientJwksUri("https://8.8.8.8/jwks"); dcrError != nil {
+		t.Errorf("a public jwks_uri is rejected: %+v", dcrError)
+	}
+
+	for _, jwksUri := range []string{
+		"http://8.8.8.8/jwks",
+		"file:///etc/passwd",
+		"https://127.0.0.1/jwks",
+		"https://localhost:8000/jwks",
+		"https://10.0.0.1/jwks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/jwks",
+	} {
+		if dcrError := checkDynamicClientJwksUri(jwksUri); dcrError == nil {
+			t.Errorf("the jwks_uri: %s is accepted", jwksUri)
+		}
+	}
+}
@@ -333,6 +333,9 @@ func PayOrder(providerName, host, paymentEnv string, order *Order, lang string)
 			return nil, nil, fmt.Errorf("failed to add transaction: %s", util.StructToJson(transaction))
 		}
 
+		// the payment is only paid once the balance has been charged
+		PublishDomainEvent(payment.Owner, DomainEventPaymentPaid, payment.GetId(), nil, payment)
+
 		hasRecharge := false
 		rechargeAmount := 0.0
 		for _, productInfo := range orderProductInfos {
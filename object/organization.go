@@ -65,6 +65,8 @@ type Organization struct {
 	PasswordObfuscatorType string     `xorm:"varchar(100)" json:"passwordObfuscatorType"`
 	PasswordObfuscatorKey  string     `xorm:"varchar(100)" json:"passwordObfuscatorKey"`
 	PasswordExpireDays     int        `json:"passwordExpireDays"`
+	PasswordHistoryDepth   int        `json:"passwordHistoryDepth"`
+	PasswordMinAgeDays     int        `json:"passwordMinAgeDays"`
 	TokenRetentionDays     int        `json:"tokenRetentionDays"`
 	RecordRetentionDays    int        `json:"recordRetentionDays"`
 	CountryCodes           []string   `xorm:"mediumtext"  json:"countryCodes"`
@@ -99,6 +101,8 @@ type Organization struct {
 	DcrPolicy string `xorm:"varchar(100)" json:"dcrPolicy"`
 
 	LdapAttributes []string `xorm:"mediumtext" json:"ldapAttributes"`
+	LdapSizeLimit  int      `json:"ldapSizeLimit"`
+	LdapTimeLimit  int      `json:"ldapTimeLimit"`
 
 	KerberosRealm       string `xorm:"varchar(200)" json:"kerberosRealm"`
 	KerberosKdcHost     string `xorm:"varchar(200)" json:"kerberosKdcHost"`
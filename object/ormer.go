@@ -460,6 +460,21 @@ func (a *Ormer) createTable() {
 		panic(err)
 	}
 
+	err = a.Engine.Sync2(new(EventSink))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.Engine.Sync2(new(EventSinkSequence))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.Engine.Sync2(new(SsfStream))
+	if err != nil {
+		panic(err)
+	}
+
 	err = a.Engine.Sync2(new(VerificationRecord))
 	if err != nil {
 		panic(err)
@@ -519,4 +534,24 @@ func (a *Ormer) createTable() {
 	if err != nil {
 		panic(err)
 	}
+
+	err = a.Engine.Sync2(new(SamlSession))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.Engine.Sync2(new(WsFedSession))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.Engine.Sync2(new(IdpSession))
+	if err != nil {
+		panic(err)
+	}
+
+	err = a.Engine.Sync2(new(SessionDevice))
+	if err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,45 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// initSqliteTestOrmer points the ormer at a fresh SQLite database with the built-in
+// user enforcer, for the tests that need the database but not a MySQL server
+func initSqliteTestOrmer(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("driverName", "sqlite")
+	t.Setenv("dataSourceName", "file:"+filepath.Join(t.TempDir(), "casdoor.db")+"?_pragma=busy_timeout(5000)")
+	t.Setenv("dbName", "")
+	t.Setenv("tableNamePrefix", "")
+
+	oldOrmer, oldUserEnforcer, oldCreateDatabase := ormer, userEnforcer, createDatabase
+	t.Cleanup(func() {
+		ormer, userEnforcer, createDatabase = oldOrmer, oldUserEnforcer, oldCreateDatabase
+	})
+
+	createDatabase = false
+	InitAdapter()
+	CreateTables()
+
+	initBuiltInUserModel()
+	initBuiltInUserAdapter()
+	initBuiltInUserEnforcer()
+	InitUserManager()
+}
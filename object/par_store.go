@@ -0,0 +1,181 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/conf"
+	"github.com/casdoor/casdoor/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// pushedAuthRequestStore mirrors deviceAuthStore for the Pushed Authorization Requests (RFC 9126).
+// The default implementation is in-memory; when redisEndpoint is configured, a Redis-backed
+// implementation is used so that a request_uri pushed to one replica can be used on another.
+type pushedAuthRequestStore interface {
+	Load(key any) (any, bool)
+	Store(key, value any)
+	Delete(key any)
+	LoadAndDelete(key any) (any, bool)
+	Range(f func(key, value any) bool)
+}
+
+const pushedAuthRequestRedisPrefix = "casdoor:par:"
+
+// PushedAuthRequestMap stores the pushed authorization requests keyed by their request_uri.
+var PushedAuthRequestMap pushedAuthRequestStore = &memoryPushedAuthRequestStore{}
+
+// InitPushedAuthRequestStore switches PushedAuthRequestMap to a Redis-backed store when
+// redisEndpoint is configured. On failure it logs a warning and keeps the in-memory store.
+func InitPushedAuthRequestStore() {
+	endpoint := conf.GetConfigString("redisEndpoint")
+	if endpoint == "" {
+		return
+	}
+
+	client, err := newRedisClient(endpoint)
+	if err != nil {
+		logs.Warn("par_store: failed to connect to Redis (%s), falling back to in-memory store: %v", endpoint, err)
+		return
+	}
+
+	PushedAuthRequestMap = &redisPushedAuthRequestStore{client: client}
+	logs.Info("par_store: using Redis backend at %s", endpoint)
+}
+
+func InitCleanupPushedAuthRequestMap() {
+	InitPushedAuthRequestStore()
+	util.SafeGoroutine(func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			now := time.Now()
+			PushedAuthRequestMap.Range(func(key, value any) bool {
+				request := value.(PushedAuthRequest)
+				if request.ExpiresAt.Before(now) {
+					PushedAuthRequestMap.Delete(key)
+				}
+				return true
+			})
+		}
+	})
+}
+
+// ── in-memory implementation (default) ──────────────────────────────────────
+
+type memoryPushedAuthRequestStore struct {
+	m sync.Map
+}
+
+func (s *memoryPushedAuthRequestStore) Load(key any) (any, bool) { return s.m.Load(key) }
+func (s *memoryPushedAuthRequestStore) Store(key, value any)     { s.m.Store(key, value) }
+func (s *memoryPushedAuthRequestStore) Delete(key any)           { s.m.Delete(key) }
+func (s *memoryPushedAuthRequestStore) LoadAndDelete(key any) (any, bool) {
+	return s.m.LoadAndDelete(key)
+}
+func (s *memoryPushedAuthRequestStore) Range(f func(key, value any) bool) { s.m.Range(f) }
+
+// ── Redis implementation ─────────────────────────────────────────────────────
+
+type redisPushedAuthRequestStore struct {
+	client *redis.Client
+}
+
+func (s *redisPushedAuthRequestStore) redisKey(key any) (string, bool) {
+	k, ok := key.(string)
+	return pushedAuthRequestRedisPrefix + k, ok
+}
+
+func (s *redisPushedAuthRequestStore) Load(key any) (any, bool) {
+	rk, ok := s.redisKey(key)
+	if !ok {
+		return nil, false
+	}
+
+	data, err := s.client.Get(context.Background(), rk).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var request PushedAuthRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, false
+	}
+	return request, true
+}
+
+func (s *redisPushedAuthRequestStore) Store(key, value any) {
+	rk, ok := s.redisKey(key)
+	if !ok {
+		return
+	}
+
+	request, ok := value.(PushedAuthRequest)
+	if !ok {
+		return
+	}
+
+	ttl := time.Until(request.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		logs.Warn("par_store: failed to marshal PushedAuthRequest: %v", err)
+		return
+	}
+
+	if err := s.client.Set(context.Background(), rk, data, ttl).Err(); err != nil {
+		logs.Warn("par_store: Redis SET failed for key %s: %v", rk, err)
+	}
+}
+
+func (s *redisPushedAuthRequestStore) Delete(key any) {
+	rk, ok := s.redisKey(key)
+	if !ok {
+		return
+	}
+
+	if err := s.client.Del(context.Background(), rk).Err(); err != nil {
+		logs.Warn("par_store: Redis DEL failed for key %s: %v", rk, err)
+	}
+}
+
+func (s *redisPushedAuthRequestStore) LoadAndDelete(key any) (any, bool) {
+	rk, ok := s.redisKey(key)
+	if !ok {
+		return nil, false
+	}
+
+	data, err := s.client.GetDel(context.Background(), rk).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var request PushedAuthRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, false
+	}
+	return request, true
+}
+
+// Range is a no-op for the Redis backend: entries expire automatically via TTL.
+func (s *redisPushedAuthRequestStore) Range(_ func(key, value any) bool) {}
@@ -285,6 +285,7 @@ func NotifyPayment(body []byte, owner string, paymentName string, lang string) (
 		return payment, nil
 	}
 
+	before := *payment
 	payment.State = newState
 	payment.Message = newMessage
 	_, err = UpdatePayment(payment.GetId(), payment)
@@ -292,6 +293,10 @@ func NotifyPayment(body []byte, owner string, paymentName string, lang string) (
 		return nil, err
 	}
 
+	if payment.State == pp.PaymentStatePaid {
+		PublishDomainEvent(payment.Owner, DomainEventPaymentPaid, payment.GetId(), &before, payment)
+	}
+
 	// Update order state based on payment status
 	order, err := getOrder(payment.Owner, payment.Order)
 	if err != nil {
@@ -28,22 +28,33 @@ import (
 )
 
 func getPermissionEnforcer(p *Permission, permissionIDs ...string) (*casbin.Enforcer, error) {
+	enforcer, _, err := newPermissionEnforcer(p, permissionIDs...)
+	if err != nil {
+		return nil, err
+	}
+	return enforcer.Enforcer, nil
+}
+
+// newPermissionEnforcer builds the enforcer of the permissions, it also returns the
+// scopes that the enforcer has been built from for the enforcer cache. The enforcer is
+// synchronized because the cached one is shared by the concurrent requests.
+func newPermissionEnforcer(p *Permission, permissionIDs ...string) (*casbin.SyncedEnforcer, []string, error) {
 	// Init an enforcer instance without specifying a model or adapter.
 	// If you specify an adapter, it will load all policies, which is a
 	// heavy process that can slow down the application.
-	enforcer, err := casbin.NewEnforcer(&log.DefaultLogger{}, false)
+	enforcer, err := casbin.NewSyncedEnforcer(&log.DefaultLogger{}, false)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	err = p.setEnforcerModel(enforcer)
+	err = p.setEnforcerModel(enforcer.Enforcer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	err = p.setEnforcerAdapter(enforcer)
+	tableName, err := p.setEnforcerAdapter(enforcer.Enforcer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	policyFilterV5 := []string{p.GetId()}
@@ -51,6 +62,17 @@ func getPermissionEnforcer(p *Permission, permissionIDs ...string) (*casbin.Enfo
 		policyFilterV5 = permissionIDs
 	}
 
+	scopes := []string{
+		getPermissionEnforcerScope(permissionEnforcerScopeModel, p.Model),
+		getPermissionEnforcerScope(permissionEnforcerScopeTable, tableName),
+	}
+	if p.Adapter != "" {
+		scopes = append(scopes, getPermissionEnforcerScope(permissionEnforcerScopeAdapter, util.GetId(p.Owner, p.Adapter)))
+	}
+	for _, permissionID := range append([]string{p.GetId()}, permissionIDs...) {
+		scopes = append(scopes, getPermissionEnforcerScope(permissionEnforcerScopePermission, permissionID))
+	}
+
 	policyFilter := xormadapter.Filter{
 		// Permission enforcers only persist p rules. Legacy g rows are rebuilt from roles at runtime.
 		Ptype: []string{"p"},
@@ -59,24 +81,24 @@ func getPermissionEnforcer(p *Permission, permissionIDs ...string) (*casbin.Enfo
 
 	err = enforcer.LoadFilteredPolicy(policyFilter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// we can rebuild group policies in memory
-	err = loadRuntimeGroupingPolicies(enforcer, p, permissionIDs...)
+	groupingScopes, err := loadRuntimeGroupingPolicies(enforcer.Enforcer, p, permissionIDs...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return enforcer, nil
+	return enforcer, append(scopes, groupingScopes...), nil
 }
 
-func (p *Permission) setEnforcerAdapter(enforcer *casbin.Enforcer) error {
+func (p *Permission) setEnforcerAdapter(enforcer *casbin.Enforcer) (string, error) {
 	tableName := "permission_rule"
 	if len(p.Adapter) != 0 {
 		adapterObj, err := getAdapter(p.Owner, p.Adapter)
 		if err != nil {
-			return err
+			return "", err
 		}
 
 		if adapterObj != nil && adapterObj.Table != "" {
@@ -86,11 +108,11 @@ func (p *Permission) setEnforcerAdapter(enforcer *casbin.Enforcer) error {
 	tableNamePrefix := conf.GetConfigString("tableNamePrefix")
 	adapter, err := xormadapter.NewAdapterByEngineWithTableName(ormer.Engine, tableName, tableNamePrefix)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	enforcer.SetAdapter(adapter)
-	return nil
+	return tableName, nil
 }
 
 func (p *Permission) setEnforcerModel(enforcer *casbin.Enforcer) error {
@@ -192,6 +214,15 @@ func (r *permissionRoleResolver) getRoles(owner string) ([]*Role, error) {
 	return roles, nil
 }
 
+// getScopes returns the enforcer cache scopes of the roles that have been resolved
+func (r *permissionRoleResolver) getScopes() []string {
+	scopes := []string{}
+	for owner := range r.rolesByOwner {
+		scopes = append(scopes, getPermissionEnforcerScope(permissionEnforcerScopeRole, owner))
+	}
+	return scopes
+}
+
 func (r *permissionRoleResolver) getRolesInRole(permissionOwner string, roleId string, visited map[string]struct{}) ([]*Role, error) {
 	if roleId == "*" {
 		roleId = util.GetId(permissionOwner, "*")
@@ -263,6 +294,24 @@ func (r *permissionGroupResolver) getGroups(owner string) ([]*Group, error) {
 	return groups, nil
 }
 
+// getScopes returns the enforcer cache scopes of the groups that have been resolved
+func (r *permissionGroupResolver) getScopes() []string {
+	owners := map[string]struct{}{}
+	for owner := range r.groupsByOwner {
+		owners[owner] = struct{}{}
+	}
+	for groupId := range r.usersByGroup {
+		owner, _ := util.GetOwnerAndNameFromIdNoCheck(groupId)
+		owners[owner] = struct{}{}
+	}
+
+	scopes := []string{}
+	for owner := range owners {
+		scopes = append(scopes, getPermissionEnforcerScope(permissionEnforcerScopeGroup, owner))
+	}
+	return scopes
+}
+
 func (r *permissionGroupResolver) getUsersInGroup(permissionOwner string, groupId string) ([]string, error) {
 	groupId = getPermissionGroupId(permissionOwner, groupId)
 	if users, ok := r.usersByGroup[groupId]; ok {
@@ -354,11 +403,9 @@ func appendRuntimeGroupingPolicy(groupingPolicies *[][]string, visited map[strin
 	visited[key] = struct{}{}
 }
 
-func getRuntimeGroupingPolicies(permissions []*Permission) ([][]string, error) {
+func getRuntimeGroupingPolicies(permissions []*Permission, roleResolver *permissionRoleResolver, groupResolver *permissionGroupResolver) ([][]string, error) {
 	var groupingPolicies [][]string
 	visitedPolicies := map[string]struct{}{}
-	roleResolver := newPermissionRoleResolver()
-	groupResolver := newPermissionGroupResolver()
 
 	for _, permission := range permissions {
 		domainExist := len(permission.Domains) > 0
@@ -440,36 +487,43 @@ func getRuntimeGroupingPolicies(permissions []*Permission) ([][]string, error) {
 	return groupingPolicies, nil
 }
 
-func loadRuntimeGroupingPolicies(enforcer *casbin.Enforcer, permission *Permission, permissionIDs ...string) error {
+// loadRuntimeGroupingPolicies returns the enforcer cache scopes of the roles and groups
+// that the grouping policies have been built from
+func loadRuntimeGroupingPolicies(enforcer *casbin.Enforcer, permission *Permission, permissionIDs ...string) ([]string, error) {
 	if !HasRoleDefinition(enforcer.GetModel()) {
-		return nil
+		return nil, nil
 	}
 
 	targetPermissions, err := getPermissionEnforcerTargets(permission, permissionIDs...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	groupingPolicies, err := getRuntimeGroupingPolicies(targetPermissions)
+	roleResolver := newPermissionRoleResolver()
+	groupResolver := newPermissionGroupResolver()
+	groupingPolicies, err := getRuntimeGroupingPolicies(targetPermissions, roleResolver, groupResolver)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	scopes := append(roleResolver.getScopes(), groupResolver.getScopes()...)
 	if len(groupingPolicies) == 0 {
-		return nil
+		return scopes, nil
 	}
 
 	enforcer.EnableAutoSave(false)
 	defer enforcer.EnableAutoSave(true)
 	_, err = enforcer.AddGroupingPolicies(groupingPolicies)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return scopes, nil
 }
 
 func addPolicies(permission *Permission) error {
+	defer invalidatePermissionEnforcerCacheByPermissions([]*Permission{permission})
+
 	enforcer, err := getPermissionEnforcer(permission)
 	if err != nil {
 		return err
@@ -482,6 +536,8 @@ func addPolicies(permission *Permission) error {
 }
 
 func removePolicies(permission *Permission) error {
+	defer invalidatePermissionEnforcerCacheByPermissions([]*Permission{permission})
+
 	enforcer, err := getPermissionEnforcer(permission)
 	if err != nil {
 		return err
@@ -502,6 +558,8 @@ func applyPermissionsPolicies(permissions []*Permission, add bool) error {
 		return nil
 	}
 
+	defer invalidatePermissionEnforcerCacheByPermissions(permissions)
+
 	groups := map[string][]*Permission{}
 	order := []string{}
 	for _, permission := range permissions {
@@ -552,7 +610,7 @@ func removePermissionsPolicies(permissions []*Permission) error {
 }
 
 func Enforce(permission *Permission, request []interface{}, permissionIds ...string) (bool, error) {
-	enforcer, err := getPermissionEnforcer(permission, permissionIds...)
+	enforcer, err := getCachedPermissionEnforcer(permission, permissionIds...)
 	if err != nil {
 		return false, err
 	}
@@ -565,7 +623,7 @@ func Enforce(permission *Permission, request []interface{}, permissionIds ...str
 }
 
 func BatchEnforce(permission *Permission, requests [][]interface{}, permissionIds ...string) ([]bool, error) {
-	enforcer, err := getPermissionEnforcer(permission, permissionIds...)
+	enforcer, err := getCachedPermissionEnforcer(permission, permissionIds...)
 	if err != nil {
 		return nil, err
 	}
@@ -576,7 +634,7 @@ func BatchEnforce(permission *Permission, requests [][]interface{}, permissionId
 	return enforcer.BatchEnforce(interfaceRequests)
 }
 
-func getEnforcers(userId string) ([]*casbin.Enforcer, error) {
+func getEnforcers(userId string) ([]*casbin.SyncedEnforcer, error) {
 	permissions, _, err := getPermissionsAndRolesByUser(userId)
 	if err != nil {
 		return nil, err
@@ -597,10 +655,10 @@ func getEnforcers(userId string) ([]*casbin.Enforcer, error) {
 		permissions = append(permissions, permissionsByRole...)
 	}
 
-	var enforcers []*casbin.Enforcer
+	var enforcers []*casbin.SyncedEnforcer
 	for _, permission := range permissions {
-		var enforcer *casbin.Enforcer
-		enforcer, err = getPermissionEnforcer(permission)
+		var enforcer *casbin.SyncedEnforcer
+		enforcer, err = getCachedPermissionEnforcer(permission)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,283 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casbin/casbin/v2"
+	"github.com/casdoor/casdoor/conf"
+	"github.com/casdoor/casdoor/util"
+	"github.com/redis/go-redis/v9"
+)
+
+// The enforcers built by Enforce() and BatchEnforce() are cached by model, adapter and
+// permission IDs. Every cached enforcer remembers the scopes it has been built from: its
+// permissions, model, adapter and policy table, and the owners of the roles and groups that
+// have been resolved into its grouping policies. A change to one of them drops the cached
+// enforcers of that scope, and is published to the other nodes when redisEndpoint is configured.
+const (
+	permissionEnforcerScopeAll        = "*"
+	permissionEnforcerScopePermission = "permission"
+	permissionEnforcerScopeModel      = "model"
+	permissionEnforcerScopeAdapter    = "adapter"
+	permissionEnforcerScopeTable      = "table"
+	permissionEnforcerScopeRole       = "role"
+	permissionEnforcerScopeGroup      = "group"
+)
+
+const (
+	permissionEnforcerCacheChannel = "casdoor:permission_enforcer_cache"
+	// the TTL only bounds the changes made outside of Casdoor, e.g. directly in the database
+	permissionEnforcerCacheTtl     = 10 * time.Minute
+	permissionEnforcerCacheMaxSize = 4096
+)
+
+type permissionEnforcerCacheEntry struct {
+	enforcer   *casbin.SyncedEnforcer
+	scopes     []string
+	expireTime time.Time
+}
+
+type permissionEnforcerCache struct {
+	mu      sync.RWMutex
+	entries map[string]*permissionEnforcerCacheEntry
+	// generation is bumped by every invalidation, an enforcer whose build has
+	// overlapped an invalidation may be stale and isn't cached
+	generation uint64
+}
+
+type permissionEnforcerCacheMessage struct {
+	Node   string   `json:"node"`
+	Scopes []string `json:"scopes"`
+}
+
+var (
+	enforcerCache = newPermissionEnforcerCache()
+
+	enforcerCacheNode        = util.GenerateId()
+	enforcerCacheRedisClient *redis.Client
+)
+
+func newPermissionEnforcerCache() *permissionEnforcerCache {
+	return &permissionEnforcerCache{
+		entries: map[string]*permissionEnforcerCacheEntry{},
+	}
+}
+
+func getPermissionEnforcerScope(kind string, id string) string {
+	return kind + ":" + id
+}
+
+// getPermissionEnforcerCacheKey returns the cache key of the enforcer of getPermissionEnforcer(),
+// the permission IDs are sorted so that the same set always hits the same enforcer
+func getPermissionEnforcerCacheKey(p *Permission, permissionIDs ...string) string {
+	ids := []string{p.GetId()}
+	if len(permissionIDs) != 0 {
+		sorted := make([]string, len(permissionIDs))
+		copy(sorted, permissionIDs)
+		sort.Strings(sorted)
+
+		ids = sorted[:0]
+		for i, id := range sorted {
+			if i == 0 || id != sorted[i-1] {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return strings.Join(append([]string{p.Model, util.GetId(p.Owner, p.Adapter)}, ids...), "\x00")
+}
+
+func (c *permissionEnforcerCache) get(key string) (*casbin.SyncedEnforcer, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expireTime) {
+		return nil, c.generation
+	}
+	return entry.enforcer, c.generation
+}
+
+func (c *permissionEnforcerCache) set(key string, enforcer *casbin.SyncedEnforcer, scopes []string, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := time.Now()
+	if len(c.entries) >= permissionEnforcerCacheMaxSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expireTime) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= permissionEnforcerCacheMaxSize {
+			return
+		}
+	}
+
+	c.entries[key] = &permissionEnforcerCacheEntry{
+		enforcer:   enforcer,
+		scopes:     scopes,
+		expireTime: now.Add(permissionEnforcerCacheTtl),
+	}
+}
+
+func (c *permissionEnforcerCache) invalidate(scopes []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if util.InSlice(scopes, permissionEnforcerScopeAll) {
+		c.entries = map[string]*permissionEnforcerCacheEntry{}
+		return
+	}
+
+	for key, entry := range c.entries {
+		for _, scope := range entry.scopes {
+			if util.InSlice(scopes, scope) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// getCachedPermissionEnforcer returns the same enforcer as getPermissionEnforcer(), it's shared
+// by the concurrent requests so the caller must only read from it and never change its policies,
+// the SyncedEnforcer locks around each of its calls so that they don't race
+func getCachedPermissionEnforcer(p *Permission, permissionIDs ...string) (*casbin.SyncedEnforcer, error) {
+	key := getPermissionEnforcerCacheKey(p, permissionIDs...)
+	enforcer, generation := enforcerCache.get(key)
+	if enforcer != nil {
+		return enforcer, nil
+	}
+
+	enforcer, scopes, err := newPermissionEnforcer(p, permissionIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	enforcerCache.set(key, enforcer, scopes, generation)
+	return enforcer, nil
+}
+
+// invalidatePermissionEnforcerCache drops the cached enforcers that have been built from
+// the given objects of a kind on all the nodes, it must be called after the change is saved
+func invalidatePermissionEnforcerCache(kind string, ids ...string) {
+	scopes := []string{permissionEnforcerScopeAll}
+	if kind != permissionEnforcerScopeAll {
+		scopes = make([]string, 0, len(ids))
+		for _, id := range ids {
+			scopes = append(scopes, getPermissionEnforcerScope(kind, id))
+		}
+		if len(scopes) == 0 {
+			return
+		}
+	}
+
+	enforcerCache.invalidate(scopes)
+
+	if enforcerCacheRedisClient == nil {
+		return
+	}
+
+	message, err := json.Marshal(permissionEnforcerCacheMessage{Node: enforcerCacheNode, Scopes: scopes})
+	if err != nil {
+		logs.Warn("permission_enforcer_cache: failed to encode the invalidation: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err = enforcerCacheRedisClient.Publish(ctx, permissionEnforcerCacheChannel, message).Err()
+	if err != nil {
+		logs.Warn("permission_enforcer_cache: failed to publish the invalidation of %v: %v", scopes, err)
+	}
+}
+
+func invalidatePermissionEnforcerCacheByPermissions(permissions []*Permission) {
+	ids := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		ids = append(ids, permission.GetId())
+	}
+	invalidatePermissionEnforcerCache(permissionEnforcerScopePermission, ids...)
+}
+
+// InitPermissionEnforcerCache subscribes to the invalidations of the other nodes when
+// redisEndpoint is configured. On failure it logs a warning and the cache stays local.
+func InitPermissionEnforcerCache() {
+	endpoint := conf.GetConfigString("redisEndpoint")
+	if endpoint == "" {
+		return
+	}
+
+	client, err := newRedisClient(endpoint)
+	if err != nil {
+		logs.Warn("permission_enforcer_cache: failed to connect to Redis (%s), the invalidations won't reach the other nodes: %v", endpoint, err)
+		return
+	}
+
+	enforcerCacheRedisClient = client
+	pubsub := client.Subscribe(context.Background(), permissionEnforcerCacheChannel)
+	util.SafeGoroutine(func() {
+		receivePermissionEnforcerCacheMessages(pubsub)
+	})
+	logs.Info("permission_enforcer_cache: using Redis invalidations at %s", endpoint)
+}
+
+func receivePermissionEnforcerCacheMessages(pubsub *redis.PubSub) {
+	for {
+		msg, err := pubsub.Receive(context.Background())
+		if errors.Is(err, redis.ErrClosed) {
+			return
+		}
+		if err != nil {
+			// the invalidations published while disconnected are lost, so the whole cache is dropped,
+			// the next Receive() reconnects
+			logs.Warn("permission_enforcer_cache: failed to receive the invalidations: %v", err)
+			enforcerCache.invalidate([]string{permissionEnforcerScopeAll})
+			time.Sleep(time.Second)
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			enforcerCache.invalidate([]string{permissionEnforcerScopeAll})
+		case *redis.Message:
+			var message permissionEnforcerCacheMessage
+			err = json.Unmarshal([]byte(msg.Payload), &message)
+			if err != nil {
+				logs.Warn("permission_enforcer_cache: failed to decode the invalidation: %v", err)
+				continue
+			}
+
+			if message.Node != enforcerCacheNode {
+				enforcerCache.invalidate(message.Scopes)
+			}
+		}
+	}
+}
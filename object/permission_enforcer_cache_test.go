@@ -0,0 +1,335 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/casbin/casbin/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGetPermissionEnforcerCacheKey(t *testing.T) {
+	permission := &Permission{Owner: "org", Name: "perm", Model: "org/model", Adapter: "adapter"}
+
+	key := getPermissionEnforcerCacheKey(permission, "org/b", "org/a", "org/b")
+	if key != getPermissionEnforcerCacheKey(permission, "org/a", "org/b") {
+		t.Errorf("the same permission IDs in another order hit another enforcer")
+	}
+	if key == getPermissionEnforcerCacheKey(permission, "org/a") {
+		t.Errorf("different permission IDs hit the same enforcer")
+	}
+
+	otherModel := &Permission{Owner: "org", Name: "perm", Model: "org/model2", Adapter: "adapter"}
+	if getPermissionEnforcerCacheKey(permission) == getPermissionEnforcerCacheKey(otherModel) {
+		t.Errorf("different models hit the same enforcer")
+	}
+}
+
+func TestPermissionEnforcerCacheInvalidate(t *testing.T) {
+	cache := newPermissionEnforcerCache()
+	roleEnforcer := &casbin.SyncedEnforcer{}
+	groupEnforcer := &casbin.SyncedEnforcer{}
+
+	_, generation := cache.get("role")
+	cache.set("role", roleEnforcer, []string{"permission:org/perm1", "role:org"}, generation)
+	cache.set("group", groupEnforcer, []string{"permission:org/perm2", "group:org"}, generation)
+
+	if enforcer, _ := cache.get("role"); enforcer != roleEnforcer {
+		t.Fatalf("the cached enforcer is not returned")
+	}
+
+	cache.invalidate([]string{"role:org", "model:org/model"})
+	if enforcer, _ := cache.get("role"); enforcer != nil {
+		t.Errorf("the enforcer is still cached after its roles have changed")
+	}
+	if enforcer, _ := cache.get("group"); enforcer != groupEnforcer {
+		t.Errorf("the enforcer of another scope has been dropped")
+	}
+
+	cache.invalidate([]string{permissionEnforcerScopeAll})
+	if enforcer, _ := cache.get("group"); enforcer != nil {
+		t.Errorf("the enforcer is still cached after the whole cache is dropped")
+	}
+}
+
+func TestPermissionEnforcerCacheSkipsStaleBuild(t *testing.T) {
+	cache := newPermissionEnforcerCache()
+
+	// an invalidation happens while the enforcer is being built
+	_, generation := cache.get("perm")
+	cache.invalidate([]string{"permission:org/perm"})
+	cache.set("perm", &casbin.SyncedEnforcer{}, []string{"permission:org/perm"}, generation)
+
+	if enforcer, _ := cache.get("perm"); enforcer != nil {
+		t.Errorf("an enforcer built before the invalidation is cached")
+	}
+}
+
+func addPermissionEnforcerCacheTestPermission(t *testing.T, permission *Permission) *Permission {
+	t.Helper()
+
+	permission.Owner = "org-cache"
+	permission.Resources = []string{"data1"}
+	permission.Actions = []string{"read"}
+	permission.Effect = "Allow"
+	permission.IsEnabled = true
+	_, err := AddPermission(permission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return permission
+}
+
+// enforcePermissionEnforcerCacheTest enforces alice's read of data1 and checks that the enforcer is cached
+func enforcePermissionEnforcerCacheTest(t *testing.T, permission *Permission) bool {
+	t.Helper()
+
+	allowed, err := Enforce(permission, []interface{}{"org-cache/alice", "data1", "read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enforcer, _ := enforcerCache.get(getPermissionEnforcerCacheKey(permission)); enforcer == nil {
+		t.Fatalf("the enforcer of the permission: %s is not cached", permission.GetId())
+	}
+	return allowed
+}
+
+func assertPermissionEnforcerEvicted(t *testing.T, permission *Permission) {
+	t.Helper()
+
+	if enforcer, _ := enforcerCache.get(getPermissionEnforcerCacheKey(permission)); enforcer != nil {
+		t.Errorf("the enforcer of the permission: %s is still cached", permission.GetId())
+	}
+}
+
+func TestUpdateRoleEvictsCachedEnforcer(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	role := &Role{Owner: "org-cache", Name: "reader", Users: []string{}, IsEnabled: true}
+	_, err := AddRole(role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	permission := addPermissionEnforcerCacheTestPermission(t, &Permission{Name: "perm-role", Roles: []string{"org-cache/reader"}})
+
+	if enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Fatalf("alice is allowed before she has the role")
+	}
+
+	role.Users = []string{"org-cache/alice"}
+	_, err = UpdateRole(role.GetId(), role, true, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPermissionEnforcerEvicted(t, permission)
+	if !enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Errorf("alice isn't allowed after she has been given the role")
+	}
+}
+
+func TestUpdateGroupsForUserEvictsCachedEnforcer(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	_, err := ormer.Engine.Insert(&Group{Owner: "org-cache", Name: "readers", IsEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	permission := addPermissionEnforcerCacheTestPermission(t, &Permission{Name: "perm-group", Groups: []string{"org-cache/readers"}})
+
+	if enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Fatalf("alice is allowed before she is in the group")
+	}
+
+	_, err = userEnforcer.UpdateGroupsForUser("org-cache/alice", []string{"org-cache/readers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPermissionEnforcerEvicted(t, permission)
+	if !enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Errorf("alice isn't allowed after she has joined the group")
+	}
+
+	_, err = userEnforcer.UpdateGroupsForUser("org-cache/alice", []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPermissionEnforcerEvicted(t, permission)
+	if enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Errorf("alice is still allowed after she has left the group")
+	}
+}
+
+func TestUpdatePermissionEvictsCachedEnforcer(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	permission := addPermissionEnforcerCacheTestPermission(t, &Permission{Name: "perm-user", Users: []string{"org-cache/bob"}})
+
+	if enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Fatalf("alice is allowed before the permission is granted to her")
+	}
+
+	permission.Users = []string{"org-cache/alice"}
+	_, err := UpdatePermission(permission.GetId(), permission)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPermissionEnforcerEvicted(t, permission)
+	if !enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Errorf("alice isn't allowed after the permission has been granted to her")
+	}
+}
+
+// TestPermissionEnforcerCacheParallelEnforce enforces on the shared enforcer from many goroutines,
+// run it with -race
+func TestPermissionEnforcerCacheParallelEnforce(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	role := &Role{Owner: "org-cache", Name: "reader", Users: []string{"org-cache/alice"}, IsEnabled: true}
+	_, err := AddRole(role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	permission := addPermissionEnforcerCacheTestPermission(t, &Permission{Name: "perm-parallel", Roles: []string{"org-cache/reader"}})
+
+	// the enforcer is built and cached first, so that all the goroutines share it
+	if !enforcePermissionEnforcerCacheTest(t, permission) {
+		t.Fatalf("alice isn't allowed to read data1")
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			allowed, err := Enforce(permission, []interface{}{"org-cache/alice", "data1", "read"})
+			if err == nil && !allowed {
+				err = fmt.Errorf("alice isn't allowed to read data1")
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			results, err := BatchEnforce(permission, [][]interface{}{{"org-cache/alice", "data1", "read"}, {"org-cache/bob", "data1", "read"}})
+			if err == nil && !reflect.DeepEqual(results, []bool{true, false}) {
+				err = fmt.Errorf("unexpected results of the batch: %v", results)
+			}
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPermissionEnforcerCacheRedisInvalidation(t *testing.T) {
+	redisServer := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	defer client.Close()
+
+	oldEnforcerCache, oldRedisClient := enforcerCache, enforcerCacheRedisClient
+	enforcerCache = newPermissionEnforcerCache()
+	t.Cleanup(func() {
+		enforcerCache, enforcerCacheRedisClient = oldEnforcerCache, oldRedisClient
+	})
+
+	ctx := context.Background()
+	pubsub := client.Subscribe(ctx, permissionEnforcerCacheChannel)
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a change on this node is published to the others
+	enforcerCacheRedisClient = client
+	invalidatePermissionEnforcerCache(permissionEnforcerScopeRole, "org-cache")
+	msg, err := pubsub.ReceiveMessage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var message permissionEnforcerCacheMessage
+	err = json.Unmarshal([]byte(msg.Payload), &message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Node != enforcerCacheNode || !reflect.DeepEqual(message.Scopes, []string{"role:org-cache"}) {
+		t.Errorf("unexpected invalidation message: %v", message)
+	}
+
+	// a change on another node evicts the enforcers of its scopes on this one
+	done := make(chan struct{})
+	go func() {
+		receivePermissionEnforcerCacheMessages(pubsub)
+		close(done)
+	}()
+
+	roleEnforcer, groupEnforcer := &casbin.SyncedEnforcer{}, &casbin.SyncedEnforcer{}
+	_, generation := enforcerCache.get("role")
+	enforcerCache.set("role", roleEnforcer, []string{"permission:org-cache/perm1", "role:org-cache"}, generation)
+	enforcerCache.set("group", groupEnforcer, []string{"permission:org-cache/perm2", "group:org-cache"}, generation)
+
+	publish := func(node string, scopes ...string) {
+		t.Helper()
+
+		payload, err := json.Marshal(permissionEnforcerCacheMessage{Node: node, Scopes: scopes})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = client.Publish(ctx, permissionEnforcerCacheChannel, payload).Err()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the node ignores its own messages, it has dropped the enforcers already
+	publish(enforcerCacheNode, "group:org-cache")
+	publish("node-other", "role:org-cache")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if enforcer, _ := enforcerCache.get("role"); enforcer == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the enforcer is still cached after another node has changed its roles")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if enforcer, _ := enforcerCache.get("group"); enforcer != groupEnforcer {
+		t.Errorf("the enforcer of another scope has been dropped")
+	}
+
+	pubsub.Close()
+	<-done
+}
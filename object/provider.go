@@ -636,6 +636,8 @@ func FromProviderToIdpInfo(ctx *context.Context, provider *Provider) (*idp.Provi
 		}
 	} else if provider.Type == "ADFS" || provider.Type == "AzureAD" || provider.Type == "AzureADB2C" || provider.Type == "Casdoor" || provider.Type == "Okta" {
 		providerInfo.HostUrl = provider.Domain
+	} else if provider.Type == "OIDC" {
+		providerInfo.HostUrl = provider.IssuerUrl
 	} else if provider.Type == "Alipay" && provider.Cert != "" {
 		cert, err := GetCert(util.GetId(provider.Owner, provider.Cert))
 		if err != nil {
@@ -0,0 +1,118 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// RadiusReplyAttribute is a RADIUS attribute that the built-in RADIUS server returns in the
+// Access-Accept of the members of a group or role, so that the NAS can authorize them.
+type RadiusReplyAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// RadiusReplyAttributeNames are the supported reply attributes. "VLAN-ID" is sent as the tunnel
+// attributes of RFC 3580 that assign the VLAN of the user.
+var RadiusReplyAttributeNames = []string{"VLAN-ID", "Filter-Id", "Session-Timeout", "Idle-Timeout", "Framed-IP-Address", "Framed-IP-Netmask"}
+
+func checkRadiusReplyAttribute(attribute *RadiusReplyAttribute) error {
+	switch attribute.Name {
+	case "VLAN-ID":
+		vlanId, err := strconv.Atoi(attribute.Value)
+		if err != nil || vlanId < 1 || vlanId > 4094 {
+			return fmt.Errorf("the VLAN ID: %s should be between 1 and 4094", attribute.Value)
+		}
+	case "Session-Timeout", "Idle-Timeout":
+		seconds, err := strconv.Atoi(attribute.Value)
+		if err != nil || seconds <= 0 {
+			return fmt.Errorf("the %s: %s should be a positive number of seconds", attribute.Name, attribute.Value)
+		}
+	case "Framed-IP-Address", "Framed-IP-Netmask":
+		ip := net.ParseIP(attribute.Value)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("the %s: %s should be an IPv4 address", attribute.Name, attribute.Value)
+		}
+	case "Filter-Id":
+		if attribute.Value == "" {
+			return fmt.Errorf("the Filter-Id should not be empty")
+		}
+	default:
+		return fmt.Errorf("the RADIUS reply attribute: %s is not supported", attribute.Name)
+	}
+	return nil
+}
+
+// CheckRadiusReplyAttributes validates the RADIUS reply attributes of a group or role.
+func CheckRadiusReplyAttributes(attributes []*RadiusReplyAttribute) error {
+	for _, attribute := range attributes {
+		err := checkRadiusReplyAttribute(attribute)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeRadiusReplyAttributes merges the attributes in order, a later attribute replaces an
+// earlier one with the same name.
+func mergeRadiusReplyAttributes(attributeLists ...[]*RadiusReplyAttribute) []*RadiusReplyAttribute {
+	values := map[string]string{}
+	for _, attributes := range attributeLists {
+		for _, attribute := range attributes {
+			if checkRadiusReplyAttribute(attribute) == nil {
+				values[attribute.Name] = attribute.Value
+			}
+		}
+	}
+
+	res := []*RadiusReplyAttribute{}
+	for _, name := range RadiusReplyAttributeNames {
+		if value, ok := values[name]; ok {
+			res = append(res, &RadiusReplyAttribute{Name: name, Value: value})
+		}
+	}
+	return res
+}
+
+// GetRadiusReplyAttributes returns the RADIUS reply attributes of a user, the attributes of the
+// roles of the user take precedence over the ones of the groups.
+func GetRadiusReplyAttributes(user *User) ([]*RadiusReplyAttribute, error) {
+	attributeLists := [][]*RadiusReplyAttribute{}
+	for _, groupId := range user.Groups {
+		group, err := GetGroup(groupId)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil && group.IsEnabled {
+			attributeLists = append(attributeLists, group.RadiusReplyAttributes)
+		}
+	}
+
+	roles, err := getRolesByUser(user.GetId())
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.IsEnabled {
+			attributeLists = append(attributeLists, role.RadiusReplyAttributes)
+		}
+	}
+
+	return mergeRadiusReplyAttributes(attributeLists...), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import "testing"
+
+func TestCheckRadiusReplyAttributes(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"VLAN-ID", "100", true},
+		{"VLAN-ID", "4095", false},
+		{"Session-Timeout", "3600", true},
+		{"Idle-Timeout", "-1", false},
+		{"Framed-IP-Address", "10.0.0.8", true},
+		{"Framed-IP-Netmask", "::1", false},
+		{"Filter-Id", "", false},
+		{"Reply-Message", "hello", false},
+	}
+	for _, c := range cases {
+		err := CheckRadiusReplyAttributes([]*RadiusReplyAttribute{{Name: c.name, Value: c.value}})
+		if (err == nil) != c.ok {
+			t.Errorf("CheckRadiusReplyAttributes(%s = %q) = %v, want ok: %v", c.name, c.value, err, c.ok)
+		}
+	}
+}
+
+func TestMergeRadiusReplyAttributes(t *testing.T) {
+	group := []*RadiusReplyAttribute{{Name: "Session-Timeout", Value: "3600"}, {Name: "VLAN-ID", Value: "10"}}
+	role := []*RadiusReplyAttribute{{Name: "VLAN-ID", Value: "20"}, {Name: "VLAN-ID", Value: "0"}}
+
+	res := mergeRadiusReplyAttributes(group, role)
+	if len(res) != 2 || res[0].Name != "VLAN-ID" || res[0].Value != "20" || res[1].Name != "Session-Timeout" {
+		t.Errorf("unexpected merged attributes: %v %v", res[0], res[1])
+	}
+}
@@ -368,6 +368,11 @@ func SendWebhooks(record *Record) error {
 		// This provides automatic retry and replay capability
 	}
 
+	err = addEventSinkEventsFromRecord(record)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		errStrings := []string{}
 		for _, err := range errs {
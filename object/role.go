@@ -37,6 +37,8 @@ type Role struct {
 	Roles     []string `xorm:"mediumtext" json:"roles"`
 	Domains   []string `xorm:"mediumtext" json:"domains"`
 	IsEnabled bool     `json:"isEnabled"`
+
+	RadiusReplyAttributes []*RadiusReplyAttribute `xorm:"mediumtext" json:"radiusReplyAttributes"`
 }
 
 func GetRoleCount(owner, field, value string) (int64, error) {
@@ -131,6 +133,10 @@ func UpdateRole(id string, role *Role, isGlobalAdmin bool, lang string) (bool, e
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeRole, owner, role.Owner)
+	}
+
 	if renameRole && affected != 0 {
 		permissions, err := GetPermissionsByRole(role.GetId())
 		if err != nil {
@@ -154,6 +160,10 @@ func AddRole(role *Role) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeRole, role.Owner)
+	}
+
 	return affected != 0, nil
 }
 
@@ -167,6 +177,14 @@ func AddRoles(roles []*Role) bool {
 			panic(err)
 		}
 	}
+
+	if affected != 0 {
+		owners := []string{}
+		for _, role := range roles {
+			owners = append(owners, role.Owner)
+		}
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeRole, owners...)
+	}
 	return affected != 0
 }
 
@@ -201,6 +219,10 @@ func deleteRole(role *Role) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		invalidatePermissionEnforcerCache(permissionEnforcerScopeRole, role.Owner)
+	}
+
 	return affected != 0, nil
 }
 
@@ -25,9 +25,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"io"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/beevik/etree"
@@ -39,7 +37,7 @@ import (
 
 // NewSamlResponse
 // returns a saml2 response
-func NewSamlResponse(application *Application, user *User, host string, certificate string, destination string, iss string, requestId string, redirectUri []string) (*etree.Element, error) {
+func NewSamlResponse(application *Application, user *User, host string, certificate string, destination string, iss string, requestId string, redirectUri []string, sessionIndex string) (*etree.Element, error) {
 	samlResponse := &etree.Element{
 		Space: "samlp",
 		Tag:   "Response",
@@ -70,16 +68,9 @@ func NewSamlResponse(application *Application, user *User, host string, certific
 	assertion.CreateAttr("IssueInstant", now)
 	assertion.CreateElement("saml:Issuer").SetText(host)
 	subject := assertion.CreateElement("saml:Subject")
-	nameIDValue := user.Name
-	if application.UseEmailAsSamlNameId {
-		nameIDValue = user.Email
-	}
+	nameIDValue, nameIDFormat := getSamlNameId(application, user)
 	nameId := subject.CreateElement("saml:NameID")
-	if application.UseEmailAsSamlNameId {
-		nameId.CreateAttr("Format", "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress")
-	} else {
-		nameId.CreateAttr("Format", "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified")
-	}
+	nameId.CreateAttr("Format", nameIDFormat)
 	nameId.SetText(nameIDValue)
 	subjectConfirmation := subject.CreateElement("saml:SubjectConfirmation")
 	subjectConfirmation.CreateAttr("Method", "urn:oasis:names:tc:SAML:2.0:cm:bearer")
@@ -100,7 +91,7 @@ func NewSamlResponse(application *Application, user *User, host string, certific
 	}
 	authnStatement := assertion.CreateElement("saml:AuthnStatement")
 	authnStatement.CreateAttr("AuthnInstant", now)
-	authnStatement.CreateAttr("SessionIndex", fmt.Sprintf("_%s", util.GenerateUUID()))
+	authnStatement.CreateAttr("SessionIndex", sessionIndex)
 	authnStatement.CreateAttr("SessionNotOnOrAfter", expireTime)
 	authnStatement.CreateElement("saml:AuthnContext").CreateElement("saml:AuthnContextClassRef").SetText("urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport")
 
@@ -199,9 +190,10 @@ type IdpSSODescriptor struct {
 	XMLName                    xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
 	ProtocolSupportEnumeration string   `xml:"protocolSupportEnumeration,attr"`
 	SigningKeyDescriptor       KeyDescriptor
-	NameIDFormats              []NameIDFormat      `xml:"NameIDFormat"`
-	SingleSignOnService        SingleSignOnService `xml:"SingleSignOnService"`
-	Attribute                  []Attribute         `xml:"Attribute"`
+	SingleLogoutServices       []SingleLogoutService `xml:"SingleLogoutService"`
+	NameIDFormats              []NameIDFormat        `xml:"NameIDFormat"`
+	SingleSignOnService        SingleSignOnService   `xml:"SingleSignOnService"`
+	Attribute                  []Attribute           `xml:"Attribute"`
 }
 
 type NameIDFormat struct {
@@ -215,6 +207,11 @@ type SingleSignOnService struct {
 	Location string `xml:"Location,attr"`
 }
 
+type SingleLogoutService struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
 type Attribute struct {
 	// XMLName      xml.Name
 	Xmlns        string   `xml:"xmlns,attr"`
@@ -272,6 +269,10 @@ func GetSamlMeta(application *Application, host string, enablePostBinding bool)
 					},
 				},
 			},
+			SingleLogoutServices: []SingleLogoutService{
+				{Binding: samlBindingPrefix + SamlBindingRedirect, Location: getSamlSloLocation(application, host)},
+				{Binding: samlBindingPrefix + SamlBindingPost, Location: getSamlSloLocation(application, host)},
+			},
 			NameIDFormats: []NameIDFormat{
 				{Value: "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"},
 				{Value: "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"},
@@ -293,39 +294,55 @@ func GetSamlMeta(application *Application, host string, enablePostBinding bool)
 	return &d, nil
 }
 
+// getSamlSigningContext returns the context signing the SAML messages of the application with its cert
+func getSamlSigningContext(application *Application, cert *Cert) (*dsig.SigningContext, error) {
+	if cert == nil {
+		return nil, errors.New("please set a cert for the application first")
+	}
+
+	if cert.Certificate == "" {
+		return nil, fmt.Errorf("the certificate field should not be empty for the cert: %v", cert)
+	}
+
+	block, _ := pem.Decode([]byte(cert.Certificate))
+	certificate := base64.StdEncoding.EncodeToString(block.Bytes)
+
+	randomKeyStore := &X509Key{
+		PrivateKey:      cert.PrivateKey,
+		X509Certificate: certificate,
+	}
+	ctx := dsig.NewDefaultSigningContext(randomKeyStore)
+	if application.SamlHashAlgorithm == "" || application.SamlHashAlgorithm == "SHA1" {
+		ctx.Hash = crypto.SHA1
+	} else if application.SamlHashAlgorithm == "SHA256" {
+		ctx.Hash = crypto.SHA256
+	} else if application.SamlHashAlgorithm == "SHA512" {
+		ctx.Hash = crypto.SHA512
+	}
+
+	if application.EnableSamlC14n10 {
+		ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList(application.SamlC14nPrefix)
+	}
+
+	return ctx, nil
+}
+
 // GetSamlResponse generates a SAML2.0 response
 // parameter samlRequest is saml request in base64 format
-func GetSamlResponse(application *Application, user *User, samlRequest string, host string) (string, string, string, error) {
+// parameter querySignature is the signature of the request when it comes over the HTTP-Redirect binding
+// parameter sessionId is the Beego session signing in, the service provider is recorded for it as a SamlSession for Single Logout
+func GetSamlResponse(application *Application, user *User, samlRequest string, querySignature *SamlQuerySignature, host string, sessionId string) (string, string, string, error) {
 	// request type
 	method := "GET"
-	samlRequest = strings.ReplaceAll(samlRequest, " ", "+")
-	// base64 decode
-	defated, err := base64.StdEncoding.DecodeString(samlRequest)
+	requestByte, err := decodeSamlMessage(samlRequest)
 	if err != nil {
 		return "", "", "", fmt.Errorf("err: Failed to decode SAML request, %s", err.Error())
 	}
 
-	var requestByte []byte
-
-	if strings.Contains(string(defated), "xmlns:") {
-		requestByte = defated
-	} else {
-		// decompress
-		var buffer bytes.Buffer
-		rdr := flate.NewReader(bytes.NewReader(defated))
-
-		for {
-
-			_, err = io.CopyN(&buffer, rdr, 1024)
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				return "", "", "", err
-			}
-		}
-
-		requestByte = buffer.Bytes()
+	// verify the signature with the imported SP certificate before reading anything from the request
+	requestByte, err = validateSamlRequestSignature(application, "SAMLRequest", samlRequest, requestByte, querySignature, application.SamlAuthnRequestsSigned)
+	if err != nil {
+		return "", "", "", err
 	}
 
 	var authnRequest saml.AuthNRequest
@@ -366,26 +383,15 @@ func GetSamlResponse(application *Application, user *User, samlRequest string, h
 	_, originBackend := getOriginFromHost(host)
 
 	// build signedResponse
-	samlResponse, err := NewSamlResponse(application, user, originBackend, certificate, authnRequest.AssertionConsumerServiceURL, authnRequest.Issuer, authnRequest.ID, application.RedirectUris)
+	sessionIndex := fmt.Sprintf("_%s", util.GenerateUUID())
+	samlResponse, err := NewSamlResponse(application, user, originBackend, certificate, authnRequest.AssertionConsumerServiceURL, authnRequest.Issuer, authnRequest.ID, application.RedirectUris, sessionIndex)
 	if err != nil {
 		return "", "", "", fmt.Errorf("err: NewSamlResponse() error, %s", err.Error())
 	}
 
-	randomKeyStore := &X509Key{
-		PrivateKey:      cert.PrivateKey,
-		X509Certificate: certificate,
-	}
-	ctx := dsig.NewDefaultSigningContext(randomKeyStore)
-	if application.SamlHashAlgorithm == "" || application.SamlHashAlgorithm == "SHA1" {
-		ctx.Hash = crypto.SHA1
-	} else if application.SamlHashAlgorithm == "SHA256" {
-		ctx.Hash = crypto.SHA256
-	} else if application.SamlHashAlgorithm == "SHA512" {
-		ctx.Hash = crypto.SHA512
-	}
-
-	if application.EnableSamlC14n10 {
-		ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList(application.SamlC14nPrefix)
+	ctx, err := getSamlSigningContext(application, cert)
+	if err != nil {
+		return "", "", "", err
 	}
 
 	// signedXML, err := ctx.SignEnvelopedLimix(samlResponse)
@@ -407,6 +413,21 @@ func GetSamlResponse(application *Application, user *User, samlRequest string, h
 		}
 	}
 
+	// Encrypt the (signed) assertion when the SP encryption certificate has been imported
+	if application.SamlSpEncryptionCert != "" {
+		assertion := samlResponse.FindElement("./Assertion")
+		if assertion != nil {
+			encryptedAssertion, err := encryptSamlAssertion(assertion, application.SamlSpEncryptionCert)
+			if err != nil {
+				return "", "", "", fmt.Errorf("err: Failed to encrypt SAML assertion, %s", err.Error())
+			}
+
+			index := assertion.Index()
+			samlResponse.RemoveChildAt(index)
+			samlResponse.InsertChildAt(index, encryptedAssertion)
+		}
+	}
+
 	// Sign the response
 	sig, err := ctx.ConstructSignature(samlResponse, true)
 	if err != nil {
@@ -444,6 +465,22 @@ func GetSamlResponse(application *Application, user *User, samlRequest string, h
 
 		xmlBytes = flated.Bytes()
 	}
+	nameIdValue, nameIdFormat := getSamlNameId(application, user)
+	_, err = AddSamlSession(&SamlSession{
+		Owner:        application.Owner,
+		Name:         sessionIndex,
+		Application:  application.Name,
+		Organization: user.Owner,
+		User:         user.Name,
+		SessionId:    sessionId,
+		Issuer:       authnRequest.Issuer,
+		NameId:       nameIdValue,
+		NameIdFormat: nameIdFormat,
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+
 	// base64 encode
 	res := base64.StdEncoding.EncodeToString(xmlBytes)
 	return res, authnRequest.AssertionConsumerServiceURL, method, err
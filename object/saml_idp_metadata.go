@@ -0,0 +1,142 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/russellhaering/gosaml2/types"
+)
+
+// SamlSpMetadata holds the SAML settings of an application read from the metadata of its service provider
+type SamlSpMetadata struct {
+	EntityId             string `json:"entityId"`
+	AcsUrl               string `json:"acsUrl"`
+	SloUrl               string `json:"sloUrl"`
+	SloBinding           string `json:"sloBinding"`
+	SloSoapUrl           string `json:"sloSoapUrl"`
+	SigningCert          string `json:"signingCert"`
+	EncryptionCert       string `json:"encryptionCert"`
+	UseEmailAsNameId     bool   `json:"useEmailAsNameId"`
+	AuthnRequestsSigned  bool   `json:"authnRequestsSigned"`
+	WantAssertionsSigned bool   `json:"wantAssertionsSigned"`
+}
+
+var samlMetadataClient = &http.Client{Timeout: 10 * time.Second}
+
+func getSamlMetadataCertificate(keyDescriptor types.KeyDescriptor) string {
+	certificates := keyDescriptor.KeyInfo.X509Data.X509Certificates
+	if len(certificates) == 0 {
+		return ""
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificates[0].Data), ""))
+	if err != nil {
+		return ""
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// ParseSamlSpMetadata reads the SPSSODescriptor of the metadata of a service provider. The HTTP-POST Assertion
+// Consumer Service is preferred as Casdoor posts its responses to the reply URL, and so is the HTTP-POST Single
+// Logout Service for the logouts that go through the browser. The SOAP Single Logout Service is kept apart,
+// it receives the LogoutRequests sent over the back channel.
+func ParseSamlSpMetadata(metadata string) (*SamlSpMetadata, error) {
+	var entityDescriptor types.EntityDescriptor
+	err := xml.Unmarshal([]byte(metadata), &entityDescriptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SAML SP metadata, %s", err.Error())
+	}
+
+	spDescriptor := entityDescriptor.SPSSODescriptor
+	if spDescriptor == nil {
+		return nil, errors.New("the SAML metadata has no SPSSODescriptor")
+	}
+
+	res := &SamlSpMetadata{
+		EntityId:             entityDescriptor.EntityID,
+		AuthnRequestsSigned:  spDescriptor.AuthnRequestsSigned,
+		WantAssertionsSigned: spDescriptor.WantAssertionsSigned,
+	}
+
+	for _, acs := range spDescriptor.AssertionConsumerServices {
+		if res.AcsUrl == "" || acs.Binding == samlBindingPrefix+SamlBindingPost {
+			res.AcsUrl = acs.Location
+		}
+		if acs.Binding == samlBindingPrefix+SamlBindingPost {
+			break
+		}
+	}
+
+	for _, slo := range spDescriptor.SingleLogoutServices {
+		binding := strings.TrimPrefix(slo.Binding, samlBindingPrefix)
+		if binding == SamlBindingSoap {
+			res.SloSoapUrl = slo.Location
+			continue
+		}
+		if binding != SamlBindingRedirect && binding != SamlBindingPost {
+			continue
+		}
+
+		if res.SloUrl == "" || binding == SamlBindingPost {
+			res.SloUrl = slo.Location
+			res.SloBinding = binding
+		}
+	}
+
+	for _, keyDescriptor := range spDescriptor.KeyDescriptors {
+		certificate := getSamlMetadataCertificate(keyDescriptor)
+		if (keyDescriptor.Use == "signing" || keyDescriptor.Use == "") && res.SigningCert == "" {
+			res.SigningCert = certificate
+		}
+		if (keyDescriptor.Use == "encryption" || keyDescriptor.Use == "") && res.EncryptionCert == "" {
+			res.EncryptionCert = certificate
+		}
+	}
+
+	if len(spDescriptor.NameIDFormats) != 0 {
+		res.UseEmailAsNameId = strings.TrimSpace(spDescriptor.NameIDFormats[0]) == samlNameIdFormatMail
+	}
+
+	return res, nil
+}
+
+// FetchSamlSpMetadata downloads the metadata of a service provider from its metadata URL
+func FetchSamlSpMetadata(metadataUrl string) (string, error) {
+	resp, err := samlMetadataClient.Get(metadataUrl)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the SAML SP metadata, status: %s", resp.Status)
+	}
+
+	// a metadata document of a single entity is far below this limit
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	xmlEncNamespace       = "http://www.w3.org/2001/04/xmlenc#"
+	xmlEncElementType     = "http://www.w3.org/2001/04/xmlenc#Element"
+	xmlEncAes256Gcm       = "http://www.w3.org/2009/xmlenc11#aes256-gcm"
+	xmlEncRsaOaepMgf1p    = "http://www.w3.org/2001/04/xmlenc#rsa-oaep-mgf1p"
+	xmlDsigNamespace      = "http://www.w3.org/2000/09/xmldsig#"
+	xmlDsigSha1DigestAlgo = "http://www.w3.org/2000/09/xmldsig#sha1"
+)
+
+var samlSigAlgHashes = map[string]crypto.Hash{
+	dsig.RSASHA1SignatureMethod:   crypto.SHA1,
+	dsig.RSASHA256SignatureMethod: crypto.SHA256,
+	dsig.RSASHA512SignatureMethod: crypto.SHA512,
+}
+
+// SamlQuerySignature is the signature of a SAML request sent over the HTTP-Redirect binding,
+// which is carried by the query string instead of the XML. RawQuery is the query string as received,
+// the signature covers the parameters in their original encoding.
+type SamlQuerySignature struct {
+	RelayState string
+	SigAlg     string
+	Signature  string
+	RawQuery   string
+}
+
+// parseSamlSpCertificate parses a certificate of a service provider, either PEM encoded
+// or the base64 DER content of an X509Certificate element as found in the metadata
+func parseSamlSpCertificate(certificate string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block != nil {
+		return x509.ParseCertificate(block.Bytes)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificate), ""))
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// validateSamlRequestSignature validates the signature of a SAML request with the SP certificate of the application,
+// either the query string signature of the HTTP-Redirect binding or the enveloped XML signature of the HTTP-POST binding.
+// It returns the XML to read the request from, which is the signed element only when signed by an XML signature.
+// A request without signature is rejected when requireSignature is true, so is any request of an application
+// without SP certificate then.
+func validateSamlRequestSignature(application *Application, param string, message string, requestByte []byte, querySignature *SamlQuerySignature, requireSignature bool) ([]byte, error) {
+	if application.SamlSpCert == "" {
+		if requireSignature {
+			return nil, fmt.Errorf("the SAML SP certificate of the application: %s is empty, but the signature of the SAML request is required", application.GetId())
+		}
+		return requestByte, nil
+	}
+
+	cert, err := parseSamlSpCertificate(application.SamlSpCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SAML SP certificate of the application: %s, %s", application.GetId(), err.Error())
+	}
+
+	return validateSamlMessageSignature(cert, param, message, requestByte, querySignature, requireSignature)
+}
+
+// validateSamlMessageSignature validates the signature of a SAML message with the certificate of its signer,
+// it is shared by the requests of the service providers and the ones of the upstream identity providers.
+func validateSamlMessageSignature(cert *x509.Certificate, param string, message string, requestByte []byte, querySignature *SamlQuerySignature, requireSignature bool) ([]byte, error) {
+	if querySignature != nil && querySignature.Signature != "" {
+		err := verifySamlQuerySignature(cert, param, message, querySignature)
+		if err != nil {
+			return nil, err
+		}
+		return requestByte, nil
+	}
+
+	doc := etree.NewDocument()
+	err := doc.ReadFromBytes(requestByte)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("the SAML request is empty")
+	}
+
+	if doc.Root().FindElement("./Signature") == nil {
+		if requireSignature {
+			return nil, errors.New("the SAML request isn't signed, but its signature is required")
+		}
+		return requestByte, nil
+	}
+
+	ctx := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: []*x509.Certificate{cert}})
+	ctx.IdAttribute = "ID"
+	validated, err := ctx.Validate(doc.Root())
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate the signature of the SAML request, %s", err.Error())
+	}
+
+	validatedDoc := etree.NewDocument()
+	validatedDoc.SetRoot(validated)
+	return validatedDoc.WriteToBytes()
+}
+
+// getSamlSignedQuery rebuilds "param=...&RelayState=...&SigAlg=..." from the raw query string, as the signer may
+// have URL encoded the values in another way than Go does. The raw values have to match the parsed ones,
+// so that the signature covers the message which is read.
+func getSamlSignedQuery(param string, message string, querySignature *SamlQuerySignature) (string, error) {
+	if querySignature.RawQuery == "" {
+		return "", errors.New("the query string of the signed SAML request is empty")
+	}
+
+	rawValues := map[string]string{}
+	for _, pair := range strings.Split(querySignature.RawQuery, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if key != param && key != "RelayState" && key != "SigAlg" {
+			continue
+		}
+		if _, ok := rawValues[key]; ok {
+			return "", fmt.Errorf("the query string of the SAML request has more than one %s", key)
+		}
+		rawValues[key] = value
+	}
+
+	expected := map[string]string{param: message, "RelayState": querySignature.RelayState, "SigAlg": querySignature.SigAlg}
+	for key, value := range expected {
+		rawValue, err := url.QueryUnescape(rawValues[key])
+		if err != nil {
+			return "", err
+		}
+		if strings.ReplaceAll(rawValue, " ", "+") != strings.ReplaceAll(value, " ", "+") {
+			return "", fmt.Errorf("the %s of the SAML request doesn't match its query string", key)
+		}
+	}
+
+	query := fmt.Sprintf("%s=%s", param, rawValues[param])
+	if rawRelayState, ok := rawValues["RelayState"]; ok {
+		query += fmt.Sprintf("&RelayState=%s", rawRelayState)
+	}
+	query += fmt.Sprintf("&SigAlg=%s", rawValues["SigAlg"])
+	return query, nil
+}
+
+// verifySamlQuerySignature verifies the signature over "param=...&RelayState=...&SigAlg=..." of the raw query string,
+// see 3.4.4.1 DEFLATE Encoding of https://docs.oasis-open.org/security/saml/v2.0/saml-bindings-2.0-os.pdf
+func verifySamlQuerySignature(cert *x509.Certificate, param string, message string, querySignature *SamlQuerySignature) error {
+	hash, ok := samlSigAlgHashes[querySignature.SigAlg]
+	if !ok {
+		return fmt.Errorf("the SAML signature algorithm: %s is not supported", querySignature.SigAlg)
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("the SAML SP certificate doesn't hold an RSA public key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(querySignature.Signature, " ", "+"))
+	if err != nil {
+		return err
+	}
+
+	query, err := getSamlSignedQuery(param, message, querySignature)
+	if err != nil {
+		return err
+	}
+
+	hasher := hash.New()
+	hasher.Write([]byte(query))
+	err = rsa.VerifyPKCS1v15(publicKey, hash, hasher.Sum(nil), signature)
+	if err != nil {
+		return fmt.Errorf("failed to validate the signature of the SAML request, %s", err.Error())
+	}
+	return nil
+}
+
+// encryptSamlAssertion encrypts the assertion for the service provider into an EncryptedAssertion,
+// with a random AES-256-GCM key which is encrypted by RSA-OAEP with the SP encryption certificate
+func encryptSamlAssertion(assertion *etree.Element, certificate string) (*etree.Element, error) {
+	cert, err := parseSamlSpCertificate(certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SAML SP encryption certificate, %s", err.Error())
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("the SAML SP encryption certificate doesn't hold an RSA public key")
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(assertion.Copy())
+	plainText, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The nonce is prepended to the cipher text and the tag appended to it, as XML Encryption 1.1 requires
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	cipherText := gcm.Seal(nonce, nonce, plainText, nil)
+
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, publicKey, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedAssertion := &etree.Element{
+		Space: "saml",
+		Tag:   "EncryptedAssertion",
+	}
+	encryptedAssertion.CreateAttr("xmlns:saml", "urn:oasis:names:tc:SAML:2.0:assertion")
+
+	encryptedData := encryptedAssertion.CreateElement("xenc:EncryptedData")
+	encryptedData.CreateAttr("xmlns:xenc", xmlEncNamespace)
+	encryptedData.CreateAttr("Type", xmlEncElementType)
+	encryptedData.CreateElement("xenc:EncryptionMethod").CreateAttr("Algorithm", xmlEncAes256Gcm)
+
+	keyInfo := encryptedData.CreateElement("ds:KeyInfo")
+	keyInfo.CreateAttr("xmlns:ds", xmlDsigNamespace)
+	encryptedKeyElement := keyInfo.CreateElement("xenc:EncryptedKey")
+	keyEncryptionMethod := encryptedKeyElement.CreateElement("xenc:EncryptionMethod")
+	keyEncryptionMethod.CreateAttr("Algorithm", xmlEncRsaOaepMgf1p)
+	keyEncryptionMethod.CreateElement("ds:DigestMethod").CreateAttr("Algorithm", xmlDsigSha1DigestAlgo)
+	encryptedKeyElement.CreateElement("ds:KeyInfo").CreateElement("ds:X509Data").CreateElement("ds:X509Certificate").SetText(base64.StdEncoding.EncodeToString(cert.Raw))
+	encryptedKeyElement.CreateElement("xenc:CipherData").CreateElement("xenc:CipherValue").SetText(base64.StdEncoding.EncodeToString(encryptedKey))
+
+	encryptedData.CreateElement("xenc:CipherData").CreateElement("xenc:CipherValue").SetText(base64.StdEncoding.EncodeToString(cipherText))
+	return encryptedAssertion, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/russellhaering/gosaml2/types"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const samlTestAuthnRequest = `<samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_request" Version="2.0"><saml:Issuer>https://sp.example.com/metadata</saml:Issuer></samlp:AuthnRequest>`
+
+func TestEncryptSamlAssertion(t *testing.T) {
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 1, "sp", "sp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := &Application{DisableSamlAttributes: true}
+	user := &User{Owner: "built-in", Name: "alice"}
+	samlResponse, err := NewSamlResponse(application, user, "https://casdoor.example.com", "", "https://sp.example.com/acs", "https://sp.example.com/metadata", "_request", nil, "_index")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := encryptSamlAssertion(samlResponse.FindElement("./Assertion"), certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(encrypted)
+	xmlBytes, err := doc.WriteToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encryptedAssertion types.EncryptedAssertion
+	err = xml.Unmarshal(xmlBytes, &encryptedAssertion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPair, err := tls.X509KeyPair([]byte(certificate), []byte(privateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertion, err := encryptedAssertion.Decrypt(&keyPair)
+	if err != nil {
+		t.Fatalf("failed to decrypt the assertion: %v", err)
+	}
+	if assertion.Subject.NameID.Value != "alice" || assertion.AuthnStatement.SessionIndex != "_index" {
+		t.Errorf("unexpected decrypted assertion: %+v", assertion)
+	}
+}
+
+func newSamlTestSpSigningContext(t *testing.T) (*Application, *dsig.SigningContext) {
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 1, "sp", "sp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := pem.Decode([]byte(certificate))
+	ctx := dsig.NewDefaultSigningContext(&X509Key{X509Certificate: base64.StdEncoding.EncodeToString(block.Bytes), PrivateKey: privateKey})
+	ctx.Hash = crypto.SHA256
+	return &Application{Owner: "admin", Name: "app-saml", SamlSpCert: certificate}, ctx
+}
+
+func TestValidateSamlRequestXmlSignature(t *testing.T) {
+	application, ctx := newSamlTestSpSigningContext(t)
+
+	doc := etree.NewDocument()
+	err := doc.ReadFromString(samlTestAuthnRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := ctx.SignEnveloped(doc.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.SetRoot(signed)
+	signedBytes, err := doc.WriteToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", "", signedBytes, nil, true)
+	if err != nil {
+		t.Errorf("a signed request isn't valid: %v", err)
+	}
+
+	tampered := strings.Replace(string(signedBytes), "https://sp.example.com/metadata", "https://evil.example.com", 1)
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", "", []byte(tampered), nil, true)
+	if err == nil {
+		t.Errorf("a tampered request is valid")
+	}
+
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", "", []byte(samlTestAuthnRequest), nil, true)
+	if err == nil {
+		t.Errorf("an unsigned request is valid while the signature is required")
+	}
+
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", "", []byte(samlTestAuthnRequest), nil, false)
+	if err != nil {
+		t.Errorf("an unsigned request isn't valid while the signature is optional: %v", err)
+	}
+}
+
+func TestValidateSamlRequestQuerySignature(t *testing.T) {
+	application, ctx := newSamlTestSpSigningContext(t)
+
+	message := base64.StdEncoding.EncodeToString([]byte(samlTestAuthnRequest))
+	query := fmt.Sprintf("SAMLRequest=%s&RelayState=%s&SigAlg=%s", url.QueryEscape(message), url.QueryEscape("state"), url.QueryEscape(dsig.RSASHA256SignatureMethod))
+	signature, err := ctx.SignString(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawQuery := query + "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature))
+	querySignature := &SamlQuerySignature{RelayState: "state", SigAlg: dsig.RSASHA256SignatureMethod, Signature: base64.StdEncoding.EncodeToString(signature), RawQuery: rawQuery}
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", message, []byte(samlTestAuthnRequest), querySignature, true)
+	if err != nil {
+		t.Errorf("a signed request isn't valid: %v", err)
+	}
+
+	querySignature.RelayState = "other"
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", message, []byte(samlTestAuthnRequest), querySignature, true)
+	if err == nil {
+		t.Errorf("a request with a tampered relay state is valid")
+	}
+
+	querySignature.RelayState = "state"
+	querySignature.RawQuery = strings.Replace(rawQuery, "RelayState=state", "RelayState=other", 1)
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", message, []byte(samlTestAuthnRequest), querySignature, true)
+	if err == nil {
+		t.Errorf("a request with a tampered query string is valid")
+	}
+
+	querySignature.RawQuery = ""
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", message, []byte(samlTestAuthnRequest), querySignature, true)
+	if err == nil {
+		t.Errorf("a request without its query string is valid")
+	}
+}
+
+func TestValidateSamlRequestQuerySignatureRawEncoding(t *testing.T) {
+	application, ctx := newSamlTestSpSigningContext(t)
+
+	// the SP encodes with lowercase hex digits and leaves "/" as is, unlike url.QueryEscape
+	message := base64.StdEncoding.EncodeToString([]byte(samlTestAuthnRequest))
+	encode := func(value string) string {
+		escaped := strings.ReplaceAll(url.QueryEscape(value), "%2F", "/")
+		return regexp.MustCompile("%[0-9A-F]{2}").ReplaceAllStringFunc(escaped, strings.ToLower)
+	}
+	query := fmt.Sprintf("SAMLRequest=%s&RelayState=%s&SigAlg=%s", encode(message), encode("https://sp.example.com/a b"), encode(dsig.RSASHA256SignatureMethod))
+	if query == fmt.Sprintf("SAMLRequest=%s&RelayState=%s&SigAlg=%s", url.QueryEscape(message), url.QueryEscape("https://sp.example.com/a b"), url.QueryEscape(dsig.RSASHA256SignatureMethod)) {
+		t.Fatal("the query string is encoded the same way as url.QueryEscape")
+	}
+	signature, err := ctx.SignString(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	querySignature := &SamlQuerySignature{
+		RelayState: "https://sp.example.com/a b",
+		SigAlg:     dsig.RSASHA256SignatureMethod,
+		Signature:  base64.StdEncoding.EncodeToString(signature),
+		RawQuery:   query + "&Signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature)),
+	}
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", message, []byte(samlTestAuthnRequest), querySignature, true)
+	if err != nil {
+		t.Errorf("a request signed over its own encoding isn't valid: %v", err)
+	}
+}
+
+func TestValidateSamlRequestSignatureWithoutSpCert(t *testing.T) {
+	application := &Application{Owner: "admin", Name: "app-saml"}
+
+	_, err := validateSamlRequestSignature(application, "SAMLRequest", "", []byte(samlTestAuthnRequest), nil, true)
+	if err == nil {
+		t.Errorf("a required signature is skipped without the SP certificate")
+	}
+
+	_, err = validateSamlRequestSignature(application, "SAMLRequest", "", []byte(samlTestAuthnRequest), nil, false)
+	if err != nil {
+		t.Errorf("an unsigned request isn't valid while the signature is optional: %v", err)
+	}
+}
+
+func TestParseSamlSpMetadata(t *testing.T) {
+	certificate, _, err := generateRsaKeys(2048, 256, 1, "sp", "sp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode([]byte(certificate))
+	certData := base64.StdEncoding.EncodeToString(block.Bytes)
+
+	metadata := fmt.Sprintf(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://sp.example.com/metadata">
+  <md:SPSSODescriptor AuthnRequestsSigned="true" WantAssertionsSigned="true" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>
+    <md:KeyDescriptor use="encryption"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>%s</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://sp.example.com/slo/redirect"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/slo/post"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:SOAP" Location="https://sp.example.com/slo/soap"/>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Artifact" Location="https://sp.example.com/acs/artifact" index="0"/>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/acs/post" index="1"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`, certData, certData)
+
+	spMetadata, err := ParseSamlSpMetadata(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := SamlSpMetadata{
+		EntityId:             "https://sp.example.com/metadata",
+		AcsUrl:               "https://sp.example.com/acs/post",
+		SloUrl:               "https://sp.example.com/slo/post",
+		SloBinding:           SamlBindingPost,
+		SloSoapUrl:           "https://sp.example.com/slo/soap",
+		SigningCert:          certificate,
+		EncryptionCert:       certificate,
+		UseEmailAsNameId:     true,
+		AuthnRequestsSigned:  true,
+		WantAssertionsSigned: true,
+	}
+	if *spMetadata != expected {
+		t.Errorf("ParseSamlSpMetadata() = %+v, want %+v", *spMetadata, expected)
+	}
+
+	if _, err = ParseSamlSpMetadata(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="idp"/>`); err == nil {
+		t.Errorf("metadata without SPSSODescriptor is accepted")
+	}
+}
@@ -0,0 +1,728 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/beego/beego/v2/server/web"
+	"github.com/beevik/etree"
+	"github.com/casdoor/casdoor/util"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	SamlBindingRedirect = "HTTP-Redirect"
+	SamlBindingPost     = "HTTP-POST"
+	SamlBindingSoap     = "SOAP"
+
+	samlBindingPrefix    = "urn:oasis:names:tc:SAML:2.0:bindings:"
+	samlStatusSuccess    = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlNameIdFormatMail = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	samlNameIdFormatName = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	samlSoapAction       = "http://www.oasis-open.org/committees/security"
+
+	// samlLogoutChainTtl is the time the browser has to go through the service providers of a logout
+	samlLogoutChainTtl = 10 * time.Minute
+)
+
+// SamlSession is a session index issued in a SAML assertion, it records which service provider
+// the Beego session has signed in to, so that the session can be logged out of it later.
+type SamlSession struct {
+	Owner        string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name         string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime  string `xorm:"varchar(100)" json:"createdTime"`
+	Application  string `xorm:"varchar(100)" json:"application"`
+	Organization string `xorm:"varchar(100) index" json:"organization"`
+	User         string `xorm:"varchar(100) index" json:"user"`
+	SessionId    string `xorm:"varchar(100) index" json:"sessionId"`
+	Issuer       string `xorm:"varchar(500)" json:"issuer"`
+	NameId       string `xorm:"varchar(500)" json:"nameId"`
+	NameIdFormat string `xorm:"varchar(100)" json:"nameIdFormat"`
+}
+
+type SamlLogoutRequest struct {
+	XMLName      xml.Name `xml:"LogoutRequest"`
+	ID           string   `xml:"ID,attr"`
+	Destination  string   `xml:"Destination,attr"`
+	Issuer       string   `xml:"Issuer"`
+	NameID       string   `xml:"NameID"`
+	SessionIndex []string `xml:"SessionIndex"`
+}
+
+type SamlLogoutResponse struct {
+	XMLName      xml.Name `xml:"LogoutResponse"`
+	ID           string   `xml:"ID,attr"`
+	InResponseTo string   `xml:"InResponseTo,attr"`
+	Issuer       string   `xml:"Issuer"`
+	Status       struct {
+		StatusCode struct {
+			Value string `xml:"Value,attr"`
+		} `xml:"StatusCode"`
+	} `xml:"Status"`
+}
+
+var samlPostFormTemplate = template.Must(template.New("samlPostForm").Parse(`<!DOCTYPE html>
+<html>
+<body onload="document.forms[0].submit()">
+<form method="post" action="{{.Url}}">
+{{range $key, $values := .Values}}{{range $values}}<input type="hidden" name="{{$key}}" value="{{.}}"/>
+{{end}}{{end}}<noscript><input type="submit" value="Continue"/></noscript>
+</form>
+</body>
+</html>`))
+
+// samlSoapLogoutResponse is the SOAP envelope of the LogoutResponse a service provider answers with over the SOAP binding
+type samlSoapLogoutResponse struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Body    struct {
+		LogoutResponse *SamlLogoutResponse `xml:"LogoutResponse"`
+	} `xml:"Body"`
+}
+
+// samlLogoutChain is a logout that goes through the browser to the service providers of a session which only
+// have a front-channel Single Logout Service, one after another. Each of them gets a LogoutRequest with the id
+// of the chain as RelayState and sends its LogoutResponse back to the SLO endpoint of Casdoor, which goes on
+// with the next one. The browser is sent to the final URL, or posts the final form, at the end.
+type samlLogoutChain struct {
+	Issuer       string
+	SamlSessions []*SamlSession
+	Application  string
+	RequestId    string
+	FinalUrl     string
+	FinalForm    url.Values
+	ExpireTime   time.Time
+}
+
+var samlLogoutChains sync.Map
+
+var samlLogoutClient = &http.Client{
+	Timeout: 10 * time.Second,
+	// the SOAP binding answers in the response body, a redirect isn't followed
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func getSamlNameId(application *Application, user *User) (string, string) {
+	if application.UseEmailAsSamlNameId {
+		return user.Email, samlNameIdFormatMail
+	}
+	return user.Name, samlNameIdFormatName
+}
+
+func getSamlSloLocation(application *Application, host string) string {
+	_, originBackend := getOriginFromHost(host)
+	return fmt.Sprintf("%s/api/saml/slo/%s/%s", originBackend, application.Owner, application.Name)
+}
+
+// decodeSamlMessage decodes a SAMLRequest or SAMLResponse parameter, which is DEFLATE compressed
+// when it comes over the HTTP-Redirect binding
+func decodeSamlMessage(message string) ([]byte, error) {
+	message = strings.ReplaceAll(message, " ", "+")
+	decoded, err := base64.StdEncoding.DecodeString(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(string(decoded), "xmlns:") {
+		return decoded, nil
+	}
+
+	var buffer bytes.Buffer
+	_, err = buffer.ReadFrom(flate.NewReader(bytes.NewReader(decoded)))
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func AddSamlSession(samlSession *SamlSession) (bool, error) {
+	samlSession.CreatedTime = util.GetCurrentTime()
+	affected, err := ormer.Engine.Insert(samlSession)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+func GetSamlSession(owner string, sessionIndex string) (*SamlSession, error) {
+	samlSession := SamlSession{Owner: owner, Name: sessionIndex}
+	existed, err := ormer.Engine.Get(&samlSession)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	return &samlSession, nil
+}
+
+// GetSamlSessionsByUser returns the SAML sessions of the user, only the ones of the Beego session
+// when sessionId isn't empty
+func GetSamlSessionsByUser(organization string, user string, sessionId string) ([]*SamlSession, error) {
+	samlSessions := []*SamlSession{}
+	session := ormer.Engine.Where(fmt.Sprintf("organization = ? and %s = ?", quoteColumn("user")), organization, user)
+	if sessionId != "" {
+		session = session.And("session_id = ?", sessionId)
+	}
+
+	err := session.Find(&samlSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return samlSessions, nil
+}
+
+func DeleteSamlSession(samlSession *SamlSession) (bool, error) {
+	affected, err := ormer.Engine.Delete(&SamlSession{Owner: samlSession.Owner, Name: samlSession.Name})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// CleanupSamlSessions drops the SAML sessions whose Beego session has expired or been destroyed without a logout
+func CleanupSamlSessions() error {
+	samlSessions := []*SamlSession{}
+	err := ormer.Engine.Find(&samlSessions)
+	if err != nil {
+		return err
+	}
+
+	for _, samlSession := range samlSessions {
+		existed, err := web.GlobalSessions.GetProvider().SessionExist(context.Background(), samlSession.SessionId)
+		if err != nil {
+			return err
+		}
+		if existed {
+			continue
+		}
+
+		_, err = DeleteSamlSession(samlSession)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func InitCleanupSamlSessions() {
+	util.SafeGoroutine(func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			err := CleanupSamlSessions()
+			if err != nil {
+				logs.Error("CleanupSamlSessions() error: %s", err.Error())
+			}
+		}
+	})
+}
+
+// ParseSamlLogoutRequest decodes the LogoutRequest sent by a service provider of the application,
+// it has to be signed with the SP certificate, so single logout isn't available without one
+func ParseSamlLogoutRequest(application *Application, samlRequest string, querySignature *SamlQuerySignature) (*SamlLogoutRequest, error) {
+	requestByte, err := decodeSamlMessage(samlRequest)
+	if err != nil {
+		return nil, fmt.Errorf("err: Failed to decode SAML request, %s", err.Error())
+	}
+
+	requestByte, err = validateSamlRequestSignature(application, "SAMLRequest", samlRequest, requestByte, querySignature, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var logoutRequest SamlLogoutRequest
+	err = xml.Unmarshal(requestByte, &logoutRequest)
+	if err != nil {
+		return nil, fmt.Errorf("err: Failed to unmarshal LogoutRequest, please check the SAML request, %s", err.Error())
+	}
+
+	if !application.IsRedirectUriValid(logoutRequest.Issuer) {
+		return nil, fmt.Errorf("err: Issuer URI: %s doesn't exist in the allowed Redirect URI list", logoutRequest.Issuer)
+	}
+
+	return &logoutRequest, nil
+}
+
+// ParseSamlLogoutResponse decodes the LogoutResponse a service provider sends back for a LogoutRequest of Casdoor
+func ParseSamlLogoutResponse(samlResponse string) (*SamlLogoutResponse, error) {
+	responseByte, err := decodeSamlMessage(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("err: Failed to decode SAML response, %s", err.Error())
+	}
+
+	var logoutResponse SamlLogoutResponse
+	err = xml.Unmarshal(responseByte, &logoutResponse)
+	if err != nil {
+		return nil, fmt.Errorf("err: Failed to unmarshal LogoutResponse, %s", err.Error())
+	}
+
+	return &logoutResponse, nil
+}
+
+// GetSamlSessionsByLogoutRequest returns the SAML sessions of the application named by the session indexes
+// of the LogoutRequest, or all the sessions of its NameID at the service provider when it has none
+func GetSamlSessionsByLogoutRequest(application *Application, logoutRequest *SamlLogoutRequest) ([]*SamlSession, error) {
+	samlSessions := []*SamlSession{}
+	if len(logoutRequest.SessionIndex) != 0 {
+		for _, sessionIndex := range logoutRequest.SessionIndex {
+			samlSession, err := GetSamlSession(application.Owner, sessionIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			if samlSession != nil && samlSession.Application == application.Name && samlSession.Issuer == logoutRequest.Issuer && samlSession.NameId == logoutRequest.NameID {
+				samlSessions = append(samlSessions, samlSession)
+			}
+		}
+		return samlSessions, nil
+	}
+
+	err := ormer.Engine.Where("owner = ? and application = ? and issuer = ? and name_id = ?", application.Owner, application.Name, logoutRequest.Issuer, logoutRequest.NameID).Find(&samlSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return samlSessions, nil
+}
+
+// LogoutSamlSession logs the Beego session of the SAML session out of Casdoor, then propagates the logout
+// to the OIDC applications and the other SAML service providers the session has signed in to
+func LogoutSamlSession(samlSession *SamlSession, host string) error {
+	// The SAML session is deleted first so that the service provider which asked for the logout
+	// doesn't receive a LogoutRequest of its own
+	_, err := DeleteSamlSession(samlSession)
+	if err != nil {
+		return err
+	}
+
+	DeleteBeegoSession([]string{samlSession.SessionId})
+	err = DeleteUserSessionId(samlSession.Organization, samlSession.User, samlSession.SessionId)
+	if err != nil {
+		return err
+	}
+
+	SendBackchannelLogout(samlSession.Organization, samlSession.User, samlSession.SessionId, host)
+	return nil
+}
+
+func newSamlLogoutRequest(issuer string, destination string, samlSession *SamlSession) *etree.Element {
+	logoutRequest := &etree.Element{
+		Space: "samlp",
+		Tag:   "LogoutRequest",
+	}
+	logoutRequest.CreateAttr("xmlns:samlp", "urn:oasis:names:tc:SAML:2.0:protocol")
+	logoutRequest.CreateAttr("xmlns:saml", "urn:oasis:names:tc:SAML:2.0:assertion")
+	logoutRequest.CreateAttr("ID", fmt.Sprintf("_%s", util.GenerateUUID()))
+	logoutRequest.CreateAttr("Version", "2.0")
+	logoutRequest.CreateAttr("IssueInstant", time.Now().UTC().Format(time.RFC3339))
+	logoutRequest.CreateAttr("Destination", destination)
+	logoutRequest.CreateElement("saml:Issuer").SetText(issuer)
+
+	nameId := logoutRequest.CreateElement("saml:NameID")
+	nameId.CreateAttr("Format", samlSession.NameIdFormat)
+	nameId.SetText(samlSession.NameId)
+	logoutRequest.CreateElement("samlp:SessionIndex").SetText(samlSession.Name)
+	return logoutRequest
+}
+
+func newSamlLogoutResponse(issuer string, destination string, inResponseTo string) *etree.Element {
+	logoutResponse := &etree.Element{
+		Space: "samlp",
+		Tag:   "LogoutResponse",
+	}
+	logoutResponse.CreateAttr("xmlns:samlp", "urn:oasis:names:tc:SAML:2.0:protocol")
+	logoutResponse.CreateAttr("xmlns:saml", "urn:oasis:names:tc:SAML:2.0:assertion")
+	logoutResponse.CreateAttr("ID", fmt.Sprintf("_%s", util.GenerateUUID()))
+	logoutResponse.CreateAttr("Version", "2.0")
+	logoutResponse.CreateAttr("IssueInstant", time.Now().UTC().Format(time.RFC3339))
+	logoutResponse.CreateAttr("Destination", destination)
+	logoutResponse.CreateAttr("InResponseTo", inResponseTo)
+	logoutResponse.CreateElement("saml:Issuer").SetText(issuer)
+	logoutResponse.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", samlStatusSuccess)
+	return logoutResponse
+}
+
+// signSamlMessage adds an enveloped signature to the message, after its Issuer as the schema requires
+func signSamlMessage(ctx *dsig.SigningContext, message *etree.Element) error {
+	sig, err := ctx.ConstructSignature(message, true)
+	if err != nil {
+		return err
+	}
+
+	message.InsertChildAt(1, sig)
+	return nil
+}
+
+// encodeSamlLogoutMessage signs and encodes a LogoutRequest or LogoutResponse for the SLO binding of the application.
+// For HTTP-Redirect it returns the URL to redirect to, with the DEFLATE encoded message and the query string signature.
+// For HTTP-POST it returns the SLO URL and the form to post, with the message signed by an enveloped signature.
+func encodeSamlLogoutMessage(application *Application, cert *Cert, message *etree.Element, param string, relayState string) (string, url.Values, error) {
+	ctx, err := getSamlSigningContext(application, cert)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return encodeSamlMessage(ctx, application.SamlSloBinding, application.SamlSloUrl, message, param, relayState)
+}
+
+// encodeSamlMessage signs and encodes a SAML message for the binding of the location it is sent to, see encodeSamlLogoutMessage
+func encodeSamlMessage(ctx *dsig.SigningContext, binding string, location string, message *etree.Element, param string, relayState string) (string, url.Values, error) {
+	if binding == SamlBindingPost {
+		err := signSamlMessage(ctx, message)
+		if err != nil {
+			return "", nil, err
+		}
+
+		doc := etree.NewDocument()
+		doc.SetRoot(message)
+		xmlBytes, err := doc.WriteToBytes()
+		if err != nil {
+			return "", nil, err
+		}
+
+		form := url.Values{param: {base64.StdEncoding.EncodeToString(xmlBytes)}}
+		if relayState != "" {
+			form.Set("RelayState", relayState)
+		}
+		return location, form, nil
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(message)
+	xmlBytes, err := doc.WriteToBytes()
+	if err != nil {
+		return "", nil, err
+	}
+
+	flated := bytes.NewBuffer(nil)
+	writer, err := flate.NewWriter(flated, flate.DefaultCompression)
+	if err != nil {
+		return "", nil, err
+	}
+	_, err = writer.Write(xmlBytes)
+	if err != nil {
+		return "", nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The signature covers the parameters in this exact order and encoding,
+	// see 3.4.4.1 DEFLATE Encoding of https://docs.oasis-open.org/security/saml/v2.0/saml-bindings-2.0-os.pdf
+	query := fmt.Sprintf("%s=%s", param, url.QueryEscape(base64.StdEncoding.EncodeToString(flated.Bytes())))
+	if relayState != "" {
+		query += fmt.Sprintf("&RelayState=%s", url.QueryEscape(relayState))
+	}
+	query += fmt.Sprintf("&SigAlg=%s", url.QueryEscape(ctx.GetSignatureMethodIdentifier()))
+
+	signature, err := ctx.SignString(query)
+	if err != nil {
+		return "", nil, err
+	}
+	query += fmt.Sprintf("&Signature=%s", url.QueryEscape(base64.StdEncoding.EncodeToString(signature)))
+
+	separator := "?"
+	if strings.Contains(location, "?") {
+		separator = "&"
+	}
+	return location + separator + query, nil, nil
+}
+
+// GetSamlLogoutResponse builds the LogoutResponse to the LogoutRequest of a service provider, see encodeSamlLogoutMessage
+// for the returned values
+func GetSamlLogoutResponse(application *Application, logoutRequest *SamlLogoutRequest, relayState string, host string) (string, url.Values, error) {
+	if application.SamlSloUrl == "" {
+		return "", nil, fmt.Errorf("the SAML single logout URL of the application: %s is empty", application.GetId())
+	}
+
+	cert, err := getCertByApplication(application)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, originBackend := getOriginFromHost(host)
+	logoutResponse := newSamlLogoutResponse(originBackend, application.SamlSloUrl, logoutRequest.ID)
+	return encodeSamlLogoutMessage(application, cert, logoutResponse, "SAMLResponse", relayState)
+}
+
+// GetSamlPostFormHtml renders the page which posts the form to the URL by itself, for the HTTP-POST binding
+func GetSamlPostFormHtml(postUrl string, form url.Values) (string, error) {
+	var buffer bytes.Buffer
+	err := samlPostFormTemplate.Execute(&buffer, map[string]interface{}{"Url": postUrl, "Values": form})
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// SendSamlLogout sends LogoutRequests to the service providers the Beego session has signed in to by SAML,
+// nothing is sent without a session id, see SendSamlLogoutAll for the other sessions of the user. The requests
+// go over the back channel with the SOAP binding, the same way as the OIDC Back-Channel Logout tokens. The
+// service providers without a SOAP Single Logout Service can only be reached through the browser, see
+// StartSamlLogoutChain.
+func SendSamlLogout(organization string, username string, sessionId string, host string) {
+	if sessionId == "" {
+		return
+	}
+
+	samlSessions, err := GetSamlSessionsByUser(organization, username, sessionId)
+	if err != nil {
+		logs.Error("failed to get the SAML sessions of the user: %s, error: %v", util.GetId(organization, username), err)
+		return
+	}
+
+	sendSamlLogout(samlSessions, host)
+}
+
+// SendSamlLogoutAll sends LogoutRequests to the service providers all the sessions of the user have signed in to by SAML
+func SendSamlLogoutAll(organization string, username string, host string) {
+	samlSessions, err := GetSamlSessionsByUser(organization, username, "")
+	if err != nil {
+		logs.Error("failed to get the SAML sessions of the user: %s, error: %v", util.GetId(organization, username), err)
+		return
+	}
+
+	sendSamlLogout(samlSessions, host)
+}
+
+func sendSamlLogout(samlSessions []*SamlSession, host string) {
+	_, originBackend := getOriginFromHost(host)
+	for _, samlSession := range samlSessions {
+		_, err := DeleteSamlSession(samlSession)
+		if err != nil {
+			logs.Error("failed to delete the SAML session: %s, error: %v", samlSession.Name, err)
+			continue
+		}
+
+		application, err := GetApplication(util.GetId(samlSession.Owner, samlSession.Application))
+		if err != nil || application == nil || application.SamlSloSoapUrl == "" {
+			continue
+		}
+
+		cert, err := getCertByApplication(application)
+		if err != nil {
+			logs.Error("failed to get the cert of the application: %s, error: %v", application.GetId(), err)
+			continue
+		}
+
+		logoutRequest := newSamlLogoutRequest(originBackend, application.SamlSloSoapUrl, samlSession)
+		requestId := logoutRequest.SelectAttrValue("ID", "")
+		message, err := getSamlSoapMessage(application, cert, logoutRequest)
+		if err != nil {
+			logs.Error("failed to encode the SAML LogoutRequest for the application: %s, error: %v", application.GetId(), err)
+			continue
+		}
+
+		util.SafeGoroutine(func() {
+			err := sendSamlSoapLogoutRequest(application.SamlSloSoapUrl, requestId, message)
+			if err != nil {
+				logs.Warning("failed to send the SAML LogoutRequest to the application: %s, error: %v", application.GetId(), err)
+			}
+		})
+	}
+}
+
+// getSamlSoapMessage signs the message and wraps it in a SOAP envelope, see 3.2 SAML SOAP Binding of
+// https://docs.oasis-open.org/security/saml/v2.0/saml-bindings-2.0-os.pdf
+func getSamlSoapMessage(application *Application, cert *Cert, message *etree.Element) ([]byte, error) {
+	ctx, err := getSamlSigningContext(application, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	err = signSamlMessage(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &etree.Element{
+		Space: "soap",
+		Tag:   "Envelope",
+	}
+	envelope.CreateAttr("xmlns:soap", "http://schemas.xmlsoap.org/soap/envelope/")
+	envelope.CreateElement("soap:Body").AddChild(message)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(envelope)
+	return doc.WriteToBytes()
+}
+
+// sendSamlSoapLogoutRequest posts the LogoutRequest to the SOAP Single Logout Service, which answers with
+// the LogoutResponse in the body of the response
+func sendSamlSoapLogoutRequest(soapUrl string, requestId string, message []byte) error {
+	req, err := http.NewRequest(http.MethodPost, soapUrl, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", samlSoapAction)
+
+	resp, err := samlLogoutClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the SOAP Single Logout Service responded with status: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	var envelope samlSoapLogoutResponse
+	err = xml.Unmarshal(data, &envelope)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal the SOAP LogoutResponse, %s", err.Error())
+	}
+
+	logoutResponse := envelope.Body.LogoutResponse
+	if logoutResponse == nil || logoutResponse.InResponseTo != requestId {
+		return fmt.Errorf("the SOAP response has no LogoutResponse to the LogoutRequest: %s", requestId)
+	}
+	if logoutResponse.Status.StatusCode.Value != samlStatusSuccess {
+		return fmt.Errorf("the LogoutResponse has the status: %s", logoutResponse.Status.StatusCode.Value)
+	}
+	return nil
+}
+
+// GetSamlFrontChannelSessions returns the SAML sessions of the Beego session whose service provider has no SOAP
+// Single Logout Service, so that it can only be logged out through the browser. They have to be read before the
+// Beego session is logged out, which deletes them.
+func GetSamlFrontChannelSessions(organization string, username string, sessionId string) ([]*SamlSession, error) {
+	res := []*SamlSession{}
+	if sessionId == "" {
+		return res, nil
+	}
+
+	samlSessions, err := GetSamlSessionsByUser(organization, username, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, samlSession := range samlSessions {
+		application, err := GetApplication(util.GetId(samlSession.Owner, samlSession.Application))
+		if err != nil {
+			return nil, err
+		}
+
+		if application != nil && application.SamlSloUrl != "" && application.SamlSloSoapUrl == "" {
+			res = append(res, samlSession)
+		}
+	}
+
+	return res, nil
+}
+
+// StartSamlLogoutChain sends the browser through the service providers of the SAML sessions before it goes
+// to the final URL, or posts the final form, see samlLogoutChain. It returns the first of them in the same
+// way as encodeSamlLogoutMessage, or the final URL and form when there is none.
+func StartSamlLogoutChain(samlSessions []*SamlSession, finalUrl string, finalForm url.Values, host string) (string, url.Values) {
+	samlLogoutChains.Range(func(key, value interface{}) bool {
+		if time.Now().After(value.(*samlLogoutChain).ExpireTime) {
+			samlLogoutChains.Delete(key)
+		}
+		return true
+	})
+
+	_, originBackend := getOriginFromHost(host)
+	chain := &samlLogoutChain{
+		Issuer:       originBackend,
+		SamlSessions: samlSessions,
+		FinalUrl:     finalUrl,
+		FinalForm:    finalForm,
+		ExpireTime:   time.Now().Add(samlLogoutChainTtl),
+	}
+	return nextSamlLogoutChainStep(util.GenerateId(), chain)
+}
+
+// ContinueSamlLogoutChain takes the LogoutResponse of a service provider in a logout chain, the RelayState is
+// the id of the chain. It returns the next service provider or the final URL and form, the third return value
+// tells whether the LogoutResponse is the one the chain waits for.
+func ContinueSamlLogoutChain(application *Application, logoutResponse *SamlLogoutResponse, relayState string) (string, url.Values, bool) {
+	value, ok := samlLogoutChains.Load(relayState)
+	if !ok {
+		return "", nil, false
+	}
+
+	chain := value.(*samlLogoutChain)
+	if chain.Application != application.GetId() || chain.RequestId != logoutResponse.InResponseTo || time.Now().After(chain.ExpireTime) {
+		return "", nil, false
+	}
+	if !samlLogoutChains.CompareAndDelete(relayState, chain) {
+		return "", nil, false
+	}
+
+	redirectUrl, form := nextSamlLogoutChainStep(relayState, chain)
+	return redirectUrl, form, true
+}
+
+// nextSamlLogoutChainStep returns the LogoutRequest to the next service provider of the chain, a service provider
+// that can't be sent one is skipped so that the logout goes on with the others
+func nextSamlLogoutChainStep(chainId string, chain *samlLogoutChain) (string, url.Values) {
+	for len(chain.SamlSessions) != 0 {
+		samlSession := chain.SamlSessions[0]
+		chain.SamlSessions = chain.SamlSessions[1:]
+
+		application, err := GetApplication(util.GetId(samlSession.Owner, samlSession.Application))
+		if err != nil || application == nil || application.SamlSloUrl == "" {
+			continue
+		}
+
+		cert, err := getCertByApplication(application)
+		if err != nil {
+			logs.Error("failed to get the cert of the application: %s, error: %v", application.GetId(), err)
+			continue
+		}
+
+		logoutRequest := newSamlLogoutRequest(chain.Issuer, application.SamlSloUrl, samlSession)
+		redirectUrl, form, err := encodeSamlLogoutMessage(application, cert, logoutRequest, "SAMLRequest", chainId)
+		if err != nil {
+			logs.Error("failed to encode the SAML LogoutRequest for the application: %s, error: %v", application.GetId(), err)
+			continue
+		}
+
+		chain.Application = application.GetId()
+		chain.RequestId = logoutRequest.SelectAttrValue("ID", "")
+		samlLogoutChains.Store(chainId, chain)
+		return redirectUrl, form
+	}
+
+	return chain.FinalUrl, chain.FinalForm
+}
@@ -0,0 +1,431 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+	"github.com/beego/beego/v2/server/web/session"
+	"github.com/beevik/etree"
+)
+
+func newSamlSloTestApplication(t *testing.T, binding string) (*Application, *Cert) {
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 1, "casdoor", "casdoor")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := &Application{
+		Owner:             "admin",
+		Name:              "app-saml",
+		RedirectUris:      []string{"https://sp.example.com/metadata"},
+		SamlSloUrl:        "https://sp.example.com/slo",
+		SamlSloBinding:    binding,
+		SamlHashAlgorithm: "SHA256",
+	}
+	return application, &Cert{Certificate: certificate, PrivateKey: privateKey}
+}
+
+func TestSamlLogoutRedirectBinding(t *testing.T) {
+	application, cert := newSamlSloTestApplication(t, SamlBindingRedirect)
+	samlSession := &SamlSession{Name: "_index", NameId: "alice", NameIdFormat: samlNameIdFormatName}
+
+	logoutRequest := newSamlLogoutRequest("https://casdoor.example.com", application.SamlSloUrl, samlSession)
+	redirectUrl, form, err := encodeSamlLogoutMessage(application, cert, logoutRequest, "SAMLRequest", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form != nil || !strings.HasPrefix(redirectUrl, application.SamlSloUrl+"?SAMLRequest=") {
+		t.Fatalf("unexpected HTTP-Redirect message: %s", redirectUrl)
+	}
+
+	// the signature covers the raw query string before "&Signature="
+	rawQuery := strings.SplitN(redirectUrl, "?", 2)[1]
+	signedQuery, _, _ := strings.Cut(rawQuery, "&Signature=")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(query.Get("Signature"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode([]byte(cert.Certificate))
+	x509Cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(signedQuery))
+	err = rsa.VerifyPKCS1v15(x509Cert.PublicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], signature)
+	if err != nil {
+		t.Errorf("the query string signature doesn't verify: %v", err)
+	}
+
+	requestByte, err := decodeSamlMessage(query.Get("SAMLRequest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(requestByte), "<samlp:SessionIndex>_index</samlp:SessionIndex>") {
+		t.Errorf("the session index is missing from the LogoutRequest: %s", requestByte)
+	}
+}
+
+func TestSamlLogoutPostBinding(t *testing.T) {
+	application, cert := newSamlSloTestApplication(t, SamlBindingPost)
+
+	logoutRequest := &SamlLogoutRequest{ID: "_request"}
+	logoutResponse := newSamlLogoutResponse("https://casdoor.example.com", application.SamlSloUrl, logoutRequest.ID)
+	postUrl, form, err := encodeSamlLogoutMessage(application, cert, logoutResponse, "SAMLResponse", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if postUrl != application.SamlSloUrl || form.Get("RelayState") != "state" {
+		t.Fatalf("unexpected HTTP-POST message: %s %v", postUrl, form)
+	}
+
+	parsed, err := ParseSamlLogoutResponse(form.Get("SAMLResponse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.InResponseTo != logoutRequest.ID || parsed.Status.StatusCode.Value != samlStatusSuccess {
+		t.Errorf("unexpected LogoutResponse: %+v", parsed)
+	}
+
+	html, err := GetSamlPostFormHtml(postUrl, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, `name="SAMLResponse"`) || !strings.Contains(html, `action="https://sp.example.com/slo"`) {
+		t.Errorf("unexpected HTTP-POST form: %s", html)
+	}
+}
+
+func TestParseSamlLogoutRequest(t *testing.T) {
+	application, _ := newSamlSloTestApplication(t, SamlBindingRedirect)
+	spApplication, ctx := newSamlTestSpSigningContext(t)
+	request := `<samlp:LogoutRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_1" Version="2.0">` +
+		`<saml:Issuer>%s</saml:Issuer><saml:NameID>alice</saml:NameID><samlp:SessionIndex>_index</samlp:SessionIndex></samlp:LogoutRequest>`
+
+	signRequest := func(issuer string) string {
+		doc := etree.NewDocument()
+		err := doc.ReadFromString(strings.Replace(request, "%s", issuer, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed, err := ctx.SignEnveloped(doc.Root())
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.SetRoot(signed)
+		signedBytes, err := doc.WriteToBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.StdEncoding.EncodeToString(signedBytes)
+	}
+
+	samlRequest := signRequest("https://sp.example.com/metadata")
+	if _, err := ParseSamlLogoutRequest(application, samlRequest, nil); err == nil {
+		t.Errorf("a LogoutRequest is accepted for an application without the SP certificate")
+	}
+
+	application.SamlSpCert = spApplication.SamlSpCert
+	logoutRequest, err := ParseSamlLogoutRequest(application, samlRequest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logoutRequest.NameID != "alice" || len(logoutRequest.SessionIndex) != 1 || logoutRequest.SessionIndex[0] != "_index" {
+		t.Errorf("unexpected LogoutRequest: %+v", logoutRequest)
+	}
+
+	unsignedRequest := base64.StdEncoding.EncodeToString([]byte(strings.Replace(request, "%s", "https://sp.example.com/metadata", 1)))
+	if _, err = ParseSamlLogoutRequest(application, unsignedRequest, nil); err == nil {
+		t.Errorf("an unsigned LogoutRequest is accepted")
+	}
+
+	if _, err = ParseSamlLogoutRequest(application, signRequest("https://evil.example.com"), nil); err == nil {
+		t.Errorf("a LogoutRequest of an unknown issuer is accepted")
+	}
+}
+
+func TestSendSamlLogoutWithoutSessionId(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	for _, sessionId := range []string{"session-1", "session-2"} {
+		_, err := AddSamlSession(&SamlSession{Owner: "admin", Name: "_index-" + sessionId, Application: "app-saml", Organization: "built-in", User: "alice", SessionId: sessionId})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	SendSamlLogout("built-in", "alice", "", "")
+	samlSessions, err := GetSamlSessionsByUser("built-in", "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samlSessions) != 2 {
+		t.Fatalf("a logout without session id has logged out %d SAML sessions", 2-len(samlSessions))
+	}
+
+	SendSamlLogout("built-in", "alice", "session-1", "")
+	samlSessions, err = GetSamlSessionsByUser("built-in", "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samlSessions) != 1 || samlSessions[0].SessionId != "session-2" {
+		t.Fatalf("unexpected SAML sessions after the logout of session-1: %+v", samlSessions)
+	}
+
+	SendSamlLogoutAll("built-in", "alice", "")
+	samlSessions, err = GetSamlSessionsByUser("built-in", "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samlSessions) != 0 {
+		t.Errorf("%d SAML sessions are left after the logout of all the sessions", len(samlSessions))
+	}
+}
+
+func TestCleanupSamlSessions(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	manager, err := session.NewManager("memory", &session.ManagerConfig{CookieName: "casdoor_session_id", Gclifetime: 3600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldGlobalSessions := web.GlobalSessions
+	web.GlobalSessions = manager
+	t.Cleanup(func() {
+		web.GlobalSessions = oldGlobalSessions
+	})
+
+	_, err = manager.GetProvider().SessionRead(context.Background(), "session-active")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sessionId := range []string{"session-active", "session-expired"} {
+		_, err = AddSamlSession(&SamlSession{Owner: "admin", Name: "_index-" + sessionId, Application: "app-saml", Organization: "built-in", User: "alice", SessionId: sessionId})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = CleanupSamlSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samlSessions, err := GetSamlSessionsByUser("built-in", "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samlSessions) != 1 || samlSessions[0].SessionId != "session-active" {
+		t.Errorf("unexpected SAML sessions after the cleanup: %+v", samlSessions)
+	}
+}
+
+func addSamlSloTestApplications(t *testing.T, applications ...*Application) {
+	t.Helper()
+
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 1, "casdoor", "casdoor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&Cert{Owner: "admin", Name: "cert-saml", Type: "x509", CryptoAlgorithm: "RS256", Certificate: certificate, PrivateKey: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, application := range applications {
+		application.Owner = "admin"
+		application.Organization = "built-in"
+		application.Cert = "cert-saml"
+		application.SamlHashAlgorithm = "SHA256"
+		_, err = ormer.Engine.Insert(application)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = AddSamlSession(&SamlSession{Owner: "admin", Name: "_index-" + application.Name, Application: application.Name, Organization: "built-in", User: "alice", SessionId: "session-1", NameId: "alice", NameIdFormat: samlNameIdFormatName})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSendSamlLogoutSoapBinding(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	soapRequests := make(chan string, 1)
+	soapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		doc := etree.NewDocument()
+		if r.Header.Get("SOAPAction") != samlSoapAction || doc.ReadFromBytes(body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		logoutRequest := doc.FindElement("/Envelope/Body/LogoutRequest")
+		if logoutRequest == nil || logoutRequest.FindElement("Signature") == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		soapRequests <- logoutRequest.FindElement("SessionIndex").Text()
+		_, _ = w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+			`<samlp:LogoutResponse xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_response" InResponseTo="` + logoutRequest.SelectAttrValue("ID", "") + `">` +
+			`<samlp:Status><samlp:StatusCode Value="` + samlStatusSuccess + `"/></samlp:Status></samlp:LogoutResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer soapServer.Close()
+
+	var frontChannelRequests int32
+	frontChannelServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&frontChannelRequests, 1)
+	}))
+	defer frontChannelServer.Close()
+
+	addSamlSloTestApplications(t,
+		&Application{Name: "app-soap", SamlSloUrl: "https://sp-soap.example.com/slo", SamlSloSoapUrl: soapServer.URL},
+		&Application{Name: "app-front", SamlSloUrl: frontChannelServer.URL, SamlSloBinding: SamlBindingPost},
+	)
+
+	SendSamlLogout("built-in", "alice", "session-1", "")
+
+	select {
+	case sessionIndex := <-soapRequests:
+		if sessionIndex != "_index-app-soap" {
+			t.Errorf("unexpected session index of the SOAP LogoutRequest: %s", sessionIndex)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no LogoutRequest is sent over the SOAP binding")
+	}
+
+	// the front-channel bindings need the browser, they aren't sent over the back channel
+	if count := atomic.LoadInt32(&frontChannelRequests); count != 0 {
+		t.Errorf("%d LogoutRequests are sent to the front-channel Single Logout Service", count)
+	}
+
+	samlSessions, err := GetSamlSessionsByUser("built-in", "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samlSessions) != 0 {
+		t.Errorf("%d SAML sessions are left after the logout", len(samlSessions))
+	}
+
+	application, cert := newSamlSloTestApplication(t, SamlBindingRedirect)
+	logoutRequest := newSamlLogoutRequest("https://casdoor.example.com", soapServer.URL, &SamlSession{Name: "_index"})
+	message, err := getSamlSoapMessage(application, cert, logoutRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = sendSamlSoapLogoutRequest(soapServer.URL, "_another-request", message); err == nil {
+		t.Errorf("a LogoutResponse to another LogoutRequest is accepted")
+	}
+}
+
+func TestSamlLogoutChain(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	redirectApplication := &Application{Name: "app-redirect", SamlSloUrl: "https://sp-redirect.example.com/slo", SamlSloBinding: SamlBindingRedirect}
+	postApplication := &Application{Name: "app-post", SamlSloUrl: "https://sp-post.example.com/slo", SamlSloBinding: SamlBindingPost}
+	soapApplication := &Application{Name: "app-soap", SamlSloUrl: "https://sp-soap.example.com/slo", SamlSloSoapUrl: "https://sp-soap.example.com/soap"}
+	addSamlSloTestApplications(t, redirectApplication, postApplication, soapApplication)
+
+	samlSessions, err := GetSamlFrontChannelSessions("built-in", "alice", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samlSessions) != 2 {
+		t.Fatalf("unexpected front-channel SAML sessions: %+v", samlSessions)
+	}
+	if samlSessions[0].Application != redirectApplication.Name {
+		samlSessions[0], samlSessions[1] = samlSessions[1], samlSessions[0]
+	}
+
+	getRequestId := func(samlRequest string) string {
+		t.Helper()
+
+		requestByte, err := decodeSamlMessage(samlRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var logoutRequest SamlLogoutRequest
+		err = xml.Unmarshal(requestByte, &logoutRequest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return logoutRequest.ID
+	}
+
+	// the browser is sent to the first service provider, with the chain as RelayState
+	redirectUrl, form := StartSamlLogoutChain(samlSessions, "https://rp.example.com/logged-out", nil, "")
+	if form != nil || !strings.HasPrefix(redirectUrl, redirectApplication.SamlSloUrl+"?") {
+		t.Fatalf("unexpected first step of the chain: %s %v", redirectUrl, form)
+	}
+	parsedUrl, err := url.Parse(redirectUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayState := parsedUrl.Query().Get("RelayState")
+	requestId := getRequestId(parsedUrl.Query().Get("SAMLRequest"))
+
+	// only the LogoutResponse of the service provider the chain waits for goes on with it
+	if _, _, ok := ContinueSamlLogoutChain(postApplication, &SamlLogoutResponse{InResponseTo: requestId}, relayState); ok {
+		t.Errorf("the LogoutResponse of another application continues the chain")
+	}
+	if _, _, ok := ContinueSamlLogoutChain(redirectApplication, &SamlLogoutResponse{InResponseTo: "_another-request"}, relayState); ok {
+		t.Errorf("the LogoutResponse to another LogoutRequest continues the chain")
+	}
+
+	postUrl, form, ok := ContinueSamlLogoutChain(redirectApplication, &SamlLogoutResponse{InResponseTo: requestId}, relayState)
+	if !ok || postUrl != postApplication.SamlSloUrl || form.Get("RelayState") != relayState {
+		t.Fatalf("unexpected second step of the chain: %s %v", postUrl, form)
+	}
+	requestId = getRequestId(form.Get("SAMLRequest"))
+
+	finalUrl, form, ok := ContinueSamlLogoutChain(postApplication, &SamlLogoutResponse{InResponseTo: requestId}, relayState)
+	if !ok || finalUrl != "https://rp.example.com/logged-out" || form != nil {
+		t.Errorf("the chain doesn't end at the final URL: %s %v", finalUrl, form)
+	}
+
+	if _, _, ok = ContinueSamlLogoutChain(postApplication, &SamlLogoutResponse{InResponseTo: requestId}, relayState); ok {
+		t.Errorf("a finished chain is continued again")
+	}
+
+	// without any service provider the browser goes to the final URL at once
+	redirectUrl, form = StartSamlLogoutChain(nil, "https://rp.example.com/logged-out", nil, "")
+	if redirectUrl != "https://rp.example.com/logged-out" || form != nil {
+		t.Errorf("unexpected step of an empty chain: %s %v", redirectUrl, form)
+	}
+}
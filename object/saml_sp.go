@@ -80,6 +80,7 @@ func ParseSamlResponse(samlResponse string, provider *Provider, host string) (*i
 		DisplayName: customUserInfo.DisplayName,
 		Email:       customUserInfo.Email,
 		AvatarUrl:   customUserInfo.AvatarUrl,
+		Sid:         assertionInfo.SessionIndex,
 	}
 
 	// Fallback: if Username is empty, use Email or NameID
@@ -217,6 +217,16 @@ func DeleteAllUserSessions(owner string, name string) (bool, error) {
 		return false, err
 	}
 
+	err = DeleteIdpSessionsByUser(owner, name, "")
+	if err != nil {
+		return false, err
+	}
+
+	err = deleteSessionDevices(owner, name, "")
+	if err != nil {
+		return false, err
+	}
+
 	return affected != 0, nil
 }
 
@@ -260,7 +270,13 @@ func DeleteUserSessionId(owner string, name string, beegoSessionId string) error
 		}
 	}
 
-	return nil
+	err = deleteSessionDevices(owner, name, beegoSessionId)
+	if err != nil {
+		return err
+	}
+
+	// the session is gone, so is the record of the provider it was signed in through
+	return DeleteIdpSessionsByUser(owner, name, beegoSessionId)
 }
 
 func DeleteBeegoSession(sessionIds []string) {
@@ -0,0 +1,228 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/beego/beego/v2/server/web"
+	"github.com/casdoor/casdoor/util"
+)
+
+// the last activity of a session is written at most once per interval, so that a signed-in
+// browser doesn't cost a database write on every request
+const sessionDeviceActiveInterval = time.Minute
+
+// SessionDeviceSessionKey is the key of the name of the session device in the Beego session, only
+// the sessions with a device record carry it
+const SessionDeviceSessionKey = "sessionDevice"
+
+var sessionDeviceActiveTimes sync.Map
+
+// SessionDevice is the record of one Beego session, i.e. one browser or device the user has
+// signed in on. The Session rows index the same Beego session ids by application. The name is
+// a random id the device is listed and revoked by, the Beego session id is a credential and never
+// leaves the server.
+type SessionDevice struct {
+	Owner          string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name           string `xorm:"varchar(100) notnull pk" json:"name"`
+	SessionId      string `xorm:"varchar(100) index" json:"-"`
+	CreatedTime    string `xorm:"varchar(100)" json:"createdTime"`
+	User           string `xorm:"varchar(100) index" json:"user"`
+	Application    string `xorm:"varchar(100)" json:"application"`
+	ClientIp       string `xorm:"varchar(100)" json:"clientIp"`
+	UserAgent      string `xorm:"varchar(500)" json:"userAgent"`
+	SigninMethod   string `xorm:"varchar(100)" json:"signinMethod"`
+	Provider       string `xorm:"varchar(100)" json:"provider"`
+	LastActiveTime string `xorm:"varchar(100)" json:"lastActiveTime"`
+
+	IsCurrent bool `xorm:"-" json:"isCurrent"`
+}
+
+// AddSessionDevice records the sign-in of the Beego session, a quick sign-in to another application
+// reuses the Beego session, so the existing record is refreshed instead
+func AddSessionDevice(sessionDevice *SessionDevice) (bool, error) {
+	sessionDevice.LastActiveTime = util.GetCurrentTime()
+	if len(sessionDevice.UserAgent) > 500 {
+		sessionDevice.UserAgent = sessionDevice.UserAgent[:500]
+	}
+
+	existingSessionDevice, err := getSessionDeviceBySessionId(sessionDevice.Owner, sessionDevice.SessionId)
+	if err != nil {
+		return false, err
+	}
+
+	var affected int64
+	if existingSessionDevice != nil {
+		sessionDevice.Name = existingSessionDevice.Name
+		affected, err = ormer.Engine.Where("owner = ? and name = ?", sessionDevice.Owner, sessionDevice.Name).
+			Cols("user", "application", "client_ip", "user_agent", "signin_method", "provider", "last_active_time").Update(sessionDevice)
+	} else {
+		sessionDevice.Name = util.GenerateId()
+		sessionDevice.CreatedTime = sessionDevice.LastActiveTime
+		affected, err = ormer.Engine.Insert(sessionDevice)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// GetSessionDevices returns the signed-in devices of the user, the records of the Beego sessions
+// that have expired or been destroyed meanwhile are dropped
+func GetSessionDevices(owner string, user string) ([]*SessionDevice, error) {
+	sessionDevices := []*SessionDevice{}
+	err := ormer.Engine.Desc("last_active_time").Where(fmt.Sprintf("owner = ? and %s = ?", quoteColumn("user")), owner, user).Find(&sessionDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*SessionDevice{}
+	for _, sessionDevice := range sessionDevices {
+		existed, err := web.GlobalSessions.GetProvider().SessionExist(context.Background(), sessionDevice.SessionId)
+		if err != nil {
+			return nil, err
+		}
+
+		if existed {
+			res = append(res, sessionDevice)
+			continue
+		}
+
+		_, err = ormer.Engine.Delete(&SessionDevice{Owner: sessionDevice.Owner, Name: sessionDevice.Name})
+		if err != nil {
+			return nil, err
+		}
+		sessionDeviceActiveTimes.Delete(sessionDevice.Name)
+	}
+
+	return res, nil
+}
+
+func GetSessionDevice(owner string, name string) (*SessionDevice, error) {
+	sessionDevice := SessionDevice{Owner: owner, Name: name}
+	existed, err := ormer.Engine.Get(&sessionDevice)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	return &sessionDevice, nil
+}
+
+func getSessionDeviceBySessionId(owner string, sessionId string) (*SessionDevice, error) {
+	sessionDevice := SessionDevice{Owner: owner, SessionId: sessionId}
+	existed, err := ormer.Engine.Get(&sessionDevice)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	return &sessionDevice, nil
+}
+
+// UpdateSessionDeviceActiveTime records the activity of the session device with the name
+func UpdateSessionDeviceActiveTime(name string) {
+	if name == "" {
+		return
+	}
+
+	now := time.Now()
+	if lastTime, ok := sessionDeviceActiveTimes.Load(name); ok && now.Sub(lastTime.(time.Time)) < sessionDeviceActiveInterval {
+		return
+	}
+	sessionDeviceActiveTimes.Store(name, now)
+
+	_, err := ormer.Engine.Where("name = ?", name).Cols("last_active_time").Update(&SessionDevice{LastActiveTime: util.GetCurrentTime()})
+	if err != nil {
+		logs.Error("UpdateSessionDeviceActiveTime() error: %s", err.Error())
+	}
+}
+
+// cleanupSessionDeviceActiveTimes forgets the sessions which haven't been active for an interval, the next
+// request of such a session writes its activity anyway, so only the sessions active right now are kept
+func cleanupSessionDeviceActiveTimes(now time.Time) {
+	sessionDeviceActiveTimes.Range(func(key, value any) bool {
+		if now.Sub(value.(time.Time)) >= sessionDeviceActiveInterval {
+			sessionDeviceActiveTimes.Delete(key)
+		}
+		return true
+	})
+}
+
+func InitCleanupSessionDeviceActiveTimes() {
+	util.SafeGoroutine(func() {
+		ticker := time.NewTicker(sessionDeviceActiveInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			cleanupSessionDeviceActiveTimes(time.Now())
+		}
+	})
+}
+
+// deleteSessionDevices drops the records of the user's Beego sessions, all of them when sessionId is empty
+func deleteSessionDevices(owner string, user string, sessionId string) error {
+	sessionDevices := []*SessionDevice{}
+	session := ormer.Engine.Where(fmt.Sprintf("owner = ? and %s = ?", quoteColumn("user")), owner, user)
+	if sessionId != "" {
+		session = session.And("session_id = ?", sessionId)
+	}
+	err := session.Cols("name").Find(&sessionDevices)
+	if err != nil {
+		return err
+	}
+
+	for _, sessionDevice := range sessionDevices {
+		_, err = ormer.Engine.Delete(&SessionDevice{Owner: owner, Name: sessionDevice.Name})
+		if err != nil {
+			return err
+		}
+		sessionDeviceActiveTimes.Delete(sessionDevice.Name)
+	}
+	return nil
+}
+
+// RevokeSessionDevice signs the user out of one device: the applications are notified, the tokens
+// issued in the session are expired and then the Beego session is destroyed
+func RevokeSessionDevice(sessionDevice *SessionDevice, host string) (bool, error) {
+	owner, user, sessionId := sessionDevice.Owner, sessionDevice.User, sessionDevice.SessionId
+
+	// the notification has to be sent before the tokens are expired, because it is
+	// sent to the applications the user holds active tokens for
+	SendBackchannelLogout(owner, user, sessionId, host)
+
+	_, err := ExpireTokenBySessionId(owner, user, sessionId)
+	if err != nil {
+		return false, err
+	}
+
+	DeleteBeegoSession([]string{sessionId})
+	err = DeleteUserSessionId(owner, user, sessionId)
+	if err != nil {
+		return false, err
+	}
+
+	PublishDomainEvent(owner, DomainEventSessionRevoked, util.GetId(owner, user), sessionDevice, nil)
+	return true, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/casdoor/casdoor/util"
+)
+
+func getSessionDeviceActiveTimesCount() int {
+	count := 0
+	sessionDeviceActiveTimes.Range(func(key, value any) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func TestSessionDeviceHidesSessionId(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	sessionDevice := &SessionDevice{Owner: "built-in", SessionId: "session-secret", User: "alice", Application: "app-1"}
+	_, err := AddSessionDevice(sessionDevice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sessionDevice.Name == "" || sessionDevice.Name == sessionDevice.SessionId {
+		t.Fatalf("the device is named by its session id: %s", sessionDevice.Name)
+	}
+	if json := util.StructToJson(sessionDevice); strings.Contains(json, "session-secret") {
+		t.Errorf("the session id is serialized: %s", json)
+	}
+
+	// a sign-in to another application in the same session refreshes the device under its name
+	_, err = AddSessionDevice(&SessionDevice{Owner: "built-in", SessionId: "session-secret", User: "alice", Application: "app-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionDevices := []*SessionDevice{}
+	err = ormer.Engine.Find(&sessionDevices, &SessionDevice{Owner: "built-in"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessionDevices) != 1 || sessionDevices[0].Name != sessionDevice.Name || sessionDevices[0].Application != "app-2" {
+		t.Fatalf("unexpected devices: %+v", sessionDevices)
+	}
+
+	// the device is found by its name, with the session id kept on the server
+	found, err := GetSessionDevice("built-in", sessionDevice.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.SessionId != "session-secret" {
+		t.Errorf("unexpected device: %+v", found)
+	}
+}
+
+func TestDeleteSessionDevicesForgetsActiveTimes(t *testing.T) {
+	initSqliteTestOrmer(t)
+	sessionDeviceActiveTimes.Clear()
+	t.Cleanup(sessionDeviceActiveTimes.Clear)
+
+	names := map[string]string{}
+	for _, sessionId := range []string{"session-1", "session-2", "session-3"} {
+		sessionDevice := &SessionDevice{Owner: "built-in", SessionId: sessionId, User: "alice"}
+		_, err := AddSessionDevice(sessionDevice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names[sessionId] = sessionDevice.Name
+		UpdateSessionDeviceActiveTime(sessionDevice.Name)
+	}
+	if count := getSessionDeviceActiveTimesCount(); count != 3 {
+		t.Fatalf("%d sessions are active, want 3", count)
+	}
+
+	err := deleteSessionDevices("built-in", "alice", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sessionDeviceActiveTimes.Load(names["session-1"]); ok {
+		t.Errorf("the activity of a deleted session is kept")
+	}
+
+	err = deleteSessionDevices("built-in", "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := getSessionDeviceActiveTimesCount(); count != 0 {
+		t.Errorf("the activity of %d deleted sessions is kept", count)
+	}
+}
+
+func TestCleanupSessionDeviceActiveTimes(t *testing.T) {
+	sessionDeviceActiveTimes.Clear()
+	t.Cleanup(sessionDeviceActiveTimes.Clear)
+
+	now := time.Now()
+	sessionDeviceActiveTimes.Store("session-active", now)
+	sessionDeviceActiveTimes.Store("session-idle", now.Add(-sessionDeviceActiveInterval))
+
+	cleanupSessionDeviceActiveTimes(now)
+	if _, ok := sessionDeviceActiveTimes.Load("session-active"); !ok {
+		t.Errorf("the activity of an active session is dropped")
+	}
+	if _, ok := sessionDeviceActiveTimes.Load("session-idle"); ok {
+		t.Errorf("the activity of an idle session is kept")
+	}
+}
+
+func TestUpdateSessionDeviceActiveTimeIsThrottled(t *testing.T) {
+	initSqliteTestOrmer(t)
+	sessionDeviceActiveTimes.Clear()
+	t.Cleanup(sessionDeviceActiveTimes.Clear)
+
+	sessionDevice := &SessionDevice{Owner: "built-in", SessionId: "session-1", User: "alice"}
+	_, err := AddSessionDevice(sessionDevice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getLastActiveTime := func() string {
+		t.Helper()
+
+		sessionDevice, err := GetSessionDevice("built-in", sessionDevice.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sessionDevice.LastActiveTime
+	}
+	setLastActiveTime := func(lastActiveTime string) {
+		t.Helper()
+
+		_, err := ormer.Engine.Where("name = ?", sessionDevice.Name).Cols("last_active_time").Update(&SessionDevice{LastActiveTime: lastActiveTime})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	setLastActiveTime("2020-01-01T00:00:00Z")
+	UpdateSessionDeviceActiveTime(sessionDevice.Name)
+	if lastActiveTime := getLastActiveTime(); lastActiveTime == "2020-01-01T00:00:00Z" {
+		t.Fatalf("the activity of the session isn't written")
+	}
+
+	// the next requests within the interval don't write to the database
+	setLastActiveTime("2020-01-01T00:00:00Z")
+	UpdateSessionDeviceActiveTime(sessionDevice.Name)
+	if lastActiveTime := getLastActiveTime(); lastActiveTime != "2020-01-01T00:00:00Z" {
+		t.Errorf("the activity of the session is written again within the interval: %s", lastActiveTime)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/casdoor/casdoor/util"
+	"github.com/xorm-io/core"
+)
+
+// Refs: https://openid.net/specs/openid-sharedsignals-framework-1_0.html
+const (
+	SsfDeliveryMethodPush = "urn:ietf:rfc:8935"
+
+	SsfStreamStatusEnabled  = "enabled"
+	SsfStreamStatusPaused   = "paused"
+	SsfStreamStatusDisabled = "disabled"
+
+	SsfEventVerification         = "https://schemas.openid.net/secevent/ssf/event-type/verification"
+	SsfEventSessionRevoked       = "https://schemas.openid.net/secevent/caep/event-type/session-revoked"
+	SsfEventCredentialChange     = "https://schemas.openid.net/secevent/caep/event-type/credential-change"
+	SsfEventAccountDisabled      = "https://schemas.openid.net/secevent/risc/event-type/account-disabled"
+	SsfEventAccountEnabled       = "https://schemas.openid.net/secevent/risc/event-type/account-enabled"
+	SsfEventAccountPurged        = "https://schemas.openid.net/secevent/risc/event-type/account-purged"
+	SsfEventSessionsRevoked      = "https://schemas.openid.net/secevent/risc/event-type/sessions-revoked"
+	SsfEventCredentialCompromise = "https://schemas.openid.net/secevent/risc/event-type/credential-compromise"
+)
+
+// SsfEventsSupported are the events Casdoor transmits, the verification event is always delivered
+var SsfEventsSupported = []string{
+	SsfEventSessionRevoked,
+	SsfEventCredentialChange,
+	SsfEventAccountDisabled,
+	SsfEventAccountEnabled,
+	SsfEventAccountPurged,
+}
+
+// SsfStream is an event stream of the Shared Signals transmitter, it is created by a receiver through
+// the stream configuration endpoint and pushes the security events of the users who have signed in to
+// the receiver application. The events go through the webhook event outbox.
+type SsfStream struct {
+	Owner       string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name        string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime string `xorm:"varchar(100)" json:"createdTime"`
+
+	Organization string `xorm:"varchar(100) index" json:"organization"`
+	Application  string `xorm:"varchar(100) index" json:"application"`
+	Issuer       string `xorm:"varchar(500)" json:"issuer"`
+	Audience     string `xorm:"varchar(500)" json:"audience"`
+
+	DeliveryMethod      string   `xorm:"varchar(100)" json:"deliveryMethod"`
+	EndpointUrl         string   `xorm:"varchar(500)" json:"endpointUrl"`
+	AuthorizationHeader string   `xorm:"varchar(1000)" json:"authorizationHeader"`
+	EventsRequested     []string `xorm:"varchar(1000)" json:"eventsRequested"`
+	EventsDelivered     []string `xorm:"varchar(1000)" json:"eventsDelivered"`
+	Description         string   `xorm:"varchar(500)" json:"description"`
+
+	Status string `xorm:"varchar(100)" json:"status"`
+	Reason string `xorm:"varchar(500)" json:"reason"`
+}
+
+// SsfStreamConfiguration is the stream configuration exchanged with the receivers
+type SsfStreamConfiguration struct {
+	StreamId        string       `json:"stream_id,omitempty"`
+	Iss             string       `json:"iss,omitempty"`
+	Aud             string       `json:"aud,omitempty"`
+	EventsSupported []string     `json:"events_supported,omitempty"`
+	EventsRequested []string     `json:"events_requested,omitempty"`
+	EventsDelivered []string     `json:"events_delivered,omitempty"`
+	Delivery        *SsfDelivery `json:"delivery,omitempty"`
+	Description     string       `json:"description,omitempty"`
+}
+
+type SsfDelivery struct {
+	Method              string `json:"method"`
+	EndpointUrl         string `json:"endpoint_url,omitempty"`
+	AuthorizationHeader string `json:"authorization_header,omitempty"`
+}
+
+type SsfStreamStatus struct {
+	StreamId string `json:"stream_id"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type SsfVerificationRequest struct {
+	StreamId string `json:"stream_id"`
+	State    string `json:"state,omitempty"`
+}
+
+// SsfError is the error response of the Shared Signals endpoints, it has the shape of the push
+// delivery errors. Refs: https://datatracker.ietf.org/doc/html/rfc8935#section-2.3
+type SsfError struct {
+	Err         string `json:"err"`
+	Description string `json:"description,omitempty"`
+}
+
+func newSsfError(err string, format string, a ...interface{}) *SsfError {
+	return &SsfError{Err: err, Description: fmt.Sprintf(format, a...)}
+}
+
+func (s *SsfStream) GetId() string {
+	return fmt.Sprintf("%s/%s", s.Owner, s.Name)
+}
+
+// getConfiguration returns the configuration of the stream, the authorization header is never
+// sent back to the receiver
+func (s *SsfStream) getConfiguration() *SsfStreamConfiguration {
+	return &SsfStreamConfiguration{
+		StreamId:        s.Name,
+		Iss:             s.Issuer,
+		Aud:             s.Audience,
+		EventsSupported: SsfEventsSupported,
+		EventsRequested: s.EventsRequested,
+		EventsDelivered: s.EventsDelivered,
+		Delivery: &SsfDelivery{
+			Method:      s.DeliveryMethod,
+			EndpointUrl: s.EndpointUrl,
+		},
+		Description: s.Description,
+	}
+}
+
+// isSsfEndpointUrlAllowed reports whether the endpoint is on a host of the redirect URIs of the receiver
+// application, so that a receiver can't make Casdoor push to an arbitrary, possibly internal, address
+func isSsfEndpointUrlAllowed(application *Application, endpointUrl *url.URL) bool {
+	for _, redirectUri := range application.RedirectUris {
+		redirectUriObj, err := url.Parse(redirectUri)
+		if err != nil || redirectUriObj.Host == "" {
+			redirectUriObj, err = url.Parse("https://" + redirectUri)
+			if err != nil {
+				continue
+			}
+		}
+
+		if redirectUriObj.Hostname() != "" && strings.EqualFold(redirectUriObj.Hostname(), endpointUrl.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSsfStreamSubject reports whether the user is a subject of the stream: a token has been issued to the
+// user for the receiver application, a receiver doesn't learn about the other users of the organization
+func isSsfStreamSubject(stream *SsfStream, user *User) (bool, error) {
+	return ormer.Engine.Exist(&Token{Owner: stream.Owner, Application: stream.Application, Organization: user.Owner, User: user.Name})
+}
+
+// applyConfiguration validates the configuration sent by the receiver and applies it to the stream,
+// a field missing from the configuration is kept unless the stream is replaced
+func (s *SsfStream) applyConfiguration(application *Application, config *SsfStreamConfiguration, isReplace bool) *SsfError {
+	if config.Delivery != nil || isReplace {
+		if config.Delivery == nil {
+			return newSsfError("invalid_request", "the delivery is missing")
+		}
+
+		method := config.Delivery.Method
+		if method == "" {
+			method = SsfDeliveryMethodPush
+		}
+		if method != SsfDeliveryMethodPush {
+			return newSsfError("invalid_request", "the delivery method: %s is not supported, only %s is", method, SsfDeliveryMethodPush)
+		}
+
+		endpointUrl, err := url.Parse(config.Delivery.EndpointUrl)
+		if err != nil || (endpointUrl.Scheme != "https" && endpointUrl.Scheme != "http") || endpointUrl.Host == "" {
+			return newSsfError("invalid_request", "the endpoint_url: %s is not a valid URL", config.Delivery.EndpointUrl)
+		}
+		if !isSsfEndpointUrlAllowed(application, endpointUrl) {
+			return newSsfError("invalid_request", "the host of the endpoint_url: %s is not in the redirect URIs of the application: %s", config.Delivery.EndpointUrl, application.Name)
+		}
+
+		s.DeliveryMethod = method
+		s.EndpointUrl = config.Delivery.EndpointUrl
+		s.AuthorizationHeader = config.Delivery.AuthorizationHeader
+	}
+
+	if config.EventsRequested != nil || isReplace {
+		s.EventsRequested = config.EventsRequested
+		if s.EventsRequested == nil {
+			s.EventsRequested = []string{}
+		}
+
+		s.EventsDelivered = []string{}
+		for _, event := range s.EventsRequested {
+			if util.InSlice(SsfEventsSupported, event) && !util.InSlice(s.EventsDelivered, event) {
+				s.EventsDelivered = append(s.EventsDelivered, event)
+			}
+		}
+	}
+
+	if config.Description != "" || isReplace {
+		s.Description = config.Description
+	}
+
+	return nil
+}
+
+// HasAnySsfStreams reports whether the database has at least one Shared Signals stream.
+func HasAnySsfStreams() (bool, error) {
+	count, err := ormer.Engine.Count(&SsfStream{})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func getSsfStreamsByOrganization(organization string) ([]*SsfStream, error) {
+	streams := []*SsfStream{}
+	err := ormer.Engine.Find(&streams, &SsfStream{Organization: organization})
+	if err != nil {
+		return nil, err
+	}
+
+	return streams, nil
+}
+
+func getSsfStream(owner string, name string) (*SsfStream, error) {
+	if owner == "" || name == "" {
+		return nil, nil
+	}
+
+	stream := SsfStream{Owner: owner, Name: name}
+	existed, err := ormer.Engine.Get(&stream)
+	if err != nil {
+		return nil, err
+	}
+	if !existed {
+		return nil, nil
+	}
+
+	return &stream, nil
+}
+
+func GetSsfStream(id string) (*SsfStream, error) {
+	owner, name, err := util.GetOwnerAndNameFromIdWithError(id)
+	if err != nil {
+		return nil, err
+	}
+	return getSsfStream(owner, name)
+}
+
+// getApplicationSsfStream returns the stream of the receiver application, the streams of the other
+// receivers are not found
+func getApplicationSsfStream(application *Application, streamId string) (*SsfStream, *SsfError, error) {
+	if streamId == "" {
+		return nil, newSsfError("invalid_request", "the stream_id is missing"), nil
+	}
+
+	stream, err := getSsfStream(application.Owner, streamId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stream == nil || stream.Application != application.Name {
+		return nil, newSsfError("not_found", "the stream: %s is not found", streamId), nil
+	}
+
+	return stream, nil, nil
+}
+
+// GetSsfStreamConfigurations returns the configurations of the streams of the receiver application
+func GetSsfStreamConfigurations(application *Application) ([]*SsfStreamConfiguration, error) {
+	streams := []*SsfStream{}
+	err := ormer.Engine.Asc("created_time").Find(&streams, &SsfStream{Owner: application.Owner, Application: application.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*SsfStreamConfiguration{}
+	for _, stream := range streams {
+		res = append(res, stream.getConfiguration())
+	}
+	return res, nil
+}
+
+func GetSsfStreamConfiguration(application *Application, streamId string) (*SsfStreamConfiguration, *SsfError, error) {
+	stream, ssfErr, err := getApplicationSsfStream(application, streamId)
+	if stream == nil {
+		return nil, ssfErr, err
+	}
+
+	return stream.getConfiguration(), nil, nil
+}
+
+// AddSsfStream creates a stream for the receiver application, the events of the users who have signed
+// in to the application are pushed to it, signed by the cert of the application
+func AddSsfStream(application *Application, config *SsfStreamConfiguration, host string) (*SsfStreamConfiguration, *SsfError, error) {
+	_, originBackend := getOriginFromHost(host)
+	stream := &SsfStream{
+		Owner:        application.Owner,
+		Name:         util.GenerateId(),
+		CreatedTime:  util.GetCurrentTime(),
+		Organization: application.Organization,
+		Application:  application.Name,
+		Issuer:       originBackend,
+		Audience:     application.ClientId,
+		Status:       SsfStreamStatusEnabled,
+	}
+
+	ssfErr := stream.applyConfiguration(application, config, true)
+	if ssfErr != nil {
+		return nil, ssfErr, nil
+	}
+
+	_, err := ormer.Engine.Insert(stream)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	StartWebhookDeliveryWorker()
+	return stream.getConfiguration(), nil, nil
+}
+
+// UpdateSsfStream updates the stream of the receiver application, the fields missing from the
+// configuration are kept unless the stream is replaced
+func UpdateSsfStream(application *Application, config *SsfStreamConfiguration, isReplace bool) (*SsfStreamConfiguration, *SsfError, error) {
+	stream, ssfErr, err := getApplicationSsfStream(application, config.StreamId)
+	if stream == nil {
+		return nil, ssfErr, err
+	}
+
+	ssfErr = stream.applyConfiguration(application, config, isReplace)
+	if ssfErr != nil {
+		return nil, ssfErr, nil
+	}
+
+	_, err = ormer.Engine.ID(core.PK{stream.Owner, stream.Name}).AllCols().Update(stream)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stream.getConfiguration(), nil, nil
+}
+
+func DeleteSsfStream(application *Application, streamId string) (*SsfError, error) {
+	stream, ssfErr, err := getApplicationSsfStream(application, streamId)
+	if stream == nil {
+		return ssfErr, err
+	}
+
+	_, err = ormer.Engine.ID(core.PK{stream.Owner, stream.Name}).Delete(&SsfStream{})
+	return nil, err
+}
+
+func GetSsfStreamStatus(application *Application, streamId string) (*SsfStreamStatus, *SsfError, error) {
+	stream, ssfErr, err := getApplicationSsfStream(application, streamId)
+	if stream == nil {
+		return nil, ssfErr, err
+	}
+
+	return &SsfStreamStatus{StreamId: stream.Name, Status: stream.Status, Reason: stream.Reason}, nil, nil
+}
+
+// UpdateSsfStreamStatus pauses, resumes or disables the stream, the events of a paused stream are
+// held until it is enabled again, a disabled stream drops them
+func UpdateSsfStreamStatus(application *Application, status *SsfStreamStatus) (*SsfStreamStatus, *SsfError, error) {
+	stream, ssfErr, err := getApplicationSsfStream(application, status.StreamId)
+	if stream == nil {
+		return nil, ssfErr, err
+	}
+
+	if !util.InSlice([]string{SsfStreamStatusEnabled, SsfStreamStatusPaused, SsfStreamStatusDisabled}, status.Status) {
+		return nil, newSsfError("invalid_request", "the status: %s is invalid", status.Status), nil
+	}
+
+	stream.Status = status.Status
+	stream.Reason = status.Reason
+	_, err = ormer.Engine.ID(core.PK{stream.Owner, stream.Name}).Cols("status", "reason").Update(stream)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &SsfStreamStatus{StreamId: stream.Name, Status: stream.Status, Reason: stream.Reason}, nil, nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestGetSsfEventsFromDomainEvent(t *testing.T) {
+	enabledUser := &User{Owner: "built-in", Name: "alice", Id: "id-1"}
+	forbiddenUser := &User{Owner: "built-in", Name: "alice", Id: "id-1", IsForbidden: true}
+
+	tests := []struct {
+		name      string
+		eventType string
+		before    *User
+		after     *User
+		want      string
+	}{
+		{name: "password changed", eventType: DomainEventUserPasswordChanged, before: enabledUser, after: enabledUser, want: SsfEventCredentialChange},
+		{name: "account disabled", eventType: DomainEventUserUpdated, before: enabledUser, after: forbiddenUser, want: SsfEventAccountDisabled},
+		{name: "account enabled", eventType: DomainEventUserUpdated, before: forbiddenUser, after: enabledUser, want: SsfEventAccountEnabled},
+		{name: "account deleted", eventType: DomainEventUserDeleted, before: enabledUser, want: SsfEventAccountPurged},
+		{name: "other update", eventType: DomainEventUserUpdated, before: enabledUser, after: enabledUser},
+		{name: "user created", eventType: DomainEventUserCreated, after: enabledUser},
+	}
+	for _, test := range tests {
+		user, events, err := getSsfEventsFromDomainEvent(test.eventType, enabledUser.GetId(), test.before, test.after)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if test.want == "" {
+			if user != nil || len(events) != 0 {
+				t.Errorf("%s: the events %v are raised, want none", test.name, events)
+			}
+			continue
+		}
+		if user == nil || user.Id != "id-1" {
+			t.Errorf("%s: the user is %v, want id-1", test.name, user)
+		}
+		if _, ok := events[test.want]; !ok || len(events) != 1 {
+			t.Errorf("%s: the events are %v, want %s", test.name, events, test.want)
+		}
+	}
+}
+
+func TestSsfStreamApplyConfiguration(t *testing.T) {
+	application := &Application{Owner: "admin", Name: "app-receiver", RedirectUris: []string{"https://receiver.example.com/callback", "app.example.org"}}
+	stream := &SsfStream{}
+	config := &SsfStreamConfiguration{
+		Delivery:        &SsfDelivery{EndpointUrl: "https://receiver.example.com/events", AuthorizationHeader: "Bearer secret"},
+		EventsRequested: []string{SsfEventSessionRevoked, SsfEventAccountDisabled, "https://example.com/unknown", SsfEventSessionRevoked},
+	}
+	if ssfErr := stream.applyConfiguration(application, config, true); ssfErr != nil {
+		t.Fatalf("a valid configuration is rejected: %s", ssfErr.Description)
+	}
+
+	if stream.DeliveryMethod != SsfDeliveryMethodPush {
+		t.Errorf("the delivery method is %s, want %s", stream.DeliveryMethod, SsfDeliveryMethodPush)
+	}
+	if !reflect.DeepEqual(stream.EventsDelivered, []string{SsfEventSessionRevoked, SsfEventAccountDisabled}) {
+		t.Errorf("the events delivered are %v", stream.EventsDelivered)
+	}
+	if stream.getConfiguration().Delivery.AuthorizationHeader != "" {
+		t.Errorf("the authorization header is sent back to the receiver")
+	}
+
+	// a partial update keeps the delivery
+	if ssfErr := stream.applyConfiguration(application, &SsfStreamConfiguration{EventsRequested: []string{SsfEventCredentialChange}}, false); ssfErr != nil {
+		t.Fatalf("a partial update is rejected: %s", ssfErr.Description)
+	}
+	if stream.EndpointUrl != "https://receiver.example.com/events" || !reflect.DeepEqual(stream.EventsDelivered, []string{SsfEventCredentialChange}) {
+		t.Errorf("the partial update has changed the stream to %v", stream)
+	}
+
+	// a redirect URI without a scheme allows its host too
+	if ssfErr := (&SsfStream{}).applyConfiguration(application, &SsfStreamConfiguration{Delivery: &SsfDelivery{EndpointUrl: "https://APP.example.org/ssf"}}, true); ssfErr != nil {
+		t.Errorf("an endpoint on a host of the redirect URIs is rejected: %s", ssfErr.Description)
+	}
+
+	invalidConfigs := []*SsfStreamConfiguration{
+		{},
+		{Delivery: &SsfDelivery{Method: "urn:ietf:rfc:8936", EndpointUrl: "https://receiver.example.com/events"}},
+		{Delivery: &SsfDelivery{EndpointUrl: "ftp://receiver.example.com/events"}},
+		{Delivery: &SsfDelivery{EndpointUrl: "not a url"}},
+		// the endpoint has to be on a host of the redirect URIs
+		{Delivery: &SsfDelivery{EndpointUrl: "http://169.254.169.254/latest/meta-data"}},
+		{Delivery: &SsfDelivery{EndpointUrl: "https://receiver.example.com.evil.com/events"}},
+	}
+	for _, config := range invalidConfigs {
+		if ssfErr := (&SsfStream{}).applyConfiguration(application, config, true); ssfErr == nil {
+			t.Errorf("the invalid configuration %v is accepted", config)
+		}
+	}
+}
+
+func TestGetSsfSubjectIdentifier(t *testing.T) {
+	tests := []struct {
+		claims jwt.MapClaims
+		event  map[string]interface{}
+		format string
+		value  string
+	}{
+		{claims: jwt.MapClaims{"sub_id": map[string]interface{}{"format": "iss_sub", "iss": "https://idp.example.com", "sub": "user-1"}}, format: "iss_sub", value: "user-1"},
+		{claims: jwt.MapClaims{"sub_id": map[string]interface{}{"format": "email", "email": "alice@example.com"}}, format: "email", value: "alice@example.com"},
+		{claims: jwt.MapClaims{}, event: map[string]interface{}{"subject": map[string]interface{}{"format": "opaque", "id": "user-2"}}, format: "opaque", value: "user-2"},
+		{claims: jwt.MapClaims{"sub": "user-3"}, format: "iss_sub", value: "user-3"},
+		{claims: jwt.MapClaims{"sub_id": map[string]interface{}{"format": "phone_number", "phone_number": "+1"}}, format: "phone_number", value: ""},
+	}
+	for _, test := range tests {
+		format, value := getSsfSubjectIdentifier(test.claims, test.event)
+		if format != test.format || value != test.value {
+			t.Errorf("getSsfSubjectIdentifier(%v) = %s, %s, want %s, %s", test.claims, format, value, test.format, test.value)
+		}
+	}
+}
+
+func TestAddSsfEventsOnlyForStreamSubjects(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 20, "cert", "org-ssf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&Cert{Owner: "admin", Name: "cert", Type: "x509", CryptoAlgorithm: "RS256", Certificate: certificate, PrivateKey: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&Application{Owner: "admin", Name: "app-ssf", Organization: "org-ssf", Cert: "cert", ClientId: "client-ssf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&SsfStream{Owner: "admin", Name: "stream-ssf", Organization: "org-ssf", Application: "app-ssf", Status: SsfStreamStatusEnabled, EventsDelivered: []string{SsfEventAccountPurged}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only alice has signed in to the receiver application
+	_, err = ormer.Engine.Insert(&Token{Owner: "admin", Name: "token-alice", Application: "app-ssf", Organization: "org-ssf", User: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&Token{Owner: "admin", Name: "token-bob", Application: "app-other", Organization: "org-ssf", User: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, user := range []*User{{Owner: "org-ssf", Name: "alice", Id: "id-alice"}, {Owner: "org-ssf", Name: "bob", Id: "id-bob"}} {
+		err = addSsfEventsFromDomainEvent("org-ssf", DomainEventUserDeleted, user.GetId(), user, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events := []*WebhookEvent{}
+	err = ormer.Engine.Where("ssf_stream = ?", "admin/stream-ssf").Find(&events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("%d events are queued for the stream, want 1", len(events))
+	}
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(events[0].Payload, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subId, _ := claims["sub_id"].(map[string]interface{})
+	if subId["sub"] != "id-alice" {
+		t.Errorf("the event is about %v, want id-alice", subId["sub"])
+	}
+}
+
+func TestGetSsfReceivedUsersByEmail(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	provider := &Provider{Owner: "org-ssf", Name: "provider-ssf", Category: "OAuth", Type: "Custom"}
+	users := []*User{
+		{Owner: "org-ssf", Name: "alice", Email: "shared@example.com", Custom: "upstream-alice"},
+		{Owner: "org-ssf", Name: "bob", Email: "shared@example.com", Properties: map[string]string{"oauth_Custom_id": "upstream-bob"}},
+		{Owner: "org-ssf", Name: "carol", Email: "shared@example.com"},
+		{Owner: "org-other", Name: "dave", Email: "shared@example.com", Custom: "upstream-dave"},
+	}
+	for _, user := range users {
+		_, err := ormer.Engine.Insert(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// carol has the same email but has never signed in through the provider
+	receivedUsers, _, err := getSsfReceivedUsers(provider, "email", "shared@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, user := range receivedUsers {
+		names = append(names, user.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"alice", "bob"}) {
+		t.Errorf("the received users are %v, want [alice bob]", names)
+	}
+}
+
+func TestCheckSsfSecurityEventReplay(t *testing.T) {
+	oldReceivedJtiMap := ReceivedJtiMap
+	ReceivedJtiMap = &memoryReceivedJtiStore{}
+	t.Cleanup(func() {
+		ReceivedJtiMap = oldReceivedJtiMap
+	})
+
+	provider := &Provider{Owner: "org-ssf", Name: "provider-ssf"}
+	otherProvider := &Provider{Owner: "org-ssf", Name: "provider-other"}
+	claims := jwt.MapClaims{"iat": float64(time.Now().Unix()), "jti": "jti-1"}
+
+	if ssfErr := checkSsfSecurityEventReplay(provider, claims); ssfErr != nil {
+		t.Fatalf("a new security event is refused: %s", ssfErr.Description)
+	}
+	if ssfErr := checkSsfSecurityEventReplay(provider, claims); ssfErr == nil {
+		t.Errorf("a replayed security event is accepted")
+	}
+	if ssfErr := checkSsfSecurityEventReplay(otherProvider, claims); ssfErr != nil {
+		t.Errorf("the same jti from another provider is refused: %s", ssfErr.Description)
+	}
+
+	oldClaims := jwt.MapClaims{"iat": float64(time.Now().Add(-ssfSecurityEventMaxAge - time.Minute).Unix()), "jti": "jti-2"}
+	if ssfErr := checkSsfSecurityEventReplay(provider, oldClaims); ssfErr == nil {
+		t.Errorf("a security event older than %s is accepted", ssfSecurityEventMaxAge)
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/idp"
+	"github.com/casdoor/casdoor/util"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ssfSecurityEventTokenType = "secevent+jwt"
+
+	// the transmitters retry a push for hours, so a received event is accepted for longer than a logout token
+	ssfSecurityEventMaxAge = 24 * time.Hour
+)
+
+// SsfTransmitterMetadata is the document served at /.well-known/ssf-configuration
+// Refs: https://openid.net/specs/openid-sharedsignals-framework-1_0.html#name-transmitter-configuration-m
+type SsfTransmitterMetadata struct {
+	SpecVersion              string                   `json:"spec_version"`
+	Issuer                   string                   `json:"issuer"`
+	JwksUri                  string                   `json:"jwks_uri"`
+	DeliveryMethodsSupported []string                 `json:"delivery_methods_supported"`
+	ConfigurationEndpoint    string                   `json:"configuration_endpoint"`
+	StatusEndpoint           string                   `json:"status_endpoint"`
+	VerificationEndpoint     string                   `json:"verification_endpoint"`
+	AuthorizationSchemes     []map[string]interface{} `json:"authorization_schemes"`
+}
+
+func GetSsfTransmitterMetadata(host string) *SsfTransmitterMetadata {
+	_, originBackend := getOriginFromHost(host)
+
+	return &SsfTransmitterMetadata{
+		SpecVersion:              "1_0",
+		Issuer:                   originBackend,
+		JwksUri:                  fmt.Sprintf("%s/.well-known/jwks", originBackend),
+		DeliveryMethodsSupported: []string{SsfDeliveryMethodPush},
+		ConfigurationEndpoint:    fmt.Sprintf("%s/api/ssf/stream", originBackend),
+		StatusEndpoint:           fmt.Sprintf("%s/api/ssf/status", originBackend),
+		VerificationEndpoint:     fmt.Sprintf("%s/api/ssf/verify", originBackend),
+		// the receivers authenticate with an access token of the client credentials grant
+		AuthorizationSchemes: []map[string]interface{}{{"spec_urn": "urn:ietf:rfc:6749"}},
+	}
+}
+
+// getSsfEventsFromDomainEvent maps a domain event to the Shared Signals events it raises and
+// the user they are about, nothing is raised for a change that isn't security related
+func getSsfEventsFromDomainEvent(eventType string, subject string, before interface{}, after interface{}) (*User, map[string]interface{}, error) {
+	beforeUser, _ := before.(*User)
+	afterUser, _ := after.(*User)
+	events := map[string]interface{}{}
+	timestamp := time.Now().Unix()
+
+	switch eventType {
+	case DomainEventSessionRevoked:
+		user, err := GetUser(subject)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		events[SsfEventSessionRevoked] = map[string]interface{}{"event_timestamp": timestamp}
+		return user, events, nil
+	case DomainEventUserPasswordChanged:
+		events[SsfEventCredentialChange] = map[string]interface{}{
+			"event_timestamp": timestamp,
+			"credential_type": "password",
+			"change_type":     "update",
+		}
+		return afterUser, events, nil
+	case DomainEventUserUpdated:
+		if beforeUser == nil || afterUser == nil || beforeUser.IsForbidden == afterUser.IsForbidden {
+			return nil, nil, nil
+		}
+
+		if afterUser.IsForbidden {
+			events[SsfEventAccountDisabled] = map[string]interface{}{}
+		} else {
+			events[SsfEventAccountEnabled] = map[string]interface{}{}
+		}
+		return afterUser, events, nil
+	case DomainEventUserDeleted:
+		events[SsfEventAccountPurged] = map[string]interface{}{}
+		return beforeUser, events, nil
+	}
+
+	return nil, nil, nil
+}
+
+func getSsfSecurityEventClaims(stream *SsfStream, jti string, subId map[string]interface{}, events map[string]interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    stream.Issuer,
+		"aud":    stream.Audience,
+		"iat":    time.Now().Unix(),
+		"jti":    jti,
+		"sub_id": subId,
+		"events": events,
+	}
+}
+
+// addSsfStreamEvent signs the security event token of the stream and queues it in the outbox
+func addSsfStreamEvent(stream *SsfStream, eventType string, subId map[string]interface{}, events map[string]interface{}) error {
+	application, err := getApplication(stream.Owner, stream.Application)
+	if err != nil {
+		return err
+	}
+	if application == nil {
+		return fmt.Errorf("the application: %s of the stream is not found", util.GetId(stream.Owner, stream.Application))
+	}
+
+	event := newSsfStreamEvent(stream, eventType)
+	claims := getSsfSecurityEventClaims(stream, event.Name, subId, events)
+	event.Payload, err = signEventTokenByApplication(application, claims, ssfSecurityEventTokenType)
+	if err != nil {
+		return err
+	}
+
+	_, err = AddWebhookEvent(event)
+	return err
+}
+
+func addSsfEventsFromDomainEvent(organization string, eventType string, subject string, before interface{}, after interface{}) error {
+	streams, err := getSsfStreamsByOrganization(organization)
+	if err != nil {
+		return err
+	}
+	if len(streams) == 0 {
+		return nil
+	}
+
+	user, events, err := getSsfEventsFromDomainEvent(eventType, subject, before, after)
+	if err != nil || user == nil {
+		return err
+	}
+
+	for _, stream := range streams {
+		if stream.Status == SsfStreamStatusDisabled {
+			continue
+		}
+
+		isSubject, err := isSsfStreamSubject(stream, user)
+		if err != nil {
+			return err
+		}
+		if !isSubject {
+			continue
+		}
+
+		for ssfEventType, ssfEvent := range events {
+			if !util.InSlice(stream.EventsDelivered, ssfEventType) {
+				continue
+			}
+
+			// the subject is the one of the id_tokens issued to the receiver
+			subId := map[string]interface{}{"format": "iss_sub", "iss": stream.Issuer, "sub": user.Id}
+			err = addSsfStreamEvent(stream, ssfEventType, subId, map[string]interface{}{ssfEventType: ssfEvent})
+			if err != nil {
+				return fmt.Errorf("stream %s: failed to create event: %w", stream.GetId(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifySsfStream queues a verification event for the stream, the receiver checks that the
+// state it has sent comes back. Refs: https://openid.net/specs/openid-sharedsignals-framework-1_0.html#name-verification
+func VerifySsfStream(application *Application, request *SsfVerificationRequest) (*SsfError, error) {
+	stream, ssfErr, err := getApplicationSsfStream(application, request.StreamId)
+	if stream == nil {
+		return ssfErr, err
+	}
+	if stream.Status == SsfStreamStatusDisabled {
+		return newSsfError("invalid_request", "the stream: %s is disabled", stream.Name), nil
+	}
+
+	event := map[string]interface{}{}
+	if request.State != "" {
+		event["state"] = request.State
+	}
+
+	subId := map[string]interface{}{"format": "opaque", "id": stream.Name}
+	err = addSsfStreamEvent(stream, SsfEventVerification, subId, map[string]interface{}{SsfEventVerification: event})
+	if err != nil {
+		return nil, err
+	}
+
+	StartWebhookDeliveryWorker()
+	return nil, nil
+}
+
+func postSsfSecurityEvent(stream *SsfStream, securityEventToken string) (int, string, error) {
+	// a redirect isn't followed, it could lead the push away from the hosts of the receiver application
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequest("POST", stream.EndpointUrl, strings.NewReader(securityEventToken))
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/secevent+jwt")
+	req.Header.Set("Accept", "application/json")
+	if stream.AuthorizationHeader != "" {
+		req.Header.Set("Authorization", stream.AuthorizationHeader)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+
+	defer resp.Body.Close()
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.StatusCode, string(bodyBytes), nil
+}
+
+// deliverSsfEvent pushes a single security event to the receiver of its stream, the events of a
+// paused stream stay pending until the stream is enabled again
+func deliverSsfEvent(event *WebhookEvent) {
+	stream, err := GetSsfStream(event.SsfStream)
+	if err != nil {
+		logs.Error(fmt.Sprintf("failed to get stream %s: %v", event.SsfStream, err))
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("get stream: %w", err))
+		return
+	}
+
+	if stream == nil {
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("stream not found"))
+		return
+	}
+
+	switch stream.Status {
+	case SsfStreamStatusPaused:
+		return
+	case SsfStreamStatusDisabled:
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, 0, "", fmt.Errorf("stream is disabled"))
+		return
+	}
+
+	event.AttemptCount++
+
+	statusCode, respBody, err := postSsfSecurityEvent(stream, event.Payload)
+	if err == nil && statusCode == http.StatusBadRequest {
+		// the receiver has rejected the event itself, sending it again won't change that
+		UpdateWebhookEventState(event, WebhookEventStatusFailed, statusCode, respBody, fmt.Errorf("the receiver rejected the event"))
+		return
+	}
+	if err == nil && (statusCode < 200 || statusCode >= 300) {
+		err = fmt.Errorf("the receiver responded with status code: %d", statusCode)
+	}
+
+	finishWebhookEventDelivery(event, err == nil, 0, 0, true, statusCode, respBody, err)
+}
+
+// getSsfSubjectIdentifier returns the format and the value of the subject of a received security event,
+// the subject is the sub_id claim, the "subject" member of the event in the earlier drafts, or the sub claim
+func getSsfSubjectIdentifier(claims jwt.MapClaims, event map[string]interface{}) (string, string) {
+	subId, ok := claims["sub_id"].(map[string]interface{})
+	if !ok {
+		subId, ok = event["subject"].(map[string]interface{})
+	}
+	if ok {
+		format, _ := subId["format"].(string)
+		switch format {
+		case "iss_sub":
+			sub, _ := subId["sub"].(string)
+			return format, sub
+		case "email":
+			email, _ := subId["email"].(string)
+			return format, email
+		case "opaque":
+			id, _ := subId["id"].(string)
+			return format, id
+		}
+		return format, ""
+	}
+
+	if sub, _ := claims["sub"].(string); sub != "" {
+		return "iss_sub", sub
+	}
+	return "", ""
+}
+
+// isUserLinkedToProvider reports whether the user has signed in through the provider or has linked it
+func isUserLinkedToProvider(user *User, provider *Provider) (bool, error) {
+	if IsFlexibleCustomProvider(provider.Type) {
+		link, err := GetThirdPartyLink(user.Owner, user.Name, provider.Name)
+		if err != nil {
+			return false, err
+		}
+		return link != nil, nil
+	}
+
+	field := reflect.Indirect(reflect.ValueOf(user)).FieldByName(provider.Type)
+	if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+		return true, nil
+	}
+	return getUserProperty(user, fmt.Sprintf("oauth_%s_id", provider.Type)) != "", nil
+}
+
+// getSsfReceivedUsers returns the local users the upstream subject has signed in as, they are found
+// through the upstream sessions, and by the email among the users of the organization of the provider
+// who are linked to the provider
+func getSsfReceivedUsers(provider *Provider, format string, value string) ([]*User, []*IdpSession, error) {
+	users := []*User{}
+	if format == "email" {
+		if provider.Owner == "admin" {
+			return users, []*IdpSession{}, nil
+		}
+
+		emailUsers := []*User{}
+		err := ormer.Engine.Where("owner = ? and email = ?", provider.Owner, value).Find(&emailUsers)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, user := range emailUsers {
+			isLinked, err := isUserLinkedToProvider(user, provider)
+			if err != nil {
+				return nil, nil, err
+			}
+			if isLinked {
+				users = append(users, user)
+			}
+		}
+		return users, []*IdpSession{}, nil
+	}
+
+	idpSessions, err := GetIdpSessions(provider, value, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, idpSession := range idpSessions {
+		userId := util.GetId(idpSession.Organization, idpSession.User)
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+
+		user, err := GetUser(userId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user != nil {
+			users = append(users, user)
+		}
+	}
+	return users, idpSessions, nil
+}
+
+// checkSsfSecurityEventReplay refuses a security event token which is too old or has been received already
+func checkSsfSecurityEventReplay(provider *Provider, claims jwt.MapClaims) *SsfError {
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return newSsfError("invalid_request", "the iat of the security event token is invalid")
+	}
+	if time.Since(issuedAt.Time) > ssfSecurityEventMaxAge {
+		return newSsfError("invalid_request", "the security event token was issued more than %s ago", ssfSecurityEventMaxAge)
+	}
+
+	// the iat may be up to a minute ahead, see the leeway of ValidateSecurityEventToken()
+	jti, _ := claims["jti"].(string)
+	expiresAt := issuedAt.Add(ssfSecurityEventMaxAge + time.Minute)
+	if !ReceivedJtiMap.Add(fmt.Sprintf("ssf:%s:%s", provider.GetId(), jti), expiresAt) {
+		return newSsfError("invalid_request", "the jti: %s of the security event token has been received already", jti)
+	}
+	return nil
+}
+
+// HandleSsfSecurityEvent validates a security event token pushed by an upstream identity provider and
+// acts on it: a revoked session ends the sessions signed in through the upstream one, and a changed
+// credential, a disabled, purged or compromised account or revoked sessions force the linked users offline.
+// It returns the number of the users acted on.
+func HandleSsfSecurityEvent(provider *Provider, securityEventToken string, host string) (int, *SsfError, error) {
+	if provider.Category != "OAuth" {
+		return 0, newSsfError("invalid_issuer", "the provider: %s doesn't support Shared Signals", provider.GetId()), nil
+	}
+
+	idpInfo, err := FromProviderToIdpInfo(nil, provider)
+	if err != nil {
+		return 0, nil, err
+	}
+	idpInfo.HostUrl = getProviderOidcIssuer(provider)
+
+	idProvider := idp.NewOidcIdProvider(idpInfo, "")
+	claims, err := idProvider.ValidateSecurityEventToken(securityEventToken)
+	if err != nil {
+		return 0, newSsfError("invalid_request", "%s", err.Error()), nil
+	}
+
+	ssfErr := checkSsfSecurityEventReplay(provider, claims)
+	if ssfErr != nil {
+		return 0, ssfErr, nil
+	}
+
+	count := 0
+	events := claims["events"].(map[string]interface{})
+	for eventType, value := range events {
+		event, _ := value.(map[string]interface{})
+		if eventType == SsfEventVerification {
+			continue
+		}
+
+		format, subject := getSsfSubjectIdentifier(claims, event)
+		if subject == "" {
+			return count, newSsfError("invalid_request", "the subject of the event: %s is missing or its format: %s is not supported", eventType, format), nil
+		}
+
+		users, idpSessions, err := getSsfReceivedUsers(provider, format, subject)
+		if err != nil {
+			return count, nil, err
+		}
+
+		switch eventType {
+		case SsfEventSessionRevoked:
+			err = LogoutIdpSessions(idpSessions, host)
+			if err != nil {
+				return count, nil, err
+			}
+			count += len(users)
+		case SsfEventCredentialChange, SsfEventAccountDisabled, SsfEventAccountPurged, SsfEventCredentialCompromise, SsfEventSessionsRevoked:
+			for _, user := range users {
+				err = terminateUserAccess(user)
+				if err != nil {
+					return count, nil, err
+				}
+				count++
+			}
+		default:
+			// the events Casdoor doesn't act on are accepted, the transmitter must not retry them
+			logs.Info(fmt.Sprintf("the security event: %s from the provider: %s is ignored", eventType, provider.GetId()))
+		}
+	}
+
+	return count, nil, nil
+}
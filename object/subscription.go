@@ -59,6 +59,7 @@ func (sub *Subscription) GetId() string {
 }
 
 func (sub *Subscription) UpdateState() error {
+	before := *sub
 	preState := sub.State
 	// update subscription state by payment state
 	if sub.State == SubStatePending {
@@ -108,6 +109,10 @@ func (sub *Subscription) UpdateState() error {
 		if err != nil {
 			return err
 		}
+
+		if sub.State == SubStateExpired {
+			PublishDomainEvent(sub.Owner, DomainEventSubscriptionExpired, sub.GetId(), &before, sub)
+		}
 	}
 
 	return nil
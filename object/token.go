@@ -45,7 +45,10 @@ type Token struct {
 	CodeIsUsed       bool   `json:"codeIsUsed"`
 	CodeExpireIn     int64  `json:"codeExpireIn"`
 	Resource         string `xorm:"varchar(255)" json:"resource"`           // RFC 8707 Resource Indicator
+	AcrValues        string `xorm:"varchar(255)" json:"acrValues"`          // the acr_values of the authorization request
 	DPoPJkt          string `xorm:"varchar(255) 'dpop_jkt'" json:"dPoPJkt"` // RFC 9449 DPoP JWK thumbprint binding
+	CertThumbprint   string `xorm:"varchar(100)" json:"certThumbprint"`     // RFC 8705 client certificate binding
+	SessionId        string `xorm:"varchar(100) index" json:"sessionId"`    // the Beego session the token was issued in
 }
 
 func GetTokenCount(owner, organization, field, value string) (int64, error) {
@@ -274,6 +277,20 @@ func ExpireTokenByUserAndApplication(owner string, username string, application
 	return affected != 0, nil
 }
 
+// ExpireTokenBySessionId expires the user's tokens issued in one Beego session, "owner" is the organization of the user
+func ExpireTokenBySessionId(owner string, username string, sessionId string) (bool, error) {
+	if sessionId == "" {
+		return false, nil
+	}
+
+	affected, err := ormer.Engine.Where(fmt.Sprintf("organization = ? and %s = ? and session_id = ? and expires_in > 0", quoteColumn("user")), owner, username, sessionId).Cols("expires_in").Update(&Token{ExpiresIn: 0})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
 // updateTokenDPoP updates the token_type and dpop_jkt columns for DPoP binding (RFC 9449).
 func updateTokenDPoP(token *Token) error {
 	_, err := ormer.Engine.ID(core.PK{token.Owner, token.Name}).Cols("token_type", "dpop_jkt").Update(token)
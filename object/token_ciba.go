@@ -0,0 +1,454 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/casdoor/casdoor/util"
+)
+
+const (
+	CibaGrantType  = "urn:openid:params:grant-type:ciba"
+	CibaExpiresIn  = 300
+	CibaInterval   = 5
+	CibaModePoll   = "poll"
+	CibaModePing   = "ping"
+	cibaKeyPrefix  = "ciba:"
+	cibaMaxExpires = 1800
+)
+
+type CibaAuthRequest struct {
+	Scope                   string
+	LoginHint               string
+	IdTokenHint             string
+	BindingMessage          string
+	ClientNotificationToken string
+	RequestedExpiry         int
+}
+
+type CibaAuthResponse struct {
+	AuthReqId string `json:"auth_req_id"`
+	ExpiresIn int    `json:"expires_in"`
+	Interval  int    `json:"interval,omitempty"`
+}
+
+// CibaRequestInfo is what the user sees on the approval page of a CIBA request
+type CibaRequestInfo struct {
+	Application    string `json:"application"`
+	Scope          string `json:"scope"`
+	BindingMessage string `json:"bindingMessage"`
+	ExpiresAt      string `json:"expiresAt"`
+}
+
+// getCibaPollKey returns the key of the poll state of a CIBA request, it's kept apart from the
+// request so that recording a poll never overwrites the decision of the user
+func getCibaPollKey(authReqId string) string {
+	return cibaKeyPrefix + "poll:" + authReqId
+}
+
+func getCibaKey(authReqId string) string {
+	return cibaKeyPrefix + authReqId
+}
+
+func getCibaDeliveryMode(application *Application) string {
+	if application.CibaTokenDeliveryMode == "" {
+		return CibaModePoll
+	}
+	return application.CibaTokenDeliveryMode
+}
+
+// resolveCibaUser finds the end user that the client wants to authenticate, identified either by
+// login_hint (username, email or phone) or by an ID token previously issued to the client.
+func resolveCibaUser(application *Application, request *CibaAuthRequest) (*User, error) {
+	if request.IdTokenHint != "" {
+		claims, err := ParseJwtTokenByApplication(request.IdTokenHint, application)
+		if err != nil {
+			return nil, err
+		}
+		if !util.InSlice(claims.Audience, application.ClientId) {
+			return nil, fmt.Errorf("the id_token_hint is not issued to this client")
+		}
+
+		return getUser(claims.Owner, claims.Name)
+	}
+
+	return GetUserByFieldsForSharedApp(application, application.Organization, request.LoginHint)
+}
+
+// StartCibaAuthentication handles a backchannel authentication request of the client, it notifies
+// the user through the push notification provider of the user and returns the auth_req_id that
+// the client uses to poll the token endpoint or to match the ping callback.
+// Refs: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html
+func StartCibaAuthentication(application *Application, request *CibaAuthRequest, host string) (*CibaAuthResponse, *TokenError, error) {
+	if !IsGrantTypeValid(CibaGrantType, application.GrantTypes) {
+		return nil, &TokenError{
+			Error:            UnauthorizedClient,
+			ErrorDescription: fmt.Sprintf("grant_type: %s is not supported in this application", CibaGrantType),
+		}, nil
+	}
+
+	if !util.InSlice(strings.Split(request.Scope, " "), "openid") {
+		return nil, &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "the scope must contain openid",
+		}, nil
+	}
+
+	expandedScope, ok := IsScopeValidAndExpand(request.Scope, application)
+	if !ok {
+		return nil, &TokenError{
+			Error:            InvalidScope,
+			ErrorDescription: "the requested scope is invalid or not defined in the application",
+		}, nil
+	}
+
+	if (request.LoginHint == "") == (request.IdTokenHint == "") {
+		return nil, &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "exactly one of login_hint and id_token_hint is required",
+		}, nil
+	}
+
+	mode := getCibaDeliveryMode(application)
+	if mode == CibaModePing && (request.ClientNotificationToken == "" || application.CibaClientNotificationEndpoint == "") {
+		return nil, &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "client_notification_token and the client notification endpoint are required in ping mode",
+		}, nil
+	}
+
+	user, err := resolveCibaUser(application, request)
+	if err != nil {
+		return nil, &TokenError{
+			Error:            "unknown_user_id",
+			ErrorDescription: err.Error(),
+		}, nil
+	}
+	if user == nil || user.IsForbidden || user.IsDeleted {
+		return nil, &TokenError{
+			Error:            "unknown_user_id",
+			ErrorDescription: "the user is not found or is forbidden to sign in",
+		}, nil
+	}
+
+	if !user.MfaPushEnabled || user.MfaPushReceiver == "" {
+		return nil, &TokenError{
+			Error:            "unknown_user_id",
+			ErrorDescription: "the user has no push notification receiver to approve the request",
+		}, nil
+	}
+
+	expiresIn := CibaExpiresIn
+	if request.RequestedExpiry > 0 && request.RequestedExpiry < cibaMaxExpires {
+		expiresIn = request.RequestedExpiry
+	}
+
+	authReqId := util.GenerateId()
+	cache := DeviceAuthCache{
+		UserName:                user.GetId(),
+		ApplicationId:           application.GetId(),
+		ClientId:                application.ClientId,
+		Scope:                   expandedScope,
+		RequestAt:               time.Now(),
+		Status:                  DeviceAuthStatusPending,
+		ExpiresIn:               expiresIn,
+		BindingMessage:          request.BindingMessage,
+		ClientNotificationToken: request.ClientNotificationToken,
+	}
+	DeviceAuthMap.Store(getCibaKey(authReqId), cache)
+
+	err = notifyCibaUser(user, application, authReqId, request.BindingMessage, host)
+	if err != nil {
+		DeviceAuthMap.Delete(getCibaKey(authReqId))
+		return nil, nil, err
+	}
+
+	resp := &CibaAuthResponse{
+		AuthReqId: authReqId,
+		ExpiresIn: expiresIn,
+	}
+	if mode == CibaModePoll {
+		resp.Interval = CibaInterval
+	}
+	return resp, nil, nil
+}
+
+func notifyCibaUser(user *User, application *Application, authReqId string, bindingMessage string, host string) error {
+	originFrontend, _ := getOriginFromHost(host)
+	link := fmt.Sprintf("%s/ciba/%s/%s", originFrontend, application.Name, authReqId)
+
+	applicationName := application.DisplayName
+	if applicationName == "" {
+		applicationName = application.Name
+	}
+
+	message := fmt.Sprintf("%s requests to sign you in.", applicationName)
+	if bindingMessage != "" {
+		message = fmt.Sprintf("%s Binding message: %s.", message, bindingMessage)
+	}
+	message = fmt.Sprintf("%s Approve or deny the request at: %s", message, link)
+
+	pushMfa := NewPushMfaUtil(&MfaProps{
+		MfaType: PushType,
+		Secret:  user.MfaPushReceiver,
+		URL:     user.MfaPushProvider,
+	})
+	return pushMfa.sendPushNotification("Sign-in request", message)
+}
+
+// StartPushApproval asks the user to approve a sign-in that doesn't come from an OAuth client, e.g.
+// a RADIUS Access-Challenge, the user approves or denies it on the same page as a CIBA request.
+// The host is the one of Casdoor that the link of the approval page is built from.
+func StartPushApproval(application *Application, user *User, bindingMessage string, host string) (string, error) {
+	if !user.MfaPushEnabled || user.MfaPushReceiver == "" {
+		return "", fmt.Errorf("the user has no push notification receiver to approve the request")
+	}
+
+	approvalId := util.GenerateId()
+	cache := DeviceAuthCache{
+		UserName:       user.GetId(),
+		ApplicationId:  application.GetId(),
+		RequestAt:      time.Now(),
+		Status:         DeviceAuthStatusPending,
+		ExpiresIn:      CibaExpiresIn,
+		BindingMessage: bindingMessage,
+	}
+	DeviceAuthMap.Store(getCibaKey(approvalId), cache)
+
+	err := notifyCibaUser(user, application, approvalId, bindingMessage, host)
+	if err != nil {
+		DeviceAuthMap.Delete(getCibaKey(approvalId))
+		return "", err
+	}
+	return approvalId, nil
+}
+
+// GetPushApprovalStatus returns the status of a push approval started by StartPushApproval, an
+// expired approval is reported as denied.
+func GetPushApprovalStatus(approvalId string, userId string) string {
+	cache, ok := getCibaCache(approvalId)
+	if !ok || cache.UserName != userId {
+		return DeviceAuthStatusDenied
+	}
+
+	if cache.Status != DeviceAuthStatusPending {
+		DeviceAuthMap.Delete(getCibaKey(approvalId))
+	}
+	return cache.Status
+}
+
+func getCibaCache(authReqId string) (DeviceAuthCache, bool) {
+	value, ok := DeviceAuthMap.Load(getCibaKey(authReqId))
+	if !ok {
+		return DeviceAuthCache{}, false
+	}
+
+	cache := value.(DeviceAuthCache)
+	if cache.RequestAt.Add(time.Duration(cache.ExpiresIn) * time.Second).Before(time.Now()) {
+		DeviceAuthMap.Delete(getCibaKey(authReqId))
+		return DeviceAuthCache{}, false
+	}
+	return cache, true
+}
+
+// GetCibaRequestInfo returns the pending CIBA request for the approval page, only the user that
+// the request is addressed to can see it.
+func GetCibaRequestInfo(authReqId string, userId string) (*CibaRequestInfo, error) {
+	cache, ok := getCibaCache(authReqId)
+	if !ok || cache.UserName != userId || cache.Status != DeviceAuthStatusPending {
+		return nil, fmt.Errorf("the authentication request is invalid or expired")
+	}
+
+	return &CibaRequestInfo{
+		Application:    cache.ApplicationId,
+		Scope:          cache.Scope,
+		BindingMessage: cache.BindingMessage,
+		ExpiresAt:      cache.RequestAt.Add(time.Duration(cache.ExpiresIn) * time.Second).Format(time.RFC3339),
+	}, nil
+}
+
+// CompleteCibaAuthentication records the decision of the user, in ping mode the client is told
+// through its notification endpoint that it can call the token endpoint.
+func CompleteCibaAuthentication(authReqId string, userId string, approved bool) error {
+	cache, ok := getCibaCache(authReqId)
+	if !ok || cache.UserName != userId || cache.Status != DeviceAuthStatusPending {
+		return fmt.Errorf("the authentication request is invalid or expired")
+	}
+
+	cache.UserSignIn = approved
+	cache.Status = DeviceAuthStatusDenied
+	if approved {
+		cache.Status = DeviceAuthStatusApproved
+	}
+	DeviceAuthMap.Store(getCibaKey(authReqId), cache)
+
+	application, err := GetApplication(cache.ApplicationId)
+	if err != nil {
+		return err
+	}
+	if application != nil && getCibaDeliveryMode(application) == CibaModePing && cache.ClientNotificationToken != "" {
+		util.SafeGoroutine(func() {
+			err := sendCibaPing(application, authReqId, cache.ClientNotificationToken)
+			if err != nil {
+				logs.Warn("failed to send the CIBA ping callback for application: %s, error: %v", application.GetId(), err)
+			}
+		})
+	}
+	return nil
+}
+
+func sendCibaPing(application *Application, authReqId string, clientNotificationToken string) error {
+	body := strings.NewReader(util.StructToJson(map[string]string{"auth_req_id": authReqId}))
+	req, err := http.NewRequest("POST", application.CibaClientNotificationEndpoint, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+clientNotificationToken)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetCibaToken handles the CIBA grant at the token endpoint, it is polled in poll mode and called
+// once after the callback in ping mode.
+func GetCibaToken(application *Application, clientSecret string, authReqId string, host string) (*Token, *TokenError, error) {
+	if subtle.ConstantTimeCompare([]byte(application.ClientSecret), []byte(clientSecret)) != 1 {
+		return nil, &TokenError{
+			Error:            InvalidClient,
+			ErrorDescription: "client_secret is invalid",
+		}, nil
+	}
+
+	if authReqId == "" {
+		return nil, &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "auth_req_id is required for this grant type",
+		}, nil
+	}
+
+	cache, ok := getCibaCache(authReqId)
+	if !ok {
+		return nil, &TokenError{
+			Error:            "expired_token",
+			ErrorDescription: "the auth_req_id has expired",
+		}, nil
+	}
+
+	if cache.ClientId != application.ClientId {
+		return nil, &TokenError{
+			Error:            InvalidGrant,
+			ErrorDescription: "the auth_req_id is not issued to this client",
+		}, nil
+	}
+
+	switch cache.Status {
+	case DeviceAuthStatusPending:
+		if !checkCibaPollInterval(authReqId, cache) {
+			return nil, &TokenError{
+				Error:            "slow_down",
+				ErrorDescription: "the client is polling too fast, the interval has been increased",
+			}, nil
+		}
+		return nil, &TokenError{
+			Error:            "authorization_pending",
+			ErrorDescription: "authorization pending",
+		}, nil
+	case DeviceAuthStatusDenied:
+		DeviceAuthMap.Delete(getCibaKey(authReqId))
+		DeviceAuthMap.Delete(getCibaPollKey(authReqId))
+		return nil, &TokenError{
+			Error:            "access_denied",
+			ErrorDescription: "the user denied the authentication request",
+		}, nil
+	case DeviceAuthStatusTokenIssued:
+		return nil, &TokenError{
+			Error:            InvalidGrant,
+			ErrorDescription: "the auth_req_id has already been used",
+		}, nil
+	}
+
+	user, err := GetUser(cache.UserName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil || user.IsForbidden {
+		return nil, &TokenError{
+			Error:            InvalidGrant,
+			ErrorDescription: "the user is not found or is forbidden to sign in",
+		}, nil
+	}
+
+	// only the poll that takes the approved request out of the store gets a token, the
+	// concurrent polls see it as used
+	value, ok := DeviceAuthMap.LoadAndDelete(getCibaKey(authReqId))
+	if !ok || value.(DeviceAuthCache).Status != DeviceAuthStatusApproved {
+		return nil, &TokenError{
+			Error:            InvalidGrant,
+			ErrorDescription: "the auth_req_id has already been used",
+		}, nil
+	}
+	cache.Status = DeviceAuthStatusTokenIssued
+	DeviceAuthMap.Store(getCibaKey(authReqId), cache)
+	DeviceAuthMap.Delete(getCibaPollKey(authReqId))
+
+	token, err := GetTokenByUser(application, user, cache.Scope, "", host, nil, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return token, nil, nil
+}
+
+// checkCibaPollInterval records a poll of a pending CIBA request and returns false if it comes
+// before the interval has elapsed since the previous one, the interval is then increased by
+// CibaInterval seconds for the following polls.
+func checkCibaPollInterval(authReqId string, cache DeviceAuthCache) bool {
+	now := time.Now()
+	poll := DeviceAuthCache{
+		RequestAt: cache.RequestAt,
+		ExpiresIn: cache.ExpiresIn,
+		Interval:  CibaInterval,
+	}
+
+	ok := true
+	if value, found := DeviceAuthMap.Load(getCibaPollKey(authReqId)); found {
+		last := value.(DeviceAuthCache)
+		poll.Interval = last.Interval
+		if now.Before(last.LastPollAt.Add(time.Duration(last.Interval) * time.Second)) {
+			poll.Interval += CibaInterval
+			ok = false
+		}
+	}
+
+	poll.LastPollAt = now
+	DeviceAuthMap.Store(getCibaPollKey(authReqId), poll)
+	return ok
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetCibaTokenErrors(t *testing.T) {
+	application := &Application{ClientId: "client", ClientSecret: "secret"}
+	authReqId := "ciba-test"
+	DeviceAuthMap.Store(getCibaKey(authReqId), DeviceAuthCache{
+		UserName:  "built-in/alice",
+		ClientId:  "client",
+		RequestAt: time.Now(),
+		Status:    DeviceAuthStatusPending,
+		ExpiresIn: CibaExpiresIn,
+	})
+	defer DeviceAuthMap.Delete(getCibaKey(authReqId))
+
+	expectError := func(clientSecret string, authReqId string, expected string) {
+		t.Helper()
+		_, tokenError, err := GetCibaToken(application, clientSecret, authReqId, "localhost:8000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tokenError == nil || tokenError.Error != expected {
+			t.Errorf("expected error %s, got: %+v", expected, tokenError)
+		}
+	}
+
+	expectError("wrong", authReqId, InvalidClient)
+	expectError("secret", authReqId, "authorization_pending")
+	expectError("secret", "unknown", "expired_token")
+
+	if _, err := GetCibaRequestInfo(authReqId, "built-in/bob"); err == nil {
+		t.Error("a CIBA request must only be visible to its user")
+	}
+	if err := CompleteCibaAuthentication(authReqId, "built-in/bob", true); err == nil {
+		t.Error("a CIBA request must only be completed by its user")
+	}
+
+	cache, _ := getCibaCache(authReqId)
+	cache.Status = DeviceAuthStatusDenied
+	DeviceAuthMap.Store(getCibaKey(authReqId), cache)
+	expectError("secret", authReqId, "access_denied")
+	expectError("secret", authReqId, "expired_token")
+
+	cache.Status = DeviceAuthStatusPending
+	cache.RequestAt = time.Now().Add(-time.Hour)
+	DeviceAuthMap.Store(getCibaKey(authReqId), cache)
+	expectError("secret", authReqId, "expired_token")
+}
+
+func TestGetCibaTokenSlowDown(t *testing.T) {
+	application := &Application{ClientId: "client", ClientSecret: "secret"}
+	authReqId := "ciba-slow-down-test"
+	DeviceAuthMap.Store(getCibaKey(authReqId), DeviceAuthCache{
+		ClientId:  "client",
+		RequestAt: time.Now(),
+		Status:    DeviceAuthStatusPending,
+		ExpiresIn: CibaExpiresIn,
+	})
+	defer DeviceAuthMap.Delete(getCibaKey(authReqId))
+	defer DeviceAuthMap.Delete(getCibaPollKey(authReqId))
+
+	for i, expected := range []string{"authorization_pending", "slow_down", "slow_down"} {
+		_, tokenError, err := GetCibaToken(application, "secret", authReqId, "localhost:8000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tokenError == nil || tokenError.Error != expected {
+			t.Errorf("poll %d: expected error %s, got: %+v", i, expected, tokenError)
+		}
+	}
+
+	value, _ := DeviceAuthMap.Load(getCibaPollKey(authReqId))
+	if interval := value.(DeviceAuthCache).Interval; interval != 3*CibaInterval {
+		t.Errorf("the interval is %d after two slow_down errors", interval)
+	}
+}
+
+func TestGetCibaTokenIsIssuedOnce(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 20, "cert", "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&Cert{Owner: "admin", Name: "cert", Type: "x509", CryptoAlgorithm: "RS256", Certificate: certificate, PrivateKey: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ormer.Engine.Insert(&User{Owner: "org", Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := &Application{Owner: "admin", Name: "app", Organization: "org", Cert: "cert", ClientId: "client", ClientSecret: "secret", ExpireInHours: 1}
+	authReqId := "ciba-issued-once-test"
+	DeviceAuthMap.Store(getCibaKey(authReqId), DeviceAuthCache{
+		UserName:  "org/alice",
+		ClientId:  "client",
+		Scope:     "openid",
+		RequestAt: time.Now(),
+		Status:    DeviceAuthStatusApproved,
+		ExpiresIn: CibaExpiresIn,
+	})
+	defer DeviceAuthMap.Delete(getCibaKey(authReqId))
+
+	var wg sync.WaitGroup
+	var issued atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, tokenError, err := GetCibaToken(application, "secret", authReqId, "localhost:8000")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if token != nil && tokenError == nil {
+				issued.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := issued.Load(); n != 1 {
+		t.Errorf("%d tokens are issued for one auth_req_id", n)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/casdoor/casdoor/i18n"
+	"github.com/casdoor/casdoor/util"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	clientJwksCacheTtl = 10 * time.Minute
+	// an unknown kid forces the JWKS to be downloaded again, but not more often than this,
+	// so that request objects with made-up kids can't be used to flood the client
+	clientJwksMinRefreshInterval = time.Minute
+)
+
+// RequestObjectSigningAlgs are the algorithms accepted for signed request objects (RFC 9101).
+// The asymmetric ones are verified with the client's JWKS or ClientCert, the HMAC ones with
+// the client secret. Unsigned request objects ("none") are never accepted.
+var RequestObjectSigningAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "HS256", "HS384", "HS512"}
+
+type clientJwksCacheItem struct {
+	jwks      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+var (
+	clientJwksCache      = map[string]*clientJwksCacheItem{}
+	clientJwksCacheMutex sync.Mutex
+
+	// clientJwksHttpClient fetches the URIs registered by the clients, such as jwks_uri. They are
+	// registered anonymously by DCR, so only public addresses are connected to.
+	clientJwksHttpClient = newPublicHttpClient(10*time.Second, isPublicIp)
+)
+
+func isPublicIp(ip string) bool {
+	return util.IsInternetIp(ip) && !util.IsIntranetIp(ip)
+}
+
+// newPublicHttpClient returns a client that only connects to the addresses accepted by isAllowedIp.
+// The address is checked when the connection is made rather than when the URI is registered, so a
+// host can't be rebound to an internal address afterwards. Redirects and proxies are not used, as
+// they would connect to a host that isn't checked.
+func newPublicHttpClient(timeout time.Duration, isAllowedIp func(ip string) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !isAllowedIp(host) {
+				return fmt.Errorf("the address: %s is not a public address", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return fmt.Errorf("the redirect to: %s is not followed", req.URL.String())
+		},
+	}
+}
+
+// getClientJwks fetches the JWKS registered by a client, the result is cached so that the key
+// set is not downloaded for every request. forceRefresh bypasses the cache, it is used when a
+// kid is not found, which happens right after the client has rotated its keys.
+func getClientJwks(jwksUri string, forceRefresh bool) (*jose.JSONWebKeySet, error) {
+	clientJwksCacheMutex.Lock()
+	item, ok := clientJwksCache[jwksUri]
+	clientJwksCacheMutex.Unlock()
+	if ok {
+		age := time.Since(item.fetchedAt)
+		if age < clientJwksCacheTtl && (!forceRefresh || age < clientJwksMinRefreshInterval) {
+			return item.jwks, nil
+		}
+	}
+
+	resp, err := clientJwksHttpClient.Get(jwksUri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the JWKS from %s, status code: %d", jwksUri, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := &jose.JSONWebKeySet{}
+	err = json.Unmarshal(data, jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	clientJwksCacheMutex.Lock()
+	clientJwksCache[jwksUri] = &clientJwksCacheItem{jwks: jwks, fetchedAt: time.Now()}
+	clientJwksCacheMutex.Unlock()
+	return jwks, nil
+}
+
+func findJwksKey(jwks *jose.JSONWebKeySet, kid string) interface{} {
+	if kid != "" {
+		keys := jwks.Key(kid)
+		if len(keys) == 0 {
+			return nil
+		}
+		return keys[0].Public().Key
+	}
+
+	// Without a kid the key set must be unambiguous
+	if len(jwks.Keys) == 1 {
+		return jwks.Keys[0].Public().Key
+	}
+	return nil
+}
+
+// getClientVerificationKey returns the public key of the client for a JWT signed by the client,
+// it is taken from the client's JWKS when one is registered, otherwise from its ClientCert.
+func getClientVerificationKey(application *Application, token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if application.ClientSecret == "" {
+			return nil, fmt.Errorf("the client secret is empty for application: [%s]", application.GetId())
+		}
+		return []byte(application.ClientSecret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if application.ClientJwksUri != "" {
+		jwks, err := getClientJwks(application.ClientJwksUri, false)
+		if err != nil {
+			return nil, err
+		}
+
+		key := findJwksKey(jwks, kid)
+		if key == nil {
+			jwks, err = getClientJwks(application.ClientJwksUri, true)
+			if err != nil {
+				return nil, err
+			}
+			key = findJwksKey(jwks, kid)
+		}
+		if key == nil {
+			return nil, fmt.Errorf("the key: %s is not found in the JWKS of application: [%s]", kid, application.GetId())
+		}
+		return key, nil
+	}
+
+	clientCert, err := getCert(application.Owner, application.ClientCert)
+	if err != nil {
+		return nil, err
+	}
+	if clientCert == nil || clientCert.Certificate == "" {
+		return nil, fmt.Errorf("client certificate is not configured for application: [%s]", application.GetId())
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		return jwt.ParseRSAPublicKeyFromPEM([]byte(clientCert.Certificate))
+	case *jwt.SigningMethodECDSA:
+		return jwt.ParseECPublicKeyFromPEM([]byte(clientCert.Certificate))
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+}
+
+// ParseRequestObject verifies a signed request object (JAR) sent by the client and returns the
+// authorization request parameters it carries.
+// Refs: https://datatracker.ietf.org/doc/html/rfc9101
+func ParseRequestObject(application *Application, requestObject string, host string) (map[string]string, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(requestObject, claims, func(token *jwt.Token) (interface{}, error) {
+		return getClientVerificationKey(application, token)
+	}, jwt.WithValidMethods(RequestObjectSigningAlgs))
+	if err != nil {
+		return nil, fmt.Errorf("the request object is invalid: %s", err.Error())
+	}
+
+	// the iss and aud claims are required so that a request object can't be replayed
+	// by another client or at another authorization server
+	if iss, _ := claims["iss"].(string); iss != application.ClientId {
+		return nil, fmt.Errorf("the issuer of the request object should be the client_id")
+	}
+	if clientId, ok := claims["client_id"]; ok && clientId != application.ClientId {
+		return nil, fmt.Errorf("the client_id of the request object doesn't match the client")
+	}
+
+	_, originBackend := getOriginFromHost(host)
+	audience, err := claims.GetAudience()
+	if err != nil {
+		return nil, err
+	}
+	if !util.InSlice(audience, originBackend) {
+		return nil, fmt.Errorf("the audience of the request object should be: %s", originBackend)
+	}
+
+	params := map[string]string{}
+	for _, name := range PushedAuthRequestParams {
+		switch value := claims[name].(type) {
+		case string:
+			params[name] = value
+		case float64:
+			params[name] = strconv.FormatInt(int64(value), 10)
+		}
+	}
+	return params, nil
+}
+
+// PushRequestObject turns a signed request object sent through the front channel into an
+// activated pushed authorization request. The login page then works with the request_uri and
+// the code is only issued for the signed parameters, which override the query ones. It isn't
+// a pushed request, so it's refused for the applications that require PAR.
+func PushRequestObject(clientId string, requestObject string, queryParams map[string]string, host string, lang string) (string, map[string]string, error) {
+	application, err := GetApplicationByClientId(clientId)
+	if err != nil {
+		return "", nil, err
+	}
+	if application == nil {
+		return "", nil, fmt.Errorf("the client_id: %s is invalid", clientId)
+	}
+	if application.RequirePushedAuthorizationRequests {
+		return "", nil, fmt.Errorf("%s", i18n.Translate(lang, "token:Pushed authorization request is required for this application"))
+	}
+
+	signedParams, err := ParseRequestObject(application, requestObject, host)
+	if err != nil {
+		return "", nil, err
+	}
+
+	params := map[string]string{}
+	for _, name := range PushedAuthRequestParams {
+		if value := queryParams[name]; value != "" {
+			params[name] = value
+		}
+	}
+	for name, value := range signedParams {
+		params[name] = value
+	}
+
+	requestUri, tokenError, err := storePushedAuthRequest(application, params, lang)
+	if err != nil {
+		return "", nil, err
+	}
+	if tokenError != nil {
+		return "", nil, fmt.Errorf("%s", tokenError.ErrorDescription)
+	}
+
+	request, err := ActivatePushedAuthRequest(application.ClientId, requestUri)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return requestUri, request.Params, nil
+}
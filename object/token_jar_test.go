@@ -0,0 +1,210 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// allowLoopbackClientJwks lets the client URIs be fetched from the test servers, which listen on
+// the loopback address that clientJwksHttpClient refuses.
+func allowLoopbackClientJwks(t *testing.T) {
+	client := clientJwksHttpClient
+	clientJwksHttpClient = newPublicHttpClient(10*time.Second, func(ip string) bool { return true })
+	t.Cleanup(func() { clientJwksHttpClient = client })
+}
+
+func TestClientJwksHttpClient(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{})
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	// the loopback address is refused when connecting, also when a host name resolves to it
+	localhostUrl := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	for _, uri := range []string{target.URL, localhostUrl} {
+		if _, err := getClientJwks(uri, true); err == nil || !strings.Contains(err.Error(), "not a public address") {
+			t.Errorf("the JWKS is fetched from the loopback address: %s: %v", uri, err)
+		}
+	}
+
+	allowLoopbackClientJwks(t)
+	if _, err := getClientJwks(target.URL, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getClientJwks(redirect.URL, true); err == nil || !strings.Contains(err.Error(), "redirect") {
+		t.Errorf("the redirect of the jwks_uri is followed: %v", err)
+	}
+}
+
+func TestParseRequestObjectWithClientSecret(t *testing.T) {
+	application := &Application{Owner: "admin", Name: "app", ClientId: "client", ClientSecret: "secret"}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":          "client",
+		"aud":          "http://localhost:8000",
+		"client_id":    "client",
+		"redirect_uri": "https://app.example.com/callback",
+		"max_age":      300,
+	})
+	requestObject, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := ParseRequestObject(application, requestObject, "localhost:8000")
+	if err != nil {
+		t.Fatalf("ParseRequestObject failed: %v", err)
+	}
+	if params["redirect_uri"] != "https://app.example.com/callback" || params["max_age"] != "300" {
+		t.Errorf("unexpected params: %v", params)
+	}
+
+	application.ClientSecret = "another-secret"
+	if _, err = ParseRequestObject(application, requestObject, "localhost:8000"); err == nil {
+		t.Error("a request object signed with another secret should be rejected")
+	}
+}
+
+func TestParseRequestObjectWithClientJwks(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "key-1", Algorithm: "PS256", Use: "sig"}}}
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	defer server.Close()
+	allowLoopbackClientJwks(t)
+
+	application := &Application{Owner: "admin", Name: "app", ClientId: "client", ClientJwksUri: server.URL}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.MapClaims{
+		"iss":       "client",
+		"aud":       "http://localhost:8000",
+		"client_id": "client",
+		"scope":     "openid",
+	})
+	token.Header["kid"] = "key-1"
+	requestObject, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := ParseRequestObject(application, requestObject, "localhost:8000")
+	if err != nil {
+		t.Fatalf("ParseRequestObject failed: %v", err)
+	}
+	if params["scope"] != "openid" {
+		t.Errorf("unexpected params: %v", params)
+	}
+
+	token = jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.MapClaims{"iss": "another-client"})
+	token.Header["kid"] = "key-1"
+	requestObject, err = token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseRequestObject(application, requestObject, "localhost:8000"); err == nil {
+		t.Error("a request object issued by another client should be rejected")
+	}
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"iss": "client"})
+	requestObject, err = unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseRequestObject(application, requestObject, "localhost:8000"); err == nil {
+		t.Error("an unsigned request object should be rejected")
+	}
+}
+
+func TestParseRequestObjectRequiresIssuerAndAudience(t *testing.T) {
+	application := &Application{Owner: "admin", Name: "app", ClientId: "client", ClientSecret: "secret"}
+
+	claimsList := []jwt.MapClaims{
+		{"aud": "http://localhost:8000", "scope": "openid"},
+		{"iss": "client", "scope": "openid"},
+		{"iss": "client", "aud": "http://another-server", "scope": "openid"},
+	}
+	for _, claims := range claimsList {
+		requestObject, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ParseRequestObject(application, requestObject, "localhost:8000"); err == nil {
+			t.Errorf("the request object with the claims %v is accepted", claims)
+		}
+	}
+}
+
+func TestGetClientJwksThrottlesRefresh(t *testing.T) {
+	var fetchCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetchCount, 1)
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{})
+	}))
+	defer server.Close()
+	allowLoopbackClientJwks(t)
+
+	for i := 0; i < 5; i++ {
+		if _, err := getClientJwks(server.URL, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count := atomic.LoadInt32(&fetchCount); count != 1 {
+		t.Errorf("the JWKS is fetched %d times for the unknown kids, want 1", count)
+	}
+}
+
+func TestPushRequestObjectRequiresPar(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	application := &Application{Owner: "admin", Name: "app", Organization: "built-in", ClientId: "client", ClientSecret: "secret", RequirePushedAuthorizationRequests: true}
+	if _, err := ormer.Engine.Insert(application); err != nil {
+		t.Fatal(err)
+	}
+
+	requestObject, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":           "client",
+		"aud":           "http://localhost:8000",
+		"response_type": "code",
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = PushRequestObject("client", requestObject, map[string]string{}, "localhost:8000", "en")
+	if err == nil || !strings.Contains(err.Error(), "Pushed authorization request is required") {
+		t.Errorf("a front-channel request object is accepted for an application requiring PAR: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/casdoor/casdoor/util"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultEncryptionEnc is the content encryption used when only the alg is configured,
+// as defined in OpenID Connect Dynamic Client Registration 1.0 Section 2.
+const defaultEncryptionEnc = "A128CBC-HS256"
+
+// EncryptionAlgs and EncryptionEncs are the JWE algorithms supported to encrypt ID tokens and
+// userinfo responses to the public key of the client.
+var (
+	EncryptionAlgs = []string{"RSA-OAEP", "RSA-OAEP-256", "ECDH-ES", "ECDH-ES+A128KW", "ECDH-ES+A256KW"}
+	EncryptionEncs = []string{"A128CBC-HS256", "A256CBC-HS512", "A128GCM", "A256GCM"}
+)
+
+// CheckEncryptionSetting validates an alg/enc pair of the client metadata, enc can't be set alone.
+func CheckEncryptionSetting(alg string, enc string) error {
+	if alg == "" {
+		if enc != "" {
+			return fmt.Errorf("the encryption enc: %s is set without an alg", enc)
+		}
+		return nil
+	}
+
+	if !util.InSlice(EncryptionAlgs, alg) {
+		return fmt.Errorf("the encryption alg: %s is not supported", alg)
+	}
+	if enc != "" && !util.InSlice(EncryptionEncs, enc) {
+		return fmt.Errorf("the encryption enc: %s is not supported", enc)
+	}
+	return nil
+}
+
+func isEncryptionKeyUsable(key *jose.JSONWebKey, alg string) bool {
+	if key.Use != "" && key.Use != "enc" {
+		return false
+	}
+	if key.Algorithm != "" && key.Algorithm != alg {
+		return false
+	}
+
+	switch key.Public().Key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RSA-")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ECDH-ES")
+	default:
+		return false
+	}
+}
+
+// getClientEncryptionKey returns the public key of the client to encrypt to, it is taken from the
+// client's JWKS when one is registered, otherwise from its ClientCert.
+func getClientEncryptionKey(application *Application, alg string) (*jose.JSONWebKey, error) {
+	if application.ClientJwksUri != "" {
+		jwks, err := getClientJwks(application.ClientJwksUri, false)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range jwks.Keys {
+			if isEncryptionKeyUsable(&key, alg) {
+				publicKey := key.Public()
+				return &publicKey, nil
+			}
+		}
+		return nil, fmt.Errorf("no encryption key for alg: %s is found in the JWKS of application: [%s]", alg, application.GetId())
+	}
+
+	clientCert, err := getCert(application.Owner, application.ClientCert)
+	if err != nil {
+		return nil, err
+	}
+	if clientCert == nil || clientCert.Certificate == "" {
+		return nil, fmt.Errorf("client certificate is not configured for application: [%s]", application.GetId())
+	}
+
+	certDerBlock, _ := pem.Decode([]byte(clientCert.Certificate))
+	if certDerBlock == nil {
+		return nil, fmt.Errorf("failed to decode the client certificate of application: [%s]", application.GetId())
+	}
+	x509Cert, err := x509.ParseCertificate(certDerBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &jose.JSONWebKey{Key: x509Cert.PublicKey}
+	if !isEncryptionKeyUsable(key, alg) {
+		return nil, fmt.Errorf("the client certificate of application: [%s] can't be used with alg: %s", application.GetId(), alg)
+	}
+	return key, nil
+}
+
+func encryptForClient(application *Application, alg string, enc string, payload []byte, contentType jose.ContentType) (string, error) {
+	if enc == "" {
+		enc = defaultEncryptionEnc
+	}
+
+	key, err := getClientEncryptionKey(application, alg)
+	if err != nil {
+		return "", err
+	}
+
+	options := &jose.EncrypterOptions{}
+	if contentType != "" {
+		options = options.WithContentType(contentType)
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.ContentEncryption(enc), jose.Recipient{
+		Algorithm: jose.KeyAlgorithm(alg),
+		Key:       key.Key,
+		KeyID:     key.KeyID,
+	}, options)
+	if err != nil {
+		return "", err
+	}
+
+	object, err := encrypter.Encrypt(payload)
+	if err != nil {
+		return "", err
+	}
+	return object.CompactSerialize()
+}
+
+// EncryptIdToken returns the ID token for the client, it is nested in a JWE when the client has
+// registered id_token_encrypted_response_alg, otherwise it is returned unchanged.
+// Refs: https://openid.net/specs/openid-connect-core-1_0.html#Encryption
+func EncryptIdToken(application *Application, idToken string) (string, error) {
+	if application == nil || application.IdTokenEncryptedResponseAlg == "" {
+		return idToken, nil
+	}
+
+	return encryptForClient(application, application.IdTokenEncryptedResponseAlg, application.IdTokenEncryptedResponseEnc, []byte(idToken), "JWT")
+}
+
+// getIdTokenForClient returns the ID token of a token returned by the token endpoint. When the ID
+// token is encrypted, the same JWT mustn't be returned in plaintext as the access token, so the ID
+// token is signed again with its own jti. It keeps the other claims of the access token, which is
+// built in the TokenFormat of the application and may be bound to the client certificate.
+func getIdTokenForClient(application *Application, token *Token) (string, error) {
+	if application.IdTokenEncryptedResponseAlg == "" {
+		return token.AccessToken, nil
+	}
+
+	cert, err := getCertByApplication(application)
+	if err != nil {
+		return "", err
+	}
+	if cert == nil {
+		return "", fmt.Errorf("The cert \"%s\" does not exist", application.Cert)
+	}
+
+	idToken, err := resignJwtToken(token.AccessToken, cert.PrivateKey, func(claims jwt.MapClaims) {
+		claims["jti"] = util.GetId(token.Owner, util.GenerateId())
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return EncryptIdToken(application, idToken)
+}
+
+// EncryptUserinfo returns the userinfo response as a JWE when the client has registered
+// userinfo_encrypted_response_alg, the second return value tells whether it is encrypted.
+func EncryptUserinfo(application *Application, userinfo interface{}) (string, bool, error) {
+	if application == nil || application.UserinfoEncryptedResponseAlg == "" {
+		return "", false, nil
+	}
+
+	payload, err := json.Marshal(userinfo)
+	if err != nil {
+		return "", false, err
+	}
+
+	res, err := encryptForClient(application, application.UserinfoEncryptedResponseAlg, application.UserinfoEncryptedResponseEnc, payload, "")
+	if err != nil {
+		return "", false, err
+	}
+	return res, true, nil
+}
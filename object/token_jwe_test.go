@@ -0,0 +1,247 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/casdoor/casdoor/util"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestEncryptIdToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &rsaKey.PublicKey, KeyID: "sig-1", Use: "sig"},
+			{Key: &rsaKey.PublicKey, KeyID: "enc-rsa", Use: "enc"},
+			{Key: &ecKey.PublicKey, KeyID: "enc-ec", Use: "enc"},
+		}}
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	defer server.Close()
+	allowLoopbackClientJwks(t)
+
+	application := &Application{Owner: "admin", Name: "app-jwe", ClientJwksUri: server.URL}
+	idToken, err := EncryptIdToken(application, "header.payload.signature")
+	if err != nil || idToken != "header.payload.signature" {
+		t.Fatalf("the ID token should be unchanged without encryption settings, got: %s, %v", idToken, err)
+	}
+
+	cases := []struct {
+		alg        string
+		enc        string
+		kid        string
+		privateKey interface{}
+	}{
+		{"RSA-OAEP-256", "A256GCM", "enc-rsa", rsaKey},
+		{"ECDH-ES", "A256GCM", "enc-ec", ecKey},
+		{"RSA-OAEP", "", "enc-rsa", rsaKey},
+	}
+	for _, c := range cases {
+		application.IdTokenEncryptedResponseAlg = c.alg
+		application.IdTokenEncryptedResponseEnc = c.enc
+
+		idToken, err = EncryptIdToken(application, "header.payload.signature")
+		if err != nil {
+			t.Fatalf("EncryptIdToken with %s failed: %v", c.alg, err)
+		}
+
+		object, err := jose.ParseEncrypted(idToken, []jose.KeyAlgorithm{jose.KeyAlgorithm(c.alg)}, []jose.ContentEncryption{jose.A256GCM, jose.A128CBC_HS256})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if object.Header.KeyID != c.kid || object.Header.ExtraHeaders["cty"] != "JWT" {
+			t.Errorf("unexpected JWE header: %+v", object.Header)
+		}
+
+		plaintext, err := object.Decrypt(c.privateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(plaintext) != "header.payload.signature" {
+			t.Errorf("unexpected plaintext: %s", plaintext)
+		}
+	}
+}
+
+func TestCheckEncryptionSetting(t *testing.T) {
+	if err := CheckEncryptionSetting("", ""); err != nil {
+		t.Error(err)
+	}
+	if err := CheckEncryptionSetting("RSA-OAEP-256", "A256GCM"); err != nil {
+		t.Error(err)
+	}
+	if err := CheckEncryptionSetting("", "A256GCM"); err == nil {
+		t.Error("enc without alg should be rejected")
+	}
+	if err := CheckEncryptionSetting("RSA1_5", ""); err == nil {
+		t.Error("RSA1_5 should not be supported")
+	}
+}
+
+func TestGetIdTokenForClient(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	encryptionKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &encryptionKey.PublicKey, KeyID: "enc-rsa", Use: "enc"}}}
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	defer server.Close()
+	allowLoopbackClientJwks(t)
+
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 20, "cert", "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &Cert{Owner: "admin", Name: "cert", Type: "x509", CryptoAlgorithm: "RS256", Certificate: certificate, PrivateKey: privateKey}
+	_, err = ormer.Engine.Insert(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCert, _ := newTestCertificate(t, "client", false, nil, nil)
+
+	cases := []struct {
+		name        string
+		tokenFormat string
+		clientCert  *x509.Certificate
+		claim       string
+		hasClaim    bool
+	}{
+		{"jwt", "JWT", nil, "phone", true},
+		{"jwt-empty-mtls", "JWT-Empty", clientCert, "address", false},
+		{"jwt-standard-mtls", "JWT-Standard", clientCert, "preferred_username", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			application := &Application{
+				Owner:                                 "admin",
+				Name:                                  "app-jwe",
+				Organization:                          "org",
+				Cert:                                  "cert",
+				ClientId:                              "client",
+				ExpireInHours:                         1,
+				TokenFormat:                           c.tokenFormat,
+				ClientJwksUri:                         server.URL,
+				IdTokenEncryptedResponseAlg:           "RSA-OAEP-256",
+				TlsClientCertificateBoundAccessTokens: c.clientCert != nil,
+			}
+			user := &User{Owner: "org", Name: "alice", Email: "alice@example.com", Phone: "12345678", Address: []string{"street"}}
+			accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "nonce", "openid", "", "localhost:8000", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token := &Token{Owner: "admin", Name: tokenName, Application: "app-jwe", Organization: "org", User: "alice", AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: 3600}
+			_, err = AddToken(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tokenError, err := bindTokenToClientCert(application, token, c.clientCert)
+			if err != nil || tokenError != nil {
+				t.Fatalf("failed to bind the token: %v, %v", tokenError, err)
+			}
+			boundToken := token.AccessToken
+
+			idToken, err := getIdTokenForClient(application, token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.AccessToken != boundToken {
+				t.Fatalf("the access token is changed by the encryption of the ID token")
+			}
+
+			object, err := jose.ParseEncrypted(idToken, []jose.KeyAlgorithm{jose.RSA_OAEP_256}, []jose.ContentEncryption{jose.A128CBC_HS256})
+			if err != nil {
+				t.Fatal(err)
+			}
+			plaintext, err := object.Decrypt(encryptionKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(plaintext) == token.AccessToken {
+				t.Fatalf("the encrypted ID token is returned in plaintext as the access token")
+			}
+
+			accessClaims := parseTestJwtClaims(t, token.AccessToken, cert)
+			idClaims := parseTestJwtClaims(t, string(plaintext), cert)
+
+			// the ID token only differs from the access token by its jti
+			if accessClaims["jti"] != util.GetId("admin", tokenName) || idClaims["jti"] == accessClaims["jti"] {
+				t.Errorf("unexpected jti of the access token: %v and the ID token: %v", accessClaims["jti"], idClaims["jti"])
+			}
+			delete(accessClaims, "jti")
+			delete(idClaims, "jti")
+			if !reflect.DeepEqual(accessClaims, idClaims) {
+				t.Errorf("the claims of the ID token: %v differ from the access token: %v", idClaims, accessClaims)
+			}
+
+			// the access token keeps the TokenFormat of the application and the cnf of the certificate
+			_, hasClaim := accessClaims[c.claim]
+			if hasClaim != c.hasClaim {
+				t.Errorf("the access token isn't built in the TokenFormat: %s: %v", c.tokenFormat, accessClaims)
+			}
+			cnf, _ := accessClaims["cnf"].(map[string]interface{})
+			if c.clientCert != nil && (cnf == nil || cnf["x5t#S256"] != GetCertThumbprint(c.clientCert)) {
+				t.Errorf("the access token isn't bound to the client certificate: %v", accessClaims)
+			}
+
+			stored, err := GetTokenByAccessToken(token.AccessToken)
+			if err != nil || stored == nil || stored.Name != tokenName {
+				t.Errorf("the access token isn't saved: %v, %v", stored, err)
+			}
+		})
+	}
+}
+
+func parseTestJwtClaims(t *testing.T, tokenString string, cert *Cert) jwt.MapClaims {
+	t.Helper()
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cert.Certificate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token.Claims.(jwt.MapClaims)
+}
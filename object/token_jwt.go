@@ -36,6 +36,10 @@ type Claims struct {
 	Provider string `json:"provider,omitempty"`
 
 	SigninMethod string `json:"signinMethod,omitempty"`
+
+	Acr      string           `json:"acr,omitempty"`
+	Amr      []string         `json:"amr,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -160,6 +164,10 @@ type ClaimsShort struct {
 	Provider  string `json:"provider,omitempty"`
 
 	SigninMethod string `json:"signinMethod,omitempty"`
+
+	Acr      string           `json:"acr,omitempty"`
+	Amr      []string         `json:"amr,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -182,6 +190,10 @@ type ClaimsWithoutThirdIdp struct {
 	Provider  string `json:"provider,omitempty"`
 
 	SigninMethod string `json:"signinMethod,omitempty"`
+
+	Acr      string           `json:"acr,omitempty"`
+	Amr      []string         `json:"amr,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -315,6 +327,9 @@ func getShortClaims(claims Claims) ClaimsShort {
 		Azp:              claims.Azp,
 		SigninMethod:     claims.SigninMethod,
 		Provider:         claims.Provider,
+		Acr:              claims.Acr,
+		Amr:              claims.Amr,
+		AuthTime:         claims.AuthTime,
 	}
 	return res
 }
@@ -330,6 +345,9 @@ func getClaimsWithoutThirdIdp(claims Claims) ClaimsWithoutThirdIdp {
 		Azp:                 claims.Azp,
 		SigninMethod:        claims.SigninMethod,
 		Provider:            claims.Provider,
+		Acr:                 claims.Acr,
+		Amr:                 claims.Amr,
+		AuthTime:            claims.AuthTime,
 	}
 	return res
 }
@@ -400,6 +418,15 @@ func getClaimsCustom(claims Claims, tokenField []string, tokenAttributes []*JwtI
 		res["azp"] = claims.Azp
 	}
 
+	// Always include the authentication context if present
+	if claims.Acr != "" {
+		res["acr"] = claims.Acr
+		res["amr"] = claims.Amr
+	}
+	if claims.AuthTime != nil {
+		res["auth_time"] = claims.AuthTime
+	}
+
 	// Always include nonce and scope as they are built-in OAuth/OIDC fields (even if empty)
 	res["nonce"] = claims.Nonce
 	res["scope"] = claims.Scope
@@ -505,7 +532,39 @@ func refineUser(user *User) *User {
 	return user
 }
 
-func generateJwtToken(application *Application, user *User, provider string, signinMethod string, nonce string, scope string, resource string, host string) (string, string, string, error) {
+func getJwtSigningMethod(application *Application) jwt.SigningMethod {
+	if application.TokenSigningMethod == "RS256" {
+		return jwt.SigningMethodRS256
+	} else if application.TokenSigningMethod == "RS512" {
+		return jwt.SigningMethodRS512
+	} else if application.TokenSigningMethod == "ES256" {
+		return jwt.SigningMethodES256
+	} else if application.TokenSigningMethod == "ES512" {
+		return jwt.SigningMethodES512
+	} else if application.TokenSigningMethod == "ES384" {
+		return jwt.SigningMethodES384
+	} else {
+		return jwt.SigningMethodRS256
+	}
+}
+
+func getJwtSigningKey(application *Application, cert *Cert) (interface{}, error) {
+	var key interface{}
+	var err error
+	if strings.Contains(application.TokenSigningMethod, "RS") || application.TokenSigningMethod == "" {
+		// RSA private key
+		key, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(cert.PrivateKey))
+	} else if strings.Contains(application.TokenSigningMethod, "ES") {
+		// ES private key
+		key, err = jwt.ParseECPrivateKeyFromPEM([]byte(cert.PrivateKey))
+	} else if strings.Contains(application.TokenSigningMethod, "Ed") {
+		// Ed private key
+		key, err = jwt.ParseEdPrivateKeyFromPEM([]byte(cert.PrivateKey))
+	}
+	return key, err
+}
+
+func generateJwtToken(application *Application, user *User, provider string, signinMethod string, nonce string, scope string, resource string, host string, authContext *AuthContext) (string, string, string, error) {
 	nowTime := time.Now()
 	expireTime := nowTime.Add(time.Duration(application.ExpireInHours * float64(time.Hour)))
 	refreshExpireTime := nowTime.Add(time.Duration(application.RefreshExpireInHours * float64(time.Hour)))
@@ -538,6 +597,9 @@ func generateJwtToken(application *Application, user *User, provider string, sig
 		Azp:          application.ClientId,
 		Provider:     provider,
 		SigninMethod: signinMethod,
+		Acr:          authContext.GetAcr(),
+		Amr:          authContext.GetAmr(),
+		AuthTime:     authContext.GetAuthTime(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    originBackend,
 			Subject:   user.Id,
@@ -563,21 +625,7 @@ func generateJwtToken(application *Application, user *User, provider string, sig
 		application.TokenFormat = "JWT"
 	}
 
-	var jwtMethod jwt.SigningMethod
-
-	if application.TokenSigningMethod == "RS256" {
-		jwtMethod = jwt.SigningMethodRS256
-	} else if application.TokenSigningMethod == "RS512" {
-		jwtMethod = jwt.SigningMethodRS512
-	} else if application.TokenSigningMethod == "ES256" {
-		jwtMethod = jwt.SigningMethodES256
-	} else if application.TokenSigningMethod == "ES512" {
-		jwtMethod = jwt.SigningMethodES512
-	} else if application.TokenSigningMethod == "ES384" {
-		jwtMethod = jwt.SigningMethodES384
-	} else {
-		jwtMethod = jwt.SigningMethodRS256
-	}
+	jwtMethod := getJwtSigningMethod(application)
 
 	// the JWT token length in "JWT-Empty" mode will be very short, as User object only has two properties: owner and name
 	if application.TokenFormat == "JWT" {
@@ -629,24 +677,14 @@ func generateJwtToken(application *Application, user *User, provider string, sig
 	var (
 		tokenString        string
 		refreshTokenString string
-		key                interface{}
 	)
 
-	if strings.Contains(application.TokenSigningMethod, "RS") || application.TokenSigningMethod == "" {
-		// RSA private key
-		key, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(cert.PrivateKey))
-	} else if strings.Contains(application.TokenSigningMethod, "ES") {
-		// ES private key
-		key, err = jwt.ParseECPrivateKeyFromPEM([]byte(cert.PrivateKey))
-	} else if strings.Contains(application.TokenSigningMethod, "Ed") {
-		// Ed private key
-		key, err = jwt.ParseEdPrivateKeyFromPEM([]byte(cert.PrivateKey))
-	}
+	key, err := getJwtSigningKey(application, cert)
 	if err != nil {
 		return "", "", "", err
 	}
 
-	token.Header["kid"] = cert.Name
+	token.Header["kid"] = cert.GetKeyId()
 	tokenString, err = token.SignedString(key)
 	if err != nil {
 		return "", "", "", err
@@ -656,6 +694,37 @@ func generateJwtToken(application *Application, user *User, provider string, sig
 	return tokenString, refreshTokenString, name, err
 }
 
+// resignJwtToken signs the token again after its claims are changed by update, the header and the
+// signing method are kept unchanged.
+func resignJwtToken(tokenString string, privateKey string, update func(claims jwt.MapClaims)) (string, error) {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return "", err
+	}
+
+	var key interface{}
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		key, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKey))
+	case *jwt.SigningMethodECDSA:
+		key, err = jwt.ParseECPrivateKeyFromPEM([]byte(privateKey))
+	case *jwt.SigningMethodEd25519:
+		key, err = jwt.ParseEdPrivateKeyFromPEM([]byte(privateKey))
+	default:
+		return "", fmt.Errorf("the signing method: %s is not supported", token.Method.Alg())
+	}
+	if err != nil {
+		return "", err
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	update(claims)
+
+	newToken := jwt.NewWithClaims(token.Method, claims)
+	newToken.Header = token.Header
+	return newToken.SignedString(key)
+}
+
 func ParseJwtTokenWithoutValidation(token string) (*jwt.Token, error) {
 	t, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
 	if err != nil {
@@ -676,12 +745,19 @@ func ParseJwtToken(token string, cert *Cert) (*Claims, error) {
 			return nil, fmt.Errorf("the certificate field should not be empty for the cert: %v", cert)
 		}
 
+		// Select the key by kid, the token may be signed by a key that has been rotated
+		kid, _ := token.Header["kid"].(string)
+		pemCertificate, err := cert.getVerificationCertificate(kid)
+		if err != nil {
+			return nil, err
+		}
+
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); ok {
 			// RSA certificate
-			certificate, err = jwt.ParseRSAPublicKeyFromPEM([]byte(cert.Certificate))
+			certificate, err = jwt.ParseRSAPublicKeyFromPEM([]byte(pemCertificate))
 		} else if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
 			// ES certificate
-			certificate, err = jwt.ParseECPublicKeyFromPEM([]byte(cert.Certificate))
+			certificate, err = jwt.ParseECPublicKeyFromPEM([]byte(pemCertificate))
 		} else {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
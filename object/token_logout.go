@@ -56,6 +56,12 @@ func generateLogoutToken(application *Application, user *User, sessionId string,
 		claims.Sid = sessionId
 	}
 
+	return signEventTokenByApplication(application, claims, "")
+}
+
+// signEventTokenByApplication signs a logout token or a security event token with the cert of the
+// application, typ is left as "JWT" when it is empty
+func signEventTokenByApplication(application *Application, claims jwt.Claims, typ string) (string, error) {
 	cert, err := getCertByApplication(application)
 	if err != nil {
 		return "", err
@@ -81,14 +87,20 @@ func generateLogoutToken(application *Application, user *User, sessionId string,
 		return "", err
 	}
 
-	token.Header["kid"] = cert.Name
+	token.Header["kid"] = cert.GetKeyId()
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
 	return token.SignedString(key)
 }
 
 // SendBackchannelLogout sends OIDC Back-Channel Logout tokens to all registered
 // backchannel_logout_uri endpoints for applications that have active tokens for the user.
 // See https://openid.net/specs/openid-connect-backchannel-1_0.html
+// The SAML service providers the user has signed in to receive a LogoutRequest too, see SendSamlLogout.
 func SendBackchannelLogout(organization, username, sessionId, host string) {
+	SendSamlLogout(organization, username, sessionId, host)
+
 	tokens, err := GetActiveTokensByUser(organization, username)
 	if err != nil || len(tokens) == 0 {
 		return
@@ -0,0 +1,286 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/casdoor/casdoor/conf"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/xorm-io/core"
+)
+
+// The client authentication methods of OAuth 2.0 Mutual-TLS (RFC 8705 Section 2).
+const (
+	TlsClientAuth           = "tls_client_auth"
+	SelfSignedTlsClientAuth = "self_signed_tls_client_auth"
+)
+
+// GetCertThumbprint returns the base64url-encoded SHA-256 thumbprint of the DER encoding of a
+// certificate, it is the x5t#S256 confirmation method of RFC 8705 Section 3.1.
+func GetCertThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ParseClientCertHeader parses the client certificate forwarded by a TLS-terminating proxy. The
+// value is a PEM certificate that may be URL-encoded (e.g. nginx $ssl_client_escaped_cert), or a
+// base64-encoded DER certificate.
+func ParseClientCertHeader(value string) (*x509.Certificate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("the client certificate header is empty")
+	}
+
+	if strings.Contains(value, "%") {
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		value = unescaped
+	}
+
+	if strings.Contains(value, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(value))
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode the client certificate header")
+		}
+		return x509.ParseCertificate(block.Bytes)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the client certificate header: %s", err.Error())
+	}
+	return x509.ParseCertificate(der)
+}
+
+// isTrustedMtlsProxy tells whether the client certificate header can be accepted from the remote
+// address. The proxies are listed in mtlsTrustedProxies as IPs or CIDRs, only the loopback
+// addresses are trusted when none is configured.
+func isTrustedMtlsProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	trustedProxies := conf.GetConfigString("mtlsTrustedProxies")
+	if trustedProxies == "" {
+		return ip.IsLoopback()
+	}
+
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			_, ipNet, err := net.ParseCIDR(proxy)
+			if err == nil && ipNet.Contains(ip) {
+				return true
+			}
+		} else if proxyIp := net.ParseIP(proxy); proxyIp != nil && proxyIp.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRequestClientCert returns the certificate that the client presented in the TLS handshake, or
+// the one forwarded in the mtlsClientCertHeader header by a trusted proxy that terminates TLS.
+func GetRequestClientCert(req *http.Request) *x509.Certificate {
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		return req.TLS.PeerCertificates[0]
+	}
+
+	headerName := conf.GetConfigString("mtlsClientCertHeader")
+	if headerName == "" {
+		return nil
+	}
+
+	value := req.Header.Get(headerName)
+	if value == "" || !isTrustedMtlsProxy(req.RemoteAddr) {
+		return nil
+	}
+
+	cert, err := ParseClientCertHeader(value)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
+
+func parseCertificateChain(certificate string) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	rest := []byte(certificate)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate is found")
+	}
+	return certs, nil
+}
+
+// checkTlsClientCert matches the presented certificate against the registered certificates. For
+// self_signed_tls_client_auth the presented certificate must be one of them, for tls_client_auth
+// it must chain to one of them and have the registered subject DN if one is configured.
+func checkTlsClientCert(method string, subjectDn string, registeredCerts []*x509.Certificate, clientCert *x509.Certificate) error {
+	switch method {
+	case SelfSignedTlsClientAuth:
+		now := time.Now()
+		if now.Before(clientCert.NotBefore) || now.After(clientCert.NotAfter) {
+			return fmt.Errorf("the client certificate is expired or not yet valid")
+		}
+
+		for _, cert := range registeredCerts {
+			if bytes.Equal(cert.Raw, clientCert.Raw) {
+				return nil
+			}
+		}
+		return fmt.Errorf("the client certificate doesn't match the registered certificate")
+	case TlsClientAuth:
+		roots := x509.NewCertPool()
+		for _, cert := range registeredCerts {
+			roots.AddCert(cert)
+		}
+
+		_, err := clientCert.Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return fmt.Errorf("the client certificate is not trusted: %s", err.Error())
+		}
+
+		if subjectDn != "" && clientCert.Subject.String() != subjectDn {
+			return fmt.Errorf("the subject DN of the client certificate: %s doesn't match the registered one", clientCert.Subject.String())
+		}
+		return nil
+	default:
+		return fmt.Errorf("the TLS client authentication method: %s is not supported", method)
+	}
+}
+
+// CheckTlsClientAuth authenticates the client with the certificate it presented, the registered
+// certificate is the ClientCert of the application.
+func CheckTlsClientAuth(application *Application, clientCert *x509.Certificate) error {
+	if clientCert == nil {
+		return fmt.Errorf("a client certificate is required for the TLS client authentication")
+	}
+
+	cert, err := getCert(application.Owner, application.ClientCert)
+	if err != nil {
+		return err
+	}
+	if cert == nil || cert.Certificate == "" {
+		return fmt.Errorf("client certificate is not configured for application: [%s]", application.GetId())
+	}
+
+	registeredCerts, err := parseCertificateChain(cert.Certificate)
+	if err != nil {
+		return fmt.Errorf("failed to parse the client certificate of application: [%s]: %s", application.GetId(), err.Error())
+	}
+
+	return checkTlsClientCert(application.TlsClientAuthMethod, application.TlsClientAuthSubjectDn, registeredCerts, clientCert)
+}
+
+// addTokenConfirmation re-signs the access token with the cnf claim that binds it to the client
+// certificate (RFC 8705 Section 3.1), the header and the other claims are kept unchanged.
+func addTokenConfirmation(accessToken string, privateKey string, thumbprint string) (string, error) {
+	return resignJwtToken(accessToken, privateKey, func(claims jwt.MapClaims) {
+		claims["cnf"] = map[string]string{"x5t#S256": thumbprint}
+	})
+}
+
+// bindTokenToClientCert binds the access token to the certificate that the client presented at the
+// token endpoint when the application issues certificate-bound access tokens, the resource servers
+// then only accept it over a connection with that certificate.
+func bindTokenToClientCert(application *Application, token *Token, clientCert *x509.Certificate) (*TokenError, error) {
+	if !application.TlsClientCertificateBoundAccessTokens {
+		return nil, nil
+	}
+
+	if clientCert == nil {
+		return &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "a client certificate is required for certificate-bound access tokens",
+		}, nil
+	}
+
+	cert, err := getCertByApplication(application)
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, fmt.Errorf("The cert \"%s\" does not exist", application.Cert)
+	}
+
+	thumbprint := GetCertThumbprint(clientCert)
+	accessToken, err := addTokenConfirmation(token.AccessToken, cert.PrivateKey, thumbprint)
+	if err != nil {
+		return nil, err
+	}
+
+	token.AccessToken = accessToken
+	token.AccessTokenHash = getTokenHash(accessToken)
+	token.CertThumbprint = thumbprint
+	_, err = ormer.Engine.ID(core.PK{token.Owner, token.Name}).Cols("access_token", "access_token_hash", "cert_thumbprint").Update(token)
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// CheckTokenClientCert checks that a certificate-bound access token is presented over a connection
+// with the certificate it is bound to.
+func CheckTokenClientCert(token *Token, clientCert *x509.Certificate) error {
+	if token.CertThumbprint == "" {
+		return nil
+	}
+
+	if clientCert == nil {
+		return fmt.Errorf("client certificate required for certificate-bound access token")
+	}
+	if GetCertThumbprint(clientCert) != token.CertThumbprint {
+		return fmt.Errorf("client certificate doesn't match the certificate-bound access token")
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestCertificate(t *testing.T, commonName string, isCA bool, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Casdoor"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestCheckTlsClientCert(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "ca", true, nil, nil)
+	client, _ := newTestCertificate(t, "client", false, ca, caKey)
+	selfSigned, _ := newTestCertificate(t, "self-signed", false, nil, nil)
+	other, _ := newTestCertificate(t, "other", false, nil, nil)
+
+	cases := []struct {
+		method     string
+		subjectDn  string
+		registered *x509.Certificate
+		presented  *x509.Certificate
+		ok         bool
+	}{
+		{SelfSignedTlsClientAuth, "", selfSigned, selfSigned, true},
+		{SelfSignedTlsClientAuth, "", selfSigned, other, false},
+		{TlsClientAuth, "", ca, client, true},
+		{TlsClientAuth, "CN=client,O=Casdoor", ca, client, true},
+		{TlsClientAuth, "CN=another,O=Casdoor", ca, client, false},
+		{TlsClientAuth, "", ca, other, false},
+		{"client_secret_basic", "", ca, client, false},
+	}
+	for _, c := range cases {
+		err := checkTlsClientCert(c.method, c.subjectDn, []*x509.Certificate{c.registered}, c.presented)
+		if (err == nil) != c.ok {
+			t.Errorf("checkTlsClientCert(%s, %q, %s) = %v, want ok: %v", c.method, c.subjectDn, c.presented.Subject.CommonName, err, c.ok)
+		}
+	}
+}
+
+func TestParseClientCertHeader(t *testing.T) {
+	cert, _ := newTestCertificate(t, "client", false, nil, nil)
+	certPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+
+	for _, value := range []string{certPem, url.QueryEscape(certPem), base64.StdEncoding.EncodeToString(cert.Raw)} {
+		parsed, err := ParseClientCertHeader(value)
+		if err != nil {
+			t.Fatalf("ParseClientCertHeader failed: %v", err)
+		}
+		if GetCertThumbprint(parsed) != GetCertThumbprint(cert) {
+			t.Errorf("the parsed certificate doesn't match the original one")
+		}
+	}
+
+	if _, err := ParseClientCertHeader("not a certificate"); err == nil {
+		t.Errorf("an invalid header should be rejected")
+	}
+
+	if !isTrustedMtlsProxy("127.0.0.1:8080") || isTrustedMtlsProxy("203.0.113.5:8080") {
+		t.Errorf("only the loopback proxies should be trusted by default")
+	}
+}
+
+func TestAddTokenConfirmation(t *testing.T) {
+	cert, _ := newTestCertificate(t, "client", false, nil, nil)
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(signingKey)}))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = "cert-built-in"
+	accessToken, err := token.SignedString(signingKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thumbprint := GetCertThumbprint(cert)
+	boundToken, err := addTokenConfirmation(accessToken, privateKey, thumbprint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := jwt.Parse(boundToken, func(token *jwt.Token) (interface{}, error) {
+		return &signingKey.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatalf("the bound token should be verifiable: %v", err)
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	cnf, _ := claims["cnf"].(map[string]interface{})
+	if cnf["x5t#S256"] != thumbprint || claims["sub"] != "alice" || parsed.Header["kid"] != "cert-built-in" {
+		t.Errorf("unexpected bound token: %v %v", parsed.Header, claims)
+	}
+
+	other, _ := newTestCertificate(t, "other", false, nil, nil)
+	boundTokenRecord := &Token{CertThumbprint: thumbprint}
+	if err = CheckTokenClientCert(boundTokenRecord, cert); err != nil {
+		t.Errorf("the bound certificate should be accepted: %v", err)
+	}
+	if CheckTokenClientCert(boundTokenRecord, other) == nil || CheckTokenClientCert(boundTokenRecord, nil) == nil {
+		t.Errorf("other certificates should be rejected")
+	}
+	if CheckTokenClientCert(&Token{}, nil) != nil {
+		t.Errorf("unbound tokens should be accepted without a certificate")
+	}
+}
@@ -15,6 +15,7 @@
 package object
 
 import (
+	"crypto/x509"
 	"fmt"
 	"strings"
 	"time"
@@ -23,7 +24,7 @@ import (
 	"github.com/casdoor/casdoor/util"
 )
 
-func GetOAuthToken(grantType string, clientId string, clientSecret string, code string, verifier string, scope string, nonce string, username string, password string, host string, refreshToken string, tag string, avatar string, lang string, subjectToken string, subjectTokenType string, assertion string, clientAssertion string, clientAssertionType string, audience string, resource string, dpopProof string) (interface{}, error) {
+func GetOAuthToken(grantType string, clientId string, clientSecret string, code string, verifier string, scope string, nonce string, username string, password string, host string, refreshToken string, tag string, avatar string, lang string, subjectToken string, subjectTokenType string, assertion string, clientAssertion string, clientAssertionType string, audience string, resource string, dpopProof string, authReqId string, clientCert *x509.Certificate) (interface{}, error) {
 	var (
 		application *Application
 		err         error
@@ -59,6 +60,18 @@ func GetOAuthToken(grantType string, clientId string, clientSecret string, code
 		}, nil
 	}
 
+	// RFC 8705: the client authenticates with its TLS client certificate instead of a secret
+	if application.TlsClientAuthMethod != "" && clientSecret == "" && clientAssertion == "" {
+		err = CheckTlsClientAuth(application, clientCert)
+		if err != nil {
+			return &TokenError{
+				Error:            InvalidClient,
+				ErrorDescription: err.Error(),
+			}, nil
+		}
+		clientSecret = application.ClientSecret
+	}
+
 	// Handle WeChat Mini Program flow separately — it does not use standard OAuth grant types
 	if tag == "wechat_miniprogram" {
 		token, tokenError, err := GetWechatMiniProgramToken(application, code, host, username, avatar, lang)
@@ -98,8 +111,10 @@ func GetOAuthToken(grantType string, clientId string, clientSecret string, code
 		token, tokenError, err = mintImplicitToken(application, username, scope, nonce, host)
 	case "urn:ietf:params:oauth:grant-type:token-exchange": // Token Exchange Grant (RFC 8693)
 		token, tokenError, err = GetTokenExchangeToken(application, clientSecret, subjectToken, subjectTokenType, audience, scope, host)
+	case CibaGrantType: // Client Initiated Backchannel Authentication
+		token, tokenError, err = GetCibaToken(application, clientSecret, authReqId, host)
 	case "refresh_token":
-		refreshToken2, err := RefreshToken(application, grantType, refreshToken, scope, clientId, clientSecret, resource, host, dpopProof)
+		refreshToken2, err := RefreshToken(application, grantType, refreshToken, scope, clientId, clientSecret, resource, host, dpopProof, clientCert)
 		if err != nil {
 			return nil, err
 		}
@@ -131,6 +146,15 @@ func GetOAuthToken(grantType string, clientId string, clientSecret string, code
 		}
 	}
 
+	// Apply certificate binding (RFC 8705) with the certificate of the mTLS connection.
+	tokenError, err = bindTokenToClientCert(application, token, clientCert)
+	if err != nil {
+		return nil, err
+	}
+	if tokenError != nil {
+		return tokenError, nil
+	}
+
 	token.CodeIsUsed = true
 
 	_, err = updateUsedByCode(token)
@@ -138,9 +162,14 @@ func GetOAuthToken(grantType string, clientId string, clientSecret string, code
 		return nil, err
 	}
 
+	idToken, err := getIdTokenForClient(application, token)
+	if err != nil {
+		return nil, err
+	}
+
 	tokenWrapper := &TokenWrapper{
 		AccessToken:  token.AccessToken,
-		IdToken:      token.AccessToken,
+		IdToken:      idToken,
 		RefreshToken: token.RefreshToken,
 		TokenType:    token.TokenType,
 		ExpiresIn:    token.ExpiresIn,
@@ -245,6 +274,21 @@ func GetAuthorizationCodeToken(application *Application, clientSecret string, co
 		}, nil
 	}
 
+	// the code is only exchanged when the authentication meets the acr_values it was issued for
+	if token.AcrValues != "" {
+		jwtToken, err := ParseJwtTokenWithoutValidation(token.AccessToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		claims := jwtToken.Claims.(*Claims)
+		if !IsAcrSatisfied(token.AcrValues, claims.Acr) {
+			return nil, &TokenError{
+				Error:            InvalidGrant,
+				ErrorDescription: fmt.Sprintf("the authentication doesn't meet the requested acr_values: [%s], acr: [%s]", token.AcrValues, claims.Acr),
+			}, nil
+		}
+	}
+
 	nowUnix := time.Now().Unix()
 	if nowUnix > token.CodeExpireIn {
 		// code must be used within 5 minutes
@@ -309,7 +353,7 @@ func GetPasswordToken(application *Application, username string, password string
 		return nil, nil, err
 	}
 
-	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", scope, "", host)
+	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", scope, "", host, nil)
 	if err != nil {
 		return nil, &TokenError{
 			Error:            EndpointError,
@@ -370,7 +414,7 @@ func GetClientCredentialsToken(application *Application, clientSecret string, sc
 		Type:  "application",
 	}
 
-	accessToken, _, tokenName, err := generateJwtToken(application, nullUser, "", "", "", scope, "", host)
+	accessToken, _, tokenName, err := generateJwtToken(application, nullUser, "", "", "", scope, "", host, nil)
 	if err != nil {
 		return nil, &TokenError{
 			Error:            EndpointError,
@@ -456,13 +500,13 @@ func GetJwtBearerToken(application *Application, assertion string, scope string,
 }
 
 // GetTokenByUser mints a token for the given user (Implicit flow helper).
-func GetTokenByUser(application *Application, user *User, scope string, nonce string, host string) (*Token, error) {
+func GetTokenByUser(application *Application, user *User, scope string, nonce string, host string, authContext *AuthContext, sessionId string) (*Token, error) {
 	err := ExtendUserWithRolesAndPermissions(user)
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", nonce, scope, "", host)
+	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", nonce, scope, "", host, authContext)
 	if err != nil {
 		return nil, err
 	}
@@ -481,6 +525,7 @@ func GetTokenByUser(application *Application, user *User, scope string, nonce st
 		Scope:        scope,
 		TokenType:    "Bearer",
 		CodeIsUsed:   true,
+		SessionId:    sessionId,
 	}
 	_, err = AddToken(token)
 	if err != nil {
@@ -575,7 +620,7 @@ func GetWechatMiniProgramToken(application *Application, code string, host strin
 		return nil, nil, err
 	}
 
-	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", "", "", host)
+	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", "", "", host, nil)
 	if err != nil {
 		return nil, &TokenError{
 			Error:            EndpointError,
@@ -708,7 +753,7 @@ func GetTokenExchangeToken(application *Application, clientSecret string, subjec
 		return nil, nil, err
 	}
 
-	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", scope, "", host)
+	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", scope, "", host, nil)
 	if err != nil {
 		return nil, &TokenError{
 			Error:            EndpointError,
@@ -749,7 +794,7 @@ func GetAccessTokenByUser(user *User, host string) (string, error) {
 		return "", fmt.Errorf("the application for user %s is not found", user.Id)
 	}
 
-	token, err := GetTokenByUser(application, user, "profile", "", host)
+	token, err := GetTokenByUser(application, user, "profile", "", host, nil, "")
 	if err != nil {
 		return "", err
 	}
@@ -16,6 +16,7 @@ package object
 
 import (
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"net/url"
@@ -71,9 +72,11 @@ type TokenError struct {
 	ErrorDescription string `json:"error_description,omitempty"`
 }
 
-// DPoPConfirmation holds the DPoP key confirmation claim (RFC 9449).
+// DPoPConfirmation holds the confirmation claim of a sender-constrained token, the DPoP key
+// (RFC 9449) or the client certificate (RFC 8705).
 type DPoPConfirmation struct {
-	JKT string `json:"jkt"`
+	JKT     string `json:"jkt,omitempty"`
+	X5tS256 string `json:"x5t#S256,omitempty"`
 }
 
 type IntrospectionResponse struct {
@@ -89,7 +92,7 @@ type IntrospectionResponse struct {
 	Aud       []string          `json:"aud,omitempty"`
 	Iss       string            `json:"iss,omitempty"`
 	Jti       string            `json:"jti,omitempty"`
-	Cnf       *DPoPConfirmation `json:"cnf,omitempty"` // RFC 9449 DPoP key binding
+	Cnf       *DPoPConfirmation `json:"cnf,omitempty"` // RFC 9449 DPoP key binding, RFC 8705 certificate binding
 }
 
 type DeviceAuthCache struct {
@@ -102,6 +105,13 @@ type DeviceAuthCache struct {
 	Status        string
 	CancelToken   string
 	ExpiresIn     int
+
+	// BindingMessage and ClientNotificationToken are only set for CIBA requests
+	BindingMessage          string
+	ClientNotificationToken string
+	// LastPollAt and Interval are only set for the poll state of a CIBA request
+	LastPollAt time.Time
+	Interval   int
 }
 
 func InitCleanupDeviceAuthMap() {
@@ -258,8 +268,7 @@ func ExpireTokenByAccessToken(accessToken string) (bool, *Application, *Token, e
 		return false, nil, nil, nil
 	}
 
-	token.ExpiresIn = 0
-	affected, err := ormer.Engine.ID(core.PK{token.Owner, token.Name}).Cols("expires_in").Update(token)
+	affected, err := expireToken(token)
 	if err != nil {
 		return false, nil, nil, err
 	}
@@ -269,10 +278,94 @@ func ExpireTokenByAccessToken(accessToken string) (bool, *Application, *Token, e
 		return false, nil, nil, err
 	}
 
-	return affected != 0, application, token, nil
+	return affected, application, token, nil
+}
+
+func expireToken(token *Token) (bool, error) {
+	token.ExpiresIn = 0
+	affected, err := ormer.Engine.ID(core.PK{token.Owner, token.Name}).Cols("expires_in").Update(token)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// RevokeToken expires the access token or refresh token issued to the application. Both values
+// belong to the same token record, so revoking either one invalidates the whole grant. The
+// token_type_hint only decides which lookup goes first. An unknown token is not an error, the
+// caller should still respond with 200, only a token issued to another client is refused.
+// Refs: https://datatracker.ietf.org/doc/html/rfc7009
+func RevokeToken(application *Application, tokenValue string, tokenTypeHint string) (*Token, *TokenError, error) {
+	if tokenValue == "" {
+		return nil, &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "token is required",
+		}, nil
+	}
+
+	tokenTypes := []string{"access_token", "refresh_token"}
+	if tokenTypeHint == "refresh_token" || tokenTypeHint == "refresh-token" {
+		tokenTypes = []string{"refresh_token", "access_token"}
+	}
+
+	var token *Token
+	for _, tokenType := range tokenTypes {
+		var err error
+		token, err = GetTokenByTokenValue(tokenValue, tokenType)
+		if err != nil {
+			return nil, nil, err
+		}
+		if token != nil {
+			break
+		}
+	}
+
+	if token == nil {
+		return nil, nil, nil
+	}
+
+	if token.Owner != application.Owner || token.Application != application.Name {
+		return nil, &TokenError{
+			Error:            UnauthorizedClient,
+			ErrorDescription: "the token was not issued to this client",
+		}, nil
+	}
+
+	if token.ExpiresIn <= 0 {
+		return token, nil, nil
+	}
+
+	_, err := expireToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return token, nil, nil
+}
+
+func CheckOAuthLogin(clientId string, responseType string, redirectUri string, scope string, state string, requestUri string, lang string) (string, *Application, error) {
+	msg, application, err := checkOAuthLogin(clientId, responseType, redirectUri, scope, lang)
+	if err != nil || msg != "" {
+		return msg, application, err
+	}
+
+	msg = checkPushedAuthRequest(application, requestUri, map[string]string{
+		"response_type": responseType,
+		"redirect_uri":  redirectUri,
+		"scope":         scope,
+		"state":         state,
+	}, lang)
+	if msg != "" {
+		return msg, application, nil
+	}
+
+	// Mask application for /api/get-app-login
+	application.ClientSecret = ""
+	return "", application, nil
 }
 
-func CheckOAuthLogin(clientId string, responseType string, redirectUri string, scope string, state string, lang string) (string, *Application, error) {
+func checkOAuthLogin(clientId string, responseType string, redirectUri string, scope string, lang string) (string, *Application, error) {
 	if responseType != "code" && responseType != "token" && responseType != "id_token" {
 		return fmt.Sprintf(i18n.Translate(lang, "token:Grant_type: %s is not supported in this application"), responseType), nil, nil
 	}
@@ -294,12 +387,10 @@ func CheckOAuthLogin(clientId string, responseType string, redirectUri string, s
 		return i18n.Translate(lang, "token:Invalid scope"), application, nil
 	}
 
-	// Mask application for /api/get-app-login
-	application.ClientSecret = ""
 	return "", application, nil
 }
 
-func GetOAuthCode(userId string, clientId string, provider string, signinMethod string, responseType string, redirectUri string, scope string, state string, nonce string, challenge string, resource string, host string, lang string) (*Code, error) {
+func GetOAuthCode(userId string, clientId string, provider string, signinMethod string, responseType string, redirectUri string, scope string, state string, nonce string, challenge string, resource string, acrValues string, maxAge string, requestUri string, host string, lang string, authContext *AuthContext, sessionId string) (*Code, error) {
 	user, err := GetUser(userId)
 	if err != nil {
 		return nil, err
@@ -318,11 +409,19 @@ func GetOAuthCode(userId string, clientId string, provider string, signinMethod
 		}, nil
 	}
 
-	msg, application, err := CheckOAuthLogin(clientId, responseType, redirectUri, scope, state, lang)
+	msg, application, err := CheckOAuthLogin(clientId, responseType, redirectUri, scope, state, requestUri, lang)
 	if err != nil {
 		return nil, err
 	}
 
+	if msg == "" && requestUri != "" {
+		msg = checkPushedAuthRequest(application, requestUri, map[string]string{
+			"nonce":          nonce,
+			"code_challenge": challenge,
+			"resource":       resource,
+		}, lang)
+	}
+
 	if msg != "" {
 		return &Code{
 			Message: msg,
@@ -330,6 +429,21 @@ func GetOAuthCode(userId string, clientId string, provider string, signinMethod
 		}, nil
 	}
 
+	// the acr_values and max_age of a pushed request are the ones the client has sent, not the
+	// ones forwarded by the browser
+	if requestUri != "" {
+		if request := getActivePushedAuthRequest(application.ClientId, requestUri); request != nil {
+			acrValues = request.Params["acr_values"]
+			maxAge = request.Params["max_age"]
+		}
+	}
+	if !authContext.IsSatisfied(acrValues, maxAge) {
+		return &Code{
+			Message: "error: the authentication doesn't meet the acr_values or max_age of the request",
+			Code:    "",
+		}, nil
+	}
+
 	// Expand regex/wildcard scopes to concrete scope names.
 	expandedScope, ok := IsScopeValidAndExpand(scope, application)
 	if !ok {
@@ -352,7 +466,7 @@ func GetOAuthCode(userId string, clientId string, provider string, signinMethod
 	if err != nil {
 		return nil, err
 	}
-	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, provider, signinMethod, nonce, scope, resource, host)
+	accessToken, refreshToken, tokenName, err := generateJwtToken(application, user, provider, signinMethod, nonce, scope, resource, host, authContext)
 	if err != nil {
 		return nil, err
 	}
@@ -378,19 +492,26 @@ func GetOAuthCode(userId string, clientId string, provider string, signinMethod
 		CodeIsUsed:    false,
 		CodeExpireIn:  time.Now().Add(time.Minute * 5).Unix(),
 		Resource:      resource,
+		AcrValues:     acrValues,
+		SessionId:     sessionId,
 	}
 	_, err = AddToken(token)
 	if err != nil {
 		return nil, err
 	}
 
+	// The pushed authorization request has been used up by the issued code
+	if requestUri != "" {
+		PushedAuthRequestMap.Delete(requestUri)
+	}
+
 	return &Code{
 		Message: "",
 		Code:    token.Code,
 	}, nil
 }
 
-func RefreshToken(application *Application, grantType string, refreshToken string, scope string, clientId string, clientSecret string, resource string, host string, dpopProof string) (interface{}, error) {
+func RefreshToken(application *Application, grantType string, refreshToken string, scope string, clientId string, clientSecret string, resource string, host string, dpopProof string, clientCert *x509.Certificate) (interface{}, error) {
 	if grantType != "refresh_token" {
 		return &TokenError{
 			Error:            UnsupportedGrantType,
@@ -420,6 +541,16 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 		}, nil
 	}
 
+	if clientSecret == "" && application.TlsClientAuthMethod != "" {
+		err = CheckTlsClientAuth(application, clientCert)
+		if err != nil {
+			return &TokenError{
+				Error:            InvalidClient,
+				ErrorDescription: err.Error(),
+			}, nil
+		}
+	}
+
 	// check whether the refresh token is valid, and has not expired.
 	token, err := GetTokenByRefreshToken(refreshToken)
 	if err != nil || token == nil {
@@ -469,7 +600,9 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 		}, nil
 	}
 
+	// the refreshed token keeps the authentication context of the original login
 	var oldTokenScope string
+	var authContext *AuthContext
 	if application.TokenFormat == "JWT-Standard" {
 		oldToken, err := ParseStandardJwtToken(refreshToken, cert)
 		if err != nil {
@@ -479,6 +612,7 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 			}, nil
 		}
 		oldTokenScope = oldToken.Scope
+		authContext = getAuthContextByClaims(oldToken.Amr, oldToken.AuthTime)
 	} else {
 		oldToken, err := ParseJwtToken(refreshToken, cert)
 		if err != nil {
@@ -488,6 +622,7 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 			}, nil
 		}
 		oldTokenScope = oldToken.Scope
+		authContext = getAuthContextByClaims(oldToken.Amr, oldToken.AuthTime)
 	}
 
 	if scope == "" {
@@ -515,7 +650,7 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 		return nil, err
 	}
 
-	newAccessToken, newRefreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", scope, resource, host)
+	newAccessToken, newRefreshToken, tokenName, err := generateJwtToken(application, user, "", "", "", scope, resource, host, authContext)
 	if err != nil {
 		return &TokenError{
 			Error:            EndpointError,
@@ -537,6 +672,7 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 		Scope:        scope,
 		TokenType:    "Bearer",
 		Resource:     resource,
+		SessionId:    token.SessionId,
 	}
 	_, err = AddToken(newToken)
 	if err != nil {
@@ -560,14 +696,27 @@ func RefreshToken(application *Application, grantType string, refreshToken strin
 		}
 	}
 
+	tokenError, err := bindTokenToClientCert(application, newToken, clientCert)
+	if err != nil {
+		return nil, err
+	}
+	if tokenError != nil {
+		return tokenError, nil
+	}
+
 	_, err = DeleteToken(token)
 	if err != nil {
 		return nil, err
 	}
 
+	idToken, err := getIdTokenForClient(application, newToken)
+	if err != nil {
+		return nil, err
+	}
+
 	tokenWrapper := &TokenWrapper{
 		AccessToken:  newToken.AccessToken,
-		IdToken:      newToken.AccessToken,
+		IdToken:      idToken,
 		RefreshToken: newToken.RefreshToken,
 		TokenType:    newToken.TokenType,
 		ExpiresIn:    newToken.ExpiresIn,
@@ -662,7 +811,7 @@ func mintImplicitToken(application *Application, username string, scope string,
 		}, nil
 	}
 
-	token, err := GetTokenByUser(application, user, scope, nonce, host)
+	token, err := GetTokenByUser(application, user, scope, nonce, host, nil, "")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -814,7 +963,7 @@ func createGuestUserToken(application *Application, clientSecret string, verifie
 		}, nil
 	}
 
-	accessToken, refreshToken, tokenName, err := generateJwtToken(application, guestUser, "", "", "", "", "", "")
+	accessToken, refreshToken, tokenName, err := generateJwtToken(application, guestUser, "", "", "", "", "", "", nil)
 	if err != nil {
 		return nil, &TokenError{
 			Error:            EndpointError,
@@ -0,0 +1,184 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/casdoor/casdoor/i18n"
+	"github.com/casdoor/casdoor/util"
+)
+
+const (
+	PushedAuthRequestUriPrefix = "urn:ietf:params:oauth:request_uri:"
+	PushedAuthRequestExpiresIn = 90
+	// PushedAuthRequestLoginTimeout is how long the request stays usable once the browser has
+	// reached the authorization endpoint with its request_uri, it covers the sign-in itself.
+	PushedAuthRequestLoginTimeout = 600
+)
+
+// PushedAuthRequestParams lists the authorization request parameters that are taken from a
+// pushed authorization request, all the other parameters of the push are ignored.
+var PushedAuthRequestParams = []string{
+	"response_type", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method",
+	"resource", "response_mode", "prompt", "login_hint", "max_age", "acr_values", "ui_locales",
+}
+
+// PushedAuthRequest is an authorization request that the client has pushed to the back channel
+// of the authorization server (RFC 9126). The browser only carries its request_uri.
+type PushedAuthRequest struct {
+	ClientId  string            `json:"clientId"`
+	Params    map[string]string `json:"params"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	Activated bool              `json:"activated"`
+}
+
+type PushedAuthResponse struct {
+	RequestUri string `json:"request_uri"`
+	ExpiresIn  int    `json:"expires_in"`
+}
+
+// PushAuthorizationRequest validates the authorization request of an authenticated client and
+// stores it, the returned request_uri is what the client sends to the authorization endpoint.
+// Refs: https://datatracker.ietf.org/doc/html/rfc9126
+func PushAuthorizationRequest(application *Application, params map[string]string, host string, lang string) (*PushedAuthResponse, *TokenError, error) {
+	if params["request_uri"] != "" {
+		return nil, &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: "request_uri must not be included in a pushed authorization request",
+		}, nil
+	}
+
+	// The pushed request may be a signed request object (RFC 9126 Section 3)
+	if params["request"] != "" {
+		signedParams, err := ParseRequestObject(application, params["request"], host)
+		if err != nil {
+			return nil, &TokenError{
+				Error:            "invalid_request_object",
+				ErrorDescription: err.Error(),
+			}, nil
+		}
+
+		for name, value := range signedParams {
+			params[name] = value
+		}
+	}
+
+	requestUri, tokenError, err := storePushedAuthRequest(application, params, lang)
+	if err != nil || tokenError != nil {
+		return nil, tokenError, err
+	}
+
+	return &PushedAuthResponse{
+		RequestUri: requestUri,
+		ExpiresIn:  PushedAuthRequestExpiresIn,
+	}, nil, nil
+}
+
+func storePushedAuthRequest(application *Application, params map[string]string, lang string) (string, *TokenError, error) {
+	pushedParams := map[string]string{}
+	for _, name := range PushedAuthRequestParams {
+		if value := params[name]; value != "" {
+			pushedParams[name] = value
+		}
+	}
+
+	msg, _, err := checkOAuthLogin(application.ClientId, pushedParams["response_type"], pushedParams["redirect_uri"], pushedParams["scope"], lang)
+	if err != nil {
+		return "", nil, err
+	}
+	if msg != "" {
+		return "", &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: msg,
+		}, nil
+	}
+
+	challengeMethod := pushedParams["code_challenge_method"]
+	if challengeMethod != "" && challengeMethod != "S256" {
+		return "", &TokenError{
+			Error:            InvalidRequest,
+			ErrorDescription: i18n.Translate(lang, "auth:Challenge method should be S256"),
+		}, nil
+	}
+
+	requestUri := PushedAuthRequestUriPrefix + util.GenerateId()
+	PushedAuthRequestMap.Store(requestUri, PushedAuthRequest{
+		ClientId:  application.ClientId,
+		Params:    pushedParams,
+		ExpiresAt: time.Now().Add(PushedAuthRequestExpiresIn * time.Second),
+	})
+	return requestUri, nil, nil
+}
+
+// ActivatePushedAuthRequest is called when the browser reaches the authorization endpoint with a
+// request_uri. The request_uri can only be used once there, after that the request is kept for
+// the duration of the sign-in so that the issued code can be checked against it.
+func ActivatePushedAuthRequest(clientId string, requestUri string) (*PushedAuthRequest, error) {
+	value, ok := PushedAuthRequestMap.LoadAndDelete(requestUri)
+	if !ok {
+		return nil, fmt.Errorf("the request_uri: %s is invalid or expired", requestUri)
+	}
+
+	request := value.(PushedAuthRequest)
+	if request.ClientId != clientId || request.Activated || request.ExpiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("the request_uri: %s is invalid or expired", requestUri)
+	}
+
+	request.Activated = true
+	request.ExpiresAt = time.Now().Add(PushedAuthRequestLoginTimeout * time.Second)
+	PushedAuthRequestMap.Store(requestUri, request)
+	return &request, nil
+}
+
+func getActivePushedAuthRequest(clientId string, requestUri string) *PushedAuthRequest {
+	value, ok := PushedAuthRequestMap.Load(requestUri)
+	if !ok {
+		return nil
+	}
+
+	request := value.(PushedAuthRequest)
+	if request.ClientId != clientId || !request.Activated || request.ExpiresAt.Before(time.Now()) {
+		return nil
+	}
+	return &request
+}
+
+// checkPushedAuthRequest makes sure that the parameters the browser carried are the ones that
+// were pushed, and that applications requiring PAR are only used through a request_uri.
+func checkPushedAuthRequest(application *Application, requestUri string, params map[string]string, lang string) string {
+	if requestUri == "" {
+		if application.RequirePushedAuthorizationRequests {
+			return i18n.Translate(lang, "token:Pushed authorization request is required for this application")
+		}
+		return ""
+	}
+
+	request := getActivePushedAuthRequest(application.ClientId, requestUri)
+	if request == nil {
+		return i18n.Translate(lang, "token:Invalid request_uri")
+	}
+
+	for name, value := range params {
+		if value == "null" {
+			value = ""
+		}
+		if request.Params[name] != value {
+			return i18n.Translate(lang, "token:The authorization request doesn't match the pushed authorization request")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivatePushedAuthRequest(t *testing.T) {
+	requestUri := PushedAuthRequestUriPrefix + "activate"
+	PushedAuthRequestMap.Store(requestUri, PushedAuthRequest{
+		ClientId:  "client",
+		Params:    map[string]string{"response_type": "code"},
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	defer PushedAuthRequestMap.Delete(requestUri)
+
+	if _, err := ActivatePushedAuthRequest("other-client", requestUri); err == nil {
+		t.Error("a request_uri must not be usable by another client")
+	}
+
+	PushedAuthRequestMap.Store(requestUri, PushedAuthRequest{
+		ClientId:  "client",
+		Params:    map[string]string{"response_type": "code"},
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	request, err := ActivatePushedAuthRequest("client", requestUri)
+	if err != nil {
+		t.Fatalf("ActivatePushedAuthRequest failed: %v", err)
+	}
+	if !request.Activated || request.Params["response_type"] != "code" {
+		t.Errorf("unexpected activated request: %+v", request)
+	}
+
+	if _, err = ActivatePushedAuthRequest("client", requestUri); err == nil {
+		t.Error("a request_uri must only be usable once at the authorization endpoint")
+	}
+}
+
+func TestCheckPushedAuthRequest(t *testing.T) {
+	application := &Application{ClientId: "client"}
+	requestUri := PushedAuthRequestUriPrefix + "check"
+	PushedAuthRequestMap.Store(requestUri, PushedAuthRequest{
+		ClientId:  "client",
+		Params:    map[string]string{"response_type": "code", "redirect_uri": "https://app.example.com/callback"},
+		ExpiresAt: time.Now().Add(time.Minute),
+		Activated: true,
+	})
+	defer PushedAuthRequestMap.Delete(requestUri)
+
+	if msg := checkPushedAuthRequest(application, "", nil, "en"); msg != "" {
+		t.Errorf("PAR should be optional by default, got: %s", msg)
+	}
+
+	application.RequirePushedAuthorizationRequests = true
+	if msg := checkPushedAuthRequest(application, "", nil, "en"); msg == "" {
+		t.Error("PAR should be required when the application requires it")
+	}
+
+	params := map[string]string{"response_type": "code", "redirect_uri": "https://app.example.com/callback", "state": "null"}
+	if msg := checkPushedAuthRequest(application, requestUri, params, "en"); msg != "" {
+		t.Errorf("the pushed parameters should match, got: %s", msg)
+	}
+
+	params["redirect_uri"] = "https://evil.example.com/callback"
+	if msg := checkPushedAuthRequest(application, requestUri, params, "en"); msg == "" {
+		t.Error("a tampered redirect_uri should be rejected")
+	}
+
+	if msg := checkPushedAuthRequest(application, PushedAuthRequestUriPrefix+"unknown", nil, "en"); msg == "" {
+		t.Error("an unknown request_uri should be rejected")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import "testing"
+
+func addRevokeTestToken(t *testing.T, name string, application string) *Token {
+	t.Helper()
+
+	token := &Token{
+		Owner:        "admin",
+		Name:         name,
+		Application:  application,
+		Organization: "org",
+		User:         "alice",
+		AccessToken:  "access-" + name,
+		RefreshToken: "refresh-" + name,
+		ExpiresIn:    3600,
+	}
+	_, err := AddToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func getRevokeTestTokenExpiresIn(t *testing.T, name string) int {
+	t.Helper()
+
+	token, err := GetToken("admin/" + name)
+	if err != nil || token == nil {
+		t.Fatalf("the token: %s is not found: %v", name, err)
+	}
+	return token.ExpiresIn
+}
+
+func TestRevokeToken(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	application := &Application{Owner: "admin", Name: "app"}
+	addRevokeTestToken(t, "token-1", "app")
+	addRevokeTestToken(t, "token-2", "app")
+	addRevokeTestToken(t, "token-3", "other-app")
+
+	// the access token revokes the whole grant
+	_, tokenError, err := RevokeToken(application, "access-token-1", "")
+	if err != nil || tokenError != nil {
+		t.Fatalf("RevokeToken() = %v, %v", tokenError, err)
+	}
+	if expiresIn := getRevokeTestTokenExpiresIn(t, "token-1"); expiresIn != 0 {
+		t.Errorf("the revoked access token expires in %d", expiresIn)
+	}
+	if token, _ := GetTokenByRefreshToken("refresh-token-1"); token == nil || token.ExpiresIn != 0 {
+		t.Errorf("the refresh token of a revoked access token is still active")
+	}
+
+	// a refresh token is found even with the wrong hint
+	_, tokenError, err = RevokeToken(application, "refresh-token-2", "access_token")
+	if err != nil || tokenError != nil {
+		t.Fatalf("RevokeToken() = %v, %v", tokenError, err)
+	}
+	if expiresIn := getRevokeTestTokenExpiresIn(t, "token-2"); expiresIn != 0 {
+		t.Errorf("the revoked refresh token expires in %d", expiresIn)
+	}
+
+	// revoking again or an unknown token isn't an error
+	for _, value := range []string{"access-token-1", "unknown"} {
+		_, tokenError, err = RevokeToken(application, value, "")
+		if err != nil || tokenError != nil {
+			t.Errorf("RevokeToken(%s) = %v, %v, want no error", value, tokenError, err)
+		}
+	}
+
+	// the token of another client is refused and stays active
+	_, tokenError, err = RevokeToken(application, "access-token-3", "")
+	if err != nil || tokenError == nil || tokenError.Error != UnauthorizedClient {
+		t.Errorf("RevokeToken() of another client = %v, %v, want %s", tokenError, err, UnauthorizedClient)
+	}
+	if expiresIn := getRevokeTestTokenExpiresIn(t, "token-3"); expiresIn != 3600 {
+		t.Errorf("the token of another client expires in %d after a refused revocation", expiresIn)
+	}
+
+	_, tokenError, _ = RevokeToken(application, "", "")
+	if tokenError == nil || tokenError.Error != InvalidRequest {
+		t.Errorf("RevokeToken() without a token = %v, want %s", tokenError, InvalidRequest)
+	}
+}
@@ -35,6 +35,9 @@ type ClaimsStandard struct {
 	Azp                 string      `json:"azp,omitempty"`
 	Provider            string      `json:"provider,omitempty"`
 
+	Acr      string           `json:"acr,omitempty"`
+	Amr      []string         `json:"amr,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -56,6 +59,9 @@ func getStandardClaims(claims Claims) ClaimsStandard {
 		RegisteredClaims: claims.RegisteredClaims,
 		Azp:              claims.Azp,
 		Provider:         claims.Provider,
+		Acr:              claims.Acr,
+		Amr:              claims.Amr,
+		AuthTime:         claims.AuthTime,
 	}
 
 	res.Phone = ""
@@ -98,12 +104,19 @@ func ParseStandardJwtToken(token string, cert *Cert) (*ClaimsStandard, error) {
 			return nil, fmt.Errorf("the certificate field should not be empty for the cert: %v", cert)
 		}
 
+		// Select the key by kid, the token may be signed by a key that has been rotated
+		kid, _ := token.Header["kid"].(string)
+		pemCertificate, err := cert.getVerificationCertificate(kid)
+		if err != nil {
+			return nil, err
+		}
+
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); ok {
 			// RSA certificate
-			certificate, err = jwt.ParseRSAPublicKeyFromPEM([]byte(cert.Certificate))
+			certificate, err = jwt.ParseRSAPublicKeyFromPEM([]byte(pemCertificate))
 		} else if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
 			// ES certificate
-			certificate, err = jwt.ParseECPublicKeyFromPEM([]byte(cert.Certificate))
+			certificate, err = jwt.ParseECPublicKeyFromPEM([]byte(pemCertificate))
 		} else {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
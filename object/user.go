@@ -238,9 +238,10 @@ type User struct {
 	Permissions []*Permission `json:"permissions"`
 	Groups      []string      `xorm:"mediumtext" json:"groups"`
 
-	LastChangePasswordTime string `xorm:"varchar(100)" json:"lastChangePasswordTime"`
-	LastSigninWrongTime    string `xorm:"varchar(100)" json:"lastSigninWrongTime"`
-	SigninWrongTimes       int    `json:"signinWrongTimes"`
+	LastChangePasswordTime string                 `xorm:"varchar(100)" json:"lastChangePasswordTime"`
+	PasswordHistory        []*PasswordHistoryItem `xorm:"mediumtext" json:"-"`
+	LastSigninWrongTime    string                 `xorm:"varchar(100)" json:"lastSigninWrongTime"`
+	SigninWrongTimes       int                    `json:"signinWrongTimes"`
 
 	ManagedAccounts     []ManagedAccount `xorm:"managedAccounts blob" json:"managedAccounts"`
 	MfaAccounts         []MfaAccount     `xorm:"mfaAccounts blob" json:"mfaAccounts"`
@@ -411,6 +412,37 @@ func GetUsersByTagWithFilter(owner string, tag string, cond builder.Cond) ([]*Us
 	return users, nil
 }
 
+// GetPaginationUsersWithFilter returns a page of the users matching the condition, an empty owner
+// means all the organizations. The primary key breaks the ties of the sort field, so consecutive
+// pages neither skip nor repeat a user.
+func GetPaginationUsersWithFilter(owner string, tag string, cond builder.Cond, offset, limit int, sortField, sortOrder string) ([]*User, error) {
+	users := []*User{}
+	session := ormer.Engine.Prepare()
+	if limit > 0 {
+		session = session.Limit(limit, offset)
+	}
+	if cond != nil {
+		session = session.Where(cond)
+	}
+
+	if sortField == "" || !util.FilterField(sortField) {
+		sortField = "created_time"
+		sortOrder = "descend"
+	}
+	if sortOrder == "ascend" {
+		session = session.Asc(util.CamelToSnakeCase(sortField))
+	} else {
+		session = session.Desc(util.CamelToSnakeCase(sortField))
+	}
+
+	err := session.Asc("owner", "name").Find(&users, &User{Owner: owner, Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func GetSortedUsers(owner string, sorter string, limit int) ([]*User, error) {
 	users := []*User{}
 	if !util.FilterSQLIdentifier(sorter) {
@@ -899,6 +931,10 @@ func UpdateUser(id string, user *User, columns []string, isAdmin bool) (bool, er
 		return false, err
 	}
 
+	if affected != 0 {
+		publishUserUpdateEvents(oldUser, user, columns)
+	}
+
 	if affected != 0 && isUserAccessRevoked(oldUser, user, columns) {
 		// The tokens and sessions are keyed by the old user name, it may have just been renamed
 		err = terminateUserAccess(oldUser)
@@ -910,6 +946,16 @@ func UpdateUser(id string, user *User, columns []string, isAdmin bool) (bool, er
 	return affected != 0, nil
 }
 
+// publishUserUpdateEvents publishes the events of an update that has written the columns of the user, all of
+// them when columns is nil. The default columns of UpdateUser() don't include the password, so it only changes
+// when the stored password, i.e. its hash, differs in a written password column.
+func publishUserUpdateEvents(oldUser *User, user *User, columns []string) {
+	publishUserEvent(DomainEventUserUpdated, oldUser, user)
+	if (columns == nil || util.InSlice(columns, "password")) && user.Password != oldUser.Password {
+		publishUserEvent(DomainEventUserPasswordChanged, oldUser, user)
+	}
+}
+
 func updateUser(id string, user *User, columns []string) (int64, error) {
 	owner, name := util.GetOwnerAndNameFromIdNoCheck(id)
 	err := user.UpdateUserHash()
@@ -972,11 +1018,16 @@ func UpdateUserForAllFields(id string, user *User) (bool, error) {
 		}
 	}
 
-	affected, err := ormer.Engine.ID(core.PK{owner, name}).AllCols().Update(user)
+	// the password history isn't part of the JSON of a user, so the given user may not carry it
+	affected, err := ormer.Engine.ID(core.PK{owner, name}).AllCols().Omit("password_history").Update(user)
 	if err != nil {
 		return false, err
 	}
 
+	if affected != 0 {
+		publishUserUpdateEvents(oldUser, user, nil)
+	}
+
 	if affected != 0 && isUserAccessRevoked(oldUser, user, nil) {
 		err = terminateUserAccess(oldUser)
 		if err != nil {
@@ -1105,6 +1156,10 @@ func AddUser(user *User, lang string) (bool, error) {
 		return false, err
 	}
 
+	if affected != 0 {
+		publishUserEvent(DomainEventUserCreated, nil, user)
+	}
+
 	return affected != 0, nil
 }
 
@@ -1244,6 +1299,7 @@ func terminateUserAccess(user *User) error {
 	// Send OIDC Back-Channel Logout notifications BEFORE expiring tokens,
 	// because SendBackchannelLogout calls GetActiveTokensByUser (expires_in > 0).
 	// The host is empty, so the issuer falls back to the configured origin
+	SendSamlLogoutAll(user.Owner, user.Name, "")
 	SendBackchannelLogout(user.Owner, user.Name, "", "")
 
 	_, err = ExpireTokenByUser(user.Owner, user.Name)
@@ -1288,13 +1344,27 @@ func DeleteUser(user *User) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	var affected bool
 	if organization != nil && organization.EnableSoftDeletion {
+		// UpdateUser() isn't used, the soft deletion is published as "user.deleted" only
 		user.IsDeleted = true
 		user.DeletedTime = util.GetCurrentTime()
-		return UpdateUser(user.GetId(), user, []string{"is_deleted", "deleted_time"}, false)
+		user.UpdatedTime = user.DeletedTime
+		var affectedRows int64
+		affectedRows, err = updateUser(user.GetId(), user, []string{"is_deleted", "deleted_time", "updated_time"})
+		affected = affectedRows != 0
 	} else {
-		return deleteUser(user)
+		affected, err = deleteUser(user)
+	}
+	if err != nil {
+		return false, err
 	}
+
+	if affected {
+		publishUserEvent(DomainEventUserDeleted, user, nil)
+	}
+
+	return affected, nil
 }
 
 func GetUserInfo(user *User, scope string, aud string, host string) (*Userinfo, error) {
@@ -1539,7 +1609,14 @@ func userChangeTrigger(owner string, oldName string, newName string) error {
 		return err
 	}
 
-	return session.Commit()
+	err = session.Commit()
+	if err != nil {
+		return err
+	}
+
+	// the user is renamed in the roles and permissions of all the organizations
+	invalidatePermissionEnforcerCache(permissionEnforcerScopeAll)
+	return nil
 }
 
 func (user *User) IsMfaEnabled() bool {
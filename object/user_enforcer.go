@@ -34,6 +34,7 @@ func (e *UserGroupEnforcer) AddGroupForUser(user string, group string) (bool, er
 		return false, err
 	}
 
+	defer invalidatePermissionEnforcerCacheByGroups([]string{group})
 	return e.enforcer.AddRoleForUser(user, GetGroupWithPrefix(group))
 }
 
@@ -47,6 +48,7 @@ func (e *UserGroupEnforcer) AddGroupsForUser(user string, groups []string) (bool
 	for i, group := range groups {
 		g[i] = GetGroupWithPrefix(group)
 	}
+	defer invalidatePermissionEnforcerCacheByGroups(groups)
 	return e.enforcer.AddRolesForUser(user, g)
 }
 
@@ -56,6 +58,7 @@ func (e *UserGroupEnforcer) DeleteGroupForUser(user string, group string) (bool,
 		return false, err
 	}
 
+	defer invalidatePermissionEnforcerCacheByGroups([]string{group})
 	return e.enforcer.DeleteRoleForUser(user, GetGroupWithPrefix(group))
 }
 
@@ -65,6 +68,16 @@ func (e *UserGroupEnforcer) DeleteGroupsForUser(user string) (bool, error) {
 		return false, err
 	}
 
+	// the groups in memory may miss the ones added by the other nodes, which are
+	// in the organization of the user as well
+	groups := []string{user}
+	if roles, err := e.enforcer.GetRolesForUser(user); err == nil {
+		for _, role := range roles {
+			groups = append(groups, GetGroupWithoutPrefix(role))
+		}
+	}
+	defer invalidatePermissionEnforcerCacheByGroups(groups)
+
 	return e.enforcer.DeleteRolesForUser(user)
 }
 
@@ -119,6 +132,17 @@ func (e *UserGroupEnforcer) GetAllUsersByGroup(group string) ([]string, error) {
 	return e.getAllUsersByGroup(group)
 }
 
+// invalidatePermissionEnforcerCacheByGroups drops the cached permission enforcers
+// that have resolved the members of the groups
+func invalidatePermissionEnforcerCacheByGroups(groups []string) {
+	owners := make([]string, 0, len(groups))
+	for _, group := range groups {
+		owner, _ := util.GetOwnerAndNameFromIdNoCheck(group)
+		owners = append(owners, owner)
+	}
+	invalidatePermissionEnforcerCache(permissionEnforcerScopeGroup, owners...)
+}
+
 func GetGroupWithPrefix(group string) string {
 	return "group:" + group
 }
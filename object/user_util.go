@@ -165,8 +165,11 @@ func GetUserByFieldsForSharedApp(application *Application, organization string,
 }
 
 func SetUserField(user *User, field string, value string) (bool, error) {
+	var before *User
 	bean := make(map[string]interface{})
 	if field == "password" {
+		before = getDomainEventUser(user)
+
 		organization, err := GetOrganizationByUser(user)
 		if err != nil {
 			return false, err
@@ -203,6 +206,10 @@ func SetUserField(user *User, field string, value string) (bool, error) {
 		return false, err
 	}
 
+	if field == "password" && affected != 0 {
+		PublishDomainEvent(user.Owner, DomainEventUserPasswordChanged, user.GetId(), before, getDomainEventUser(user))
+	}
+
 	return affected != 0, nil
 }
 
@@ -45,6 +45,11 @@ type Webhook struct {
 	IsUserExtended bool      `json:"isUserExtended"`
 	SingleOrgOnly  bool      `json:"singleOrgOnly"`
 	IsEnabled      bool      `json:"isEnabled"`
+	PayloadFormat  string    `xorm:"varchar(100)" json:"payloadFormat"`
+
+	// Signing secrets, the deliveries are signed by both while a secret is being rotated
+	SigningSecret         string `xorm:"varchar(100)" json:"signingSecret"`
+	PreviousSigningSecret string `xorm:"varchar(100)" json:"previousSigningSecret"`
 
 	// Retry configuration
 	MaxRetries            int  `xorm:"int default 3" json:"maxRetries"`
@@ -160,6 +165,10 @@ func UpdateWebhook(id string, webhook *Webhook, isGlobalAdmin bool, lang string)
 }
 
 func AddWebhook(webhook *Webhook) (bool, error) {
+	if webhook.SigningSecret == "" {
+		webhook.SigningSecret = generateWebhookSigningSecret()
+	}
+
 	affected, err := ormer.Engine.Insert(webhook)
 	if err != nil {
 		return false, err
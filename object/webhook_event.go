@@ -40,12 +40,20 @@ type WebhookEvent struct {
 	UpdatedTime string `xorm:"varchar(100)" json:"updatedTime"`
 
 	Webhook      string             `xorm:"varchar(200) index" json:"webhook"`
+	EventSink    string             `xorm:"varchar(200) index" json:"eventSink"`
+	SsfStream    string             `xorm:"varchar(200) index" json:"ssfStream"`
 	Organization string             `xorm:"varchar(100) index" json:"organization"`
 	EventType    string             `xorm:"varchar(100)" json:"eventType"`
 	State        WebhookEventStatus `xorm:"varchar(50) index" json:"state"`
 
-	// Payload stores the event data (Record)
-	Payload string `xorm:"mediumtext" json:"payload"`
+	// Payload stores the event data, a Record or a DomainEvent when IsDomainEvent is set, the signed
+	// security event token for a Shared Signals stream
+	Payload       string `xorm:"mediumtext" json:"payload"`
+	IsDomainEvent bool   `json:"isDomainEvent"`
+
+	// The events of an event sink with the same partition key are delivered in the order of their sequence
+	PartitionKey string `xorm:"varchar(200) index" json:"partitionKey"`
+	Sequence     int64  `xorm:"bigint" json:"sequence"`
 
 	// Extended user data if applicable
 	ExtendedUser string `xorm:"mediumtext" json:"extendedUser"`
@@ -158,7 +166,7 @@ func GetPendingWebhookEvents(limit int) ([]*WebhookEvent, error) {
 	err := ormer.Engine.
 		Where("state = ? OR state = ?", WebhookEventStatusPending, WebhookEventStatusRetrying).
 		And("(next_retry_time = '' OR next_retry_time <= ?)", currentTime).
-		Asc("created_time").
+		Asc("created_time", "sequence").
 		Limit(limit).
 		Find(&events)
 	if err != nil {
@@ -246,26 +254,30 @@ func (e *WebhookEvent) GetId() string {
 	return fmt.Sprintf("%s/%s", e.Owner, e.Name)
 }
 
-// CreateWebhookEventFromRecord creates a webhook event from a record
-func CreateWebhookEventFromRecord(webhook *Webhook, record *Record, extendedUser *User) (*WebhookEvent, error) {
+func newWebhookEvent(webhook *Webhook, organization string, eventType string) *WebhookEvent {
 	maxRetries := webhook.MaxRetries
 	if maxRetries <= 0 {
 		maxRetries = 3
 	}
 
-	event := &WebhookEvent{
+	return &WebhookEvent{
 		Owner:        webhook.Owner,
 		Name:         util.GenerateId(),
 		CreatedTime:  util.GetCurrentTime(),
 		UpdatedTime:  util.GetCurrentTime(),
 		Webhook:      webhook.GetId(),
-		Organization: record.Organization,
-		EventType:    record.Action,
+		Organization: organization,
+		EventType:    eventType,
 		State:        WebhookEventStatusPending,
-		Payload:      util.StructToJson(record),
 		AttemptCount: 0,
 		MaxRetries:   maxRetries,
 	}
+}
+
+// CreateWebhookEventFromRecord creates a webhook event from a record
+func CreateWebhookEventFromRecord(webhook *Webhook, record *Record, extendedUser *User) (*WebhookEvent, error) {
+	event := newWebhookEvent(webhook, record.Organization, record.Action)
+	event.Payload = util.StructToJson(record)
 
 	if extendedUser != nil {
 		event.ExtendedUser = util.StructToJson(extendedUser)
@@ -278,3 +290,87 @@ func CreateWebhookEventFromRecord(webhook *Webhook, record *Record, extendedUser
 
 	return event, nil
 }
+
+// CreateWebhookEventFromDomainEvent creates a webhook event from a domain event, the id of the
+// domain event is the one of the webhook event, so that every delivery of it carries the same id
+func CreateWebhookEventFromDomainEvent(webhook *Webhook, domainEvent *DomainEvent) (*WebhookEvent, error) {
+	event := newWebhookEvent(webhook, domainEvent.Organization, domainEvent.Type)
+	domainEvent.Id = event.Name
+	event.Payload = util.StructToJson(domainEvent)
+	event.IsDomainEvent = true
+
+	_, err := AddWebhookEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func newEventSinkEvent(eventSink *EventSink, organization string, eventType string, partitionKey string) *WebhookEvent {
+	maxRetries := eventSink.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+
+	return &WebhookEvent{
+		Owner:        eventSink.Owner,
+		Name:         util.GenerateId(),
+		CreatedTime:  util.GetCurrentTime(),
+		UpdatedTime:  util.GetCurrentTime(),
+		EventSink:    eventSink.GetId(),
+		Organization: organization,
+		EventType:    eventType,
+		State:        WebhookEventStatusPending,
+		PartitionKey: partitionKey,
+		AttemptCount: 0,
+		MaxRetries:   maxRetries,
+	}
+}
+
+// CreateEventSinkEventFromRecord creates an event sink event from a record
+func CreateEventSinkEventFromRecord(eventSink *EventSink, record *Record) (*WebhookEvent, error) {
+	partitionKey := eventSink.getPartitionKey(record.Organization, util.GetId(record.Organization, record.User))
+	event := newEventSinkEvent(eventSink, record.Organization, record.Action, partitionKey)
+	event.Payload = util.StructToJson(record)
+
+	_, err := addEventSinkEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// CreateEventSinkEventFromDomainEvent creates an event sink event from a domain event
+func CreateEventSinkEventFromDomainEvent(eventSink *EventSink, domainEvent *DomainEvent) (*WebhookEvent, error) {
+	partitionKey := eventSink.getPartitionKey(domainEvent.Organization, domainEvent.Subject)
+	event := newEventSinkEvent(eventSink, domainEvent.Organization, domainEvent.Type, partitionKey)
+	domainEvent.Id = event.Name
+	event.Payload = util.StructToJson(domainEvent)
+	event.IsDomainEvent = true
+
+	_, err := addEventSinkEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// newSsfStreamEvent creates the outbox event of a Shared Signals stream, its payload is the signed
+// security event token, whose jti is the name of the event
+func newSsfStreamEvent(stream *SsfStream, eventType string) *WebhookEvent {
+	return &WebhookEvent{
+		Owner:        stream.Owner,
+		Name:         util.GenerateId(),
+		CreatedTime:  util.GetCurrentTime(),
+		UpdatedTime:  util.GetCurrentTime(),
+		SsfStream:    stream.GetId(),
+		Organization: stream.Organization,
+		EventType:    eventType,
+		State:        WebhookEventStatusPending,
+		AttemptCount: 0,
+		MaxRetries:   3,
+	}
+}
@@ -15,19 +15,94 @@
 package object
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/casdoor/casdoor/util"
 )
 
-func sendWebhook(webhook *Webhook, record *Record, extendedUser *User) (int, string, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
+// The deliveries are signed as the Standard Webhooks specification (https://www.standardwebhooks.com) does,
+// so that the receivers can verify them with its libraries
+const (
+	webhookIdHeader            = "webhook-id"
+	webhookTimestampHeader     = "webhook-timestamp"
+	webhookSignatureHeader     = "webhook-signature"
+	webhookSigningSecretPrefix = "whsec_"
+)
+
+// The deliveries of a webhook with the CloudEvents payload format are CloudEvents 1.0 (https://cloudevents.io)
+// in the structured content mode
+const (
+	WebhookPayloadFormatCasdoor     = "Casdoor"
+	WebhookPayloadFormatCloudEvents = "CloudEvents"
+
+	cloudEventSpecVersion = "1.0"
+	cloudEventContentType = "application/cloudevents+json"
+	cloudEventTypePrefix  = "org.casdoor."
+)
+
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func generateWebhookSigningSecret() string {
+	secret := make([]byte, 24)
+	_, err := rand.Read(secret)
+	if err != nil {
+		panic(err)
+	}
+
+	return webhookSigningSecretPrefix + base64.StdEncoding.EncodeToString(secret)
+}
+
+// getWebhookSigningKey returns the HMAC key of the secret, a "whsec_" secret carries a base64 key,
+// any other secret is used as it is
+func getWebhookSigningKey(secret string) []byte {
+	if strings.HasPrefix(secret, webhookSigningSecretPrefix) {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, webhookSigningSecretPrefix))
+		if err == nil {
+			return key
+		}
+	}
+	return []byte(secret)
+}
+
+// getWebhookSignature signs the delivery with each secret of the webhook, the signatures are separated
+// by spaces so that a receiver holding either secret accepts it during a rotation
+func getWebhookSignature(webhook *Webhook, eventId string, timestamp int64, body string) string {
+	signatures := []string{}
+	for _, secret := range []string{webhook.SigningSecret, webhook.PreviousSigningSecret} {
+		if secret == "" {
+			continue
+		}
+
+		mac := hmac.New(sha256.New, getWebhookSigningKey(secret))
+		mac.Write([]byte(fmt.Sprintf("%s.%d.%s", eventId, timestamp, body)))
+		signatures = append(signatures, "v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	}
+	return strings.Join(signatures, " ")
+}
+
+func getWebhookRecordBody(webhook *Webhook, record *Record, extendedUser *User) string {
 	userMap := make(map[string]interface{})
-	var body io.Reader
+	var body string
 
 	if webhook.TokenFields != nil && len(webhook.TokenFields) > 0 && extendedUser != nil {
 		userValue := reflect.ValueOf(extendedUser).Elem()
@@ -50,7 +125,7 @@ func sendWebhook(webhook *Webhook, record *Record, extendedUser *User) (int, str
 			ExtendedUser: userMap,
 		}
 
-		body = strings.NewReader(util.StructToJson(recordEx))
+		body = util.StructToJson(recordEx)
 	} else {
 		type RecordEx struct {
 			Record
@@ -61,20 +136,77 @@ func sendWebhook(webhook *Webhook, record *Record, extendedUser *User) (int, str
 			ExtendedUser: extendedUser,
 		}
 
-		body = strings.NewReader(util.StructToJson(recordEx))
+		body = util.StructToJson(recordEx)
+	}
+
+	return body
+}
+
+// getCloudEventBody wraps the data into a CloudEvent, the id of the CloudEvent is the id of the webhook event
+func getCloudEventBody(eventId string, organization string, eventType string, subject string, eventTime string, data string) string {
+	cloudEvent := &CloudEvent{
+		SpecVersion:     cloudEventSpecVersion,
+		Id:              eventId,
+		Source:          getDomainEventSource(organization),
+		Type:            cloudEventTypePrefix + eventType,
+		Subject:         subject,
+		Time:            eventTime,
+		DataContentType: "application/json",
+		Data:            json.RawMessage(data),
+	}
+	return util.StructToJson(cloudEvent)
+}
+
+// sendWebhook delivers the record, eventId stays the same for the retries and the replays of an event,
+// so that the receivers can drop the duplicates
+func sendWebhook(webhook *Webhook, record *Record, extendedUser *User, eventId string) (int, string, error) {
+	body := getWebhookRecordBody(webhook, record, extendedUser)
+	if webhook.PayloadFormat == WebhookPayloadFormatCloudEvents {
+		body = getCloudEventBody(eventId, record.Organization, record.Action, record.User, record.CreatedTime, body)
+	}
+
+	return postWebhook(webhook, body, eventId)
+}
+
+func getDomainEventBody(domainEvent *DomainEvent, payloadFormat string) string {
+	if payloadFormat == WebhookPayloadFormatCloudEvents {
+		return getCloudEventBody(domainEvent.Id, domainEvent.Organization, domainEvent.Type, domainEvent.Subject, domainEvent.Time, util.StructToJson(domainEvent.Data))
 	}
+	return util.StructToJson(domainEvent)
+}
+
+// sendDomainEventWebhook delivers the domain event, its id is the one of the webhook event
+func sendDomainEventWebhook(webhook *Webhook, domainEvent *DomainEvent) (int, string, error) {
+	body := getDomainEventBody(domainEvent, webhook.PayloadFormat)
+	return postWebhook(webhook, body, domainEvent.Id)
+}
 
-	req, err := http.NewRequest(webhook.Method, webhook.Url, body)
+func postWebhook(webhook *Webhook, body string, eventId string) (int, string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest(webhook.Method, webhook.Url, strings.NewReader(body))
 	if err != nil {
 		return 0, "", err
 	}
 
-	req.Header.Set("Content-Type", webhook.ContentType)
+	if webhook.PayloadFormat == WebhookPayloadFormatCloudEvents {
+		req.Header.Set("Content-Type", cloudEventContentType)
+	} else {
+		req.Header.Set("Content-Type", webhook.ContentType)
+	}
 
 	for _, header := range webhook.Headers {
 		req.Header.Set(header.Name, header.Value)
 	}
 
+	// the timestamp is the one of the attempt, the receivers reject the deliveries that are too old
+	// to protect themselves against replays
+	timestamp := time.Now().Unix()
+	req.Header.Set(webhookIdHeader, eventId)
+	req.Header.Set(webhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	if signature := getWebhookSignature(webhook, eventId, timestamp, body); signature != "" {
+		req.Header.Set(webhookSignatureHeader, signature)
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return 0, "", err
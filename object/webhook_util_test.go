@@ -0,0 +1,162 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// verifyWebhookSignature checks a delivery the way a Standard Webhooks receiver does
+func verifyWebhookSignature(secret string, header http.Header, body string) bool {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, "whsec_"))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(header.Get("webhook-id") + "." + header.Get("webhook-timestamp") + "." + body))
+	expected := "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	for _, signature := range strings.Split(header.Get("webhook-signature"), " ") {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSendWebhookSignature(t *testing.T) {
+	var header http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		bodyBytes, _ := io.ReadAll(r.Body)
+		body = string(bodyBytes)
+	}))
+	defer server.Close()
+
+	oldSecret := generateWebhookSigningSecret()
+	newSecret := generateWebhookSigningSecret()
+	webhook := &Webhook{Url: server.URL, Method: "POST", ContentType: "application/json", SigningSecret: oldSecret}
+	record := &Record{Owner: "built-in", Name: "record", Action: "login"}
+
+	_, _, err := sendWebhook(webhook, record, nil, "event-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("webhook-id") != "event-1" || header.Get("webhook-timestamp") == "" {
+		t.Fatalf("unexpected webhook headers: %v", header)
+	}
+	if !verifyWebhookSignature(oldSecret, header, body) {
+		t.Errorf("the delivery isn't signed by the signing secret: %s", header.Get("webhook-signature"))
+	}
+
+	// while the secret is rotated, the receivers holding either secret accept the delivery
+	webhook.SigningSecret, webhook.PreviousSigningSecret = newSecret, oldSecret
+	_, _, err = sendWebhook(webhook, record, nil, "event-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifyWebhookSignature(newSecret, header, body) || !verifyWebhookSignature(oldSecret, header, body) {
+		t.Errorf("the delivery isn't signed by both secrets during the rotation: %s", header.Get("webhook-signature"))
+	}
+
+	if verifyWebhookSignature(newSecret, header, strings.Replace(body, "login", "logout", 1)) {
+		t.Errorf("a tampered delivery is accepted")
+	}
+
+	webhook.SigningSecret, webhook.PreviousSigningSecret = "", ""
+	_, _, err = sendWebhook(webhook, record, nil, "event-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("webhook-signature") != "" {
+		t.Errorf("a webhook without a secret sends a signature: %s", header.Get("webhook-signature"))
+	}
+}
+
+func TestSendDomainEventWebhook(t *testing.T) {
+	var header http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		bodyBytes, _ := io.ReadAll(r.Body)
+		body = string(bodyBytes)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{Url: server.URL, Method: "POST", ContentType: "application/json"}
+	domainEvent := &DomainEvent{
+		Id:           "event-1",
+		Type:         DomainEventUserPasswordChanged,
+		Organization: "built-in",
+		Subject:      "built-in/admin",
+		Time:         "2026-01-01T00:00:00Z",
+		Data:         DomainEventData{Before: &User{Owner: "built-in", Name: "admin"}, After: &User{Owner: "built-in", Name: "admin", PasswordType: "bcrypt"}},
+	}
+
+	_, _, err := sendDomainEventWebhook(webhook, domainEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var casdoorEvent map[string]interface{}
+	err = json.Unmarshal([]byte(body), &casdoorEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("Content-Type") != "application/json" || casdoorEvent["id"] != "event-1" || casdoorEvent["type"] != DomainEventUserPasswordChanged {
+		t.Errorf("unexpected domain event delivery: %s %s", header.Get("Content-Type"), body)
+	}
+
+	webhook.PayloadFormat = WebhookPayloadFormatCloudEvents
+	_, _, err = sendDomainEventWebhook(webhook, domainEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cloudEvent CloudEvent
+	err = json.Unmarshal([]byte(body), &cloudEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("Content-Type") != "application/cloudevents+json" {
+		t.Errorf("unexpected content type of a CloudEvent: %s", header.Get("Content-Type"))
+	}
+	if cloudEvent.SpecVersion != "1.0" || cloudEvent.Id != "event-1" || cloudEvent.Source != "/organizations/built-in" ||
+		cloudEvent.Type != "org.casdoor.user.password_changed" || cloudEvent.Subject != "built-in/admin" || cloudEvent.Time != domainEvent.Time {
+		t.Errorf("unexpected CloudEvent attributes: %s", body)
+	}
+	if header.Get("webhook-id") != cloudEvent.Id {
+		t.Errorf("the webhook id %s isn't the id of the CloudEvent %s", header.Get("webhook-id"), cloudEvent.Id)
+	}
+
+	var data DomainEventData
+	err = json.Unmarshal(cloudEvent.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Before == nil || data.After.(map[string]interface{})["passwordType"] != "bcrypt" {
+		t.Errorf("unexpected CloudEvent data: %s", string(cloudEvent.Data))
+	}
+}
@@ -39,6 +39,20 @@ func StartWebhookDeliveryWorker() {
 		logs.Error("failed to check webhooks, webhook delivery worker not started: " + err.Error())
 		return
 	}
+	if !has {
+		has, err = HasAnyEventSinks()
+		if err != nil {
+			logs.Error("failed to check event sinks, webhook delivery worker not started: " + err.Error())
+			return
+		}
+	}
+	if !has {
+		has, err = HasAnySsfStreams()
+		if err != nil {
+			logs.Error("failed to check Shared Signals streams, webhook delivery worker not started: " + err.Error())
+			return
+		}
+	}
 	if !has {
 		return
 	}
@@ -112,6 +126,15 @@ func processWebhookEvents() {
 
 // deliverWebhookEvent attempts to deliver a single webhook event
 func deliverWebhookEvent(event *WebhookEvent) {
+	if event.EventSink != "" {
+		deliverEventSinkEvent(event)
+		return
+	}
+	if event.SsfStream != "" {
+		deliverSsfEvent(event)
+		return
+	}
+
 	// Get the webhook configuration
 	webhook, err := GetWebhook(event.Webhook)
 	if err != nil {
@@ -134,9 +157,16 @@ func deliverWebhookEvent(event *WebhookEvent) {
 		return
 	}
 
-	// Parse the record from payload
+	// Parse the record or the domain event from payload
 	var record Record
-	err = json.Unmarshal([]byte(event.Payload), &record)
+	var domainEvent DomainEvent
+	if event.IsDomainEvent {
+		err = json.Unmarshal([]byte(event.Payload), &domainEvent)
+		// the failed deliveries of a domain event are recorded for its organization
+		record = Record{Owner: domainEvent.Organization, Organization: domainEvent.Organization, Action: domainEvent.Type}
+	} else {
+		err = json.Unmarshal([]byte(event.Payload), &record)
+	}
 	if err != nil {
 		event.State = WebhookEventStatusFailed
 		event.LastError = fmt.Sprintf("Invalid payload: %v", err)
@@ -159,22 +189,34 @@ func deliverWebhookEvent(event *WebhookEvent) {
 	event.AttemptCount++
 
 	// Attempt to send the webhook
-	statusCode, respBody, err := sendWebhook(webhook, &record, extendedUser)
+	var statusCode int
+	var respBody string
+	if event.IsDomainEvent {
+		statusCode, respBody, err = sendDomainEventWebhook(webhook, &domainEvent)
+	} else {
+		statusCode, respBody, err = sendWebhook(webhook, &record, extendedUser, event.Name)
+	}
 
 	// Add webhook record for backward compatibility (only if non-200 status)
 	if statusCode != 200 {
 		addWebhookRecord(webhook, &record, statusCode, respBody, err)
 	}
 
+	finishWebhookEventDelivery(event, err == nil && statusCode >= 200 && statusCode < 300, webhook.MaxRetries, webhook.RetryInterval, webhook.UseExponentialBackoff, statusCode, respBody, err)
+}
+
+// finishWebhookEventDelivery records the result of a delivery attempt, a failed event is retried
+// until it reaches the max retries of its webhook or event sink
+func finishWebhookEventDelivery(event *WebhookEvent, succeeded bool, defaultMaxRetries int, retryInterval int, useExponentialBackoff bool, statusCode int, respBody string, err error) {
 	// Determine the result
-	if err == nil && statusCode >= 200 && statusCode < 300 {
+	if succeeded {
 		// Success
 		UpdateWebhookEventState(event, WebhookEventStatusSuccess, statusCode, respBody, nil)
 	} else {
 		// Failed - decide whether to retry
 		maxRetries := event.MaxRetries
 		if maxRetries <= 0 {
-			maxRetries = webhook.MaxRetries
+			maxRetries = defaultMaxRetries
 		}
 		if maxRetries <= 0 {
 			maxRetries = 3 // Default
@@ -186,12 +228,11 @@ func deliverWebhookEvent(event *WebhookEvent) {
 			UpdateWebhookEventState(event, WebhookEventStatusFailed, statusCode, respBody, err)
 		} else {
 			// Schedule retry
-			retryInterval := webhook.RetryInterval
 			if retryInterval <= 0 {
 				retryInterval = 60 // Default 60 seconds
 			}
 
-			nextRetryTime := calculateNextRetryTime(event.AttemptCount, retryInterval, webhook.UseExponentialBackoff)
+			nextRetryTime := calculateNextRetryTime(event.AttemptCount, retryInterval, useExponentialBackoff)
 			event.NextRetryTime = nextRetryTime
 			event.State = WebhookEventStatusRetrying
 
@@ -31,19 +31,33 @@ type OidcDiscovery struct {
 	Issuer                                 string   `json:"issuer"`
 	AuthorizationEndpoint                  string   `json:"authorization_endpoint"`
 	TokenEndpoint                          string   `json:"token_endpoint"`
+	TokenEndpointAuthMethodsSupported      []string `json:"token_endpoint_auth_methods_supported"`
 	UserinfoEndpoint                       string   `json:"userinfo_endpoint"`
 	DeviceAuthorizationEndpoint            string   `json:"device_authorization_endpoint"`
 	RegistrationEndpoint                   string   `json:"registration_endpoint,omitempty"`
 	JwksUri                                string   `json:"jwks_uri"`
 	IntrospectionEndpoint                  string   `json:"introspection_endpoint"`
+	RevocationEndpoint                     string   `json:"revocation_endpoint"`                        // RFC 7009
+	RevocationEndpointAuthMethodsSupported []string `json:"revocation_endpoint_auth_methods_supported"` // RFC 8414
+	TlsClientCertificateBoundAccessTokens  bool     `json:"tls_client_certificate_bound_access_tokens"` // RFC 8705
+	PushedAuthorizationRequestEndpoint     string   `json:"pushed_authorization_request_endpoint"`      // RFC 9126
+	RequirePushedAuthorizationRequests     bool     `json:"require_pushed_authorization_requests"`      // RFC 9126
+	BackchannelAuthenticationEndpoint      string   `json:"backchannel_authentication_endpoint"`        // OpenID Connect CIBA
+	BackchannelTokenDeliveryModesSupported []string `json:"backchannel_token_delivery_modes_supported"` // OpenID Connect CIBA
+	BackchannelUserCodeParameterSupported  bool     `json:"backchannel_user_code_parameter_supported"`  // OpenID Connect CIBA
 	ResponseTypesSupported                 []string `json:"response_types_supported"`
 	ResponseModesSupported                 []string `json:"response_modes_supported"`
 	GrantTypesSupported                    []string `json:"grant_types_supported"`
 	SubjectTypesSupported                  []string `json:"subject_types_supported"`
 	IdTokenSigningAlgValuesSupported       []string `json:"id_token_signing_alg_values_supported"`
+	IdTokenEncryptionAlgValuesSupported    []string `json:"id_token_encryption_alg_values_supported"`
+	IdTokenEncryptionEncValuesSupported    []string `json:"id_token_encryption_enc_values_supported"`
+	UserinfoEncryptionAlgValuesSupported   []string `json:"userinfo_encryption_alg_values_supported"`
+	UserinfoEncryptionEncValuesSupported   []string `json:"userinfo_encryption_enc_values_supported"`
 	ScopesSupported                        []string `json:"scopes_supported"`
 	CodeChallengeMethodsSupported          []string `json:"code_challenge_methods_supported"`
 	ClaimsSupported                        []string `json:"claims_supported"`
+	AcrValuesSupported                     []string `json:"acr_values_supported"`
 	RequestParameterSupported              bool     `json:"request_parameter_supported"`
 	RequestObjectSigningAlgValuesSupported []string `json:"request_object_signing_alg_values_supported"`
 	EndSessionEndpoint                     string   `json:"end_session_endpoint"`
@@ -135,10 +149,15 @@ func GetOidcDiscovery(host string, applicationName string) OidcDiscovery {
 	scopes := []string{"openid", "email", "profile", "address", "phone", "offline_access"}
 
 	// Merge application-specific custom scopes if application is provided
+	var application *Application
 	if applicationName != "" {
 		applicationId := util.GetId("admin", applicationName)
-		application, err := GetApplication(applicationId)
-		if err == nil && application != nil && len(application.Scopes) > 0 {
+		var err error
+		application, err = GetApplication(applicationId)
+		if err != nil {
+			application = nil
+		}
+		if application != nil && len(application.Scopes) > 0 {
 			for _, scope := range application.Scopes {
 				// Add custom scope names to the scopes list
 				if scope.Name != "" {
@@ -157,21 +176,35 @@ func GetOidcDiscovery(host string, applicationName string) OidcDiscovery {
 		Issuer:                                 issuer,
 		AuthorizationEndpoint:                  fmt.Sprintf("%s/login/oauth/authorize", originFrontend),
 		TokenEndpoint:                          fmt.Sprintf("%s/api/login/oauth/access_token", originBackend),
+		TokenEndpointAuthMethodsSupported:      []string{"client_secret_basic", "client_secret_post", "private_key_jwt", TlsClientAuth, SelfSignedTlsClientAuth},
 		UserinfoEndpoint:                       fmt.Sprintf("%s/api/userinfo", originBackend),
 		DeviceAuthorizationEndpoint:            fmt.Sprintf("%s/api/device-auth", originBackend),
 		RegistrationEndpoint:                   fmt.Sprintf("%s/api/oauth/register", originBackend),
 		JwksUri:                                jwksUri,
 		IntrospectionEndpoint:                  fmt.Sprintf("%s/api/login/oauth/introspect", originBackend),
+		RevocationEndpoint:                     fmt.Sprintf("%s/api/login/oauth/revoke", originBackend),
+		RevocationEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "private_key_jwt", TlsClientAuth, SelfSignedTlsClientAuth},
+		TlsClientCertificateBoundAccessTokens:  true,
+		PushedAuthorizationRequestEndpoint:     fmt.Sprintf("%s/api/login/oauth/par", originBackend),
+		RequirePushedAuthorizationRequests:     application != nil && application.RequirePushedAuthorizationRequests,
+		BackchannelAuthenticationEndpoint:      fmt.Sprintf("%s/api/login/oauth/bc-authorize", originBackend),
+		BackchannelTokenDeliveryModesSupported: []string{CibaModePoll, CibaModePing},
+		BackchannelUserCodeParameterSupported:  false,
 		ResponseTypesSupported:                 []string{"code", "token", "id_token", "code token", "code id_token", "token id_token", "code token id_token", "none"},
 		ResponseModesSupported:                 []string{"query", "fragment", "form_post"},
-		GrantTypesSupported:                    []string{"authorization_code", "implicit", "password", "client_credentials", "refresh_token", "urn:ietf:params:oauth:grant-type:device_code", "urn:ietf:params:oauth:grant-type:token-exchange"},
+		GrantTypesSupported:                    []string{"authorization_code", "implicit", "password", "client_credentials", "refresh_token", "urn:ietf:params:oauth:grant-type:device_code", "urn:ietf:params:oauth:grant-type:token-exchange", CibaGrantType},
 		SubjectTypesSupported:                  []string{"public"},
 		IdTokenSigningAlgValuesSupported:       []string{"RS256", "RS512", "ES256", "ES384", "ES512"},
+		IdTokenEncryptionAlgValuesSupported:    EncryptionAlgs,
+		IdTokenEncryptionEncValuesSupported:    EncryptionEncs,
+		UserinfoEncryptionAlgValuesSupported:   EncryptionAlgs,
+		UserinfoEncryptionEncValuesSupported:   EncryptionEncs,
 		ScopesSupported:                        scopes,
 		CodeChallengeMethodsSupported:          []string{"S256"},
-		ClaimsSupported:                        []string{"iss", "ver", "sub", "aud", "iat", "exp", "id", "type", "displayName", "avatar", "permanentAvatar", "email", "phone", "location", "affiliation", "title", "homepage", "bio", "tag", "region", "language", "score", "ranking", "isOnline", "isAdmin", "isForbidden", "signupApplication", "ldap"},
+		ClaimsSupported:                        []string{"iss", "ver", "sub", "aud", "iat", "exp", "id", "type", "displayName", "avatar", "permanentAvatar", "email", "phone", "location", "affiliation", "title", "homepage", "bio", "tag", "region", "language", "score", "ranking", "isOnline", "isAdmin", "isForbidden", "signupApplication", "ldap", "acr", "amr", "auth_time"},
+		AcrValuesSupported:                     AcrValuesSupported,
 		RequestParameterSupported:              true,
-		RequestObjectSigningAlgValuesSupported: []string{"HS256", "HS384", "HS512"},
+		RequestObjectSigningAlgValuesSupported: RequestObjectSigningAlgs,
 		EndSessionEndpoint:                     fmt.Sprintf("%s/api/logout", originBackend),
 		BackchannelLogoutSupported:             true,
 		BackchannelLogoutSessionSupported:      true,
@@ -220,20 +253,26 @@ func GetJsonWebKeySet(applicationName string) (jose.JSONWebKeySet, error) {
 			return jwks, fmt.Errorf("the certificate field should not be empty for the cert: %v", cert)
 		}
 
-		certPemBlock := []byte(cert.Certificate)
-		certDerBlock, _ := pem.Decode(certPemBlock)
-		x509Cert, err := x509.ParseCertificate(certDerBlock.Bytes)
-		if err != nil {
-			return jwks, err
-		}
+		// A cert under rotation also publishes its next and retired keys
+		for _, key := range cert.getPublishedKeys() {
+			certPemBlock := []byte(key.Certificate)
+			certDerBlock, _ := pem.Decode(certPemBlock)
+			if certDerBlock == nil {
+				return jwks, fmt.Errorf("failed to decode the certificate of the key: %s", key.KeyId)
+			}
+			x509Cert, err := x509.ParseCertificate(certDerBlock.Bytes)
+			if err != nil {
+				return jwks, err
+			}
 
-		var jwk jose.JSONWebKey
-		jwk.Key = x509Cert.PublicKey
-		jwk.Certificates = []*x509.Certificate{x509Cert}
-		jwk.KeyID = cert.Name
-		jwk.Algorithm = cert.CryptoAlgorithm
-		jwk.Use = "sig"
-		jwks.Keys = append(jwks.Keys, jwk)
+			var jwk jose.JSONWebKey
+			jwk.Key = x509Cert.PublicKey
+			jwk.Certificates = []*x509.Certificate{x509Cert}
+			jwk.KeyID = key.KeyId
+			jwk.Algorithm = cert.CryptoAlgorithm
+			jwk.Use = "sig"
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
 	}
 
 	return jwks, nil
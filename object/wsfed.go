@@ -0,0 +1,351 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/casdoor/casdoor/util"
+)
+
+// The actions of the WS-Federation passive requestor profile, see
+// http://docs.oasis-open.org/wsfed/federation/v1.2/os/ws-federation-1.2-spec-os.html
+const (
+	WsFedActionSignin         = "wsignin1.0"
+	WsFedActionSignout        = "wsignout1.0"
+	WsFedActionSignoutCleanup = "wsignoutcleanup1.0"
+)
+
+const (
+	wsFedNamespace       = "http://docs.oasis-open.org/wsfed/federation/200706"
+	wsFedAuthNamespace   = "http://docs.oasis-open.org/wsfed/authorization/200706"
+	wsTrustNamespace     = "http://schemas.xmlsoap.org/ws/2005/02/trust"
+	wsPolicyNamespace    = "http://schemas.xmlsoap.org/ws/2004/09/policy"
+	wsAddressNamespace   = "http://www.w3.org/2005/08/addressing"
+	wsUtilityNamespace   = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
+	wsFedSaml11TokenType = "urn:oasis:names:tc:SAML:1.0:assertion"
+	wsFedClaimNamespace  = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims"
+	wsFedRoleNamespace   = "http://schemas.microsoft.com/ws/2008/06/identity/claims"
+)
+
+type wsFedClaim struct {
+	Namespace   string
+	Name        string
+	DisplayName string
+	Values      []string
+}
+
+var wsFedClaimTypes = []wsFedClaim{
+	{Namespace: wsFedClaimNamespace, Name: "name", DisplayName: "Name"},
+	{Namespace: wsFedClaimNamespace, Name: "emailaddress", DisplayName: "E-Mail Address"},
+	{Namespace: wsFedClaimNamespace, Name: "upn", DisplayName: "UPN"},
+	{Namespace: wsFedClaimNamespace, Name: "givenname", DisplayName: "Given Name"},
+	{Namespace: wsFedClaimNamespace, Name: "surname", DisplayName: "Surname"},
+	{Namespace: wsFedRoleNamespace, Name: "role", DisplayName: "Role"},
+}
+
+// getWsFedClaims returns the claims issued to the relying parties, which are the claim types of AD FS
+// in wsFedClaimTypes followed by the SAML attributes of the application whose names are claim type URIs
+func getWsFedClaims(application *Application, user *User) ([]wsFedClaim, error) {
+	err := ExtendUserWithRolesAndPermissions(user)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{}
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
+
+	values := map[string][]string{
+		"name":         {user.Name},
+		"emailaddress": {user.Email},
+		"upn":          {user.Email},
+		"givenname":    {user.FirstName},
+		"surname":      {user.LastName},
+		"role":         roles,
+	}
+
+	claims := []wsFedClaim{}
+	for _, claimType := range wsFedClaimTypes {
+		claimType.Values = values[claimType.Name]
+		claims = append(claims, claimType)
+	}
+
+	for _, item := range application.SamlAttributes {
+		index := strings.LastIndex(item.Name, "/")
+		if index <= 0 {
+			continue
+		}
+
+		claims = append(claims, wsFedClaim{Namespace: item.Name[:index], Name: item.Name[index+1:], Values: replaceAttributeValue(user, item.Value)})
+	}
+
+	return claims, nil
+}
+
+// newWsFedAssertion returns the SAML 1.1 assertion issued to the relying party of the realm,
+// SAML 1.1 rather than 2.0 is the token type which the WS-Federation relying parties like SharePoint expect
+func newWsFedAssertion(application *Application, user *User, claims []wsFedClaim, issuer string, realm string, now time.Time, expireTime time.Time) *etree.Element {
+	assertion := &etree.Element{
+		Space: "saml",
+		Tag:   "Assertion",
+	}
+	assertion.CreateAttr("xmlns:saml", "urn:oasis:names:tc:SAML:1.0:assertion")
+	assertion.CreateAttr("MajorVersion", "1")
+	assertion.CreateAttr("MinorVersion", "1")
+	assertion.CreateAttr("AssertionID", fmt.Sprintf("_%s", util.GenerateUUID()))
+	assertion.CreateAttr("Issuer", issuer)
+	assertion.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+
+	condition := assertion.CreateElement("saml:Conditions")
+	condition.CreateAttr("NotBefore", now.Format(time.RFC3339))
+	condition.CreateAttr("NotOnOrAfter", expireTime.Format(time.RFC3339))
+	condition.CreateElement("saml:AudienceRestrictionCondition").CreateElement("saml:Audience").SetText(realm)
+
+	nameIdValue, nameIdFormat := getSamlNameId(application, user)
+	createSubject := func(parent *etree.Element) {
+		subject := parent.CreateElement("saml:Subject")
+		nameIdentifier := subject.CreateElement("saml:NameIdentifier")
+		nameIdentifier.CreateAttr("Format", nameIdFormat)
+		nameIdentifier.SetText(nameIdValue)
+		subject.CreateElement("saml:SubjectConfirmation").CreateElement("saml:ConfirmationMethod").SetText("urn:oasis:names:tc:SAML:1.0:cm:bearer")
+	}
+
+	attributeStatement := assertion.CreateElement("saml:AttributeStatement")
+	createSubject(attributeStatement)
+	for _, claim := range claims {
+		values := []string{}
+		for _, value := range claim.Values {
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		attribute := attributeStatement.CreateElement("saml:Attribute")
+		attribute.CreateAttr("AttributeName", claim.Name)
+		attribute.CreateAttr("AttributeNamespace", claim.Namespace)
+		for _, value := range values {
+			attribute.CreateElement("saml:AttributeValue").SetText(value)
+		}
+	}
+
+	authenticationStatement := assertion.CreateElement("saml:AuthenticationStatement")
+	authenticationStatement.CreateAttr("AuthenticationMethod", "urn:oasis:names:tc:SAML:1.0:am:password")
+	authenticationStatement.CreateAttr("AuthenticationInstant", now.Format(time.RFC3339))
+	createSubject(authenticationStatement)
+
+	return assertion
+}
+
+// newWsFedRstr wraps the signed assertion into the RequestSecurityTokenResponse posted as wresult
+func newWsFedRstr(assertion *etree.Element, realm string, now time.Time, expireTime time.Time) *etree.Element {
+	rstr := &etree.Element{
+		Space: "t",
+		Tag:   "RequestSecurityTokenResponse",
+	}
+	rstr.CreateAttr("xmlns:t", wsTrustNamespace)
+
+	lifetime := rstr.CreateElement("t:Lifetime")
+	created := lifetime.CreateElement("wsu:Created")
+	created.CreateAttr("xmlns:wsu", wsUtilityNamespace)
+	created.SetText(now.Format(time.RFC3339))
+	expires := lifetime.CreateElement("wsu:Expires")
+	expires.CreateAttr("xmlns:wsu", wsUtilityNamespace)
+	expires.SetText(expireTime.Format(time.RFC3339))
+
+	appliesTo := rstr.CreateElement("wsp:AppliesTo")
+	appliesTo.CreateAttr("xmlns:wsp", wsPolicyNamespace)
+	endpointReference := appliesTo.CreateElement("wsa:EndpointReference")
+	endpointReference.CreateAttr("xmlns:wsa", wsAddressNamespace)
+	endpointReference.CreateElement("wsa:Address").SetText(realm)
+
+	rstr.CreateElement("t:RequestedSecurityToken").AddChild(assertion)
+	rstr.CreateElement("t:TokenType").SetText(wsFedSaml11TokenType)
+	rstr.CreateElement("t:RequestType").SetText(wsTrustNamespace + "/Issue")
+	rstr.CreateElement("t:KeyType").SetText("http://schemas.xmlsoap.org/ws/2005/05/identity/NoProofKey")
+	return rstr
+}
+
+// GetWsFedResponse returns the RequestSecurityTokenResponse carrying the signed SAML 1.1 assertion for the realm,
+// which is posted as wresult to the returned reply URL. The reply URL defaults to the realm when wreply is absent.
+// The relying party is recorded for the Beego session sessionId as a WsFedSession, to be signed out along with it.
+func GetWsFedResponse(application *Application, user *User, realm string, reply string, host string, sessionId string) (string, string, error) {
+	if realm == "" {
+		return "", "", errors.New("the WS-Federation request doesn't have the wtrealm parameter")
+	}
+	if !application.IsRedirectUriValid(realm) {
+		return "", "", fmt.Errorf("the realm: %s doesn't exist in the allowed Redirect URI list", realm)
+	}
+
+	if reply == "" {
+		reply = realm
+	}
+	if !strings.HasPrefix(reply, "http://") && !strings.HasPrefix(reply, "https://") {
+		return "", "", fmt.Errorf("the reply URL: %s is not an HTTP URL", reply)
+	}
+	if !application.IsRedirectUriValid(reply) {
+		return "", "", fmt.Errorf("the reply URL: %s doesn't exist in the allowed Redirect URI list", reply)
+	}
+
+	cert, err := getCertByApplication(application)
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, err := getSamlSigningContext(application, cert)
+	if err != nil {
+		return "", "", err
+	}
+	ctx.IdAttribute = "AssertionID"
+
+	_, originBackend := getOriginFromHost(host)
+	now := time.Now().UTC()
+	expireTime := now.Add(time.Hour)
+	if application.ExpireInHours > 0 {
+		expireTime = now.Add(time.Duration(application.ExpireInHours * float64(time.Hour)))
+	}
+
+	claims, err := getWsFedClaims(application, user)
+	if err != nil {
+		return "", "", err
+	}
+
+	// the signature of a SAML 1.1 assertion comes after its statements
+	assertion := newWsFedAssertion(application, user, claims, originBackend, realm, now, expireTime)
+	sig, err := ctx.ConstructSignature(assertion, true)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign the WS-Federation assertion, %s", err.Error())
+	}
+	assertion.AddChild(sig)
+
+	rstr := newWsFedRstr(assertion, realm, now, expireTime)
+	doc := etree.NewDocument()
+	doc.SetRoot(rstr)
+	res, err := doc.WriteToString()
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = AddWsFedSession(&WsFedSession{
+		Owner:        application.Owner,
+		Application:  application.Name,
+		Organization: user.Owner,
+		User:         user.Name,
+		SessionId:    sessionId,
+		Realm:        realm,
+		Reply:        reply,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return res, reply, nil
+}
+
+// getWsFedLocation returns the passive requestor endpoint of the application
+func getWsFedLocation(application *Application, host string) string {
+	_, originBackend := getOriginFromHost(host)
+	return fmt.Sprintf("%s/api/wsfed/%s/%s", originBackend, application.Owner, application.Name)
+}
+
+// GetWsFedRedirectAddress returns the login page which signs the user in for a wsignin1.0 request
+func GetWsFedRedirectAddress(owner string, application string, realm string, reply string, wctx string, host string) string {
+	originFrontend, _ := getOriginFromHost(host)
+	query := url.Values{}
+	query.Set("wtrealm", realm)
+	if reply != "" {
+		query.Set("wreply", reply)
+	}
+	if wctx != "" {
+		query.Set("wctx", wctx)
+	}
+	return fmt.Sprintf("%s/login/wsfed/authorize/%s/%s?%s", originFrontend, owner, application, query.Encode())
+}
+
+// GetWsFedMetadata returns the signed FederationMetadata.xml of the application, which publishes
+// the passive requestor endpoint, the signing certificate and the offered claim types
+func GetWsFedMetadata(application *Application, host string) (string, error) {
+	cert, err := getCertByApplication(application)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, err := getSamlSigningContext(application, cert)
+	if err != nil {
+		return "", err
+	}
+
+	block, _ := pem.Decode([]byte(cert.Certificate))
+	if block == nil {
+		return "", fmt.Errorf("failed to decode the certificate of the cert: %s", cert.GetId())
+	}
+	certificate := base64.StdEncoding.EncodeToString(block.Bytes)
+
+	_, originBackend := getOriginFromHost(host)
+	location := getWsFedLocation(application, host)
+
+	entityDescriptor := &etree.Element{Tag: "EntityDescriptor"}
+	entityDescriptor.CreateAttr("xmlns", "urn:oasis:names:tc:SAML:2.0:metadata")
+	entityDescriptor.CreateAttr("ID", fmt.Sprintf("_%s", util.GenerateUUID()))
+	entityDescriptor.CreateAttr("entityID", originBackend)
+
+	roleDescriptor := entityDescriptor.CreateElement("RoleDescriptor")
+	roleDescriptor.CreateAttr("xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance")
+	roleDescriptor.CreateAttr("xmlns:fed", wsFedNamespace)
+	roleDescriptor.CreateAttr("xsi:type", "fed:SecurityTokenServiceType")
+	roleDescriptor.CreateAttr("protocolSupportEnumeration", wsFedNamespace)
+
+	keyDescriptor := roleDescriptor.CreateElement("KeyDescriptor")
+	keyDescriptor.CreateAttr("use", "signing")
+	keyInfo := keyDescriptor.CreateElement("KeyInfo")
+	keyInfo.CreateAttr("xmlns", xmlDsigNamespace)
+	keyInfo.CreateElement("X509Data").CreateElement("X509Certificate").SetText(certificate)
+
+	claimTypesOffered := roleDescriptor.CreateElement("fed:ClaimTypesOffered")
+	for _, claim := range wsFedClaimTypes {
+		claimType := claimTypesOffered.CreateElement("auth:ClaimType")
+		claimType.CreateAttr("xmlns:auth", wsFedAuthNamespace)
+		claimType.CreateAttr("Uri", fmt.Sprintf("%s/%s", claim.Namespace, claim.Name))
+		claimType.CreateAttr("Optional", "true")
+		claimType.CreateElement("auth:DisplayName").SetText(claim.DisplayName)
+	}
+
+	for _, tag := range []string{"fed:SecurityTokenServiceEndpoint", "fed:PassiveRequestorEndpoint"} {
+		endpointReference := roleDescriptor.CreateElement(tag).CreateElement("wsa:EndpointReference")
+		endpointReference.CreateAttr("xmlns:wsa", wsAddressNamespace)
+		endpointReference.CreateElement("wsa:Address").SetText(location)
+	}
+
+	signed, err := ctx.SignEnveloped(entityDescriptor)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the WS-Federation metadata, %s", err.Error())
+	}
+
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	doc.SetRoot(signed)
+	return doc.WriteToString()
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/url"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/beego/beego/v2/server/web"
+	"github.com/casdoor/casdoor/util"
+)
+
+// WsFedSession records that a Beego session has signed in to a relying party by WS-Federation, so that
+// the relying party can be signed out with wsignoutcleanup1.0 when the session signs out.
+type WsFedSession struct {
+	Owner        string `xorm:"varchar(100) notnull pk" json:"owner"`
+	Name         string `xorm:"varchar(100) notnull pk" json:"name"`
+	CreatedTime  string `xorm:"varchar(100)" json:"createdTime"`
+	Application  string `xorm:"varchar(100)" json:"application"`
+	Organization string `xorm:"varchar(100) index" json:"organization"`
+	User         string `xorm:"varchar(100) index" json:"user"`
+	SessionId    string `xorm:"varchar(100) index" json:"sessionId"`
+	Realm        string `xorm:"varchar(500)" json:"realm"`
+	Reply        string `xorm:"varchar(500)" json:"reply"`
+}
+
+var wsFedSignoutTemplate = template.Must(template.New("wsFedSignout").Parse(`<!DOCTYPE html>
+<html>
+<body{{if .RedirectUrl}} onload="window.location.href = '{{.RedirectUrl}}'"{{end}}>
+{{range .CleanupUrls}}<iframe src="{{.}}" style="display:none"></iframe>
+{{end}}{{if .RedirectUrl}}<noscript><a href="{{.RedirectUrl}}">Continue</a></noscript>{{end}}
+</body>
+</html>`))
+
+// AddWsFedSession records the relying party for the Beego session, a relying party signed in to again
+// by the same session is recorded only once
+func AddWsFedSession(wsFedSession *WsFedSession) (bool, error) {
+	existed, err := ormer.Engine.Exist(&WsFedSession{Owner: wsFedSession.Owner, Application: wsFedSession.Application, SessionId: wsFedSession.SessionId, Realm: wsFedSession.Realm})
+	if err != nil {
+		return false, err
+	}
+	if existed {
+		return false, nil
+	}
+
+	wsFedSession.Name = util.GenerateId()
+	wsFedSession.CreatedTime = util.GetCurrentTime()
+	affected, err := ormer.Engine.Insert(wsFedSession)
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// GetWsFedSessionsBySessionId returns the relying parties the Beego session has signed in to
+func GetWsFedSessionsBySessionId(organization string, user string, sessionId string) ([]*WsFedSession, error) {
+	wsFedSessions := []*WsFedSession{}
+	err := ormer.Engine.Where(fmt.Sprintf("organization = ? and %s = ? and session_id = ?", quoteColumn("user")), organization, user, sessionId).Find(&wsFedSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return wsFedSessions, nil
+}
+
+func DeleteWsFedSession(wsFedSession *WsFedSession) (bool, error) {
+	affected, err := ormer.Engine.Delete(&WsFedSession{Owner: wsFedSession.Owner, Name: wsFedSession.Name})
+	if err != nil {
+		return false, err
+	}
+
+	return affected != 0, nil
+}
+
+// PopWsFedSignoutCleanupUrls drops the WS-Federation sessions of the Beego session and returns the wsignoutcleanup1.0
+// URLs of their relying parties, except the one of realm which has asked for the sign-out
+func PopWsFedSignoutCleanupUrls(organization string, user string, sessionId string, realm string) ([]string, error) {
+	wsFedSessions, err := GetWsFedSessionsBySessionId(organization, user, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanupUrls := []string{}
+	for _, wsFedSession := range wsFedSessions {
+		_, err = DeleteWsFedSession(wsFedSession)
+		if err != nil {
+			return nil, err
+		}
+
+		if wsFedSession.Realm == realm {
+			continue
+		}
+
+		cleanupUrl, err := url.Parse(wsFedSession.Reply)
+		if err != nil {
+			continue
+		}
+		query := cleanupUrl.Query()
+		query.Set("wa", WsFedActionSignoutCleanup)
+		cleanupUrl.RawQuery = query.Encode()
+		cleanupUrls = append(cleanupUrls, cleanupUrl.String())
+	}
+
+	return cleanupUrls, nil
+}
+
+// GetWsFedSignoutHtml renders the page which signs the user out of the relying parties in hidden frames,
+// then goes on to redirectUrl when it isn't empty
+func GetWsFedSignoutHtml(cleanupUrls []string, redirectUrl string) (string, error) {
+	var buffer bytes.Buffer
+	err := wsFedSignoutTemplate.Execute(&buffer, map[string]interface{}{"CleanupUrls": cleanupUrls, "RedirectUrl": redirectUrl})
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// CleanupWsFedSessions drops the WS-Federation sessions whose Beego session has expired or been destroyed without a sign-out
+func CleanupWsFedSessions() error {
+	wsFedSessions := []*WsFedSession{}
+	err := ormer.Engine.Find(&wsFedSessions)
+	if err != nil {
+		return err
+	}
+
+	for _, wsFedSession := range wsFedSessions {
+		existed, err := web.GlobalSessions.GetProvider().SessionExist(context.Background(), wsFedSession.SessionId)
+		if err != nil {
+			return err
+		}
+		if existed {
+			continue
+		}
+
+		_, err = DeleteWsFedSession(wsFedSession)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func InitCleanupWsFedSessions() {
+	util.SafeGoroutine(func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			err := CleanupWsFedSessions()
+			if err != nil {
+				logs.Error("CleanupWsFedSessions() error: %s", err.Error())
+			}
+		}
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package object
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+func TestWsFedRstr(t *testing.T) {
+	certificate, privateKey, err := generateRsaKeys(2048, 256, 1, "casdoor", "casdoor")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := &Application{SamlHashAlgorithm: "SHA256", UseEmailAsSamlNameId: true}
+	user := &User{Owner: "built-in", Name: "alice", Email: "alice@example.com"}
+	claims := []wsFedClaim{
+		{Namespace: wsFedClaimNamespace, Name: "emailaddress", Values: []string{user.Email}},
+		{Namespace: wsFedClaimNamespace, Name: "givenname", Values: []string{""}},
+		{Namespace: wsFedRoleNamespace, Name: "role", Values: []string{"admin", "editor"}},
+	}
+
+	ctx, err := getSamlSigningContext(application, &Cert{Certificate: certificate, PrivateKey: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.IdAttribute = "AssertionID"
+
+	now := time.Now().UTC()
+	assertion := newWsFedAssertion(application, user, claims, "https://casdoor.example.com", "urn:sharepoint:portal", now, now.Add(time.Hour))
+	sig, err := ctx.ConstructSignature(assertion, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertion.AddChild(sig)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(newWsFedRstr(assertion, "urn:sharepoint:portal", now, now.Add(time.Hour)))
+	wresult, err := doc.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read the token back as a relying party does
+	doc = etree.NewDocument()
+	err = doc.ReadFromString(wresult)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := doc.FindElement("./RequestSecurityTokenResponse/AppliesTo/EndpointReference/Address"); address == nil || address.Text() != "urn:sharepoint:portal" {
+		t.Fatalf("the RSTR doesn't apply to the realm: %s", wresult)
+	}
+
+	token := doc.FindElement("./RequestSecurityTokenResponse/RequestedSecurityToken/Assertion")
+	if token == nil {
+		t.Fatalf("the RSTR has no assertion: %s", wresult)
+	}
+
+	block, _ := pem.Decode([]byte(certificate))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validationContext := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: []*x509.Certificate{cert}})
+	validationContext.IdAttribute = "AssertionID"
+	validated, err := validationContext.Validate(token)
+	if err != nil {
+		t.Fatalf("the assertion signature doesn't validate: %v", err)
+	}
+
+	if audience := validated.FindElement("./Conditions/AudienceRestrictionCondition/Audience"); audience == nil || audience.Text() != "urn:sharepoint:portal" {
+		t.Errorf("unexpected audience of the assertion")
+	}
+	if nameIdentifier := validated.FindElement("./AuthenticationStatement/Subject/NameIdentifier"); nameIdentifier == nil || nameIdentifier.Text() != user.Email {
+		t.Errorf("unexpected subject of the assertion")
+	}
+
+	attributes := validated.FindElements("./AttributeStatement/Attribute")
+	if len(attributes) != 2 {
+		t.Fatalf("the empty claim should be skipped, got %d attributes", len(attributes))
+	}
+	if attributes[1].SelectAttrValue("AttributeNamespace", "") != wsFedRoleNamespace || len(attributes[1].FindElements("./AttributeValue")) != 2 {
+		t.Errorf("unexpected role claim of the assertion")
+	}
+}
+
+func TestGetWsFedResponseValidatesRealm(t *testing.T) {
+	application := &Application{RedirectUris: []string{"https://rp.example.com/", "urn:sharepoint:portal"}}
+	user := &User{Owner: "built-in", Name: "alice"}
+
+	tests := []struct {
+		realm string
+		reply string
+	}{
+		{realm: "", reply: ""},
+		{realm: "https://evil.example.com/", reply: ""},
+		{realm: "urn:sharepoint:portal", reply: ""},
+		{realm: "urn:sharepoint:portal", reply: "https://evil.example.com/"},
+	}
+	for _, test := range tests {
+		_, _, err := GetWsFedResponse(application, user, test.realm, test.reply, "casdoor.example.com", "")
+		if err == nil {
+			t.Errorf("GetWsFedResponse(%q, %q) is accepted", test.realm, test.reply)
+		}
+	}
+}
+
+func TestGetWsFedRedirectAddress(t *testing.T) {
+	address := GetWsFedRedirectAddress("admin", "app-sharepoint", "urn:sharepoint:portal", "https://rp.example.com/_trust/", "ctx=1&x", "casdoor.example.com")
+	address, query, _ := strings.Cut(address, "?")
+	if !strings.HasSuffix(address, "/login/wsfed/authorize/admin/app-sharepoint") {
+		t.Errorf("unexpected login page: %s", address)
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("wtrealm") != "urn:sharepoint:portal" || values.Get("wreply") != "https://rp.example.com/_trust/" || values.Get("wctx") != "ctx=1&x" {
+		t.Errorf("unexpected query of the login page: %s", query)
+	}
+}
+
+func TestPopWsFedSignoutCleanupUrls(t *testing.T) {
+	initSqliteTestOrmer(t)
+
+	wsFedSessions := []*WsFedSession{
+		{Owner: "admin", Application: "app-sharepoint", Organization: "built-in", User: "alice", SessionId: "session-1", Realm: "urn:sharepoint:portal", Reply: "https://portal.example.com/_trust/"},
+		{Owner: "admin", Application: "app-wiki", Organization: "built-in", User: "alice", SessionId: "session-1", Realm: "https://wiki.example.com/", Reply: "https://wiki.example.com/signin?tenant=a"},
+		{Owner: "admin", Application: "app-wiki", Organization: "built-in", User: "alice", SessionId: "session-2", Realm: "https://wiki.example.com/", Reply: "https://wiki.example.com/signin?tenant=a"},
+	}
+	for _, wsFedSession := range wsFedSessions {
+		_, err := AddWsFedSession(wsFedSession)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	added, err := AddWsFedSession(&WsFedSession{Owner: "admin", Application: "app-wiki", Organization: "built-in", User: "alice", SessionId: "session-1", Realm: "https://wiki.example.com/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added {
+		t.Errorf("the relying party is recorded twice for the session")
+	}
+
+	cleanupUrls, err := PopWsFedSignoutCleanupUrls("built-in", "alice", "session-1", "urn:sharepoint:portal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cleanupUrls) != 1 || cleanupUrls[0] != "https://wiki.example.com/signin?tenant=a&wa=wsignoutcleanup1.0" {
+		t.Errorf("unexpected cleanup URLs: %v", cleanupUrls)
+	}
+
+	left, err := GetWsFedSessionsBySessionId("built-in", "alice", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 0 {
+		t.Errorf("%d WS-Federation sessions are left after the sign-out", len(left))
+	}
+
+	left, err = GetWsFedSessionsBySessionId("built-in", "alice", "session-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 1 {
+		t.Errorf("the WS-Federation session of another Beego session has been signed out")
+	}
+
+	html, err := GetWsFedSignoutHtml(cleanupUrls, "https://portal.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, `<iframe src="https://wiki.example.com/signin?tenant=a&amp;wa=wsignoutcleanup1.0"`) || !strings.Contains(html, "https:\\/\\/portal.example.com\\/") {
+		t.Errorf("unexpected sign-out page: %s", html)
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/casdoor/casdoor/conf"
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2869"
+	"layeh.com/radius/vendors/microsoft"
+)
+
+const (
+	eapCodeRequest  = 1
+	eapCodeResponse = 2
+	eapCodeSuccess  = 3
+	eapCodeFailure  = 4
+)
+
+const (
+	eapTypeIdentity = 1
+	eapTypeNak      = 3
+	eapTypePeap     = 25
+	eapTypeMsChapV2 = 26
+	eapTypeTlv      = 33
+)
+
+type eapPacket struct {
+	Code       byte
+	Identifier byte
+	Type       byte
+	Data       []byte
+}
+
+func parseEapPacket(b []byte) (*eapPacket, error) {
+	if len(b) < 4 {
+		return nil, fmt.Errorf("EAP packet is too short")
+	}
+
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if length < 4 || length > len(b) {
+		return nil, fmt.Errorf("invalid EAP packet length: %d", length)
+	}
+
+	packet := &eapPacket{Code: b[0], Identifier: b[1]}
+	if packet.Code == eapCodeRequest || packet.Code == eapCodeResponse {
+		if length < 5 {
+			return nil, fmt.Errorf("EAP packet has no type")
+		}
+		packet.Type = b[4]
+		packet.Data = b[5:length]
+	}
+	return packet, nil
+}
+
+func (p *eapPacket) encode() []byte {
+	if p.Code == eapCodeSuccess || p.Code == eapCodeFailure {
+		return []byte{p.Code, p.Identifier, 0, 4}
+	}
+
+	b := make([]byte, 5, 5+len(p.Data))
+	b[0] = p.Code
+	b[1] = p.Identifier
+	binary.BigEndian.PutUint16(b[2:4], uint16(5+len(p.Data)))
+	b[4] = p.Type
+	return append(b, p.Data...)
+}
+
+// getMessageAuthenticator computes the Message-Authenticator of RFC 3579 over the packet whose
+// Message-Authenticator is zeroed, authenticator is the one of the Access-Request.
+func getMessageAuthenticator(p *radius.Packet, authenticator [16]byte) ([]byte, error) {
+	q := *p
+	q.Authenticator = authenticator
+	q.Attributes = make(radius.Attributes, 0, len(p.Attributes))
+	for _, avp := range p.Attributes {
+		if avp.Type == rfc2869.MessageAuthenticator_Type {
+			avp = &radius.AVP{Type: avp.Type, Attribute: make(radius.Attribute, 16)}
+		}
+		q.Attributes = append(q.Attributes, avp)
+	}
+
+	b, err := q.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(md5.New, p.Secret)
+	mac.Write(b)
+	return mac.Sum(nil), nil
+}
+
+func isMessageAuthenticatorValid(p *radius.Packet) bool {
+	value, err := rfc2869.MessageAuthenticator_Lookup(p)
+	if err != nil || len(value) != 16 {
+		return false
+	}
+
+	expected, err := getMessageAuthenticator(p, p.Authenticator)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(value, expected)
+}
+
+// signResponse adds the Message-Authenticator that RFC 3579 requires in the responses that carry
+// an EAP-Message, the response authenticator is computed afterwards when the packet is encoded.
+func signResponse(response *radius.Packet) error {
+	err := rfc2869.MessageAuthenticator_Set(response, make([]byte, 16))
+	if err != nil {
+		return err
+	}
+
+	value, err := getMessageAuthenticator(response, response.Authenticator)
+	if err != nil {
+		return err
+	}
+	return rfc2869.MessageAuthenticator_Set(response, value)
+}
+
+// eapMethod is an EAP method run by the server after the Identity exchange.
+type eapMethod interface {
+	// start returns the type data of the first request.
+	start() ([]byte, error)
+	// handle processes the type data of a response, done means that the method has succeeded.
+	handle(data []byte) (request []byte, done bool, err error)
+	getUser() *object.User
+	// getMppeKeys returns the MS-MPPE-Send-Key and MS-MPPE-Recv-Key of the RADIUS server.
+	getMppeKeys() ([]byte, []byte, error)
+	close()
+}
+
+type eapMsChapV2 struct {
+	*msChapV2
+}
+
+func (m *eapMsChapV2) getUser() *object.User {
+	return m.User
+}
+
+func (m *eapMsChapV2) close() {}
+
+type EapSession struct {
+	Organization string
+	Identity     string
+	Type         byte
+	Identifier   byte
+	Method       eapMethod
+	ExpiredAt    time.Time
+}
+
+// EapSessionMap holds the EAP conversations by the State attribute of their last Access-Challenge.
+var EapSessionMap sync.Map
+
+func addEapSession(session *EapSession) string {
+	state := util.GenerateId()
+	session.ExpiredAt = time.Now().Add(StateExpiredTime)
+	EapSessionMap.Store(state, session)
+	return state
+}
+
+func takeEapSession(state string) (*EapSession, bool) {
+	value, ok := EapSessionMap.LoadAndDelete(state)
+	if !ok {
+		return nil, false
+	}
+
+	session := value.(*EapSession)
+	if session.ExpiredAt.Before(time.Now()) {
+		session.Method.close()
+		return nil, false
+	}
+	return session, true
+}
+
+func sweepEapSessions() {
+	now := time.Now()
+	EapSessionMap.Range(func(key, value interface{}) bool {
+		if session := value.(*EapSession); session.ExpiredAt.Before(now) {
+			if _, ok := EapSessionMap.LoadAndDelete(key); ok {
+				session.Method.close()
+			}
+		}
+		return true
+	})
+}
+
+// getEapType returns the EAP method offered first, PEAP needs the certificate of the server.
+func getEapType() byte {
+	if conf.GetConfigString("radiusCertId") != "" {
+		return eapTypePeap
+	}
+	return eapTypeMsChapV2
+}
+
+// getEapUsername parses the user from an EAP identity or MSCHAPv2 name, it can be the name of
+// the user or "organization/name". The organization is always the one bound to the NAS, an
+// identity that names another organization is rejected.
+func getEapUsername(organization string, name string) (string, error) {
+	if strings.Contains(name, "/") {
+		owner, username, err := util.GetOwnerAndNameFromIdWithError(name)
+		if err != nil {
+			return "", err
+		}
+		if owner != organization {
+			return "", fmt.Errorf("the user: %s doesn't belong to the organization: %s of the NAS", name, organization)
+		}
+		return username, nil
+	}
+	return name, nil
+}
+
+func newEapCheckUser(organization string) checkUserFunc {
+	return func(name string, isPasswordCorrect func(password string) bool) (*object.User, error) {
+		username, err := getEapUsername(organization, name)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err := object.CheckUserPasswordWithChallenge(organization, username, isPasswordCorrect, "en")
+		if err != nil {
+			return nil, err
+		}
+
+		// the EAP methods have no room for a second factor
+		if user.IsMfaEnabled() {
+			return nil, fmt.Errorf("the user: %s has MFA enabled, which EAP doesn't support", user.GetId())
+		}
+		return user, nil
+	}
+}
+
+func newEapMethod(eapType byte, organization string) (eapMethod, error) {
+	checkUser := newEapCheckUser(organization)
+	switch eapType {
+	case eapTypePeap:
+		tlsConfig, err := getPeapTlsConfig(conf.GetConfigString("radiusCertId"))
+		if err != nil {
+			return nil, err
+		}
+		return newPeap(tlsConfig, checkUser), nil
+	case eapTypeMsChapV2:
+		return &eapMsChapV2{newMsChapV2(checkUser)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported EAP type: %d", eapType)
+	}
+}
+
+// handleEapRequest authenticates the Access-Requests that carry an EAP-Message (RFC 3579).
+func handleEapRequest(w radius.ResponseWriter, r *radius.Request, organization string) {
+	if !isMessageAuthenticatorValid(r.Packet) {
+		// RFC 3579 requires to silently discard the request
+		log.Printf("handleEapRequest() invalid Message-Authenticator from %s", r.RemoteAddr)
+		return
+	}
+
+	request, err := parseEapPacket(rfc2869.EAPMessage_Get(r.Packet))
+	if err != nil || request.Code != eapCodeResponse {
+		writeEapFailure(w, r, 0)
+		return
+	}
+
+	var session *EapSession
+	state := rfc2865.State_GetString(r.Packet)
+	if state == "" {
+		if request.Type != eapTypeIdentity {
+			writeEapFailure(w, r, request.Identifier)
+			return
+		}
+
+		session = &EapSession{
+			Organization: organization,
+			Identity:     string(request.Data),
+			Identifier:   request.Identifier,
+		}
+		startEapMethod(w, r, session, getEapType())
+		return
+	}
+
+	session, ok := takeEapSession(state)
+	if !ok || request.Identifier != session.Identifier {
+		if ok {
+			session.Method.close()
+		}
+		writeEapFailure(w, r, request.Identifier)
+		return
+	}
+
+	if request.Type == eapTypeNak {
+		session.Method.close()
+		// the peer only accepts EAP-MSCHAPv2 without a tunnel
+		if session.Type == eapTypePeap && len(request.Data) > 0 && request.Data[0] == eapTypeMsChapV2 {
+			startEapMethod(w, r, session, eapTypeMsChapV2)
+			return
+		}
+		writeEapFailure(w, r, request.Identifier)
+		return
+	}
+
+	if request.Type != session.Type {
+		session.Method.close()
+		writeEapFailure(w, r, request.Identifier)
+		return
+	}
+
+	data, done, err := session.Method.handle(request.Data)
+	if err != nil {
+		log.Printf("handleEapRequest() failed to authenticate %s, err = %v", session.Identity, err)
+		session.Method.close()
+		writeEapFailure(w, r, request.Identifier)
+		return
+	}
+
+	if done {
+		writeEapSuccess(w, r, session)
+		return
+	}
+
+	writeEapChallenge(w, r, session, data)
+}
+
+func startEapMethod(w radius.ResponseWriter, r *radius.Request, session *EapSession, eapType byte) {
+	method, err := newEapMethod(eapType, session.Organization)
+	if err != nil {
+		log.Printf("startEapMethod() failed, err = %v", err)
+		writeEapFailure(w, r, session.Identifier)
+		return
+	}
+
+	data, err := method.start()
+	if err != nil {
+		log.Printf("startEapMethod() failed, err = %v", err)
+		method.close()
+		writeEapFailure(w, r, session.Identifier)
+		return
+	}
+
+	session.Type = eapType
+	session.Method = method
+	writeEapChallenge(w, r, session, data)
+}
+
+func writeEapResponse(w radius.ResponseWriter, response *radius.Packet, eap *eapPacket) {
+	err := rfc2869.EAPMessage_Set(response, eap.encode())
+	if err == nil {
+		err = signResponse(response)
+	}
+	if err != nil {
+		log.Printf("writeEapResponse() failed, err = %v", err)
+		return
+	}
+
+	w.Write(response)
+}
+
+func writeEapChallenge(w radius.ResponseWriter, r *radius.Request, session *EapSession, data []byte) {
+	session.Identifier++
+	response := r.Response(radius.CodeAccessChallenge)
+
+	err := rfc2865.State_SetString(response, addEapSession(session))
+	if err != nil {
+		log.Printf("writeEapChallenge() failed, err = %v", err)
+		return
+	}
+
+	writeEapResponse(w, response, &eapPacket{Code: eapCodeRequest, Identifier: session.Identifier, Type: session.Type, Data: data})
+}
+
+func writeEapSuccess(w radius.ResponseWriter, r *radius.Request, session *EapSession) {
+	defer session.Method.close()
+
+	user := session.Method.getUser()
+	response, err := newAccessAccept(r, user)
+	if err != nil {
+		log.Printf("writeEapSuccess() failed, err = %v", err)
+		writeEapFailure(w, r, session.Identifier)
+		return
+	}
+
+	sendKey, recvKey, err := session.Method.getMppeKeys()
+	if err == nil {
+		err = microsoft.MSMPPESendKey_Add(response, sendKey)
+	}
+	if err == nil {
+		err = microsoft.MSMPPERecvKey_Add(response, recvKey)
+	}
+	if err == nil {
+		err = rfc2865.UserName_SetString(response, user.Name)
+	}
+	if err != nil {
+		log.Printf("writeEapSuccess() failed to add the keys, err = %v", err)
+		writeEapFailure(w, r, session.Identifier)
+		return
+	}
+
+	writeEapResponse(w, response, &eapPacket{Code: eapCodeSuccess, Identifier: session.Identifier})
+}
+
+func writeEapFailure(w radius.ResponseWriter, r *radius.Request, identifier byte) {
+	writeEapResponse(w, r.Response(radius.CodeAccessReject), &eapPacket{Code: eapCodeFailure, Identifier: identifier})
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/casdoor/casdoor/object"
+	"layeh.com/radius/rfc2759"
+	"layeh.com/radius/rfc3079"
+)
+
+// the OpCodes of EAP-MSCHAPv2 (draft-kamath-pppext-eap-mschapv2)
+const (
+	msChapV2Challenge = 1
+	msChapV2Response  = 2
+	msChapV2Success   = 3
+	msChapV2Failure   = 4
+)
+
+const msChapV2ServerName = "casdoor"
+
+// checkUserFunc checks the user who answers a challenge, isPasswordCorrect tells whether the
+// response matches a password.
+type checkUserFunc func(username string, isPasswordCorrect func(password string) bool) (*object.User, error)
+
+// msChapV2 is the server side of an EAP-MSCHAPv2 conversation, it works on the type data of the
+// EAP packets so that it can run directly over RADIUS and inside the PEAP tunnel.
+type msChapV2 struct {
+	checkUser checkUserFunc
+
+	identifier byte
+	challenge  []byte
+	ntResponse []byte
+	password   []byte
+	succeeded  bool
+
+	User *object.User
+}
+
+func newMsChapV2(checkUser checkUserFunc) *msChapV2 {
+	return &msChapV2{checkUser: checkUser}
+}
+
+func newMsChapV2Packet(opCode byte, identifier byte, value []byte) []byte {
+	data := make([]byte, 4, 4+len(value))
+	data[0] = opCode
+	data[1] = identifier
+	binary.BigEndian.PutUint16(data[2:4], uint16(4+len(value)))
+	return append(data, value...)
+}
+
+// start returns the Challenge request.
+func (m *msChapV2) start() ([]byte, error) {
+	m.challenge = make([]byte, 16)
+	_, err := rand.Read(m.challenge)
+	if err != nil {
+		return nil, err
+	}
+	m.identifier++
+
+	value := append([]byte{byte(len(m.challenge))}, m.challenge...)
+	value = append(value, msChapV2ServerName...)
+	return newMsChapV2Packet(msChapV2Challenge, m.identifier, value), nil
+}
+
+// getMsChapV2Username removes the Windows domain from the name of the peer, RFC 2759 computes
+// the challenge hash without it.
+func getMsChapV2Username(name string) string {
+	if i := strings.LastIndex(name, "\\"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// handle processes a response of the peer, it returns the next request or done when the peer
+// has acknowledged the Success request.
+func (m *msChapV2) handle(data []byte) (request []byte, done bool, err error) {
+	if len(data) == 0 {
+		return nil, false, fmt.Errorf("empty MSCHAPv2 packet")
+	}
+
+	switch data[0] {
+	case msChapV2Response:
+		if m.challenge == nil || m.succeeded {
+			return nil, false, fmt.Errorf("unexpected MSCHAPv2 response")
+		}
+		// OpCode, MS-CHAPv2-ID, MS-Length, Value-Size, then the peer challenge (16), the
+		// reserved octets (8), the NT-Response (24) and the flags (1), followed by the name
+		if len(data) < 5+49 || data[1] != m.identifier || data[4] != 49 {
+			return nil, false, fmt.Errorf("invalid MSCHAPv2 response")
+		}
+
+		peerChallenge := data[5:21]
+		ntResponse := data[29:53]
+		username := getMsChapV2Username(string(data[54:]))
+
+		user, err := m.checkUser(username, func(password string) bool {
+			expected, err := rfc2759.GenerateNTResponse(m.challenge, peerChallenge, []byte(username), []byte(password))
+			if err != nil || !bytes.Equal(expected, ntResponse) {
+				return false
+			}
+			m.password = []byte(password)
+			return true
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		authenticatorResponse, err := rfc2759.GenerateAuthenticatorResponse(m.challenge, peerChallenge, ntResponse, []byte(username), m.password)
+		if err != nil {
+			return nil, false, err
+		}
+
+		m.User = user
+		m.ntResponse = append([]byte(nil), ntResponse...)
+		m.succeeded = true
+		return newMsChapV2Packet(msChapV2Success, m.identifier, []byte(authenticatorResponse+" M=OK")), false, nil
+	case msChapV2Success:
+		if !m.succeeded {
+			return nil, false, fmt.Errorf("unexpected MSCHAPv2 success")
+		}
+		return nil, true, nil
+	case msChapV2Failure:
+		return nil, false, fmt.Errorf("the peer has aborted MSCHAPv2")
+	default:
+		return nil, false, fmt.Errorf("unknown MSCHAPv2 OpCode: %d", data[0])
+	}
+}
+
+// getMppeKeys returns the MS-MPPE-Send-Key and MS-MPPE-Recv-Key of RFC 3079 when MSCHAPv2 is
+// used without a tunnel.
+func (m *msChapV2) getMppeKeys() ([]byte, []byte, error) {
+	sendKey, err := rfc3079.MakeKey(m.ntResponse, m.password, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recvKey, err := rfc3079.MakeKey(m.ntResponse, m.password, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sendKey, recvKey, nil
+}
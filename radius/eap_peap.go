@@ -0,0 +1,378 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/casdoor/casdoor/object"
+)
+
+const (
+	peapFlagLength = 0x80
+	peapFlagMore   = 0x40
+	peapFlagStart  = 0x20
+)
+
+const (
+	peapFragmentSize = 1000
+	peapMaxInputSize = 64 * 1024
+	peapTimeout      = time.Second * 10
+)
+
+// the Result TLV of the EAP-TLV method, its value is Success
+var peapResultSuccess = []byte{0x80, 0x03, 0x00, 0x02, 0x00, 0x01}
+
+// peapTransport is the net.Conn of the TLS tunnel, the records are carried by the EAP-PEAP
+// packets instead of a socket. Read tells the driver through waiting that the TLS side has
+// written everything it can before blocking for the next message of the peer.
+type peapTransport struct {
+	incoming chan []byte
+	waiting  chan struct{}
+	pending  []byte
+	output   bytes.Buffer
+}
+
+func newPeapTransport() *peapTransport {
+	return &peapTransport{
+		incoming: make(chan []byte),
+		waiting:  make(chan struct{}, 1),
+	}
+}
+
+func (t *peapTransport) Read(b []byte) (int, error) {
+	for len(t.pending) == 0 {
+		err := t.waitForPeer()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(b, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+// waitForPeer waits for the next message of the peer, which can be an empty acknowledgement.
+func (t *peapTransport) waitForPeer() error {
+	select {
+	case t.waiting <- struct{}{}:
+	default:
+	}
+	data, ok := <-t.incoming
+	if !ok {
+		return io.EOF
+	}
+	t.pending = append(t.pending, data...)
+	return nil
+}
+
+func (t *peapTransport) Write(b []byte) (int, error) {
+	return t.output.Write(b)
+}
+
+func (t *peapTransport) Close() error                       { return nil }
+func (t *peapTransport) LocalAddr() net.Addr                { return &net.IPAddr{} }
+func (t *peapTransport) RemoteAddr() net.Addr               { return &net.IPAddr{} }
+func (t *peapTransport) SetDeadline(_ time.Time) error      { return nil }
+func (t *peapTransport) SetReadDeadline(_ time.Time) error  { return nil }
+func (t *peapTransport) SetWriteDeadline(_ time.Time) error { return nil }
+
+// peap is the server side of PEAPv0, the TLS tunnel and the inner EAP-MSCHAPv2 run in their own
+// goroutine which is driven by the EAP-PEAP responses of the peer.
+type peap struct {
+	transport *peapTransport
+	conn      *tls.Conn
+	checkUser checkUserFunc
+	done      chan struct{}
+	closeOnce sync.Once
+
+	input        []byte
+	output       []byte
+	outputLength int
+
+	user *object.User
+	msk  []byte
+	err  error
+}
+
+func newPeap(config *tls.Config, checkUser checkUserFunc) *peap {
+	transport := newPeapTransport()
+	return &peap{
+		transport: transport,
+		conn:      tls.Server(transport, config),
+		checkUser: checkUser,
+		done:      make(chan struct{}),
+	}
+}
+
+func getPeapTlsConfig(certId string) (*tls.Config, error) {
+	rawCert, err := object.GetCert(certId)
+	if err != nil {
+		return nil, err
+	}
+	if rawCert == nil {
+		return nil, fmt.Errorf("cert is empty")
+	}
+
+	cert, err := tls.X509KeyPair([]byte(rawCert.Certificate), []byte(rawCert.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// PEAPv0 derives its keys with the TLS 1.2 PRF, TLS 1.3 isn't defined for it and the older
+	// versions are deprecated (RFC 8996)
+	return &tls.Config{
+		MinVersion:             tls.VersionTLS12,
+		MaxVersion:             tls.VersionTLS12,
+		Certificates:           []tls.Certificate{cert},
+		SessionTicketsDisabled: true,
+	}, nil
+}
+
+func (p *peap) start() ([]byte, error) {
+	go p.run()
+
+	_, finished, err := p.wait()
+	if err != nil {
+		return nil, err
+	}
+	if finished {
+		return nil, fmt.Errorf("the PEAP tunnel has stopped: %v", p.err)
+	}
+	return []byte{peapFlagStart}, nil
+}
+
+func (p *peap) handle(data []byte) ([]byte, bool, error) {
+	if len(data) == 0 {
+		return nil, false, fmt.Errorf("empty PEAP packet")
+	}
+
+	flags := data[0]
+	if flags&0x07 != 0 {
+		return nil, false, fmt.Errorf("unsupported PEAP version: %d", flags&0x07)
+	}
+
+	payload := data[1:]
+	if flags&peapFlagLength != 0 {
+		if len(payload) < 4 {
+			return nil, false, fmt.Errorf("invalid PEAP packet")
+		}
+		payload = payload[4:]
+	}
+
+	p.input = append(p.input, payload...)
+	if len(p.input) > peapMaxInputSize {
+		return nil, false, fmt.Errorf("the PEAP message is too long")
+	}
+	if flags&peapFlagMore != 0 {
+		// acknowledge the fragment
+		return []byte{0}, false, nil
+	}
+
+	if len(p.input) == 0 && len(p.output) > 0 {
+		// the peer has acknowledged a fragment of the server
+		return p.nextFragment(), false, nil
+	}
+
+	input := p.input
+	p.input = nil
+	output, finished, err := p.exchange(input)
+	if err != nil {
+		return nil, false, err
+	}
+	if finished {
+		if p.err != nil {
+			return nil, false, p.err
+		}
+		return nil, true, nil
+	}
+
+	p.output = output
+	p.outputLength = len(output)
+	return p.nextFragment(), false, nil
+}
+
+func (p *peap) nextFragment() []byte {
+	data := []byte{0}
+	if p.outputLength > peapFragmentSize && len(p.output) == p.outputLength {
+		data[0] |= peapFlagLength
+		data = binary.BigEndian.AppendUint32(data, uint32(p.outputLength))
+	}
+
+	n := len(p.output)
+	if n > peapFragmentSize {
+		n = peapFragmentSize
+		data[0] |= peapFlagMore
+	}
+
+	data = append(data, p.output[:n]...)
+	p.output = p.output[n:]
+	return data
+}
+
+// exchange passes the TLS records of the peer to the tunnel and returns the records to send back,
+// finished means that the tunnel goroutine has returned.
+func (p *peap) exchange(input []byte) ([]byte, bool, error) {
+	select {
+	case p.transport.incoming <- input:
+	case <-p.done:
+		return nil, true, nil
+	}
+	return p.wait()
+}
+
+func (p *peap) wait() ([]byte, bool, error) {
+	finished := false
+	select {
+	case <-p.transport.waiting:
+	case <-p.done:
+		finished = true
+	case <-time.After(peapTimeout):
+		return nil, false, fmt.Errorf("the PEAP tunnel has timed out")
+	}
+
+	output := append([]byte(nil), p.transport.output.Bytes()...)
+	p.transport.output.Reset()
+	return output, finished, nil
+}
+
+func (p *peap) run() {
+	defer close(p.done)
+	p.err = p.authenticate()
+}
+
+func (p *peap) writeInner(data []byte) error {
+	_, err := p.conn.Write(data)
+	return err
+}
+
+func (p *peap) readInner() ([]byte, error) {
+	buf := make([]byte, 4096)
+	n, err := p.conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("empty inner EAP packet")
+	}
+	return buf[:n], nil
+}
+
+// authenticate runs the tunnel: the TLS handshake, then the inner EAP-MSCHAPv2 whose packets are
+// sent without the EAP header as PEAPv0 does, and finally the EAP-TLV Result.
+func (p *peap) authenticate() error {
+	err := p.conn.Handshake()
+	if err != nil {
+		return err
+	}
+
+	// the peer acknowledges the Finished message before the inner method starts
+	err = p.transport.waitForPeer()
+	if err != nil {
+		return err
+	}
+
+	state := p.conn.ConnectionState()
+	msk, err := state.ExportKeyingMaterial("client EAP encryption", nil, 64)
+	if err != nil {
+		return err
+	}
+
+	err = p.writeInner([]byte{eapTypeIdentity})
+	if err != nil {
+		return err
+	}
+	data, err := p.readInner()
+	if err != nil {
+		return err
+	}
+	if data[0] != eapTypeIdentity {
+		return fmt.Errorf("unexpected inner EAP type: %d", data[0])
+	}
+
+	mschap := newMsChapV2(p.checkUser)
+	request, err := mschap.start()
+	for err == nil {
+		err = p.writeInner(append([]byte{eapTypeMsChapV2}, request...))
+		if err != nil {
+			return err
+		}
+
+		data, err = p.readInner()
+		if err != nil {
+			return err
+		}
+		if data[0] != eapTypeMsChapV2 {
+			return fmt.Errorf("unexpected inner EAP type: %d", data[0])
+		}
+
+		var done bool
+		request, done, err = mschap.handle(data[1:])
+		if done {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	tlv := &eapPacket{Code: eapCodeRequest, Type: eapTypeTlv, Data: peapResultSuccess}
+	err = p.writeInner(tlv.encode())
+	if err != nil {
+		return err
+	}
+	data, err = p.readInner()
+	if err != nil {
+		return err
+	}
+
+	response, err := parseEapPacket(data)
+	if err != nil {
+		return err
+	}
+	if response.Code != eapCodeResponse || response.Type != eapTypeTlv || !bytes.Equal(response.Data, peapResultSuccess) {
+		return fmt.Errorf("the peer hasn't confirmed the result of PEAP")
+	}
+
+	p.user = mschap.User
+	p.msk = msk
+	return nil
+}
+
+func (p *peap) getUser() *object.User {
+	return p.user
+}
+
+// getMppeKeys splits the MSK of the tunnel, MS-MPPE-Recv-Key is its first half.
+func (p *peap) getMppeKeys() ([]byte, []byte, error) {
+	if len(p.msk) != 64 {
+		return nil, nil, fmt.Errorf("the PEAP tunnel has no keys")
+	}
+	return p.msk[32:], p.msk[:32], nil
+}
+
+func (p *peap) close() {
+	p.closeOnce.Do(func() {
+		close(p.transport.incoming)
+	})
+}
@@ -0,0 +1,334 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/casdoor/casdoor/object"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2759"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2868"
+	"layeh.com/radius/rfc2869"
+)
+
+func newTestCheckUser(name string, password string) checkUserFunc {
+	return func(username string, isPasswordCorrect func(password string) bool) (*object.User, error) {
+		if username != name || isPasswordCorrect("wrong") || !isPasswordCorrect(password) {
+			return nil, fmt.Errorf("password or code is incorrect")
+		}
+		return &object.User{Owner: "built-in", Name: username}, nil
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newMsChapV2Response(identifier byte, peerChallenge []byte, ntResponse []byte, name string) []byte {
+	value := []byte{49}
+	value = append(value, peerChallenge...)
+	value = append(value, make([]byte, 8)...)
+	value = append(value, ntResponse...)
+	value = append(value, 0)
+	value = append(value, name...)
+	return newMsChapV2Packet(msChapV2Response, identifier, value)
+}
+
+func TestMsChapV2(t *testing.T) {
+	// the example of RFC 2759, section 9.2
+	m := newMsChapV2(newTestCheckUser("User", "clientPass"))
+	_, err := m.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.challenge = decodeHex(t, "5B5D7C7D7B3F2F3E3C2C602132262628")
+	peerChallenge := decodeHex(t, "21402324255E262A28295F2B3A337C7E")
+	ntResponse := decodeHex(t, "82309ECD8D708B5EA08FAA3981CD83544233114A3D85D6DF")
+
+	request, done, err := m.handle(newMsChapV2Response(m.identifier, peerChallenge, ntResponse, "DOMAIN\\User"))
+	if err != nil || done {
+		t.Fatalf("the response should be accepted, err = %v", err)
+	}
+	if request[0] != msChapV2Success || !strings.HasPrefix(string(request[4:]), "S=407A5589115FD0D6209F510FE9C04566932CDA56") {
+		t.Errorf("unexpected success request: %q", request)
+	}
+
+	_, done, err = m.handle([]byte{msChapV2Success})
+	if err != nil || !done {
+		t.Errorf("the success acknowledgement should finish the method, err = %v", err)
+	}
+
+	sendKey, recvKey, err := m.getMppeKeys()
+	if err != nil || len(sendKey) != 16 || len(recvKey) != 16 || bytes.Equal(sendKey, recvKey) {
+		t.Errorf("unexpected MPPE keys: %x %x, err = %v", sendKey, recvKey, err)
+	}
+
+	m = newMsChapV2(newTestCheckUser("User", "anotherPass"))
+	_, _ = m.start()
+	m.challenge = decodeHex(t, "5B5D7C7D7B3F2F3E3C2C602132262628")
+	if _, _, err = m.handle(newMsChapV2Response(m.identifier, peerChallenge, ntResponse, "User")); err == nil {
+		t.Errorf("a wrong password should be rejected")
+	}
+}
+
+func TestMessageAuthenticator(t *testing.T) {
+	packet := radius.New(radius.CodeAccessRequest, []byte("secret"))
+	rfc2865.UserName_SetString(packet, "alice")
+	identity := &eapPacket{Code: eapCodeResponse, Identifier: 1, Type: eapTypeIdentity, Data: []byte("alice")}
+	rfc2869.EAPMessage_Set(packet, identity.encode())
+	err := signResponse(packet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := packet.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := radius.Parse(b, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isMessageAuthenticatorValid(parsed) {
+		t.Errorf("the Message-Authenticator should be valid")
+	}
+
+	parsed.Secret = []byte("another secret")
+	if isMessageAuthenticatorValid(parsed) {
+		t.Errorf("the Message-Authenticator of another secret should be invalid")
+	}
+
+	eap, err := parseEapPacket(rfc2869.EAPMessage_Get(parsed))
+	if err != nil || eap.Type != eapTypeIdentity || string(eap.Data) != "alice" {
+		t.Errorf("unexpected EAP packet: %v, err = %v", eap, err)
+	}
+}
+
+func newTestTlsConfig(t *testing.T) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "radius.casdoor.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tls.Config{
+		MaxVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+}
+
+// runTestPeapPeer is the supplicant side of PEAPv0 with the inner EAP-MSCHAPv2.
+func runTestPeapPeer(peer *peap, username string, password string) error {
+	err := peer.conn.Handshake()
+	if err != nil {
+		return err
+	}
+
+	state := peer.conn.ConnectionState()
+	peer.msk, err = state.ExportKeyingMaterial("client EAP encryption", nil, 64)
+	if err != nil {
+		return err
+	}
+
+	data, err := peer.readInner()
+	if err != nil || data[0] != eapTypeIdentity {
+		return fmt.Errorf("expected the identity request, err = %v", err)
+	}
+	err = peer.writeInner(append([]byte{eapTypeIdentity}, username...))
+	if err != nil {
+		return err
+	}
+
+	data, err = peer.readInner()
+	if err != nil || data[0] != eapTypeMsChapV2 || data[1] != msChapV2Challenge {
+		return fmt.Errorf("expected the MSCHAPv2 challenge, err = %v", err)
+	}
+	identifier, challenge := data[2], data[6:22]
+	peerChallenge := make([]byte, 16)
+	_, _ = rand.Read(peerChallenge)
+	ntResponse, err := rfc2759.GenerateNTResponse(challenge, peerChallenge, []byte(username), []byte(password))
+	if err != nil {
+		return err
+	}
+	err = peer.writeInner(append([]byte{eapTypeMsChapV2}, newMsChapV2Response(identifier, peerChallenge, ntResponse, username)...))
+	if err != nil {
+		return err
+	}
+
+	data, err = peer.readInner()
+	if err != nil || data[0] != eapTypeMsChapV2 || data[1] != msChapV2Success {
+		return fmt.Errorf("expected the MSCHAPv2 success, err = %v", err)
+	}
+	expected, _ := rfc2759.GenerateAuthenticatorResponse(challenge, peerChallenge, ntResponse, []byte(username), []byte(password))
+	if !strings.HasPrefix(string(data[5:]), expected) {
+		return fmt.Errorf("invalid authenticator response: %s", data[5:])
+	}
+	err = peer.writeInner([]byte{eapTypeMsChapV2, msChapV2Success})
+	if err != nil {
+		return err
+	}
+
+	data, err = peer.readInner()
+	if err != nil {
+		return err
+	}
+	tlv, err := parseEapPacket(data)
+	if err != nil || tlv.Type != eapTypeTlv {
+		return fmt.Errorf("expected the result TLV, err = %v", err)
+	}
+	response := &eapPacket{Code: eapCodeResponse, Identifier: tlv.Identifier, Type: eapTypeTlv, Data: tlv.Data}
+	return peer.writeInner(response.encode())
+}
+
+// runTestPeap drives the server with the EAP-PEAP responses of a supplicant.
+func runTestPeap(t *testing.T, password string) (*peap, *peap, error) {
+	server := newPeap(newTestTlsConfig(t), newTestCheckUser("alice", "123"))
+	request, err := server.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request[0] != peapFlagStart {
+		t.Fatalf("the first request should start PEAP, got: %x", request)
+	}
+
+	peer := &peap{transport: newPeapTransport(), done: make(chan struct{})}
+	peer.conn = tls.Client(peer.transport, &tls.Config{MaxVersion: tls.VersionTLS12, InsecureSkipVerify: true})
+	go func() {
+		defer close(peer.done)
+		peer.err = runTestPeapPeer(peer, "alice", password)
+	}()
+	defer peer.close()
+
+	output, _, err := peer.wait()
+	for i := 0; err == nil && i < 20; i++ {
+		var done bool
+		request, done, err = server.handle(append([]byte{0}, output...))
+		if err != nil || done {
+			return server, peer, err
+		}
+
+		input := []byte{}
+		for err == nil {
+			payload := request[1:]
+			if request[0]&peapFlagLength != 0 {
+				if total := binary.BigEndian.Uint32(payload); total <= peapFragmentSize {
+					t.Errorf("only the fragmented messages should have the length: %d", total)
+				}
+				payload = payload[4:]
+			}
+			input = append(input, payload...)
+			if request[0]&peapFlagMore == 0 {
+				break
+			}
+			request, _, err = server.handle([]byte{0})
+		}
+
+		output, _, err = peer.exchange(input)
+	}
+	return server, peer, fmt.Errorf("PEAP hasn't finished: %v", err)
+}
+
+func TestPeap(t *testing.T) {
+	server, peer, err := runTestPeap(t, "123")
+	if err != nil {
+		t.Fatalf("PEAP should succeed, err = %v, peer err = %v", err, peer.err)
+	}
+	if server.getUser() == nil || server.getUser().Name != "alice" {
+		t.Errorf("unexpected user: %v", server.getUser())
+	}
+
+	sendKey, recvKey, err := server.getMppeKeys()
+	if err != nil || !bytes.Equal(recvKey, peer.msk[:32]) || !bytes.Equal(sendKey, peer.msk[32:]) {
+		t.Errorf("the MPPE keys should be derived from the MSK of the peer, err = %v", err)
+	}
+
+	_, _, err = runTestPeap(t, "456")
+	if err == nil {
+		t.Errorf("PEAP with a wrong password should fail")
+	}
+}
+
+func TestAddReplyAttribute(t *testing.T) {
+	packet := radius.New(radius.CodeAccessAccept, []byte("secret"))
+	attributes := []*object.RadiusReplyAttribute{
+		{Name: "VLAN-ID", Value: "100"},
+		{Name: "Filter-Id", Value: "staff"},
+		{Name: "Session-Timeout", Value: "3600"},
+		{Name: "Framed-IP-Address", Value: "10.0.0.8"},
+	}
+	for _, attribute := range attributes {
+		err := addReplyAttribute(packet, attribute)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, vlanId := rfc2868.TunnelPrivateGroupID_GetString(packet)
+	_, tunnelType := rfc2868.TunnelType_Get(packet)
+	if vlanId != "100" || tunnelType != tunnelTypeVlan {
+		t.Errorf("unexpected VLAN attributes: %s %d", vlanId, tunnelType)
+	}
+	if rfc2865.FilterID_GetString(packet) != "staff" || rfc2865.SessionTimeout_Get(packet) != 3600 || rfc2865.FramedIPAddress_Get(packet).String() != "10.0.0.8" {
+		t.Errorf("unexpected reply attributes")
+	}
+
+	if addReplyAttribute(packet, &object.RadiusReplyAttribute{Name: "Framed-IP-Address", Value: "::1"}) == nil {
+		t.Errorf("an IPv6 address should be rejected")
+	}
+}
+
+func TestGetEapUsername(t *testing.T) {
+	for _, name := range []string{"alice", "org/alice"} {
+		username, err := getEapUsername("org", name)
+		if err != nil || username != "alice" {
+			t.Errorf("getEapUsername(%s) = %s, %v", name, username, err)
+		}
+	}
+
+	for _, name := range []string{"built-in/admin", "other/alice", "/alice"} {
+		if _, err := getEapUsername("org", name); err == nil {
+			t.Errorf("the identity: %s overrides the organization of the NAS", name)
+		}
+	}
+}
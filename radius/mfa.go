@@ -0,0 +1,202 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/casdoor/casdoor/conf"
+	"github.com/casdoor/casdoor/object"
+	"github.com/casdoor/casdoor/util"
+)
+
+// mfaAuthVerification is the verification method of the providers that send the MFA codes.
+const mfaAuthVerification = "mfaAuth"
+
+// StateMap holds the pending Access-Challenges by their State attribute, it is shared by the
+// handler goroutines of concurrent requests.
+var StateMap sync.Map
+
+const StateExpiredTime = time.Second * 120
+
+type AccessStateContent struct {
+	UserId     string
+	MfaType    string
+	ApprovalId string
+	ExpiredAt  time.Time
+}
+
+func addAccessState(content *AccessStateContent) string {
+	state := util.GenerateId()
+	StateMap.Store(state, content)
+	return state
+}
+
+// takeAccessState removes the state so that each challenge response is only accepted once.
+func takeAccessState(state string) (*AccessStateContent, bool) {
+	value, ok := StateMap.LoadAndDelete(state)
+	if !ok {
+		return nil, false
+	}
+
+	content := value.(*AccessStateContent)
+	if content.ExpiredAt.Before(time.Now()) {
+		return nil, false
+	}
+	return content, true
+}
+
+func sweepAccessStates() {
+	now := time.Now()
+	StateMap.Range(func(key, value interface{}) bool {
+		if value.(*AccessStateContent).ExpiredAt.Before(now) {
+			StateMap.Delete(key)
+		}
+		return true
+	})
+}
+
+func startAccessStateSweeper() {
+	ticker := time.NewTicker(StateExpiredTime)
+	util.SafeGoroutine(func() {
+		for range ticker.C {
+			sweepAccessStates()
+			sweepEapSessions()
+		}
+	})
+}
+
+// getPushApprovalHost returns the host that the link of a push approval is built from. A RADIUS
+// request doesn't come over HTTP, so it's the configured origin, or the local web server.
+func getPushApprovalHost() string {
+	for _, key := range []string{"originFrontend", "origin"} {
+		if u, err := url.Parse(conf.GetConfigString(key)); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return fmt.Sprintf("localhost:%s", conf.GetConfigString("httpport"))
+}
+
+// sendMfaChallenge prepares the second factor of the user and returns the Reply-Message of the
+// Access-Challenge. SMS and email codes are sent here, a push notification asks the user to
+// approve the sign-in in Casdoor.
+func sendMfaChallenge(user *object.User, mfaProps *object.MfaProps, content *AccessStateContent, remoteAddr string) (string, error) {
+	switch mfaProps.MfaType {
+	case object.TotpType, object.RadiusType:
+		return "please enter OTP", nil
+	case object.SmsType, object.EmailType:
+		application, err := object.GetApplicationByUser(user)
+		if err != nil {
+			return "", err
+		}
+		if application == nil {
+			return "", fmt.Errorf("the application of the user: %s does not exist", user.GetId())
+		}
+
+		organization, err := object.GetOrganizationByUser(user)
+		if err != nil {
+			return "", err
+		}
+		if organization == nil {
+			return "", fmt.Errorf("the organization: %s does not exist", user.Owner)
+		}
+
+		if mfaProps.MfaType == object.EmailType {
+			provider, err := application.GetEmailProvider(mfaAuthVerification)
+			if err != nil {
+				return "", err
+			}
+			if provider == nil {
+				return "", fmt.Errorf("no Email provider is configured for the application: %s", application.Name)
+			}
+
+			err = object.SendVerificationCodeToEmail(organization, user, provider, remoteAddr, mfaProps.Secret, mfaAuthVerification, "", application.Name, application)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("please enter the code sent to %s", util.GetMaskedEmail(mfaProps.Secret)), nil
+		}
+
+		countryCode := user.GetCountryCode(mfaProps.CountryCode)
+		provider, err := application.GetSmsProvider(mfaAuthVerification, countryCode)
+		if err != nil {
+			return "", err
+		}
+		if provider == nil {
+			return "", fmt.Errorf("no SMS provider is configured for the application: %s", application.Name)
+		}
+
+		phone, ok := util.GetE164Number(mfaProps.Secret, countryCode)
+		if !ok {
+			return "", fmt.Errorf("the phone number of the user: %s is invalid", user.GetId())
+		}
+
+		err = object.SendVerificationCodeToPhone(organization, user, provider, remoteAddr, phone, application)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("please enter the code sent to %s", util.GetMaskedPhone(mfaProps.Secret)), nil
+	case object.PushType:
+		application, err := object.GetApplicationByUser(user)
+		if err != nil {
+			return "", err
+		}
+		if application == nil {
+			return "", fmt.Errorf("the application of the user: %s does not exist", user.GetId())
+		}
+
+		content.ApprovalId, err = object.StartPushApproval(application, user, "RADIUS", getPushApprovalHost())
+		if err != nil {
+			return "", err
+		}
+		return "please approve the sign-in request sent to your device, then press enter", nil
+	default:
+		return "", fmt.Errorf("the MFA type: %s is not supported", mfaProps.MfaType)
+	}
+}
+
+// verifyMfaResponse checks the response to an Access-Challenge. For push approvals the first
+// return value is false while the user hasn't decided yet.
+func verifyMfaResponse(user *object.User, content *AccessStateContent, password string) (bool, error) {
+	if content.MfaType == object.PushType {
+		switch object.GetPushApprovalStatus(content.ApprovalId, user.GetId()) {
+		case object.DeviceAuthStatusApproved:
+			return true, nil
+		case object.DeviceAuthStatusPending:
+			return false, nil
+		default:
+			return false, fmt.Errorf("the sign-in request is denied or expired")
+		}
+	}
+
+	mfaProps := user.GetMfaProps(content.MfaType, false)
+	if mfaProps == nil || !mfaProps.Enabled {
+		return false, fmt.Errorf("the MFA type: %s is not enabled", content.MfaType)
+	}
+
+	mfaUtil := object.GetMfaUtil(content.MfaType, mfaProps)
+	if mfaUtil == nil {
+		return false, fmt.Errorf("the MFA type: %s is not supported", content.MfaType)
+	}
+
+	err := mfaUtil.Verify(password)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
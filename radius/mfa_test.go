@@ -0,0 +1,90 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAccessState(t *testing.T) {
+	state := addAccessState(&AccessStateContent{UserId: "built-in/alice", MfaType: "app", ExpiredAt: time.Now().Add(StateExpiredTime)})
+
+	content, ok := takeAccessState(state)
+	if !ok || content.UserId != "built-in/alice" {
+		t.Fatalf("the state should be found, got: %v", content)
+	}
+	if _, ok = takeAccessState(state); ok {
+		t.Errorf("the state should only be accepted once")
+	}
+
+	expired := addAccessState(&AccessStateContent{UserId: "built-in/bob", ExpiredAt: time.Now().Add(-time.Second)})
+	if _, ok = takeAccessState(expired); ok {
+		t.Errorf("an expired state should be rejected")
+	}
+
+	expired = addAccessState(&AccessStateContent{UserId: "built-in/bob", ExpiredAt: time.Now().Add(-time.Second)})
+	sweepAccessStates()
+	if _, ok = StateMap.Load(expired); ok {
+		t.Errorf("an expired state should be swept")
+	}
+}
+
+func TestAccessStateConcurrency(t *testing.T) {
+	state := addAccessState(&AccessStateContent{UserId: "built-in/alice", ExpiredAt: time.Now().Add(StateExpiredTime)})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addAccessState(&AccessStateContent{ExpiredAt: time.Now().Add(StateExpiredTime)})
+			if _, ok := takeAccessState(state); ok {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+			sweepAccessStates()
+		}()
+	}
+	wg.Wait()
+
+	if accepted != 1 {
+		t.Errorf("the state should be accepted exactly once, got: %d", accepted)
+	}
+}
+
+func TestGetPushApprovalHost(t *testing.T) {
+	t.Setenv("originFrontend", "")
+	t.Setenv("origin", "https://door.example.com")
+	if host := getPushApprovalHost(); host != "door.example.com" {
+		t.Errorf("the host of the origin is expected, got: %s", host)
+	}
+
+	t.Setenv("originFrontend", "https://login.example.com")
+	if host := getPushApprovalHost(); host != "login.example.com" {
+		t.Errorf("the host of the frontend origin is expected, got: %s", host)
+	}
+
+	t.Setenv("originFrontend", "")
+	t.Setenv("origin", "")
+	t.Setenv("httpport", "8000")
+	if host := getPushApprovalHost(); host != "localhost:8000" {
+		t.Errorf("the local web server is expected, got: %s", host)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package radius
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+
+	"github.com/casdoor/casdoor/object"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2868"
+)
+
+// tunnelTypeVlan is the Tunnel-Type value of RFC 3580 for VLAN assignment.
+const tunnelTypeVlan = rfc2868.TunnelType(13)
+
+func addReplyAttribute(p *radius.Packet, attribute *object.RadiusReplyAttribute) error {
+	switch attribute.Name {
+	case "VLAN-ID":
+		if _, err := strconv.Atoi(attribute.Value); err != nil {
+			return err
+		}
+		err := rfc2868.TunnelType_Add(p, 0, tunnelTypeVlan)
+		if err != nil {
+			return err
+		}
+		err = rfc2868.TunnelMediumType_Add(p, 0, rfc2868.TunnelMediumType_Value_IEEE802)
+		if err != nil {
+			return err
+		}
+		return rfc2868.TunnelPrivateGroupID_AddString(p, 0, attribute.Value)
+	case "Filter-Id":
+		return rfc2865.FilterID_AddString(p, attribute.Value)
+	case "Session-Timeout", "Idle-Timeout":
+		seconds, err := strconv.ParseUint(attribute.Value, 10, 32)
+		if err != nil {
+			return err
+		}
+		if attribute.Name == "Session-Timeout" {
+			return rfc2865.SessionTimeout_Add(p, rfc2865.SessionTimeout(seconds))
+		}
+		return rfc2865.IdleTimeout_Add(p, rfc2865.IdleTimeout(seconds))
+	case "Framed-IP-Address", "Framed-IP-Netmask":
+		ip := net.ParseIP(attribute.Value).To4()
+		if ip == nil {
+			return fmt.Errorf("invalid IPv4 address: %s", attribute.Value)
+		}
+		if attribute.Name == "Framed-IP-Address" {
+			return rfc2865.FramedIPAddress_Add(p, ip)
+		}
+		return rfc2865.FramedIPNetmask_Add(p, ip)
+	default:
+		return fmt.Errorf("unsupported attribute: %s", attribute.Name)
+	}
+}
+
+// newAccessAccept creates the Access-Accept of a user with the reply attributes of the groups
+// and roles of the user.
+func newAccessAccept(r *radius.Request, user *object.User) (*radius.Packet, error) {
+	response := r.Response(radius.CodeAccessAccept)
+
+	attributes, err := object.GetRadiusReplyAttributes(user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attribute := range attributes {
+		err = addReplyAttribute(response, attribute)
+		if err != nil {
+			log.Printf("newAccessAccept() failed to add the attribute: %s = %s, err = %v", attribute.Name, attribute.Value, err)
+		}
+	}
+	return response, nil
+}
+
+func writeAccessAccept(w radius.ResponseWriter, r *radius.Request, user *object.User) {
+	response, err := newAccessAccept(r, user)
+	if err != nil {
+		log.Printf("writeAccessAccept() failed, err = %v", err)
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
+
+	w.Write(response)
+}
@@ -26,16 +26,9 @@ import (
 	"layeh.com/radius"
 	"layeh.com/radius/rfc2865"
 	"layeh.com/radius/rfc2866"
+	"layeh.com/radius/rfc2869"
 )
 
-var StateMap map[string]AccessStateContent
-
-const StateExpiredTime = time.Second * 120
-
-type AccessStateContent struct {
-	ExpiredAt time.Time
-}
-
 func StartRadiusServer() {
 	secret := conf.GetConfigString("radiusSecret")
 	server := radius.PacketServer{
@@ -43,6 +36,7 @@ func StartRadiusServer() {
 		Handler:      radius.HandlerFunc(handlerRadius),
 		SecretSource: radius.StaticSecretSource([]byte(secret)),
 	}
+	startAccessStateSweeper()
 	log.Printf("Starting Radius server on %s", server.Addr)
 	if err := server.ListenAndServe(); err != nil {
 		log.Printf("StartRadiusServer() failed, err = %v", err)
@@ -74,76 +68,92 @@ func handleAccessRequest(w radius.ResponseWriter, r *radius.Request) {
 		}
 	}
 
-	var user *object.User
-	var err error
+	if _, err := rfc2869.EAPMessage_Lookup(r.Packet); err == nil {
+		handleEapRequest(w, r, organization)
+		return
+	}
 
-	if state == "" {
-		user, err = object.CheckUserPassword(organization, username, password, "en")
-	} else {
-		user, err = object.GetUser(fmt.Sprintf("%s/%s", organization, username))
+	if state != "" {
+		handleAccessChallengeResponse(w, r, organization, username, state, password)
+		return
 	}
 
+	user, err := object.CheckUserPassword(organization, username, password, "en")
 	if err != nil {
 		w.Write(r.Response(radius.CodeAccessReject))
 		return
 	}
 
-	if user.IsMfaEnabled() {
-		mfaProp := user.GetMfaProps(object.TotpType, false)
-		if mfaProp == nil {
-			w.Write(r.Response(radius.CodeAccessReject))
-			return
-		}
+	if !user.IsMfaEnabled() {
+		writeAccessAccept(w, r, user)
+		return
+	}
 
-		if StateMap == nil {
-			StateMap = map[string]AccessStateContent{}
-		}
+	mfaProps := user.GetPreferredMfaProps(false)
+	if mfaProps == nil || !mfaProps.Enabled {
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
 
-		if state != "" {
-			stateContent, ok := StateMap[state]
-			if !ok {
-				w.Write(r.Response(radius.CodeAccessReject))
-				return
-			}
+	content := &AccessStateContent{
+		UserId:    user.GetId(),
+		MfaType:   mfaProps.MfaType,
+		ExpiredAt: time.Now().Add(StateExpiredTime),
+	}
+	message, err := sendMfaChallenge(user, mfaProps, content, getRemoteIp(r))
+	if err != nil {
+		log.Printf("handleAccessRequest() failed to send the MFA challenge, err = %v", err)
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
 
-			delete(StateMap, state)
-			if stateContent.ExpiredAt.Before(time.Now()) {
-				w.Write(r.Response(radius.CodeAccessReject))
-				return
-			}
+	writeAccessChallenge(w, r, content, message)
+}
 
-			mfaUtil := object.GetMfaUtil(mfaProp.MfaType, mfaProp)
-			if mfaUtil.Verify(password) != nil {
-				w.Write(r.Response(radius.CodeAccessReject))
-				return
-			}
+func handleAccessChallengeResponse(w radius.ResponseWriter, r *radius.Request, organization string, username string, state string, password string) {
+	content, ok := takeAccessState(state)
+	if !ok || content.UserId != util.GetId(organization, username) {
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
 
-			w.Write(r.Response(radius.CodeAccessAccept))
-			return
-		}
+	user, err := object.GetUser(content.UserId)
+	if err != nil || user == nil || user.IsForbidden || user.IsDeleted {
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
 
-		responseState := util.GenerateId()
-		StateMap[responseState] = AccessStateContent{
-			time.Now().Add(StateExpiredTime),
-		}
+	verified, err := verifyMfaResponse(user, content, password)
+	if err != nil {
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
 
-		err = rfc2865.State_Set(r.Packet, []byte(responseState))
-		if err != nil {
-			w.Write(r.Response(radius.CodeAccessReject))
-			return
-		}
+	if !verified {
+		// the push approval is still pending, the NAS can send the response again
+		writeAccessChallenge(w, r, content, "the sign-in request is not approved yet, please approve it and press enter")
+		return
+	}
 
-		err = rfc2865.ReplyMessage_Set(r.Packet, []byte("please enter OTP"))
-		if err != nil {
-			w.Write(r.Response(radius.CodeAccessReject))
-			return
-		}
+	writeAccessAccept(w, r, user)
+}
 
-		r.Packet.Code = radius.CodeAccessChallenge
-		w.Write(r.Packet)
+func writeAccessChallenge(w radius.ResponseWriter, r *radius.Request, content *AccessStateContent, message string) {
+	response := r.Response(radius.CodeAccessChallenge)
+
+	err := rfc2865.State_Set(response, []byte(addAccessState(content)))
+	if err != nil {
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
+	}
+
+	err = rfc2865.ReplyMessage_SetString(response, message)
+	if err != nil {
+		w.Write(r.Response(radius.CodeAccessReject))
+		return
 	}
 
-	w.Write(r.Response(radius.CodeAccessAccept))
+	w.Write(response)
 }
 
 func handleAccountingRequest(w radius.ResponseWriter, r *radius.Request) {
@@ -16,6 +16,7 @@ package radius
 
 import (
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/casdoor/casdoor/object"
@@ -65,3 +66,11 @@ func GetAccountingFromRequest(r *radius.Request) *object.RadiusAccounting {
 	}
 	return ra
 }
+
+func getRemoteIp(r *radius.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr.String())
+	if err != nil {
+		return r.RemoteAddr.String()
+	}
+	return host
+}
@@ -35,6 +35,7 @@ var orgOwnerObject = []string{
 	"-organization",
 	"-syncer",
 	"-webhook",
+	"-event-sink",
 	"-application",
 	"-token",
 }
@@ -73,7 +74,8 @@ func checkIsOrgOwnerObject(urlPath string) bool {
 
 func getUsername(ctx *context.Context) (username string) {
 	username, ok := ctx.Input.Session("username").(string)
-	if !ok || username == "" {
+	isSessionUser := ok && username != ""
+	if !isSessionUser {
 		username, _ = getUsernameByClientIdSecret(ctx)
 	}
 
@@ -103,6 +105,14 @@ func getUsername(ctx *context.Context) (username string) {
 		return ""
 	}
 
+	// only the browser sessions are devices, the requests signed in by a token, a client secret or
+	// an access key are not, see AutoSigninFilter()
+	if isSessionUser && ctx.Input.GetData("autoSignin") == nil {
+		if sessionDevice, ok := ctx.Input.Session(object.SessionDeviceSessionKey).(string); ok {
+			object.UpdateSessionDeviceActiveTime(sessionDevice)
+		}
+	}
+
 	return
 }
 
@@ -275,6 +285,22 @@ func getUrlPath(ctx *context.Context) string {
 		return "/api/saml/redirect"
 	}
 
+	if strings.HasPrefix(urlPath, "/api/saml/slo") {
+		return "/api/saml/slo"
+	}
+
+	if strings.HasPrefix(urlPath, "/api/wsfed") {
+		return "/api/wsfed"
+	}
+
+	if strings.HasPrefix(urlPath, "/api/provider-logout") {
+		return "/api/provider-logout"
+	}
+
+	if strings.HasPrefix(urlPath, "/api/ssf-receiver") {
+		return "/api/ssf-receiver"
+	}
+
 	return urlPath
 }
 
@@ -100,6 +100,13 @@ func AutoSigninFilter(ctx *context.Context) {
 			}
 		}
 
+		// Validate the client certificate for certificate-bound tokens (RFC 8705).
+		err = object.CheckTokenClientCert(token, object.GetRequestClientCert(ctx.Request))
+		if err != nil {
+			responseError(ctx, err.Error())
+			return
+		}
+
 		application, err := object.GetApplicationByUserId(fmt.Sprintf("app/%s", token.Application))
 		if err != nil {
 			responseError(ctx, err.Error())
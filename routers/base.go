@@ -213,11 +213,34 @@ func getSessionUser(ctx *context.Context) string {
 	return user.(string)
 }
 
+func getSessionId(ctx *context.Context) string {
+	return ctx.Input.CruSession.SessionID(stdcontext.Background())
+}
+
+func getSessionAuthContext(ctx *context.Context, userId string) *object.AuthContext {
+	session := ctx.Input.CruSession.Get(stdcontext.Background(), object.AuthContextSessionKey)
+	if session == nil {
+		return nil
+	}
+
+	sessionAuthContext := struct {
+		UserId string
+		*object.AuthContext
+	}{}
+	err := util.JsonToStruct(session.(string), &sessionAuthContext)
+	if err != nil || sessionAuthContext.UserId != userId {
+		return nil
+	}
+	return sessionAuthContext.AuthContext
+}
+
 func setSessionUser(ctx *context.Context, user string) {
 	err := ctx.Input.CruSession.Set(stdcontext.Background(), "username", user)
 	if err != nil {
 		panic(err)
 	}
+	// the user is signed in by the credentials of the request rather than by the browser session
+	ctx.Input.SetData("autoSignin", true)
 
 	// https://github.com/beego/beego/issues/3445#issuecomment-455411915
 	ctx.Input.CruSession.SessionRelease(stdcontext.Background(), ctx.ResponseWriter)
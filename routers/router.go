@@ -58,9 +58,16 @@ func InitAPI() {
 	web.Router("/api/user", &controllers.ApiController{}, "GET:GetUserinfo2")
 	web.Router("/api/unlink", &controllers.ApiController{}, "POST:Unlink")
 	web.Router("/api/get-saml-login", &controllers.ApiController{}, "GET:GetSamlLogin")
+	web.Router("/api/oidc-login", &controllers.ApiController{}, "GET:HandleOidcLogin")
+	web.Router("/api/provider-logout/:owner/:provider", &controllers.ApiController{}, "GET,POST:HandleProviderLogout")
+	web.Router("/api/ssf-receiver/:owner/:provider", &controllers.ApiController{}, "POST:HandleSsfReceiver")
 	web.Router("/api/acs", &controllers.ApiController{}, "POST:HandleSamlLogin")
 	web.Router("/api/saml/metadata", &controllers.ApiController{}, "GET:GetSamlMeta")
 	web.Router("/api/saml/redirect/:owner/:application", &controllers.ApiController{}, "*:HandleSamlRedirect")
+	web.Router("/api/saml/slo/:owner/:application", &controllers.ApiController{}, "*:HandleSamlLogout")
+	web.Router("/api/parse-saml-sp-metadata", &controllers.ApiController{}, "POST:ParseSamlSpMetadata")
+	web.Router("/api/wsfed/:owner/:application", &controllers.ApiController{}, "*:HandleWsFed")
+	web.Router("/api/wsfed/:owner/:application/FederationMetadata.xml", &controllers.ApiController{}, "GET:GetWsFedMetadata")
 	web.Router("/api/webhook", &controllers.ApiController{}, "*:HandleOfficialAccountEvent")
 	web.Router("/api/get-qrcode", &controllers.ApiController{}, "GET:GetQRCode")
 	web.Router("/api/get-webhook-event", &controllers.ApiController{}, "GET:GetWebhookEventType")
@@ -69,6 +76,8 @@ func InitAPI() {
 	web.Router("/api/device-auth", &controllers.ApiController{}, "POST:DeviceAuth")
 	web.Router("/api/cancel-device-auth", &controllers.ApiController{}, "POST:CancelDeviceAuth")
 	web.Router("/api/device-auth-complete", &controllers.ApiController{}, "POST:DeviceAuthComplete")
+	web.Router("/api/get-ciba-request", &controllers.ApiController{}, "GET:GetCibaRequest")
+	web.Router("/api/complete-ciba-request", &controllers.ApiController{}, "POST:CompleteCibaRequest")
 	web.Router("/api/kerberos-login", &controllers.ApiController{}, "GET:KerberosLogin")
 
 	web.Router("/api/get-organizations", &controllers.ApiController{}, "GET:GetOrganizations")
@@ -182,6 +191,7 @@ func InitAPI() {
 	web.Router("/api/add-cert", &controllers.ApiController{}, "POST:AddCert")
 	web.Router("/api/delete-cert", &controllers.ApiController{}, "POST:DeleteCert")
 	web.Router("/api/update-cert-domain-expire", &controllers.ApiController{}, "POST:UpdateCertDomainExpire")
+	web.Router("/api/rotate-cert", &controllers.ApiController{}, "POST:RotateCert")
 
 	web.Router("/api/get-keys", &controllers.ApiController{}, "GET:GetKeys")
 	web.Router("/api/get-global-keys", &controllers.ApiController{}, "GET:GetGlobalKeys")
@@ -245,6 +255,10 @@ func InitAPI() {
 	web.Router("/api/add-session", &controllers.ApiController{}, "POST:AddSession")
 	web.Router("/api/delete-session", &controllers.ApiController{}, "POST:DeleteSession")
 	web.Router("/api/is-session-duplicated", &controllers.ApiController{}, "GET:IsSessionDuplicated")
+	web.Router("/api/get-account-session-devices", &controllers.ApiController{}, "GET:GetAccountSessionDevices")
+	web.Router("/api/revoke-account-session-device", &controllers.ApiController{}, "POST:RevokeAccountSessionDevice")
+	web.Router("/api/get-session-devices", &controllers.ApiController{}, "GET:GetSessionDevices")
+	web.Router("/api/revoke-session-device", &controllers.ApiController{}, "POST:RevokeSessionDevice")
 
 	web.Router("/api/get-tokens", &controllers.ApiController{}, "GET:GetTokens")
 	web.Router("/api/get-token", &controllers.ApiController{}, "GET:GetToken")
@@ -336,6 +350,12 @@ func InitAPI() {
 	web.Router("/api/add-webhook", &controllers.ApiController{}, "POST:AddWebhook")
 	web.Router("/api/delete-webhook", &controllers.ApiController{}, "POST:DeleteWebhook")
 
+	web.Router("/api/get-event-sinks", &controllers.ApiController{}, "GET:GetEventSinks")
+	web.Router("/api/get-event-sink", &controllers.ApiController{}, "GET:GetEventSink")
+	web.Router("/api/update-event-sink", &controllers.ApiController{}, "POST:UpdateEventSink")
+	web.Router("/api/add-event-sink", &controllers.ApiController{}, "POST:AddEventSink")
+	web.Router("/api/delete-event-sink", &controllers.ApiController{}, "POST:DeleteEventSink")
+
 	// Webhook event routes
 	web.Router("/api/get-webhook-events", &controllers.ApiController{}, "GET:GetWebhookEvents")
 	web.Router("/api/get-webhook-event-detail", &controllers.ApiController{}, "GET:GetWebhookEvent")
@@ -370,9 +390,16 @@ func InitAPI() {
 	web.Router("/api/login/oauth/access_token", &controllers.ApiController{}, "POST:GetOAuthToken")
 	web.Router("/api/login/oauth/refresh_token", &controllers.ApiController{}, "POST:RefreshToken")
 	web.Router("/api/login/oauth/introspect", &controllers.ApiController{}, "POST:IntrospectToken")
+	web.Router("/api/login/oauth/revoke", &controllers.ApiController{}, "POST:RevokeToken")
+	web.Router("/api/login/oauth/par", &controllers.ApiController{}, "POST:PushAuthorizationRequest")
+	web.Router("/api/login/oauth/bc-authorize", &controllers.ApiController{}, "POST:BackchannelAuthenticate")
 	web.Router("/api/oauth/register", &controllers.ApiController{}, "POST:DynamicClientRegister")
 	web.Router("/api/oauth/register/:clientId", &controllers.ApiController{}, "GET:DynamicClientRead;PUT:DynamicClientUpdate;DELETE:DynamicClientDelete")
 
+	web.Router("/api/ssf/stream", &controllers.ApiController{}, "GET:GetSsfStream;POST:AddSsfStream;PATCH,PUT:UpdateSsfStream;DELETE:DeleteSsfStream")
+	web.Router("/api/ssf/status", &controllers.ApiController{}, "GET:GetSsfStreamStatus;POST:UpdateSsfStreamStatus")
+	web.Router("/api/ssf/verify", &controllers.ApiController{}, "POST:VerifySsfStream")
+
 	web.Router("/api/get-records", &controllers.ApiController{}, "GET:GetRecords")
 	web.Router("/api/get-records-filter", &controllers.ApiController{}, "POST:GetRecordsByFilter")
 	web.Router("/api/add-record", &controllers.ApiController{}, "POST:AddRecord")
@@ -404,6 +431,7 @@ func InitAPI() {
 	web.Router("/.well-known/webfinger", &controllers.RootController{}, "GET:GetWebFinger")
 	web.Router("/.well-known/:application/webfinger", &controllers.RootController{}, "GET:GetWebFingerByApplication")
 	web.Router("/.well-known/oauth-protected-resource", &controllers.RootController{}, "GET:GetOauthProtectedResourceMetadata")
+	web.Router("/.well-known/ssf-configuration", &controllers.RootController{}, "GET:GetSsfConfiguration")
 	web.Router("/.well-known/:application/oauth-protected-resource", &controllers.RootController{}, "GET:GetOauthProtectedResourceMetadataByApplication")
 
 	web.Router("/cas/:organization/:application/serviceValidate", &controllers.RootController{}, "GET:CasServiceValidate")
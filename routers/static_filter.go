@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -79,10 +80,16 @@ func fastAutoSignin(ctx *context.Context) (string, error) {
 	nonce := ctx.Input.Query("nonce")
 	codeChallenge := ctx.Input.Query("code_challenge")
 	resource := ctx.Input.Query("resource")
+	requestUri := ctx.Input.Query("request_uri")
 	if clientId == "" || responseType != "code" || redirectUri == "" {
 		return "", nil
 	}
 
+	// the login page checks the authentication context of the session against acr_values and max_age
+	if ctx.Input.Query("acr_values") != "" || ctx.Input.Query("max_age") != "" {
+		return "", nil
+	}
+
 	application, err := object.GetApplicationByClientId(clientId)
 	if err != nil {
 		return "", err
@@ -121,7 +128,7 @@ func fastAutoSignin(ctx *context.Context) (string, error) {
 		return "", nil
 	}
 
-	code, err := object.GetOAuthCode(userId, clientId, "", "autoSignin", responseType, redirectUri, scope, state, nonce, codeChallenge, resource, ctx.Request.Host, getAcceptLanguage(ctx))
+	code, err := object.GetOAuthCode(userId, clientId, "", "autoSignin", responseType, redirectUri, scope, state, nonce, codeChallenge, resource, "", "", requestUri, ctx.Request.Host, getAcceptLanguage(ctx), getSessionAuthContext(ctx, userId), getSessionId(ctx))
 	if err != nil {
 		return "", err
 	} else if code.Message != "" {
@@ -136,6 +143,53 @@ func fastAutoSignin(ctx *context.Context) (string, error) {
 	return res, nil
 }
 
+// expandPushedAuthRequest replaces the parameters of an authorization request that only carries a
+// request_uri with the ones the client has pushed (RFC 9126), so the login page can use them. The
+// request_uri is kept in the URL and the issued code is checked against the pushed request.
+// A signed request object (RFC 9101) is verified and turned into such a pushed request first.
+func expandPushedAuthRequest(ctx *context.Context) (string, error) {
+	clientId := ctx.Input.Query("client_id")
+	requestUri := ctx.Input.Query("request_uri")
+	requestObject := ctx.Input.Query("request")
+
+	var params map[string]string
+	if requestObject != "" && requestUri == "" {
+		queryParams := map[string]string{}
+		for _, name := range object.PushedAuthRequestParams {
+			queryParams[name] = ctx.Input.Query(name)
+		}
+
+		var err error
+		requestUri, params, err = object.PushRequestObject(clientId, requestObject, queryParams, ctx.Request.Host, getAcceptLanguage(ctx))
+		if err != nil {
+			return "", err
+		}
+	} else {
+		if requestUri == "" || !strings.HasPrefix(requestUri, object.PushedAuthRequestUriPrefix) {
+			return "", nil
+		}
+
+		// The request_uri has already been expanded by a previous redirect
+		if ctx.Input.Query("response_type") != "" {
+			return "", nil
+		}
+
+		request, err := object.ActivatePushedAuthRequest(clientId, requestUri)
+		if err != nil {
+			return "", err
+		}
+		params = request.Params
+	}
+
+	query := url.Values{}
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	query.Set("client_id", clientId)
+	query.Set("request_uri", requestUri)
+	return fmt.Sprintf("%s?%s", ctx.Request.URL.Path, query.Encode()), nil
+}
+
 func StaticFilter(ctx *context.Context) {
 	urlPath := ctx.Request.URL.Path
 
@@ -160,6 +214,17 @@ func StaticFilter(ctx *context.Context) {
 	}
 
 	if urlPath == "/login/oauth/authorize" {
+		parUrl, err := expandPushedAuthRequest(ctx)
+		if err != nil {
+			responseError(ctx, err.Error())
+			return
+		}
+
+		if parUrl != "" {
+			http.Redirect(ctx.ResponseWriter, ctx.Request, parUrl, http.StatusFound)
+			return
+		}
+
 		redirectUrl, err := fastAutoSignin(ctx)
 		if err != nil {
 			responseError(ctx, err.Error())
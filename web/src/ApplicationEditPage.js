@@ -152,6 +152,8 @@ class ApplicationEditPage extends React.Component {
       tokenAttributes: [],
       samlAttributes: [],
       samlMetadata: null,
+      samlSpMetadata: "",
+      samlSpMetadataUrl: "",
       isAuthorized: true,
       activeMenuKey: window.location.hash?.slice(1) || "basic",
       menuMode: "horizontal",
@@ -286,6 +288,36 @@ class ApplicationEditPage extends React.Component {
       });
   }
 
+  parseSamlSpMetadata() {
+    ApplicationBackend.parseSamlSpMetadata(this.state.samlSpMetadata, this.state.samlSpMetadataUrl)
+      .then((res) => {
+        if (res.status !== "ok") {
+          Setting.showMessage("error", `${i18next.t("application:Failed to parse SAML SP metadata")}: ${res.msg}`);
+          return;
+        }
+
+        const spMetadata = res.data;
+        const application = this.state.application;
+        const redirectUris = application.redirectUris ?? [];
+        if (spMetadata.entityId !== "" && !redirectUris.includes(spMetadata.entityId)) {
+          application.redirectUris = [...redirectUris, spMetadata.entityId];
+        }
+        application.samlReplyUrl = spMetadata.acsUrl;
+        application.samlSloUrl = spMetadata.sloUrl;
+        application.samlSloBinding = spMetadata.sloBinding;
+        application.samlSloSoapUrl = spMetadata.sloSoapUrl;
+        application.samlSpCert = spMetadata.signingCert;
+        application.samlSpEncryptionCert = spMetadata.encryptionCert;
+        application.useEmailAsSamlNameId = spMetadata.useEmailAsNameId;
+        application.samlAuthnRequestsSigned = spMetadata.authnRequestsSigned;
+        application.enableSamlAssertionSignature = application.enableSamlAssertionSignature || spMetadata.wantAssertionsSigned;
+        this.setState({
+          application: application,
+        });
+        Setting.showMessage("success", i18next.t("application:SAML SP metadata parsed successfully"));
+      });
+  }
+
   parseApplicationField(key, value) {
     if (["offset"].includes(key)) {
       value = Setting.myParseInt(value);
@@ -812,6 +844,16 @@ class ApplicationEditPage extends React.Component {
               }} />
             </Col>
           </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 3}>
+              {Setting.getLabel(i18next.t("application:Require PAR"), i18next.t("application:Require PAR - Tooltip"))} :
+            </Col>
+            <Col span={1} >
+              <Switch checked={this.state.application.requirePushedAuthorizationRequests} onChange={checked => {
+                this.updateApplicationField("requirePushedAuthorizationRequests", checked);
+              }} />
+            </Col>
+          </Row>
           <Row style={{marginTop: "20px"}} >
             <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
               {Setting.getLabel(i18next.t("application:Grant types"), i18next.t("application:Grant types - Tooltip"))} :
@@ -833,11 +875,42 @@ class ApplicationEditPage extends React.Component {
                     {id: "urn:ietf:params:oauth:grant-type:device_code", name: "Device Code"},
                     {id: "urn:ietf:params:oauth:grant-type:jwt-bearer", name: "JWT Bearer"},
                     {id: "urn:ietf:params:oauth:grant-type:token-exchange", name: "Token Exchange"},
+                    {id: "urn:openid:params:grant-type:ciba", name: "CIBA"},
                   ].map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
                 }
               </Select>
             </Col>
           </Row>
+          {
+            this.state.application.grantTypes?.includes("urn:openid:params:grant-type:ciba") ? (
+              <React.Fragment>
+                <Row style={{marginTop: "20px"}} >
+                  <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+                    {Setting.getLabel(i18next.t("application:CIBA delivery mode"), i18next.t("application:CIBA delivery mode - Tooltip"))} :
+                  </Col>
+                  <Col span={21} >
+                    <Select virtual={false} style={{width: "100%"}} value={this.state.application.cibaTokenDeliveryMode || "poll"} onChange={(value => {this.updateApplicationField("cibaTokenDeliveryMode", value);})}
+                      options={[{id: "poll", name: "Poll"}, {id: "ping", name: "Ping"}].map((item) => Setting.getOption(item.name, item.id))}
+                    />
+                  </Col>
+                </Row>
+                {
+                  this.state.application.cibaTokenDeliveryMode === "ping" ? (
+                    <Row style={{marginTop: "20px"}} >
+                      <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+                        {Setting.getLabel(i18next.t("application:CIBA notification endpoint"), i18next.t("application:CIBA notification endpoint - Tooltip"))} :
+                      </Col>
+                      <Col span={21} >
+                        <Input prefix={<LinkOutlined />} value={this.state.application.cibaClientNotificationEndpoint} onChange={e => {
+                          this.updateApplicationField("cibaClientNotificationEndpoint", e.target.value);
+                        }} />
+                      </Col>
+                    </Row>
+                  ) : null
+                }
+              </React.Fragment>
+            ) : null
+          }
           {
             (this.state.application.category === "Agent") ? (
               <Row style={{marginTop: "20px"}} >
@@ -940,6 +1013,22 @@ class ApplicationEditPage extends React.Component {
       {this.state.activeMenuKey === "saml" && (
         <React.Fragment>
           <Row style={{marginTop: "10px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:SAML SP metadata"), i18next.t("application:SAML SP metadata - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input prefix={<LinkOutlined />} placeholder={i18next.t("application:SAML SP metadata URL")} value={this.state.samlSpMetadataUrl} onChange={e => {
+                this.setState({samlSpMetadataUrl: e.target.value});
+              }} />
+              <Input.TextArea style={{marginTop: "10px"}} autoSize={{minRows: 3, maxRows: 10}} placeholder={i18next.t("application:SAML SP metadata XML")} value={this.state.samlSpMetadata} onChange={e => {
+                this.setState({samlSpMetadata: e.target.value});
+              }} />
+              <Button style={{marginTop: "10px"}} type="primary" disabled={this.state.samlSpMetadataUrl === "" && this.state.samlSpMetadata === ""} onClick={() => this.parseSamlSpMetadata()}>
+                {i18next.t("application:Import SAML SP metadata")}
+              </Button>
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
             <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
               {Setting.getLabel(i18next.t("application:SAML reply URL"), i18next.t("application:Redirect URL (Assertion Consumer Service POST Binding URL) - Tooltip"))} :
             </Col>
@@ -949,6 +1038,75 @@ class ApplicationEditPage extends React.Component {
               }} />
             </Col>
           </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:SAML single logout URL"), i18next.t("application:SAML single logout URL - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input prefix={<LinkOutlined />} value={this.state.application.samlSloUrl} onChange={e => {
+                this.updateApplicationField("samlSloUrl", e.target.value);
+              }} />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:SAML single logout binding"), i18next.t("application:SAML single logout binding - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Select virtual={false} style={{width: "100%"}}
+                value={this.state.application.samlSloBinding === "" ? "HTTP-Redirect" : this.state.application.samlSloBinding}
+                onChange={(value => {
+                  this.updateApplicationField("samlSloBinding", value);
+                })} >
+                {
+                  [
+                    {id: "HTTP-Redirect", name: "HTTP-Redirect"},
+                    {id: "HTTP-POST", name: "HTTP-POST"},
+                  ].map((item, index) => <Option key={index} value={item.id}>{item.name}</Option>)
+                }
+              </Select>
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:SAML single logout SOAP URL"), i18next.t("application:SAML single logout SOAP URL - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input prefix={<LinkOutlined />} value={this.state.application.samlSloSoapUrl} onChange={e => {
+                this.updateApplicationField("samlSloSoapUrl", e.target.value);
+              }} />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:SAML SP certificate"), i18next.t("application:SAML SP certificate - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input.TextArea autoSize={{minRows: 3, maxRows: 10}} value={this.state.application.samlSpCert} onChange={e => {
+                this.updateApplicationField("samlSpCert", e.target.value);
+              }} />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+              {Setting.getLabel(i18next.t("application:SAML authn requests signed"), i18next.t("application:SAML authn requests signed - Tooltip"))} :
+            </Col>
+            <Col span={1} >
+              <Switch checked={this.state.application.samlAuthnRequestsSigned} onChange={checked => {
+                this.updateApplicationField("samlAuthnRequestsSigned", checked);
+              }} />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:SAML SP encryption certificate"), i18next.t("application:SAML SP encryption certificate - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input.TextArea autoSize={{minRows: 3, maxRows: 10}} value={this.state.application.samlSpEncryptionCert} onChange={e => {
+                this.updateApplicationField("samlSpEncryptionCert", e.target.value);
+              }} />
+            </Col>
+          </Row>
           <Row style={{marginTop: "20px"}} >
             <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
               {Setting.getLabel(i18next.t("application:Enable SAML compression"), i18next.t("application:Enable SAML compression - Tooltip"))} :
@@ -1090,6 +1248,23 @@ class ApplicationEditPage extends React.Component {
               </Row>
             )
           }
+          {
+            this.state.mode === "add" ? null : (
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+                  {Setting.getLabel(i18next.t("application:WS-Federation metadata URL"), i18next.t("application:WS-Federation metadata URL - Tooltip"))} :
+                </Col>
+                <Col span={21}>
+                  <Input prefix={<LinkOutlined />} readOnly value={`${window.location.origin}/api/wsfed/admin/${encodeURIComponent(this.state.applicationName)}/FederationMetadata.xml`} addonAfter={
+                    <CopyOutlined onClick={() => {
+                      copy(`${window.location.origin}/api/wsfed/admin/${encodeURIComponent(this.state.applicationName)}/FederationMetadata.xml`);
+                      Setting.showMessage("success", i18next.t("general:Copied to clipboard successfully"));
+                    }} />
+                  } />
+                </Col>
+              </Row>
+            )
+          }
         </React.Fragment>
       )}
       {this.state.activeMenuKey === "providers" && (
@@ -1513,6 +1688,102 @@ class ApplicationEditPage extends React.Component {
               </Select>
             </Col>
           </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:Client JWKS URL"), i18next.t("application:Client JWKS URL - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input prefix={<LinkOutlined />} value={this.state.application.clientJwksUri} onChange={e => {
+                this.updateApplicationField("clientJwksUri", e.target.value);
+              }} />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:TLS client auth"), i18next.t("application:TLS client auth - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Select virtual={false} style={{width: "100%"}} value={this.state.application.tlsClientAuthMethod ?? ""} onChange={(value => {
+                this.updateApplicationField("tlsClientAuthMethod", value);
+              })}
+              options={[
+                {id: "", name: i18next.t("general:None")},
+                {id: "tls_client_auth", name: "tls_client_auth"},
+                {id: "self_signed_tls_client_auth", name: "self_signed_tls_client_auth"},
+              ].map((item) => Setting.getOption(item.name, item.id))}
+              />
+            </Col>
+          </Row>
+          {
+            this.state.application.tlsClientAuthMethod !== "tls_client_auth" ? null : (
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+                  {Setting.getLabel(i18next.t("application:TLS client subject DN"), i18next.t("application:TLS client subject DN - Tooltip"))} :
+                </Col>
+                <Col span={21} >
+                  <Input value={this.state.application.tlsClientAuthSubjectDn} onChange={e => {
+                    this.updateApplicationField("tlsClientAuthSubjectDn", e.target.value);
+                  }} />
+                </Col>
+              </Row>
+            )
+          }
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 3}>
+              {Setting.getLabel(i18next.t("application:Certificate-bound tokens"), i18next.t("application:Certificate-bound tokens - Tooltip"))} :
+            </Col>
+            <Col span={1} >
+              <Switch checked={this.state.application.tlsClientCertificateBoundAccessTokens} onChange={checked => {
+                this.updateApplicationField("tlsClientCertificateBoundAccessTokens", checked);
+              }} />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:ID token encryption"), i18next.t("application:ID token encryption - Tooltip"))} :
+            </Col>
+            <Col span={10} >
+              <Select virtual={false} style={{width: "100%"}} value={this.state.application.idTokenEncryptedResponseAlg ?? ""} onChange={(value => {
+                this.updateApplicationField("idTokenEncryptedResponseAlg", value);
+                if (value === "") {
+                  this.updateApplicationField("idTokenEncryptedResponseEnc", "");
+                }
+              })}
+              options={[{id: "", name: i18next.t("general:None")}, ...["RSA-OAEP", "RSA-OAEP-256", "ECDH-ES", "ECDH-ES+A128KW", "ECDH-ES+A256KW"].map(item => ({id: item, name: item}))].map((item) => Setting.getOption(item.name, item.id))}
+              />
+            </Col>
+            <Col span={1} />
+            <Col span={10} >
+              <Select virtual={false} style={{width: "100%"}} disabled={!this.state.application.idTokenEncryptedResponseAlg} value={this.state.application.idTokenEncryptedResponseEnc || "A128CBC-HS256"} onChange={(value => {
+                this.updateApplicationField("idTokenEncryptedResponseEnc", value);
+              })}
+              options={["A128CBC-HS256", "A256CBC-HS512", "A128GCM", "A256GCM"].map((item) => Setting.getOption(item, item))}
+              />
+            </Col>
+          </Row>
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
+              {Setting.getLabel(i18next.t("application:Userinfo encryption"), i18next.t("application:Userinfo encryption - Tooltip"))} :
+            </Col>
+            <Col span={10} >
+              <Select virtual={false} style={{width: "100%"}} value={this.state.application.userinfoEncryptedResponseAlg ?? ""} onChange={(value => {
+                this.updateApplicationField("userinfoEncryptedResponseAlg", value);
+                if (value === "") {
+                  this.updateApplicationField("userinfoEncryptedResponseEnc", "");
+                }
+              })}
+              options={[{id: "", name: i18next.t("general:None")}, ...["RSA-OAEP", "RSA-OAEP-256", "ECDH-ES", "ECDH-ES+A128KW", "ECDH-ES+A256KW"].map(item => ({id: item, name: item}))].map((item) => Setting.getOption(item.name, item.id))}
+              />
+            </Col>
+            <Col span={1} />
+            <Col span={10} >
+              <Select virtual={false} style={{width: "100%"}} disabled={!this.state.application.userinfoEncryptedResponseAlg} value={this.state.application.userinfoEncryptedResponseEnc || "A128CBC-HS256"} onChange={(value => {
+                this.updateApplicationField("userinfoEncryptedResponseEnc", value);
+              })}
+              options={["A128CBC-HS256", "A256CBC-HS512", "A128GCM", "A256GCM"].map((item) => Setting.getOption(item, item))}
+              />
+            </Col>
+          </Row>
           <Row style={{marginTop: "20px"}} >
             <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 3}>
               {Setting.getLabel(i18next.t("application:Failed signin limit"), i18next.t("application:Failed signin limit - Tooltip"))} :
@@ -114,6 +114,18 @@ class CertEditPage extends React.Component {
     });
   }
 
+  rotateCert() {
+    CertBackend.rotateCert(this.state.cert.owner, this.state.cert.name)
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("cert:Keys rotated successfully"));
+          this.getCert();
+        } else {
+          Setting.showMessage("error", `${i18next.t("cert:Failed to rotate keys")}: ${res.msg}`);
+        }
+      });
+  }
+
   renderCert() {
     const editorWidth = Setting.isMobile() ? 22 : 9;
     return (
@@ -264,6 +276,59 @@ class CertEditPage extends React.Component {
             </Row>
           )
         }
+        {
+          this.state.cert.type === "x509" ? (
+            <React.Fragment>
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                  {Setting.getLabel(i18next.t("cert:Rotation interval"), i18next.t("cert:Rotation interval - Tooltip"))} :
+                </Col>
+                <Col span={22} >
+                  <InputNumber min={0} value={this.state.cert.rotationInterval} addonAfter={i18next.t("organization:days")} onChange={value => {
+                    this.updateCertField("rotationInterval", value);
+                  }} />
+                  {
+                    this.state.mode === "add" ? null : (
+                      <Button style={{marginLeft: "20px"}} onClick={() => this.rotateCert()}>{i18next.t("cert:Rotate now")}</Button>
+                    )
+                  }
+                </Col>
+              </Row>
+              <Row style={{marginTop: "20px"}} >
+                <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                  {Setting.getLabel(i18next.t("cert:Key ID"), i18next.t("cert:Key ID - Tooltip"))} :
+                </Col>
+                <Col span={22} >
+                  <Input disabled value={this.state.cert.keyId || this.state.cert.name} />
+                </Col>
+              </Row>
+              {
+                this.state.cert.nextKeyId ? (
+                  <Row style={{marginTop: "20px"}} >
+                    <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                      {Setting.getLabel(i18next.t("cert:Next key ID"), i18next.t("cert:Next key ID - Tooltip"))} :
+                    </Col>
+                    <Col span={22} >
+                      <Input disabled value={this.state.cert.nextKeyId} />
+                    </Col>
+                  </Row>
+                ) : null
+              }
+              {
+                this.state.cert.retiredKeyId ? (
+                  <Row style={{marginTop: "20px"}} >
+                    <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                      {Setting.getLabel(i18next.t("cert:Retired key ID"), i18next.t("cert:Retired key ID - Tooltip"))} :
+                    </Col>
+                    <Col span={22} >
+                      <Input disabled value={`${this.state.cert.retiredKeyId} (${i18next.t("general:Expire time")}: ${this.state.cert.retiredExpireTime})`} />
+                    </Col>
+                  </Row>
+                ) : null
+              }
+            </React.Fragment>
+          ) : null
+        }
         {
           this.state.cert.type === "SSL" ? (
             <React.Fragment>
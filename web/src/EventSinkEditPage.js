@@ -0,0 +1,360 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import Loading from "./common/Loading";
+import {Button, Card, Col, Input, InputNumber, Row, Select, Switch} from "antd";
+import * as EventSinkBackend from "./backend/EventSinkBackend";
+import * as OrganizationBackend from "./backend/OrganizationBackend";
+import * as Setting from "./Setting";
+import i18next from "i18next";
+
+const {Option} = Select;
+
+class EventSinkEditPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+      eventSinkName: props.match.params.eventSinkName,
+      eventSink: null,
+      organizations: [],
+      mode: props.location.mode !== undefined ? props.location.mode : "edit",
+    };
+  }
+
+  UNSAFE_componentWillMount() {
+    this.getEventSink();
+    this.getOrganizations();
+  }
+
+  getEventSink() {
+    if (this.state.mode === "add" && this.props.location.eventSink) {
+      const eventSink = this.props.location.eventSink;
+      this.setState({
+        eventSink: eventSink,
+      });
+      return;
+    }
+
+    EventSinkBackend.getEventSink("admin", this.state.eventSinkName, this.props.account.owner)
+      .then((res) => {
+        if (res.data === null) {
+          this.props.history.push("/404");
+          return;
+        }
+
+        this.setState({
+          eventSink: res.data,
+        });
+      });
+  }
+
+  getOrganizations() {
+    OrganizationBackend.getOrganizations("admin")
+      .then((res) => {
+        this.setState({
+          organizations: res.data || [],
+        });
+      });
+  }
+
+  parseEventSinkField(key, value) {
+    if (["maxRetries", "retryInterval"].includes(key)) {
+      value = Setting.myParseInt(value);
+    }
+    return value;
+  }
+
+  updateEventSinkField(key, value) {
+    value = this.parseEventSinkField(key, value);
+
+    const eventSink = this.state.eventSink;
+    eventSink[key] = value;
+    this.setState({
+      eventSink: eventSink,
+    });
+  }
+
+  getEndpointPlaceholder() {
+    switch (this.state.eventSink.type) {
+    case "Kafka":
+      return "broker1:9092,broker2:9092";
+    case "NATS":
+      return "nats://localhost:4222";
+    case "AMQP":
+      return "amqp://localhost:5672/";
+    case "Redis Streams":
+      return "redis://localhost:6379/0";
+    default:
+      return "";
+    }
+  }
+
+  getTopicLabel() {
+    switch (this.state.eventSink.type) {
+    case "NATS":
+      return i18next.t("eventSink:Subject");
+    case "AMQP":
+      return i18next.t("eventSink:Exchange");
+    case "Redis Streams":
+      return i18next.t("eventSink:Stream");
+    default:
+      return i18next.t("eventSink:Topic");
+    }
+  }
+
+  renderEventSink() {
+    return (
+      <Card size="small" title={
+        <div>
+          {this.state.mode === "add" ? i18next.t("eventSink:New Event Sink") : i18next.t("eventSink:Edit Event Sink")}&nbsp;&nbsp;&nbsp;&nbsp;
+          <Button onClick={() => this.submitEventSinkEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" onClick={() => this.submitEventSinkEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+          {this.state.mode === "add" ? <Button style={{marginLeft: "20px"}} onClick={() => this.deleteEventSink()}>{i18next.t("general:Cancel")}</Button> : null}
+        </div>
+      } style={(Setting.isMobile()) ? {margin: "5px"} : {}} type="inner">
+        <Row style={{marginTop: "10px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Organization"), i18next.t("general:Organization - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} disabled={!Setting.isAdminUser(this.props.account)} value={this.state.eventSink.organization} onChange={(value => {this.updateEventSinkField("organization", value);})}>
+              {
+                this.state.organizations.map((organization, index) => <Option key={index} value={organization.name}>{organization.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Name"), i18next.t("general:Name - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.eventSink.name} onChange={e => {
+              this.updateEventSinkField("name", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Type"), i18next.t("eventSink:Type - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.eventSink.type} onChange={(value => {this.updateEventSinkField("type", value);})}>
+              {
+                Setting.getEventSinkTypes().map((type, index) => <Option key={index} value={type}>{type}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("provider:Endpoint"), i18next.t("eventSink:Endpoint - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.eventSink.endpoint} placeholder={this.getEndpointPlaceholder()} onChange={e => {
+              this.updateEventSinkField("endpoint", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(this.getTopicLabel(), i18next.t("eventSink:Topic - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.eventSink.topic} onChange={e => {
+              this.updateEventSinkField("topic", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("signup:Username"), i18next.t("eventSink:Username - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input value={this.state.eventSink.username} onChange={e => {
+              this.updateEventSinkField("username", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("general:Password"), i18next.t("general:Password - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input.Password value={this.state.eventSink.password} onChange={e => {
+              this.updateEventSinkField("password", e.target.value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("webhook:Payload format"), i18next.t("webhook:Payload format - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.eventSink.payloadFormat || "Casdoor"} onChange={(value => {this.updateEventSinkField("payloadFormat", value);})}>
+              {
+                [
+                  {id: "Casdoor", name: "Casdoor"},
+                  {id: "CloudEvents", name: "CloudEvents 1.0"},
+                ].map((payloadFormat, index) => <Option key={index} value={payloadFormat.id}>{payloadFormat.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("eventSink:Partition key"), i18next.t("eventSink:Partition key - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.eventSink.partitionKey} onChange={(value => {this.updateEventSinkField("partitionKey", value);})}>
+              {
+                [
+                  {id: "", name: i18next.t("general:None")},
+                  {id: "Organization", name: i18next.t("general:Organization")},
+                  {id: "Subject", name: i18next.t("eventSink:Subject")},
+                ].map((partitionKey, index) => <Option key={index} value={partitionKey.id}>{partitionKey.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("webhook:Events"), i18next.t("webhook:Events - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} mode="multiple" style={{width: "100%"}}
+              value={this.state.eventSink.events}
+              onChange={value => {
+                this.updateEventSinkField("events", value);
+              }} >
+              {
+                Setting.getDomainEventTypes().concat(Setting.getApiPaths()).map((option, index) => {
+                  return (
+                    <Option key={option} value={option}>{option}</Option>
+                  );
+                })
+              }
+            </Select>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("eventSink:Max retries"), i18next.t("eventSink:Max retries - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={1} value={this.state.eventSink.maxRetries} onChange={value => {
+              this.updateEventSinkField("maxRetries", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("eventSink:Retry interval"), i18next.t("eventSink:Retry interval - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <InputNumber min={1} value={this.state.eventSink.retryInterval} onChange={value => {
+              this.updateEventSinkField("retryInterval", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("eventSink:Use exponential backoff"), i18next.t("eventSink:Use exponential backoff - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            <Switch checked={this.state.eventSink.useExponentialBackoff} onChange={checked => {
+              this.updateEventSinkField("useExponentialBackoff", checked);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("webhook:Single org only"), i18next.t("webhook:Single org only - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            {/* turning this off makes the event sink receive the events of every organization, which is reserved for global admins */}
+            <Switch disabled={!Setting.isAdminUser(this.props.account)} checked={this.state.eventSink.singleOrgOnly} onChange={checked => {
+              this.updateEventSinkField("singleOrgOnly", checked);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("general:Is enabled"), i18next.t("general:Is enabled - Tooltip"))} :
+          </Col>
+          <Col span={1} >
+            <Switch checked={this.state.eventSink.isEnabled} onChange={checked => {
+              this.updateEventSinkField("isEnabled", checked);
+            }} />
+          </Col>
+        </Row>
+      </Card>
+    );
+  }
+
+  submitEventSinkEdit(exitAfterSave) {
+    const eventSink = Setting.deepCopy(this.state.eventSink);
+    const isAdd = this.state.mode === "add";
+    const apiCall = isAdd
+      ? EventSinkBackend.addEventSink(eventSink)
+      : EventSinkBackend.updateEventSink(this.state.eventSink.owner, this.state.eventSinkName, eventSink);
+    apiCall
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully saved"));
+          this.setState({
+            eventSinkName: this.state.eventSink.name,
+            mode: "edit",
+          });
+
+          if (exitAfterSave) {
+            this.props.history.push("/event-sinks");
+          } else {
+            this.props.history.push(`/event-sinks/${this.state.eventSink.name}`);
+          }
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to save")}: ${res.msg}`);
+          if (!isAdd) {
+            this.updateEventSinkField("name", this.state.eventSinkName);
+          }
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  }
+
+  deleteEventSink() {
+    this.props.history.push("/event-sinks");
+  }
+
+  render() {
+    return (
+      <div>
+        {
+          this.state.eventSink !== null ? this.renderEventSink() : <Loading type="page" tip={i18next.t("login:Loading")} />
+        }
+        <div style={{margin: "20px 40px"}}>
+          <Button size="large" onClick={() => this.submitEventSinkEdit(false)}>{i18next.t("general:Save")}</Button>
+          <Button style={{marginLeft: "20px"}} type="primary" size="large" onClick={() => this.submitEventSinkEdit(true)}>{i18next.t("general:Save & Exit")}</Button>
+          {this.state.mode === "add" ? <Button style={{marginLeft: "20px"}} size="large" onClick={() => this.deleteEventSink()}>{i18next.t("general:Cancel")}</Button> : null}
+        </div>
+      </div>
+    );
+  }
+}
+
+export default EventSinkEditPage;
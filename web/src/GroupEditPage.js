@@ -20,6 +20,7 @@ import * as OrganizationBackend from "./backend/OrganizationBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
 import PropertyTable from "./table/propertyTable";
+import RadiusReplyAttributeTable from "./table/RadiusReplyAttributeTable";
 
 class GroupEditPage extends React.Component {
   constructor(props) {
@@ -228,6 +229,17 @@ class GroupEditPage extends React.Component {
             />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("group:RADIUS reply attributes"), i18next.t("group:RADIUS reply attributes - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <RadiusReplyAttributeTable
+              table={this.state.group.radiusReplyAttributes}
+              onUpdateTable={(value) => {this.updateGroupField("radiusReplyAttributes", value);}}
+            />
+          </Col>
+        </Row>
       </Card>
     );
   }
@@ -375,6 +375,7 @@ class OrganizationEditPage extends React.Component {
                 {value: "Aa123", name: i18next.t("user:The password must contain at least one uppercase letter, one lowercase letter and one digit")},
                 {value: "SpecialChar", name: i18next.t("user:The password must contain at least one special character")},
                 {value: "NoRepeat", name: i18next.t("user:The password must not contain any repeated characters")},
+                {value: "NotBreached", name: i18next.t("user:The password must not appear in a known data breach")},
               ].map((item) => Setting.getOption(item.name, item.value))}
             />
           </Col>
@@ -417,6 +418,26 @@ class OrganizationEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("organization:Password history depth"), i18next.t("organization:Password history depth - Tooltip"))} :
+          </Col>
+          <Col span={4} >
+            <InputNumber min={0} value={this.state.organization.passwordHistoryDepth} onChange={value => {
+              this.updateOrganizationField("passwordHistoryDepth", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("organization:Password min age days"), i18next.t("organization:Password min age days - Tooltip"))} :
+          </Col>
+          <Col span={4} >
+            <InputNumber min={0} value={this.state.organization.passwordMinAgeDays} onChange={value => {
+              this.updateOrganizationField("passwordMinAgeDays", value);
+            }} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
             {Setting.getLabel(i18next.t("organization:Token retention days"), i18next.t("organization:Token retention days - Tooltip"))} :
@@ -859,6 +880,26 @@ class OrganizationEditPage extends React.Component {
             />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("organization:LDAP size limit"), i18next.t("organization:LDAP size limit - Tooltip"))} :
+          </Col>
+          <Col span={4} >
+            <InputNumber min={0} value={this.state.organization.ldapSizeLimit ?? 0} onChange={value => {
+              this.updateOrganizationField("ldapSizeLimit", value);
+            }} />
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 19 : 2}>
+            {Setting.getLabel(i18next.t("organization:LDAP time limit"), i18next.t("organization:LDAP time limit - Tooltip"))} :
+          </Col>
+          <Col span={4} >
+            <InputNumber min={0} addonAfter={i18next.t("usage:seconds")} value={this.state.organization.ldapTimeLimit ?? 0} onChange={value => {
+              this.updateOrganizationField("ldapTimeLimit", value);
+            }} />
+          </Col>
+        </Row>
         {
           // LDAPs belong to an existing organization, so they can only be managed after the organization is saved
           this.state.mode === "add" ? null : (
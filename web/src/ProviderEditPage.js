@@ -81,6 +81,17 @@ const defaultUserMapping = {
   title: "title",
 };
 
+const defaultOidcUserMapping = {
+  id: "sub",
+  username: "preferred_username",
+  displayName: "name",
+  email: "email",
+  avatarUrl: "picture",
+  phone: "phone_number",
+  firstName: "given_name",
+  lastName: "family_name",
+};
+
 const defaultEmailMapping = {
   fromName: "fromName",
   fromAddress: "fromAddress",
@@ -910,6 +921,9 @@ class ProviderEditPage extends React.Component {
               this.updateProviderField("type", value);
               if (value === "Local File System") {
                 this.updateProviderField("domain", Setting.getFullServerUrl());
+              } else if (value === "OIDC") {
+                this.updateProviderField("scopes", "openid profile email");
+                this.updateProviderField("userMapping", {...defaultOidcUserMapping});
               } else if (value.startsWith("Custom") && this.state.provider.category === "OAuth") {
                 this.updateProviderField("customAuthUrl", "https://door.casdoor.com/login/oauth/authorize");
                 this.updateProviderField("scopes", "openid profile email");
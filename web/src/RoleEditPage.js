@@ -22,6 +22,7 @@ import * as RoleBackend from "./backend/RoleBackend";
 import * as Setting from "./Setting";
 import i18next from "i18next";
 import PaginateSelect from "./common/PaginateSelect";
+import RadiusReplyAttributeTable from "./table/RadiusReplyAttributeTable";
 
 class RoleEditPage extends React.Component {
   constructor(props) {
@@ -234,6 +235,17 @@ class RoleEditPage extends React.Component {
             }} />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("group:RADIUS reply attributes"), i18next.t("group:RADIUS reply attributes - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <RadiusReplyAttributeTable
+              table={this.state.role.radiusReplyAttributes}
+              onUpdateTable={(value) => {this.updateRoleField("radiusReplyAttributes", value);}}
+            />
+          </Col>
+        </Row>
       </Card>
     );
   }
@@ -594,6 +594,7 @@ export const GetTranslatedUserItems = () => {
     {name: "Face ID", label: i18next.t("login:Face ID")},
     {name: "MFA accounts", label: i18next.t("user:MFA accounts")},
     {name: "MFA items", label: i18next.t("general:MFA items")},
+    {name: "Sessions", label: i18next.t("general:Sessions")},
   ];
 };
 
@@ -959,6 +960,7 @@ export const MfaRulePrompted = "Prompted";
 export const MfaRuleOptional = "Optional";
 
 export const RequiredUpdatePassword = "RequiredUpdatePassword";
+export const RequiredReauthentication = "RequiredReauthentication";
 
 export function goToUpdatePassword() {
   // remember where the login was started from, to go back after the password is updated
@@ -1288,11 +1290,11 @@ export function getClickable(text) {
 }
 
 export function getProviderLogoURL(provider) {
-  if (provider.type.startsWith("Custom") && provider.customLogo) {
+  if ((provider.type.startsWith("Custom") || provider.type === "OIDC") && provider.customLogo) {
     return provider.customLogo;
   }
   if (provider.category === "OAuth") {
-    const type = provider.type.startsWith("Custom") ? "Custom" : provider.type;
+    const type = provider.type.startsWith("Custom") || provider.type === "OIDC" ? "Custom" : provider.type;
     return `${StaticBaseUrl}/img/social_${type.toLowerCase()}.png`;
   } else {
     const info = OtherProviderInfo[provider.category][provider.type];
@@ -1393,6 +1395,7 @@ export function getProviderTypeOptions(category) {
         {id: "Yammer", name: "Yammer"},
         {id: "Yandex", name: "Yandex"},
         {id: "Zoom", name: "Zoom"},
+        {id: "OIDC", name: "OIDC"},
         {id: "Custom", name: "Custom"},
         {id: "Custom2", name: "Custom2"},
         {id: "Custom3", name: "Custom3"},
@@ -2326,6 +2329,10 @@ export function checkLoginMfa(res, body, params, handleLogin, componentThis, req
     } else {
       componentThis.props.onLoginSuccess(requireRedirect);
     }
+  } else if (res.data === RequiredReauthentication) {
+    // the session doesn't meet the acr_values or max_age of the request, so the user signs in again
+    componentThis.setState({requiredReauthentication: true});
+    showMessage("info", i18next.t("login:Please sign in again to continue"));
   } else if (res.data === NextMfa) {
     componentThis.setState({
       mfaProps: res.data2,
@@ -2536,7 +2543,7 @@ export function filterTableColumns(columns, formItems, actionKey = "op") {
 }
 
 export function getApiPaths() {
-  const objects = ["organization", "group", "user", "application", "provider", "resource", "cert", "role", "permission", "model", "adapter", "enforcer", "session", "token", "product", "payment", "plan", "pricing", "subscription", "syncer", "webhook", "form", "invitation", "ldap", "order", "ticket", "transaction"];
+  const objects = ["organization", "group", "user", "application", "provider", "resource", "cert", "role", "permission", "model", "adapter", "enforcer", "session", "token", "product", "payment", "plan", "pricing", "subscription", "syncer", "webhook", "event-sink", "form", "invitation", "ldap", "order", "ticket", "transaction"];
   const res = [];
 
   // Auth and user session APIs
@@ -2628,6 +2635,14 @@ export function getApiPaths() {
   return res;
 }
 
+export function getEventSinkTypes() {
+  return ["Kafka", "NATS", "AMQP", "Redis Streams"];
+}
+
+export function getDomainEventTypes() {
+  return ["user.created", "user.updated", "user.deleted", "user.password_changed", "user.mfa_enabled", "user.mfa_disabled", "session.revoked", "payment.paid", "subscription.expired"];
+}
+
 export function getItemId(item) {
   return item.owner + "/" + item.name;
 }
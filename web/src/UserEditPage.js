@@ -52,6 +52,8 @@ import TransactionTable from "./table/TransactionTable";
 import CartTable from "./table/CartTable";
 import * as TransactionBackend from "./backend/TransactionBackend";
 import ConsentTable from "./table/ConsentTable";
+import SessionDeviceTable from "./table/SessionDeviceTable";
+import * as SessionBackend from "./backend/SessionBackend";
 import {Content, Header} from "antd/es/layout/layout";
 import Sider from "antd/es/layout/Sider";
 
@@ -76,6 +78,7 @@ class UserEditPage extends React.Component {
       openFaceRecognitionModal: false,
       transactions: [],
       consents: [],
+      sessionDevices: [],
       activeMenuKey: window.location.hash?.slice(1) || "",
       menuMode: "Horizontal",
     };
@@ -129,6 +132,7 @@ class UserEditPage extends React.Component {
           loading: false,
         }, () => {
           this.getApplicationsByOrganization(this.state.organizationName);
+          this.getSessionDevices();
         });
 
         // Load user transactions
@@ -152,6 +156,30 @@ class UserEditPage extends React.Component {
       });
   }
 
+  getSessionDevices() {
+    if (!this.isSelfOrAdmin()) {
+      return;
+    }
+
+    const promise = this.isSelf() ?
+      SessionBackend.getAccountSessionDevices() :
+      SessionBackend.getSessionDevices(this.state.organizationName, this.state.userName);
+
+    promise
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({
+            sessionDevices: res.data ?? [],
+          });
+        } else {
+          Setting.showMessage("error", `${i18next.t("general:Failed to load")}: ${res.msg}`);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  }
+
   getOrganizations() {
     OrganizationBackend.getOrganizations("admin")
       .then((res) => {
@@ -1169,6 +1197,24 @@ class UserEditPage extends React.Component {
           </Col>
         </Row>
       );
+    } else if (accountItem.name === "Sessions") {
+      return (
+        !this.isSelfOrAdmin() || this.state.mode === "add" ? null : (
+          <Row style={{marginTop: "20px"}}>
+            <Col style={{marginTop: "5px"}} span={Setting.isMobile() ? 22 : 2}>
+              {Setting.getLabel(i18next.t("general:Sessions"), i18next.t("user:Sessions - Tooltip"))} :
+            </Col>
+            <Col span={22}>
+              <SessionDeviceTable
+                title={i18next.t("general:Sessions")}
+                table={this.state.sessionDevices}
+                isSelf={this.isSelf()}
+                onUpdateTable={() => this.getSessionDevices()}
+              />
+            </Col>
+          </Row>
+        )
+      );
     } else if (accountItem.name === "Multi-factor authentication") {
       return (
         // the MFA items are read from and written to the saved user, so they need the user to exist
@@ -160,6 +160,16 @@ class WebhookEditPage extends React.Component {
     return value;
   }
 
+  // the current secret keeps signing the deliveries along with the new one until it is cleared,
+  // so that the receivers can be switched to the new secret without losing any delivery
+  rotateSigningSecret() {
+    const secret = new Uint8Array(24);
+    window.crypto.getRandomValues(secret);
+
+    this.updateWebhookField("previousSigningSecret", this.state.webhook.signingSecret);
+    this.updateWebhookField("signingSecret", `whsec_${btoa(String.fromCharCode(...secret))}`);
+  }
+
   updateWebhookField(key, value) {
     value = this.parseWebhookField(key, value);
 
@@ -184,7 +194,16 @@ class WebhookEditPage extends React.Component {
         preview["extendedUser"] = userTemplate;
       }
     }
-    const previewText = JSON.stringify(preview, null, 2);
+    const previewText = JSON.stringify(this.state.webhook.payloadFormat === "CloudEvents" ? {
+      "specversion": "1.0",
+      "id": "b1c2a3d4-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+      "source": `/organizations/${preview.organization}`,
+      "type": `org.casdoor.${preview.action}`,
+      "subject": preview.user,
+      "time": preview.createdTime,
+      "datacontenttype": "application/json",
+      "data": preview,
+    } : preview, null, 2);
 
     return (
       <Card size="small" title={
@@ -259,6 +278,21 @@ class WebhookEditPage extends React.Component {
             </Select>
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("webhook:Payload format"), i18next.t("webhook:Payload format - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Select virtual={false} style={{width: "100%"}} value={this.state.webhook.payloadFormat || "Casdoor"} onChange={(value => {this.updateWebhookField("payloadFormat", value);})}>
+              {
+                [
+                  {id: "Casdoor", name: "Casdoor"},
+                  {id: "CloudEvents", name: "CloudEvents 1.0"},
+                ].map((payloadFormat, index) => <Option key={index} value={payloadFormat.id}>{payloadFormat.name}</Option>)
+              }
+            </Select>
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("webhook:Headers"), i18next.t("webhook:Headers - Tooltip"))} :
@@ -271,6 +305,31 @@ class WebhookEditPage extends React.Component {
             />
           </Col>
         </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("webhook:Signing secret"), i18next.t("webhook:Signing secret - Tooltip"))} :
+          </Col>
+          <Col span={20} >
+            <Input.Password value={this.state.webhook.signingSecret} onChange={e => {
+              this.updateWebhookField("signingSecret", e.target.value);
+            }} />
+          </Col>
+          <Col span={2} >
+            <Button style={{marginLeft: "10px"}} onClick={() => this.rotateSigningSecret()}>
+              {i18next.t("webhook:Rotate")}
+            </Button>
+          </Col>
+        </Row>
+        <Row style={{marginTop: "20px"}} >
+          <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+            {Setting.getLabel(i18next.t("webhook:Previous signing secret"), i18next.t("webhook:Previous signing secret - Tooltip"))} :
+          </Col>
+          <Col span={22} >
+            <Input.Password value={this.state.webhook.previousSigningSecret} allowClear onChange={e => {
+              this.updateWebhookField("previousSigningSecret", e.target.value);
+            }} />
+          </Col>
+        </Row>
         <Row style={{marginTop: "20px"}} >
           <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
             {Setting.getLabel(i18next.t("webhook:Events"), i18next.t("webhook:Events - Tooltip"))} :
@@ -282,7 +341,7 @@ class WebhookEditPage extends React.Component {
                 this.updateWebhookField("events", value);
               }} >
               {
-                Setting.getApiPaths().map((option, index) => {
+                Setting.getDomainEventTypes().concat(Setting.getApiPaths()).map((option, index) => {
                   return (
                     <Option key={option} value={option}>{option}</Option>
                   );
@@ -34,6 +34,7 @@ class WebhookListPage extends BaseListPage {
       url: "https://example.com/callback",
       method: "POST",
       contentType: "application/json",
+      payloadFormat: "Casdoor",
       headers: [],
       events: ["signup", "login", "logout", "update-user"],
       isEnabled: true,
@@ -60,9 +60,18 @@ export function oAuthParamsToQuery(oAuthParams) {
   const resourceQuery = oAuthParams.resource
     ? `&resource=${encodeURIComponent(oAuthParams.resource)}`
     : "";
+  const requestUriQuery = oAuthParams.requestUri
+    ? `&request_uri=${encodeURIComponent(oAuthParams.requestUri)}`
+    : "";
+  const acrValuesQuery = oAuthParams.acrValues
+    ? `&acr_values=${encodeURIComponent(oAuthParams.acrValues)}`
+    : "";
+  const maxAgeQuery = oAuthParams.maxAge
+    ? `&max_age=${encodeURIComponent(oAuthParams.maxAge)}`
+    : "";
 
   // code
-  return `?clientId=${oAuthParams.clientId}&responseType=${oAuthParams.responseType}&redirectUri=${encodeURIComponent(oAuthParams.redirectUri)}&type=${oAuthParams.type}&scope=${oAuthParams.scope}&state=${oAuthParams.state}&nonce=${oAuthParams.nonce}&code_challenge_method=${oAuthParams.challengeMethod}&code_challenge=${oAuthParams.codeChallenge}${resourceQuery}`;
+  return `?clientId=${oAuthParams.clientId}&responseType=${oAuthParams.responseType}&redirectUri=${encodeURIComponent(oAuthParams.redirectUri)}&type=${oAuthParams.type}&scope=${oAuthParams.scope}&state=${oAuthParams.state}&nonce=${oAuthParams.nonce}&code_challenge_method=${oAuthParams.challengeMethod}&code_challenge=${oAuthParams.codeChallenge}${resourceQuery}${requestUriQuery}${acrValuesQuery}${maxAgeQuery}`;
 }
 
 export function getApplicationLogin(params) {
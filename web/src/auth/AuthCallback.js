@@ -146,6 +146,12 @@ class AuthCallback extends React.Component {
           const redirectUri = res.data2.redirectUrl;
           Setting.goToLink(`${redirectUri}${redirectUri.includes("?") ? "&" : "?"}SAMLResponse=${encodeURIComponent(SAMLResponse)}&RelayState=${oAuthParams.relayState}`);
         }
+      } else if (responseType === "wsfed") {
+        createFormAndSubmit(res.data2, {
+          wa: "wsignin1.0",
+          wresult: res.data,
+          wctx: oAuthParams.wsFedContext,
+        });
       }
     };
 
@@ -181,8 +187,11 @@ class AuthCallback extends React.Component {
         const samlRequest = innerParams.get("SAMLRequest");
         // cas don't use 'redirect_url', it is called 'service'
         const casService = innerParams.get("service");
+        const wsFedRealm = innerParams.get("wtrealm");
         if (samlRequest !== null && samlRequest !== undefined && samlRequest !== "") {
           return "saml";
+        } else if (wsFedRealm !== null && wsFedRealm !== undefined && wsFedRealm !== "") {
+          return "wsfed";
         } else if (casService !== null && casService !== undefined && casService !== "") {
           return "cas";
         }
@@ -273,6 +282,7 @@ class AuthCallback extends React.Component {
 
     // Retrieve the code verifier for PKCE if it exists
     const codeVerifier = Provider.getCodeVerifier(params.get("state"));
+    const idpNonce = Provider.getIdpNonce(params.get("state"));
 
     const body = {
       type: this.getResponseType(),
@@ -280,6 +290,12 @@ class AuthCallback extends React.Component {
       provider: providerName,
       code: code,
       samlRequest: samlRequest,
+      relayState: innerParams.get("RelayState") || "",
+      sigAlg: innerParams.get("SigAlg") || "",
+      signature: innerParams.get("Signature") || "",
+      samlQuery: samlRequest ? Util.getQueryParamsFromState(params.get("state")).replace(/^\?/, "") : "",
+      wsFedRealm: innerParams.get("wtrealm") || "",
+      wsFedReply: innerParams.get("wreply") || "",
       // state: innerParams.get("state"),
       state: applicationName,
       invitationCode: innerParams.get("invitationCode") || "",
@@ -287,6 +303,7 @@ class AuthCallback extends React.Component {
       method: method,
       userCode: innerParams.get("userCode") || "",
       codeVerifier: codeVerifier, // Include PKCE code verifier
+      idpNonce: idpNonce || "",
       // The selector on the login page is gone by now, so use the language it stored
       language: Setting.getSigninLanguage(),
     };
@@ -295,6 +312,9 @@ class AuthCallback extends React.Component {
     if (codeVerifier) {
       Provider.clearCodeVerifier(params.get("state"));
     }
+    if (idpNonce) {
+      Provider.clearIdpNonce(params.get("state"));
+    }
 
     const reactFallbackPayload = this.consumeReactFallbackPayload();
     if (reactFallbackPayload !== null) {
@@ -0,0 +1,149 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Card, Result, Space} from "antd";
+import * as ApplicationBackend from "../backend/ApplicationBackend";
+import * as CibaBackend from "../backend/CibaBackend";
+import * as Setting from "../Setting";
+import i18next from "i18next";
+import {withRouter} from "react-router-dom";
+
+class CibaPage extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      applicationName: props.match?.params?.applicationName,
+      authReqId: props.match?.params?.authReqId,
+      request: undefined,
+      msg: "",
+      completing: false,
+      result: "",
+    };
+  }
+
+  componentDidMount() {
+    this.getApplication();
+    this.getCibaRequest();
+  }
+
+  getApplication() {
+    ApplicationBackend.getApplication("admin", this.state.applicationName)
+      .then((res) => {
+        if (res.status === "error") {
+          Setting.showMessage("error", res.msg);
+          return;
+        }
+
+        this.props.onUpdateApplication(res.data);
+      });
+  }
+
+  getCibaRequest() {
+    CibaBackend.getCibaRequest(this.state.authReqId)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({request: res.data});
+        } else {
+          this.setState({request: null, msg: res.msg});
+        }
+      });
+  }
+
+  completeCibaRequest(action) {
+    this.setState({completing: true});
+    CibaBackend.completeCibaRequest(this.state.authReqId, action)
+      .then((res) => {
+        if (res.status === "ok") {
+          this.setState({result: action});
+        } else {
+          Setting.showMessage("error", res.msg);
+          this.setState({completing: false});
+        }
+      });
+  }
+
+  render() {
+    const application = this.props.application;
+    const {request, result, completing} = this.state;
+
+    if (request === undefined || application === undefined) {
+      return null;
+    }
+
+    if (request === null) {
+      return (
+        <Result status="error" title={i18next.t("application:Invalid sign-in request")} subTitle={this.state.msg} />
+      );
+    }
+
+    if (result !== "") {
+      return (
+        <Result
+          status={result === "approve" ? "success" : "warning"}
+          title={result === "approve" ? i18next.t("application:Sign-in request approved") : i18next.t("application:Sign-in request denied")}
+          subTitle={i18next.t("application:You can close this page now")}
+        />
+      );
+    }
+
+    const applicationName = application?.displayName || application?.name || request.application;
+
+    return (
+      <div className="login-content">
+        <div className={Setting.isDarkTheme(this.props.themeAlgorithm) ? "login-panel-dark" : "login-panel"}>
+          <div className="login-form">
+            <Card style={{padding: "32px", width: 450, borderRadius: "12px"}}>
+              <div style={{textAlign: "center", marginBottom: 24}}>
+                {application?.logo && (
+                  <div style={{marginBottom: 16}}>
+                    <img src={application.logo} alt={applicationName} style={{height: 56, objectFit: "contain"}} />
+                  </div>
+                )}
+                <h2 style={{margin: 0, fontWeight: 600, fontSize: "24px"}}>
+                  {i18next.t("application:Sign-in request")}
+                </h2>
+              </div>
+              <p style={{fontSize: 15, textAlign: "center"}}>
+                <span style={{fontWeight: 600}}>{applicationName}</span>
+                {" "}{i18next.t("application:requests to sign you in")}
+              </p>
+              {request.bindingMessage && (
+                <div style={{textAlign: "center", marginBottom: 16}}>
+                  <div style={{fontSize: 13, color: "#8c8c8c"}}>{i18next.t("application:Binding message")}</div>
+                  <div style={{fontSize: 20, fontWeight: 600}}>{request.bindingMessage}</div>
+                </div>
+              )}
+              <p style={{fontSize: 13, color: "#8c8c8c", textAlign: "center"}}>
+                {i18next.t("general:Scopes")}: {request.scope}
+              </p>
+              <div style={{textAlign: "center", marginTop: 24}}>
+                <Space size={16}>
+                  <Button type="primary" size="large" shape="round" loading={completing} disabled={completing} onClick={() => this.completeCibaRequest("approve")}>
+                    {i18next.t("permission:Allow")}
+                  </Button>
+                  <Button size="large" shape="round" disabled={completing} onClick={() => this.completeCibaRequest("deny")}>
+                    {i18next.t("permission:Deny")}
+                  </Button>
+                </Space>
+              </div>
+            </Card>
+          </div>
+        </div>
+      </div>
+    );
+  }
+}
+
+export default withRouter(CibaPage);
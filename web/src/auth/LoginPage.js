@@ -74,6 +74,7 @@ class LoginPage extends React.Component {
       userCode: props.userCode ?? (props.match?.params?.userCode ?? null),
       userCodeStatus: "",
       prefilledUsername: urlParams.get("username") || urlParams.get("login_hint"),
+      requiredReauthentication: false,
     };
 
     if (this.state.type === "cas" && props.match?.params.casApplicationName !== undefined) {
@@ -93,7 +94,7 @@ class LoginPage extends React.Component {
 
   componentDidMount() {
     if (this.getApplicationObj() === undefined) {
-      if (this.state.type === "login" || this.state.type === "saml") {
+      if (this.state.type === "login" || this.state.type === "saml" || this.state.type === "wsfed") {
         this.getApplication();
       } else if (this.state.type === "code" || this.state.type === "cas" || this.state.type === "device") {
         this.getApplicationLogin();
@@ -191,7 +192,7 @@ class LoginPage extends React.Component {
       return null;
     }
 
-    if (this.state.owner === null || this.state.type === "saml") {
+    if (this.state.owner === null || this.state.type === "saml" || this.state.type === "wsfed") {
       ApplicationBackend.getApplication("admin", this.state.applicationName)
         .then((res) => {
           if (res.status === "error") {
@@ -330,6 +331,15 @@ class LoginPage extends React.Component {
       values["samlRequest"] = oAuthParams.samlRequest;
       values["type"] = "saml";
       values["relayState"] = oAuthParams.relayState;
+      values["sigAlg"] = oAuthParams.sigAlg;
+      values["signature"] = oAuthParams.signature;
+      values["samlQuery"] = oAuthParams.samlQuery;
+    }
+
+    if (oAuthParams?.wsFedRealm) {
+      values["wsFedRealm"] = oAuthParams.wsFedRealm;
+      values["wsFedReply"] = oAuthParams.wsFedReply;
+      values["type"] = "wsfed";
     }
   }
 
@@ -598,6 +608,16 @@ class LoginPage extends React.Component {
                 const redirectUri = res.data2.redirectUrl;
                 Setting.goToLink(`${redirectUri}${redirectUri.includes("?") ? "&" : "?"}SAMLResponse=${encodeURIComponent(SAMLResponse)}&RelayState=${encodeURIComponent(oAuthParams.relayState)}`);
               }
+            } else if (responseType === "wsfed") {
+              if (res.data === RequiredMfa) {
+                this.props.onLoginSuccess(window.location.href);
+                return;
+              }
+              createFormAndSubmit(res.data2, {
+                wa: "wsignin1.0",
+                wresult: res.data,
+                wctx: oAuthParams.wsFedContext,
+              });
             }
           };
 
@@ -658,6 +678,11 @@ class LoginPage extends React.Component {
       return;
     }
 
+    if (window.location.pathname.startsWith("/login/wsfed/authorize")) {
+      Setting.goToLink(`/login/wsfed/authorize/${name}/${application.name}-org-${name}?${searchParams.toString()}`);
+      return;
+    }
+
     if (window.location.pathname.startsWith("/cas")) {
       Setting.goToLink(`/cas/${application.name}-org-${name}/${name}/login?${searchParams.toString()}`);
       return;
@@ -1286,6 +1311,11 @@ class LoginPage extends React.Component {
       return null;
     }
 
+    if (this.state.requiredReauthentication) {
+      this.sendSilentSigninData("user-not-logged-in");
+      return null;
+    }
+
     if (this.state.userCode && this.state.userCodeStatus === "success") {
       return null;
     }
@@ -56,6 +56,19 @@ export function clearCodeVerifier(state) {
   localStorage.removeItem(`pkce_verifier_${state}`);
 }
 
+// The nonce of an OIDC login, the id_token returned by the provider must carry it
+function storeIdpNonce(state, nonce) {
+  localStorage.setItem(`oidc_nonce_${state}`, nonce);
+}
+
+export function getIdpNonce(state) {
+  return localStorage.getItem(`oidc_nonce_${state}`);
+}
+
+export function clearIdpNonce(state) {
+  localStorage.removeItem(`oidc_nonce_${state}`);
+}
+
 const authInfo = {
   Google: {
     scope: "profile+email",
@@ -166,6 +179,10 @@ const authInfo = {
   Custom: {
     endpoint: "https://example.com/",
   },
+  OIDC: {
+    scope: "openid profile email",
+    endpoint: "https://example.com/",
+  },
   Bilibili: {
     endpoint: "https://passport.bilibili.com/register/pc_oauth2.html",
   },
@@ -555,6 +572,11 @@ export function getAuthUrl(application, provider, method, code) {
     return `${endpoint}?client_key=${provider.clientId}&redirect_uri=${redirectUri}&state=${state}&response_type=code&scope=${scope}`;
   } else if (provider.type === "Kwai") {
     return `${endpoint}?app_id=${provider.clientId}&redirect_uri=${redirectUri}&state=${state}&response_type=code&scope=${scope}`;
+  } else if (provider.type === "OIDC") {
+    // the endpoints are looked up from the discovery document of the issuer by the backend
+    const nonce = generateCodeVerifier();
+    storeIdpNonce(state, nonce);
+    return `${Setting.ServerUrl}/api/oidc-login?id=${encodeURIComponent(`${provider.owner}/${provider.name}`)}&redirectUri=${encodeURIComponent(redirectUri)}&state=${encodeURIComponent(state)}&codeChallenge=${codeChallenge}&nonce=${nonce}`;
   } else if (type === "Custom") {
     let authUrl = `${provider.customAuthUrl}?client_id=${provider.clientId}&redirect_uri=${encodeURIComponent(redirectUri)}&scope=${encodeURIComponent(provider.scopes)}&response_type=code&state=${encodeURIComponent(state)}`;
     if (provider.enablePkce) {
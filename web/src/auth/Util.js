@@ -140,10 +140,20 @@ export function getOAuthGetParameters(params) {
   const responseMode = getRefinedValue(queries.get("response_mode"));
   const samlRequest = getRefinedValue(lowercaseQueries["samlRequest".toLowerCase()]);
   const relayState = getRefinedValue(lowercaseQueries["RelayState".toLowerCase()]);
+  const sigAlg = getRefinedValue(lowercaseQueries["SigAlg".toLowerCase()]);
+  const signature = getRefinedValue(lowercaseQueries["Signature".toLowerCase()]);
+  // the query string signature of the SAML request covers the parameters in their original encoding
+  const samlQuery = (params !== undefined) ? "" : window.location.search.replace(/^\?/, "");
+  const wsFedRealm = getRefinedValue(queries.get("wtrealm"));
+  const wsFedReply = getRefinedValue(queries.get("wreply"));
+  const wsFedContext = getRefinedValue(queries.get("wctx"));
   const noRedirect = getRefinedValue(lowercaseQueries["noRedirect".toLowerCase()]);
   const resource = getRefinedValue(queries.get("resource"));
+  const requestUri = getRefinedValue(queries.get("request_uri"));
+  const acrValues = getRefinedValue(queries.get("acr_values"));
+  const maxAge = getRefinedValue(queries.get("max_age"));
 
-  if (clientId === "" && samlRequest === "") {
+  if (clientId === "" && samlRequest === "" && wsFedRealm === "") {
     // login
     return null;
   } else {
@@ -160,8 +170,17 @@ export function getOAuthGetParameters(params) {
       responseMode: responseMode,
       samlRequest: samlRequest,
       relayState: relayState,
+      sigAlg: sigAlg,
+      signature: signature,
+      samlQuery: samlQuery,
+      wsFedRealm: wsFedRealm,
+      wsFedReply: wsFedReply,
+      wsFedContext: wsFedContext,
       noRedirect: noRedirect,
       resource: resource,
+      requestUri: requestUri,
+      acrValues: acrValues,
+      maxAge: maxAge,
       type: "code",
     };
   }
@@ -101,3 +101,15 @@ export function getSamlMetadata(owner, name, enablePostBinding) {
     },
   }).then(res => res.text());
 }
+
+export function parseSamlSpMetadata(metadata, metadataUrl) {
+  return fetch(`${Setting.ServerUrl}/api/parse-saml-sp-metadata`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify({metadata: metadata, metadataUrl: metadataUrl}),
+    headers: {
+      "Content-Type": "application/json",
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
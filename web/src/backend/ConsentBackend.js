@@ -27,6 +27,9 @@ export function grantConsent(consent, oAuthParams) {
     nonce: oAuthParams.nonce || "",
     challenge: oAuthParams.codeChallenge || "",
     resource: oAuthParams.resource || "",
+    acrValues: oAuthParams.acrValues || "",
+    maxAge: oAuthParams.maxAge || "",
+    requestUri: oAuthParams.requestUri || "",
   };
   return fetch(`${Setting.ServerUrl}/api/grant-consent`, {
     method: "POST",
@@ -0,0 +1,74 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import * as Setting from "../Setting";
+
+export function getEventSinks(owner, organization, page = "", pageSize = "", field = "", value = "", sortField = "", sortOrder = "") {
+  return fetch(`${Setting.ServerUrl}/api/get-event-sinks?owner=${owner}&organization=${encodeURIComponent(organization)}&p=${page}&pageSize=${pageSize}&field=${field}&value=${value}&sortField=${sortField}&sortOrder=${sortOrder}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function getEventSink(owner, name, organization = owner) {
+  return fetch(`${Setting.ServerUrl}/api/get-event-sink?id=${owner}/${encodeURIComponent(name)}&organization=${encodeURIComponent(organization)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function updateEventSink(owner, name, eventSink) {
+  const newEventSink = Setting.deepCopy(eventSink);
+  return fetch(`${Setting.ServerUrl}/api/update-event-sink?id=${owner}/${encodeURIComponent(name)}`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify(newEventSink),
+    headers: {
+      "Content-Type": "application/json",
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function addEventSink(eventSink) {
+  const newEventSink = Setting.deepCopy(eventSink);
+  return fetch(`${Setting.ServerUrl}/api/add-event-sink`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify(newEventSink),
+    headers: {
+      "Content-Type": "application/json",
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function deleteEventSink(eventSink) {
+  const newEventSink = Setting.deepCopy(eventSink);
+  return fetch(`${Setting.ServerUrl}/api/delete-event-sink`, {
+    method: "POST",
+    credentials: "include",
+    body: JSON.stringify(newEventSink),
+    headers: {
+      "Content-Type": "application/json",
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
@@ -40,3 +40,43 @@ export function deleteSession(session, sessionId = "") {
     },
   }).then(res => res.json());
 }
+
+export function getAccountSessionDevices() {
+  return fetch(`${Setting.ServerUrl}/api/get-account-session-devices`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function revokeAccountSessionDevice(device) {
+  return fetch(`${Setting.ServerUrl}/api/revoke-account-session-device?device=${encodeURIComponent(device)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function getSessionDevices(owner, user) {
+  return fetch(`${Setting.ServerUrl}/api/get-session-devices?owner=${owner}&user=${encodeURIComponent(user)}`, {
+    method: "GET",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
+
+export function revokeSessionDevice(owner, user, device) {
+  return fetch(`${Setting.ServerUrl}/api/revoke-session-device?id=${owner}/${encodeURIComponent(user)}&device=${encodeURIComponent(device)}`, {
+    method: "POST",
+    credentials: "include",
+    headers: {
+      "Accept-Language": Setting.getAcceptLanguage(),
+    },
+  }).then(res => res.json());
+}
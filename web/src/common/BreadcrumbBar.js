@@ -60,6 +60,7 @@ const RESOURCE_LABELS = {
   "syncers": "general:Syncers",
   "webhooks": "general:Webhooks",
   "webhook-events": "general:Webhook Events",
+  "event-sinks": "general:Event Sinks",
   "tickets": "general:Tickets",
   "ldap": "general:LDAP",
   "mfa": "general:MFA",
@@ -96,6 +96,7 @@ export const NavItemTree = ({disabled, checkedKeys, defaultExpandedKeys, onCheck
             {title: i18next.t("general:Syncers"), key: "/syncers"},
             {title: i18next.t("general:Webhooks"), key: "/webhooks"},
             {title: i18next.t("general:Webhook Events"), key: "/webhook-events"},
+            {title: i18next.t("general:Event Sinks"), key: "/event-sinks"},
             {title: i18next.t("general:Tickets"), key: "/tickets"},
             {title: i18next.t("general:Swagger"), key: "/swagger"},
           ],
@@ -54,12 +54,18 @@ function isValidOption_NoRepeat(password) {
   return "";
 }
 
+// the breached password corpus is only available to the backend, which checks the password on submit
+function isValidOption_NotBreached(password) {
+  return "";
+}
+
 const checkers = {
   AtLeast6: isValidOption_AtLeast6,
   AtLeast8: isValidOption_AtLeast8,
   Aa123: isValidOption_Aa123,
   SpecialChar: isValidOption_SpecialChar,
   NoRepeat: isValidOption_NoRepeat,
+  NotBreached: isValidOption_NotBreached,
 };
 
 function getOptionDescription(option, password) {
@@ -69,6 +75,7 @@ function getOptionDescription(option, password) {
   case "Aa123": return i18next.t("user:The password must contain at least one uppercase letter, one lowercase letter and one digit");
   case "SpecialChar": return i18next.t("user:The password must contain at least one special character");
   case "NoRepeat": return i18next.t("user:The password must not contain any repeated characters");
+  case "NotBreached": return i18next.t("user:The password must not appear in a known data breach");
   }
 }
 
@@ -13,10 +13,12 @@
 // limitations under the License.
 
 import React from "react";
-import {Col, Input, Radio, Row, Select, Switch} from "antd";
+import {Button, Col, Input, Radio, Row, Select, Switch} from "antd";
 import {LinkOutlined} from "@ant-design/icons";
 import * as Setting from "../Setting";
 import i18next from "i18next";
+import {authConfig} from "../auth/Auth";
+import copy from "copy-to-clipboard";
 
 const {TextArea} = Input;
 const {Option} = Select;
@@ -161,6 +163,90 @@ export function renderOAuthProviderFields(provider, updateProviderField, renderU
           </React.Fragment>
         ) : null
       }
+      {
+        provider.type === "OIDC" ? (
+          <React.Fragment>
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("provider:Issuer URL"), i18next.t("provider:Issuer URL - Tooltip"))} :
+              </Col>
+              <Col span={22} >
+                <Input prefix={<LinkOutlined />} value={provider.issuerUrl} placeholder="https://keycloak.example.com/realms/master" onChange={e => {
+                  updateProviderField("issuerUrl", e.target.value);
+                }} />
+              </Col>
+            </Row>
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("provider:Scope"), i18next.t("provider:Scope - Tooltip"))} :
+              </Col>
+              <Col span={22} >
+                <Input value={provider.scopes} placeholder="openid profile email" onChange={e => {
+                  updateProviderField("scopes", e.target.value);
+                }} />
+              </Col>
+            </Row>
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("provider:User mapping"), i18next.t("provider:User mapping - Tooltip"))} :
+              </Col>
+              <Col span={22} >
+                {renderUserMappingInput()}
+              </Col>
+            </Row>
+            <Row style={{marginTop: "20px"}} >
+              <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+                {Setting.getLabel(i18next.t("general:Favicon"), i18next.t("general:Favicon - Tooltip"))} :
+              </Col>
+              <Col span={22} >
+                <Input prefix={<LinkOutlined />} value={provider.customLogo} onChange={e => {
+                  updateProviderField("customLogo", e.target.value);
+                }} />
+              </Col>
+            </Row>
+          </React.Fragment>
+        ) : null
+      }
+      {
+        ["OIDC", "Okta", "Casdoor", "ADFS"].includes(provider.type) ? (
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+              {Setting.getLabel(i18next.t("provider:Back-channel logout URL"), i18next.t("provider:Back-channel logout URL - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input value={`${authConfig.serverUrl}/api/provider-logout/${provider.owner}/${provider.name}`} readOnly="readonly" />
+            </Col>
+            <Col span={1}>
+              <Button type="primary" onClick={() => {
+                copy(`${authConfig.serverUrl}/api/provider-logout/${provider.owner}/${provider.name}`);
+                Setting.showMessage("success", i18next.t("general:Copied to clipboard successfully"));
+              }}>
+                {i18next.t("general:Copy")}
+              </Button>
+            </Col>
+          </Row>
+        ) : null
+      }
+      {
+        ["OIDC", "Okta", "Casdoor", "ADFS"].includes(provider.type) ? (
+          <Row style={{marginTop: "20px"}} >
+            <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+              {Setting.getLabel(i18next.t("provider:Shared Signals receiver URL"), i18next.t("provider:Shared Signals receiver URL - Tooltip"))} :
+            </Col>
+            <Col span={21} >
+              <Input value={`${authConfig.serverUrl}/api/ssf-receiver/${provider.owner}/${provider.name}`} readOnly="readonly" />
+            </Col>
+            <Col span={1}>
+              <Button type="primary" onClick={() => {
+                copy(`${authConfig.serverUrl}/api/ssf-receiver/${provider.owner}/${provider.name}`);
+                Setting.showMessage("success", i18next.t("general:Copied to clipboard successfully"));
+              }}>
+                {i18next.t("general:Copy")}
+              </Button>
+            </Col>
+          </Row>
+        ) : null
+      }
       {
         provider.type.startsWith("Custom") ? (
           <React.Fragment>
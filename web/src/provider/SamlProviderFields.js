@@ -128,6 +128,22 @@ export function renderSamlProviderFields(provider, updateProviderField, metadata
           </Button>
         </Col>
       </Row>
+      <Row style={{marginTop: "20px"}} >
+        <Col style={{marginTop: "5px"}} span={(Setting.isMobile()) ? 22 : 2}>
+          {Setting.getLabel(i18next.t("provider:SP SLO URL"), i18next.t("provider:SP SLO URL - Tooltip"))} :
+        </Col>
+        <Col span={21} >
+          <Input value={`${authConfig.serverUrl}/api/provider-logout/${provider.owner}/${provider.name}`} readOnly="readonly" />
+        </Col>
+        <Col span={1}>
+          <Button type="primary" onClick={() => {
+            copy(`${authConfig.serverUrl}/api/provider-logout/${provider.owner}/${provider.name}`);
+            Setting.showMessage("success", i18next.t("general:Copied to clipboard successfully"));
+          }}>
+            {i18next.t("general:Copy")}
+          </Button>
+        </Col>
+      </Row>
     </React.Fragment>
   );
 }
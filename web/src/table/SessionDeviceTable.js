@@ -0,0 +1,146 @@
+// Copyright 2026 The Casdoor Authors. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import React from "react";
+import {Button, Popconfirm, Table, Tag} from "antd";
+import * as Setting from "../Setting";
+import i18next from "i18next";
+import * as SessionBackend from "../backend/SessionBackend";
+
+class SessionDeviceTable extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {
+      classes: props,
+    };
+  }
+
+  revokeSessionDevice(record) {
+    const promise = this.props.isSelf ?
+      SessionBackend.revokeAccountSessionDevice(record.name) :
+      SessionBackend.revokeSessionDevice(record.owner, record.user, record.name);
+
+    promise
+      .then((res) => {
+        if (res.status === "ok") {
+          Setting.showMessage("success", i18next.t("general:Successfully revoked"));
+          this.props.onUpdateTable();
+        } else {
+          Setting.showMessage("error", res.msg);
+        }
+      })
+      .catch(error => {
+        Setting.showMessage("error", `${i18next.t("general:Failed to connect to server")}: ${error}`);
+      });
+  }
+
+  renderTable(table) {
+    const columns = [
+      {
+        title: i18next.t("general:User agent"),
+        dataIndex: "userAgent",
+        key: "userAgent",
+        render: (text, record) => {
+          return (
+            <div>
+              {text}
+              {record.isCurrent ? <Tag color="green" style={{marginLeft: "8px"}}>{i18next.t("user:Current session")}</Tag> : null}
+            </div>
+          );
+        },
+      },
+      {
+        title: i18next.t("general:Client IP"),
+        dataIndex: "clientIp",
+        key: "clientIp",
+        width: "150px",
+      },
+      {
+        title: i18next.t("general:Application"),
+        dataIndex: "application",
+        key: "application",
+        width: "150px",
+      },
+      {
+        title: i18next.t("user:Sign-in method"),
+        dataIndex: "signinMethod",
+        key: "signinMethod",
+        width: "150px",
+        render: (text, record) => {
+          return record.provider ? `${text} (${record.provider})` : text;
+        },
+      },
+      {
+        title: i18next.t("general:Created time"),
+        dataIndex: "createdTime",
+        key: "createdTime",
+        width: "180px",
+        render: (text) => {
+          return Setting.getFormattedDate(text);
+        },
+      },
+      {
+        title: i18next.t("user:Last active time"),
+        dataIndex: "lastActiveTime",
+        key: "lastActiveTime",
+        width: "180px",
+        render: (text) => {
+          return Setting.getFormattedDate(text);
+        },
+      },
+      {
+        title: i18next.t("general:Action"),
+        key: "action",
+        width: "100px",
+        render: (_, record, __) => {
+          return (
+            <Popconfirm
+              title={i18next.t("user:Are you sure you want to sign out of this device?")}
+              disabled={record.isCurrent}
+              onConfirm={() => this.revokeSessionDevice(record)}
+              okText={i18next.t("general:OK")}
+              cancelText={i18next.t("general:Cancel")}
+            >
+              <Button type="primary" danger size="small" disabled={record.isCurrent}>
+                {i18next.t("user:Sign out")}
+              </Button>
+            </Popconfirm>
+          );
+        },
+      },
+    ];
+
+    return (
+      <Table scroll={{x: "max-content"}} rowKey="name" columns={columns} dataSource={table} size="middle" bordered pagination={false}
+        title={() => (
+          <div>
+            {this.props.title}
+          </div>
+        )}
+      />
+    );
+  }
+
+  render() {
+    return (
+      <div>
+        {
+          this.renderTable(this.props.table)
+        }
+      </div>
+    );
+  }
+}
+
+export default SessionDeviceTable;